@@ -19,8 +19,12 @@ import (
 	"context"
 	"net"
 	"net/http"
+	"strings"
 
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
+
+	"go.opentelemetry.io/collector/config/configauth"
 )
 
 type ctxKey struct{}
@@ -28,6 +32,51 @@ type ctxKey struct{}
 // Client represents a generic client that sends data to any receiver supported by the OT receiver
 type Client struct {
 	IP string
+
+	// Receiver is the name of the receiver the data was received by, e.g. "otlp/internal".
+	Receiver string
+
+	// Subject is the subject authenticated by the receiver, empty if the receiver doesn't
+	// authenticate the requests.
+	Subject string
+
+	// Groups are the groups of the authenticated subject.
+	Groups []string
+
+	// Metadata holds the request headers or gRPC metadata the receiver is configured to keep,
+	// with lower case keys.
+	Metadata map[string][]string
+}
+
+// Option sets additional information on the Client extracted from a request.
+type Option func(c *Client, md metadataSource)
+
+// metadataSource returns the values of the given lower case key of the request metadata.
+type metadataSource func(key string) []string
+
+// WithReceiver sets the name of the receiver on the Client.
+func WithReceiver(name string) Option {
+	return func(c *Client, _ metadataSource) {
+		c.Receiver = name
+	}
+}
+
+// WithMetadata keeps the values of the given request headers or gRPC metadata keys, which are
+// matched case-insensitively, in the Client.
+func WithMetadata(keys ...string) Option {
+	return func(c *Client, md metadataSource) {
+		for _, key := range keys {
+			key = strings.ToLower(key)
+			values := md(key)
+			if len(values) == 0 {
+				continue
+			}
+			if c.Metadata == nil {
+				c.Metadata = map[string][]string{}
+			}
+			c.Metadata[key] = append([]string(nil), values...)
+		}
+	}
 }
 
 // NewContext takes an existing context and derives a new context with the client value stored on it
@@ -41,24 +90,65 @@ func FromContext(ctx context.Context) (*Client, bool) {
 	return c, ok
 }
 
-// FromGRPC takes a GRPC context and tries to extract client information from it
-func FromGRPC(ctx context.Context) (*Client, bool) {
-	if p, ok := peer.FromContext(ctx); ok {
-		ip := parseIP(p.Addr.String())
-		if ip != "" {
-			return &Client{ip}, true
+// FromGRPC takes a GRPC context and tries to extract client information from it. If the
+// context already holds a Client, a copy of it with the options applied is returned.
+func FromGRPC(ctx context.Context, opts ...Option) (*Client, bool) {
+	c := fromContext(ctx)
+	if c.IP == "" {
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			c.IP = parseIP(p.Addr.String())
+		}
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	return c.apply(opts, func(key string) []string {
+		return md.Get(key)
+	})
+}
+
+// FromHTTP takes a net/http Request object and tries to extract client information from it. If
+// the request's context already holds a Client, a copy of it with the options applied is returned.
+func FromHTTP(r *http.Request, opts ...Option) (*Client, bool) {
+	c := fromContext(r.Context())
+	if c.IP == "" {
+		c.IP = parseIP(r.RemoteAddr)
+	}
+
+	return c.apply(opts, func(key string) []string {
+		return r.Header.Values(key)
+	})
+}
+
+// fromContext returns a copy of the Client of the context, or a new Client holding the
+// authenticated subject and groups.
+func fromContext(ctx context.Context) *Client {
+	if c, ok := FromContext(ctx); ok {
+		cp := *c
+		if c.Metadata != nil {
+			// the options may add keys, the map of the original Client must be left untouched
+			cp.Metadata = make(map[string][]string, len(c.Metadata))
+			for k, v := range c.Metadata {
+				cp.Metadata[k] = v
+			}
 		}
+		return &cp
 	}
-	return nil, false
+
+	c := &Client{}
+	c.Subject, _ = configauth.SubjectFromContext(ctx)
+	c.Groups, _ = configauth.GroupsFromContext(ctx)
+	return c
 }
 
-// FromHTTP takes a net/http Request object and tries to extract client information from it
-func FromHTTP(r *http.Request) (*Client, bool) {
-	ip := parseIP(r.RemoteAddr)
-	if ip == "" {
+// apply applies the options, returning false when nothing is known about the client
+func (c *Client) apply(opts []Option, md metadataSource) (*Client, bool) {
+	for _, opt := range opts {
+		opt(c, md)
+	}
+	if c.IP == "" && c.Receiver == "" && c.Subject == "" && len(c.Groups) == 0 && len(c.Metadata) == 0 {
 		return nil, false
 	}
-	return &Client{ip}, true
+	return c, true
 }
 
 func parseIP(source string) string {
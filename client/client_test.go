@@ -17,12 +17,19 @@ package client
 
 import (
 	"context"
+	"io/ioutil"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
+
+	"go.opentelemetry.io/collector/config/configauth"
 )
 
 func TestClientContext(t *testing.T) {
@@ -30,7 +37,7 @@ func TestClientContext(t *testing.T) {
 		"1.1.1.1", "127.0.0.1", "1111", "ip",
 	}
 	for _, ip := range ips {
-		ctx := NewContext(context.Background(), &Client{ip})
+		ctx := NewContext(context.Background(), &Client{IP: ip})
 		c, ok := FromContext(ctx)
 		assert.True(t, ok)
 		assert.NotNil(t, c)
@@ -58,3 +65,92 @@ func TestParsingHTTP(t *testing.T) {
 	assert.NotNil(t, client)
 	assert.Equal(t, client.IP, "192.168.1.2")
 }
+
+// authenticatedContext returns a context authenticated by configauth as the agent-1 subject
+func authenticatedContext(t *testing.T) context.Context {
+	dir, err := ioutil.TempDir("", "client")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "api_keys")
+	require.NoError(t, ioutil.WriteFile(file, []byte("some-key agent-1 department-1\n"), 0600))
+
+	auth, err := configauth.NewAuthenticator(configauth.Authentication{APIKeys: &configauth.APIKeys{File: file}})
+	require.NoError(t, err)
+	require.NoError(t, auth.Start(context.Background()))
+	ctx, err := auth.Authenticate(context.Background(), map[string][]string{"authorization": {"Bearer some-key"}})
+	require.NoError(t, err)
+	return ctx
+}
+
+func TestParsingGRPCWithOptions(t *testing.T) {
+	ctx := peer.NewContext(authenticatedContext(t), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("192.168.1.1"), Port: 80},
+	})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(
+		"x-tenant", "acme",
+		"x-tenant", "globex",
+		"x-other", "value",
+	))
+
+	client, ok := FromGRPC(ctx, WithReceiver("otlp/internal"), WithMetadata("X-Tenant", "x-missing"))
+	assert.True(t, ok)
+	assert.Equal(t, &Client{
+		IP:       "192.168.1.1",
+		Receiver: "otlp/internal",
+		Subject:  "agent-1",
+		Groups:   []string{"department-1"},
+		Metadata: map[string][]string{"x-tenant": {"acme", "globex"}},
+	}, client)
+}
+
+func TestParsingHTTPWithOptions(t *testing.T) {
+	r, err := http.NewRequest(http.MethodPost, "http://localhost", nil)
+	require.NoError(t, err)
+	r = r.WithContext(authenticatedContext(t))
+	r.RemoteAddr = "192.168.1.2:1234"
+	r.Header.Add("X-Tenant", "acme")
+	r.Header.Add("X-Other", "value")
+
+	client, ok := FromHTTP(r, WithReceiver("zipkin"), WithMetadata("x-tenant"))
+	assert.True(t, ok)
+	assert.Equal(t, &Client{
+		IP:       "192.168.1.2",
+		Receiver: "zipkin",
+		Subject:  "agent-1",
+		Groups:   []string{"department-1"},
+		Metadata: map[string][]string{"x-tenant": {"acme"}},
+	}, client)
+}
+
+func TestParsingWithClientInContext(t *testing.T) {
+	existing := &Client{
+		IP:       "192.168.1.3",
+		Metadata: map[string][]string{"x-tenant": {"acme"}},
+	}
+	ctx := NewContext(context.Background(), existing)
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-other", "value"))
+
+	client, ok := FromGRPC(ctx, WithReceiver("otlp"), WithMetadata("x-other"))
+	assert.True(t, ok)
+	assert.Equal(t, &Client{
+		IP:       "192.168.1.3",
+		Receiver: "otlp",
+		Metadata: map[string][]string{"x-tenant": {"acme"}, "x-other": {"value"}},
+	}, client)
+
+	// the client of the context is left untouched
+	assert.Equal(t, &Client{
+		IP:       "192.168.1.3",
+		Metadata: map[string][]string{"x-tenant": {"acme"}},
+	}, existing)
+}
+
+func TestParsingWithoutClientInformation(t *testing.T) {
+	client, ok := FromGRPC(context.Background(), WithMetadata("x-tenant"))
+	assert.False(t, ok)
+	assert.Nil(t, client)
+
+	client, ok = FromHTTP(&http.Request{RemoteAddr: "invalid"})
+	assert.False(t, ok)
+	assert.Nil(t, client)
+}
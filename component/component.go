@@ -93,6 +93,14 @@ type Host interface {
 	GetExporters() map[configmodels.DataType]map[configmodels.Exporter]Exporter
 }
 
+// ExportReporter is an extra interface for Host, implemented by the hosts notifying the
+// extensions implementing ExportWatcher of the outcome of the export operations.
+type ExportReporter interface {
+	// ReportExport notifies the ExportWatcher extensions of the result of an export operation.
+	// It is called synchronously by the exporters.
+	ReportExport(result ExportResult)
+}
+
 // Factory interface must be implemented by all component factories.
 type Factory interface {
 	// Type gets the type of the component created by this factory.
@@ -46,6 +46,38 @@ type PipelineWatcher interface {
 	NotReady() error
 }
 
+// PipelineConfigWatcher is an extra interface for ServiceExtension hosted by the
+// OpenTelemetry Collector that is to be implemented by extensions interested in the
+// configuration of the pipelines, e.g.: to report the health of each pipeline.
+type PipelineConfigWatcher interface {
+	// PipelinesConfigured notifies the ServiceExtension of the pipelines of the service.
+	// It is called each time the pipelines are built, before Ready.
+	PipelinesConfigured(pipelines configmodels.Pipelines) error
+}
+
+// ExportWatcher is an extra interface for ServiceExtension hosted by the OpenTelemetry
+// Collector that is to be implemented by extensions interested in the outcome of the
+// export operations, e.g.: to report the health of the exporters.
+type ExportWatcher interface {
+	// ExportCompleted notifies the ServiceExtension of the result of an export operation.
+	// It is called synchronously by the exporters, so it must return quickly.
+	ExportCompleted(result ExportResult)
+}
+
+// ExportResult is the outcome of an export operation, passed to ExportWatcher.
+type ExportResult struct {
+	// ExporterName is the name of the exporter in the configuration.
+	ExporterName string
+	// DataType is the type of the exported data.
+	DataType configmodels.DataType
+	// NumSent is the number of items successfully sent.
+	NumSent int64
+	// NumFailedToSend is the number of items that failed to be sent.
+	NumFailedToSend int64
+	// Err is the error of the operation, nil if it succeeded.
+	Err error
+}
+
 // ExtensionCreateParams is passed to ExtensionFactory.Create* functions.
 type ExtensionCreateParams struct {
 	// Logger that the factory can use during creation and can pass to the created
@@ -52,6 +52,7 @@ const (
 	errMissingReceivers
 	errMissingExporters
 	errUnmarshalTopLevelStructureError
+	errInvalidExporterConfig
 )
 
 const (
@@ -648,9 +649,27 @@ func validateExporters(cfg *configmodels.Config) error {
 			msg:  "no enabled exporters specified in config",
 		}
 	}
+
+	// The settings shared by the exporters, e.g. configgrpc.GRPCClientSettings, can check that
+	// they are consistent before anything is built.
+	for name, exp := range cfg.Exporters {
+		if v, ok := exp.(validator); ok {
+			if err := v.Validate(); err != nil {
+				return &configError{
+					code: errInvalidExporterConfig,
+					msg:  fmt.Sprintf("exporter %q has invalid configuration: %v", name, err),
+				}
+			}
+		}
+	}
 	return nil
 }
 
+// validator is implemented by the configs checking their own settings.
+type validator interface {
+	Validate() error
+}
+
 // expandEnvConfig creates a new viper config with expanded values for all the values (simple, list or map value).
 // It does not expand the keys.
 func expandEnvConfig(v *viper.Viper) {
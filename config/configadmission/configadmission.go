@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package configadmission implements the admission control of the receivers, based on the
+// size of the requests being processed.
+package configadmission
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// Admission defines the admission control settings for the receiver
+type Admission struct {
+	// LimitMiB is the maximum size, in MiB, of the requests being processed at any time.
+	// Required.
+	LimitMiB uint32 `mapstructure:"limit_mib"`
+
+	// WaitingLimitMiB is the maximum size, in MiB, of the requests waiting to be admitted. Requests
+	// which don't fit in it are rejected right away.
+	// Optional, default value: 0, the requests never wait.
+	WaitingLimitMiB uint32 `mapstructure:"waiting_limit_mib"`
+
+	// WaitTimeout is the maximum time a request waits to be admitted.
+	// Optional, default value: 0, the requests wait until they are cancelled.
+	WaitTimeout time.Duration `mapstructure:"wait_timeout"`
+}
+
+const mibBytes = 1024 * 1024
+
+var errNoLimit = errors.New("admission limit_mib must be greater than zero")
+
+func (a *Admission) newController() (*controller, error) {
+	if a.LimitMiB == 0 {
+		return nil, errNoLimit
+	}
+	if a.WaitTimeout < 0 {
+		return nil, errors.New("admission wait_timeout must not be negative")
+	}
+	return newController(int64(a.LimitMiB)*mibBytes, int64(a.WaitingLimitMiB)*mibBytes, a.WaitTimeout), nil
+}
+
+// Controller admits the requests of one or more servers within the same limits, e.g. the servers
+// of the different protocols of a receiver.
+type Controller struct {
+	controller *controller
+}
+
+// NewController creates a controller to be shared by several servers, see
+// Controller.ToServerOptions and Controller.ToHTTPHandler.
+func (a *Admission) NewController() (*Controller, error) {
+	c, err := a.newController()
+	if err != nil {
+		return nil, err
+	}
+	return &Controller{controller: c}, nil
+}
+
+// ToServerOptions builds a set of server options ready to be used by the gRPC server, admitting its
+// requests within the limits of its own controller.
+func (a *Admission) ToServerOptions() ([]grpc.ServerOption, error) {
+	c, err := a.NewController()
+	if err != nil {
+		return nil, err
+	}
+	return c.ToServerOptions(), nil
+}
+
+// ToHTTPHandler wraps the handler of an HTTP server with a middleware admitting each request within
+// the limits of its own controller.
+func (a *Admission) ToHTTPHandler(handler http.Handler) (http.Handler, error) {
+	c, err := a.NewController()
+	if err != nil {
+		return nil, err
+	}
+	return c.ToHTTPHandler(handler), nil
+}
+
+// ToServerOptions builds a set of server options ready to be used by the gRPC server. The tap handle
+// refuses the new RPCs before their messages are read while the limits are reached, the interceptors
+// are chained, so that they run after the authentication ones.
+func (c *Controller) ToServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.InTapHandle(c.controller.tapHandle),
+		grpc.ChainUnaryInterceptor(c.controller.unaryInterceptor),
+		grpc.ChainStreamInterceptor(c.controller.streamInterceptor),
+	}
+}
+
+// ToHTTPHandler wraps the handler of an HTTP server with a middleware admitting each request
+func (c *Controller) ToHTTPHandler(handler http.Handler) http.Handler {
+	return c.controller.httpHandler(handler)
+}
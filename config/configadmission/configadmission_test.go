@@ -0,0 +1,131 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configadmission
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToServerOptions(t *testing.T) {
+	cfg := Admission{LimitMiB: 64, WaitingLimitMiB: 16, WaitTimeout: time.Second}
+
+	// test
+	opts, err := cfg.ToServerOptions()
+
+	// verify
+	assert.NoError(t, err)
+	assert.Len(t, opts, 3) // we have a tap handle and two interceptors
+}
+
+func TestToHTTPHandler(t *testing.T) {
+	cfg := Admission{LimitMiB: 1}
+
+	called := false
+	handler, err := cfg.ToHTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	require.NoError(t, err)
+
+	// test
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	// verify
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, called)
+}
+
+func TestSharedController(t *testing.T) {
+	cfg := Admission{LimitMiB: 1}
+	shared, err := cfg.NewController()
+	require.NoError(t, err)
+
+	received := make(chan struct{})
+	done := make(chan struct{})
+	first := shared.ToHTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(received)
+		<-done
+	}))
+	second := shared.ToHTTPHandler(http.NotFoundHandler())
+	// the servers with the same settings don't share their limits unless given the same controller
+	other, err := cfg.ToHTTPHandler(http.NotFoundHandler())
+	require.NoError(t, err)
+
+	// test
+	go first.ServeHTTP(httptest.NewRecorder(), newRequest(mibBytes))
+	<-received
+	defer close(done)
+	secondRec := httptest.NewRecorder()
+	second.ServeHTTP(secondRec, newRequest(1))
+	otherRec := httptest.NewRecorder()
+	other.ServeHTTP(otherRec, newRequest(1))
+
+	// verify
+	assert.Equal(t, http.StatusTooManyRequests, secondRec.Code)
+	assert.Equal(t, http.StatusNotFound, otherRec.Code)
+}
+
+// newRequest returns a request with a body of the given size.
+func newRequest(size int) *http.Request {
+	return httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat("a", size)))
+}
+
+func TestInvalidConfiguration(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		cfg  Admission
+	}{
+		{
+			name: "no limit",
+			cfg:  Admission{WaitingLimitMiB: 1},
+		},
+		{
+			name: "negative wait timeout",
+			cfg:  Admission{LimitMiB: 1, WaitTimeout: -time.Second},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := tt.cfg.ToServerOptions()
+			assert.Error(t, err)
+			assert.Nil(t, opts)
+
+			c, err := tt.cfg.NewController()
+			assert.Error(t, err)
+			assert.Nil(t, c)
+
+			handler, err := tt.cfg.ToHTTPHandler(http.NotFoundHandler())
+			assert.Error(t, err)
+			assert.Nil(t, handler)
+		})
+	}
+}
+
+func TestLimitsInMiB(t *testing.T) {
+	cfg := Admission{LimitMiB: 2, WaitingLimitMiB: 3, WaitTimeout: time.Second}
+
+	c, err := cfg.newController()
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 2*1024*1024, c.limit)
+	assert.EqualValues(t, 3*1024*1024, c.waitingLimit)
+	assert.Equal(t, time.Second, c.waitTimeout)
+}
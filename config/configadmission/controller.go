@@ -0,0 +1,145 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configadmission
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// errTooLarge is returned for the requests larger than the whole limit, which can never be admitted.
+	errTooLarge = errors.New("request is larger than the admission limit")
+
+	// errLimitReached is returned when a request can't be admitted nor wait for the admission.
+	errLimitReached = errors.New("too many bytes in flight, try again later")
+
+	// errWaitTimeout is returned when a request waited for the admission longer than the wait timeout.
+	errWaitTimeout = errors.New("timed out waiting for the admission, try again later")
+)
+
+// controller is a semaphore weighted by bytes. Requests are admitted while the bytes in flight
+// stay within the limit, otherwise they wait in FIFO order, as long as the bytes waiting stay
+// within the waiting limit.
+type controller struct {
+	limit        int64
+	waitingLimit int64
+	waitTimeout  time.Duration
+
+	lock     sync.Mutex
+	inFlight int64
+	waiting  int64
+	waiters  list.List
+}
+
+type waiter struct {
+	n        int64
+	admitted chan struct{}
+}
+
+func newController(limit, waitingLimit int64, waitTimeout time.Duration) *controller {
+	return &controller{
+		limit:        limit,
+		waitingLimit: waitingLimit,
+		waitTimeout:  waitTimeout,
+	}
+}
+
+// acquire admits n bytes, waiting if needed. Each successful call must be followed by a call to
+// release with the same number of bytes.
+func (c *controller) acquire(ctx context.Context, n int64) error {
+	if n > c.limit {
+		return errTooLarge
+	}
+
+	c.lock.Lock()
+	if c.waiters.Len() == 0 && c.inFlight+n <= c.limit {
+		c.inFlight += n
+		c.lock.Unlock()
+		return nil
+	}
+	if c.waiting+n > c.waitingLimit {
+		c.lock.Unlock()
+		return errLimitReached
+	}
+	w := &waiter{n: n, admitted: make(chan struct{})}
+	elem := c.waiters.PushBack(w)
+	c.waiting += n
+	c.lock.Unlock()
+
+	var timeout <-chan time.Time
+	if c.waitTimeout > 0 {
+		timer := time.NewTimer(c.waitTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	var err error
+	select {
+	case <-w.admitted:
+		return nil
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-timeout:
+		err = errWaitTimeout
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	select {
+	case <-w.admitted:
+		// admitted concurrently with the cancellation, give the bytes back
+		c.inFlight -= n
+	default:
+		c.waiters.Remove(elem)
+		c.waiting -= n
+	}
+	// the waiters behind this one may fit now
+	c.admitWaiters()
+	return err
+}
+
+// full reports whether a new request, whatever its size, would neither be admitted nor wait.
+func (c *controller) full() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return (c.waiters.Len() > 0 || c.inFlight >= c.limit) && c.waiting >= c.waitingLimit
+}
+
+// release gives back n bytes previously admitted by acquire.
+func (c *controller) release(n int64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.inFlight -= n
+	c.admitWaiters()
+}
+
+// admitWaiters admits the waiters in order, stopping at the first one that doesn't fit, so that
+// the large requests aren't starved by the small ones. It must be called with the lock held.
+func (c *controller) admitWaiters() {
+	for elem := c.waiters.Front(); elem != nil; elem = c.waiters.Front() {
+		w := elem.Value.(*waiter)
+		if c.inFlight+w.n > c.limit {
+			return
+		}
+		c.waiters.Remove(elem)
+		c.waiting -= w.n
+		c.inFlight += w.n
+		close(w.admitted)
+	}
+}
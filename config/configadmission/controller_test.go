@@ -0,0 +1,175 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configadmission
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquireWithinLimit(t *testing.T) {
+	c := newController(10, 0, 0)
+
+	require.NoError(t, c.acquire(context.Background(), 4))
+	require.NoError(t, c.acquire(context.Background(), 6))
+	assert.EqualValues(t, 10, c.inFlight)
+
+	c.release(4)
+	c.release(6)
+	assert.EqualValues(t, 0, c.inFlight)
+}
+
+func TestAcquireTooLarge(t *testing.T) {
+	c := newController(10, 100, 0)
+
+	assert.Equal(t, errTooLarge, c.acquire(context.Background(), 11))
+	assert.EqualValues(t, 0, c.inFlight)
+	assert.EqualValues(t, 0, c.waiting)
+}
+
+func TestAcquireRejectedWithoutWaitingLimit(t *testing.T) {
+	c := newController(10, 0, 0)
+	require.NoError(t, c.acquire(context.Background(), 8))
+
+	assert.Equal(t, errLimitReached, c.acquire(context.Background(), 3))
+	assert.EqualValues(t, 8, c.inFlight)
+}
+
+func TestAcquireWaits(t *testing.T) {
+	c := newController(10, 5, 0)
+	require.NoError(t, c.acquire(context.Background(), 8))
+
+	admitted := make(chan error)
+	go func() {
+		admitted <- c.acquire(context.Background(), 5)
+	}()
+	waitFor(t, c, 5)
+
+	// the waiting limit is reached
+	assert.Equal(t, errLimitReached, c.acquire(context.Background(), 1))
+
+	c.release(8)
+	assert.NoError(t, <-admitted)
+	assert.EqualValues(t, 5, c.inFlight)
+	assert.EqualValues(t, 0, c.waiting)
+}
+
+func TestAcquireAdmitsInOrder(t *testing.T) {
+	c := newController(10, 20, 0)
+	require.NoError(t, c.acquire(context.Background(), 10))
+
+	large := make(chan error)
+	go func() {
+		large <- c.acquire(context.Background(), 8)
+	}()
+	waitFor(t, c, 8)
+
+	// small requests don't overtake the waiting ones, even if they fit
+	small := make(chan error)
+	go func() {
+		small <- c.acquire(context.Background(), 2)
+	}()
+	waitFor(t, c, 10)
+
+	c.release(5)
+	select {
+	case <-large:
+		t.Fatal("admitted before enough bytes were released")
+	case <-small:
+		t.Fatal("admitted before a waiting request")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	c.release(5)
+	assert.NoError(t, <-large)
+	assert.NoError(t, <-small)
+	assert.EqualValues(t, 10, c.inFlight)
+	assert.EqualValues(t, 0, c.waiting)
+}
+
+func TestAcquireWaitTimeout(t *testing.T) {
+	c := newController(10, 10, 10*time.Millisecond)
+	require.NoError(t, c.acquire(context.Background(), 10))
+
+	assert.Equal(t, errWaitTimeout, c.acquire(context.Background(), 1))
+	assert.EqualValues(t, 10, c.inFlight)
+	assert.EqualValues(t, 0, c.waiting)
+}
+
+func TestAcquireCancelled(t *testing.T) {
+	c := newController(10, 10, 0)
+	require.NoError(t, c.acquire(context.Background(), 6))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := make(chan error)
+	go func() {
+		cancelled <- c.acquire(ctx, 8)
+	}()
+	waitFor(t, c, 8)
+
+	small := make(chan error)
+	go func() {
+		small <- c.acquire(context.Background(), 2)
+	}()
+	waitFor(t, c, 10)
+
+	// the request behind the cancelled one is admitted right away
+	cancel()
+	assert.Equal(t, context.Canceled, <-cancelled)
+	assert.NoError(t, <-small)
+	assert.EqualValues(t, 8, c.inFlight)
+	assert.EqualValues(t, 0, c.waiting)
+}
+
+func TestFull(t *testing.T) {
+	c := newController(10, 5, 0)
+	assert.False(t, c.full())
+
+	require.NoError(t, c.acquire(context.Background(), 10))
+	assert.False(t, c.full()) // a request may still wait
+
+	waiting := make(chan error)
+	go func() {
+		waiting <- c.acquire(context.Background(), 5)
+	}()
+	waitFor(t, c, 5)
+	assert.True(t, c.full())
+
+	c.release(10)
+	assert.NoError(t, <-waiting)
+	assert.False(t, c.full())
+}
+
+func TestFullWithoutWaitingLimit(t *testing.T) {
+	c := newController(10, 0, 0)
+	require.NoError(t, c.acquire(context.Background(), 9))
+	assert.False(t, c.full())
+
+	require.NoError(t, c.acquire(context.Background(), 1))
+	assert.True(t, c.full())
+}
+
+// waitFor waits until the given number of bytes are waiting for the admission.
+func waitFor(t *testing.T, c *controller, waiting int64) {
+	require.Eventually(t, func() bool {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		return c.waiting == waiting
+	}, time.Second, time.Millisecond)
+}
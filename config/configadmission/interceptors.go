@@ -0,0 +1,207 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configadmission
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/tap"
+)
+
+const (
+	// retryAfter is the delay suggested to the clients of the rejected requests.
+	retryAfter = time.Second
+
+	// bodyChunkSize is the size of the chunks in which the bodies of unknown length are admitted.
+	bodyChunkSize = 64 * 1024
+)
+
+// tapHandle refuses the new RPCs while the limits are reached, before their messages are read and
+// decoded. It runs in the I/O goroutine of the connection, so it must not wait: the RPCs it lets
+// through are admitted by the interceptors once their messages are decoded, their size being
+// unknown until then. The memory used by the messages being decoded is bounded by the maximum
+// size of the received messages of the server. gRPC can't send a status from a tap handle: the
+// refused RPCs are reset with REFUSED_STREAM, which the clients see as a retryable UNAVAILABLE
+// without a RetryInfo.
+func (c *controller) tapHandle(ctx context.Context, _ *tap.Info) (context.Context, error) {
+	if c.full() {
+		return nil, errLimitReached
+	}
+	return ctx, nil
+}
+
+func (c *controller) unaryInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	n := messageSize(req)
+	if err := c.acquire(ctx, n); err != nil {
+		return nil, grpcError(err)
+	}
+	defer c.release(n)
+	return handler(ctx, req)
+}
+
+func (c *controller) streamInterceptor(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	admitted := &admittedServerStream{ServerStream: stream, controller: c}
+	defer admitted.release()
+	return handler(srv, admitted)
+}
+
+// admittedServerStream admits each message received on a grpc.ServerStream. A message is considered
+// processed once the next one is requested, or the stream ends.
+type admittedServerStream struct {
+	grpc.ServerStream
+	controller *controller
+	admitted   int64
+}
+
+func (s *admittedServerStream) RecvMsg(m interface{}) error {
+	s.release()
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	n := messageSize(m)
+	if err := s.controller.acquire(s.Context(), n); err != nil {
+		return grpcError(err)
+	}
+	s.admitted = n
+	return nil
+}
+
+func (s *admittedServerStream) release() {
+	if s.admitted > 0 {
+		s.controller.release(s.admitted)
+		s.admitted = 0
+	}
+}
+
+// messageSize returns the serialized size of a gRPC message.
+func messageSize(m interface{}) int64 {
+	switch msg := m.(type) {
+	case interface{ Size() int }:
+		// gogoproto generated messages
+		return int64(msg.Size())
+	case proto.Message:
+		return int64(proto.Size(msg))
+	}
+	return 0
+}
+
+// grpcError converts an admission error to a gRPC status. The rejections which may succeed later
+// carry the delay after which the clients should retry.
+func grpcError(err error) error {
+	switch err {
+	case errTooLarge:
+		// not retryable, see https://github.com/open-telemetry/opentelemetry-specification/blob/master/specification/protocol/otlp.md#failures
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errLimitReached, errWaitTimeout:
+		st, detailsErr := status.New(codes.ResourceExhausted, err.Error()).WithDetails(&errdetails.RetryInfo{
+			RetryDelay: ptypes.DurationProto(retryAfter),
+		})
+		if detailsErr != nil {
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+		return st.Err()
+	}
+	return status.FromContextError(err).Err()
+}
+
+func (c *controller) httpHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := r.ContentLength
+		if n < 0 {
+			// chunked or decompressed bodies are admitted while they are read
+			body, err := c.readBody(r.Context(), r.Body)
+			if err != nil {
+				writeHTTPError(w, err)
+				return
+			}
+			n = int64(len(body))
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		} else if err := c.acquire(r.Context(), n); err != nil {
+			writeHTTPError(w, err)
+			return
+		}
+		defer c.release(n)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// bodyReadError wraps the errors reading the body of a request.
+type bodyReadError struct {
+	err error
+}
+
+func (e *bodyReadError) Error() string {
+	return "failed to read the request body: " + e.err.Error()
+}
+
+// readBody reads a body of unknown length, admitting it chunk by chunk. On success, the whole
+// body has been admitted.
+func (c *controller) readBody(ctx context.Context, r io.Reader) ([]byte, error) {
+	var body []byte
+	chunk := make([]byte, bodyChunkSize)
+	for {
+		n, err := io.ReadFull(r, chunk)
+		if n > 0 {
+			if int64(len(body)+n) > c.limit {
+				c.release(int64(len(body)))
+				return nil, errTooLarge
+			}
+			if err := c.acquire(ctx, int64(n)); err != nil {
+				c.release(int64(len(body)))
+				return nil, err
+			}
+			body = append(body, chunk[:n]...)
+		}
+		switch err {
+		case nil:
+		case io.EOF, io.ErrUnexpectedEOF:
+			return body, nil
+		default:
+			c.release(int64(len(body)))
+			return nil, &bodyReadError{err: err}
+		}
+	}
+}
+
+// writeHTTPError writes the response of a rejected request. The rejections which may succeed later
+// carry the delay after which the clients should retry.
+func writeHTTPError(w http.ResponseWriter, err error) {
+	if _, ok := err.(*bodyReadError); ok {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	code := http.StatusServiceUnavailable
+	switch err {
+	case errTooLarge:
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	case errLimitReached:
+		code = http.StatusTooManyRequests
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter/time.Second)))
+	http.Error(w, err.Error(), code)
+}
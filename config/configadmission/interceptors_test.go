@@ -0,0 +1,323 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configadmission
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/tap"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// sizedMessage is a gRPC message reporting its size like the gogoproto generated ones.
+type sizedMessage int
+
+func (m sizedMessage) Size() int {
+	return int(m)
+}
+
+func TestMessageSize(t *testing.T) {
+	assert.EqualValues(t, 42, messageSize(sizedMessage(42)))
+	assert.EqualValues(t, 7, messageSize(wrapperspb.String("hello"))) // tag, length and value
+	assert.EqualValues(t, 0, messageSize("not a message"))
+}
+
+func TestTapHandle(t *testing.T) {
+	c := newController(10, 0, 0)
+	ctx := context.Background()
+
+	tapCtx, err := c.tapHandle(ctx, &tap.Info{})
+	assert.NoError(t, err)
+	assert.Equal(t, ctx, tapCtx)
+
+	// the new RPCs are refused before their messages are read once the limit is reached
+	require.NoError(t, c.acquire(ctx, 10))
+	_, err = c.tapHandle(ctx, &tap.Info{})
+	assert.Equal(t, errLimitReached, err)
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	c := newController(10, 0, 0)
+
+	var inFlight int64
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		inFlight = c.inFlight
+		return nil, nil
+	}
+
+	// test
+	_, err := c.unaryInterceptor(context.Background(), sizedMessage(6), &grpc.UnaryServerInfo{}, handler)
+
+	// verify
+	assert.NoError(t, err)
+	assert.EqualValues(t, 6, inFlight)
+	assert.EqualValues(t, 0, c.inFlight)
+}
+
+func TestUnaryInterceptorRejects(t *testing.T) {
+	c := newController(10, 0, 0)
+	require.NoError(t, c.acquire(context.Background(), 5))
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		t.Fatal("the handler should not have been called")
+		return nil, nil
+	}
+
+	// test
+	_, err := c.unaryInterceptor(context.Background(), sizedMessage(6), &grpc.UnaryServerInfo{}, handler)
+
+	// verify
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, time.Second, retryDelay(t, err))
+	assert.EqualValues(t, 5, c.inFlight)
+}
+
+// fakeServerStream receives the given messages, then io.EOF.
+type fakeServerStream struct {
+	grpc.ServerStream
+	messages []sizedMessage
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *fakeServerStream) RecvMsg(m interface{}) error {
+	if len(s.messages) == 0 {
+		return io.EOF
+	}
+	*m.(*sizedMessage) = s.messages[0]
+	s.messages = s.messages[1:]
+	return nil
+}
+
+func TestStreamInterceptor(t *testing.T) {
+	c := newController(10, 0, 0)
+
+	var inFlight []int64
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		for {
+			var m sizedMessage
+			if err := stream.RecvMsg(&m); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+			inFlight = append(inFlight, c.inFlight)
+		}
+	}
+
+	// test
+	err := c.streamInterceptor(nil, &fakeServerStream{messages: []sizedMessage{4, 10, 2}}, &grpc.StreamServerInfo{}, handler)
+
+	// verify
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{4, 10, 2}, inFlight) // each message is released when the next one is received
+	assert.EqualValues(t, 0, c.inFlight)
+}
+
+func TestStreamInterceptorRejects(t *testing.T) {
+	c := newController(10, 0, 0)
+
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		var m sizedMessage
+		for {
+			if err := stream.RecvMsg(&m); err != nil {
+				return err
+			}
+		}
+	}
+
+	// test
+	err := c.streamInterceptor(nil, &fakeServerStream{messages: []sizedMessage{4, 11}}, &grpc.StreamServerInfo{}, handler)
+
+	// verify
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.EqualValues(t, 0, c.inFlight)
+}
+
+func TestGRPCError(t *testing.T) {
+	err := grpcError(errTooLarge)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Empty(t, status.Convert(err).Details(), "too large requests must not be retried")
+
+	err = grpcError(errWaitTimeout)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, time.Second, retryDelay(t, err))
+
+	assert.Equal(t, codes.Canceled, status.Code(grpcError(context.Canceled)))
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(grpcError(context.DeadlineExceeded)))
+}
+
+func retryDelay(t *testing.T, err error) time.Duration {
+	details := status.Convert(err).Details()
+	require.Len(t, details, 1)
+	retryInfo, ok := details[0].(*errdetails.RetryInfo)
+	require.True(t, ok)
+	return retryInfo.RetryDelay.AsDuration()
+}
+
+func TestHTTPHandler(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		inFlight      int64
+		waitTimeout   time.Duration
+		body          io.Reader
+		contentLength int64
+		expectedCode  int
+		retryAfter    string
+	}{
+		{
+			name:          "admitted",
+			body:          strings.NewReader("0123456789"),
+			contentLength: 10,
+			expectedCode:  http.StatusOK,
+		},
+		{
+			name:          "admitted unknown length",
+			body:          strings.NewReader("0123456789"),
+			contentLength: -1,
+			expectedCode:  http.StatusOK,
+		},
+		{
+			name:          "too large",
+			body:          strings.NewReader("01234567890"),
+			contentLength: 11,
+			expectedCode:  http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:          "too large unknown length",
+			body:          strings.NewReader("01234567890"),
+			contentLength: -1,
+			expectedCode:  http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:          "limit reached",
+			inFlight:      5,
+			body:          strings.NewReader("012345"),
+			contentLength: 6,
+			expectedCode:  http.StatusTooManyRequests,
+			retryAfter:    "1",
+		},
+		{
+			name:          "limit reached unknown length",
+			inFlight:      5,
+			body:          strings.NewReader("012345"),
+			contentLength: -1,
+			expectedCode:  http.StatusTooManyRequests,
+			retryAfter:    "1",
+		},
+		{
+			name:          "wait timeout",
+			inFlight:      5,
+			waitTimeout:   time.Millisecond,
+			body:          strings.NewReader("012345"),
+			contentLength: 6,
+			expectedCode:  http.StatusServiceUnavailable,
+			retryAfter:    "1",
+		},
+		{
+			name:          "body read error",
+			body:          iotest.TimeoutReader(strings.NewReader("0123")),
+			contentLength: -1,
+			expectedCode:  http.StatusBadRequest,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newController(10, 10, tt.waitTimeout)
+			if tt.waitTimeout == 0 {
+				c.waitingLimit = 0
+			}
+			require.NoError(t, c.acquire(context.Background(), tt.inFlight))
+
+			var body string
+			var inFlight int64
+			handler := c.httpHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, err := ioutil.ReadAll(r.Body)
+				require.NoError(t, err)
+				body = string(b)
+				inFlight = c.inFlight
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/", tt.body)
+			req.ContentLength = tt.contentLength
+
+			// test
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			// verify
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			assert.Equal(t, tt.retryAfter, rec.Header().Get("Retry-After"))
+			if tt.expectedCode == http.StatusOK {
+				assert.Equal(t, "0123456789", body)
+				assert.EqualValues(t, 10, inFlight)
+			}
+			assert.EqualValues(t, tt.inFlight, c.inFlight)
+			assert.EqualValues(t, 0, c.waiting)
+		})
+	}
+}
+
+func TestReadBodyInChunks(t *testing.T) {
+	c := newController(3*bodyChunkSize, 0, 0)
+
+	// test
+	body, err := c.readBody(context.Background(), strings.NewReader(strings.Repeat("0", 2*bodyChunkSize+1)))
+
+	// verify
+	require.NoError(t, err)
+	assert.Len(t, body, 2*bodyChunkSize+1)
+	assert.EqualValues(t, 2*bodyChunkSize+1, c.inFlight)
+}
+
+func TestReadBodyReleasesOnError(t *testing.T) {
+	c := newController(3*bodyChunkSize, 0, 0)
+	readErr := errors.New("connection reset")
+
+	// test
+	_, err := c.readBody(context.Background(), io.MultiReader(
+		strings.NewReader(strings.Repeat("0", bodyChunkSize)),
+		&errReader{err: readErr},
+	))
+
+	// verify
+	assert.Equal(t, &bodyReadError{err: readErr}, err)
+	assert.EqualValues(t, 0, c.inFlight)
+}
+
+type errReader struct {
+	err error
+}
+
+func (r *errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
@@ -0,0 +1,125 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+)
+
+type apiKey struct {
+	subject string
+	groups  []string
+}
+
+type apiKeysAuthenticator struct {
+	attribute string
+	config    APIKeys
+
+	// the keys are indexed by their SHA-256 hash, so that the lookups don't depend on the key contents
+	keys map[[sha256.Size]byte]apiKey
+}
+
+var (
+	_ Authenticator = (*apiKeysAuthenticator)(nil)
+
+	errNoAPIKeysFileProvided = errors.New("no file provided for the API keys configuration")
+	errInvalidAPIKey         = errors.New("invalid API key")
+)
+
+func newAPIKeysAuthenticator(cfg Authentication) (*apiKeysAuthenticator, error) {
+	if cfg.APIKeys.File == "" {
+		return nil, errNoAPIKeysFileProvided
+	}
+	if cfg.Attribute == "" {
+		cfg.Attribute = defaultAttribute
+	}
+
+	return &apiKeysAuthenticator{
+		attribute: cfg.Attribute,
+		config:    *cfg.APIKeys,
+	}, nil
+}
+
+func (a *apiKeysAuthenticator) Authenticate(ctx context.Context, headers map[string][]string) (context.Context, error) {
+	key, err := credentialsFromHeaders(headers, a.attribute, "Bearer")
+	if err != nil {
+		return ctx, err
+	}
+
+	k, ok := a.keys[sha256.Sum256([]byte(key))]
+	if !ok {
+		return ctx, errInvalidAPIKey
+	}
+
+	ctx = context.WithValue(ctx, subjectKey, k.subject)
+	ctx = context.WithValue(ctx, groupsKey, k.groups)
+	return ctx, nil
+}
+
+func (a *apiKeysAuthenticator) Start(context.Context) error {
+	keys, err := loadAPIKeys(a.config.File)
+	if err != nil {
+		return fmt.Errorf("failed to load the API keys: %w", err)
+	}
+	a.keys = keys
+	return nil
+}
+
+func (a *apiKeysAuthenticator) Close() error {
+	return nil
+}
+
+func (a *apiKeysAuthenticator) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return defaultUnaryInterceptor(ctx, req, info, handler, a.Authenticate)
+}
+
+func (a *apiKeysAuthenticator) StreamInterceptor(srv interface{}, str grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return defaultStreamInterceptor(srv, str, info, handler, a.Authenticate)
+}
+
+func (a *apiKeysAuthenticator) HTTPInterceptor(next http.Handler) http.Handler {
+	return defaultHTTPInterceptor(next, a.Authenticate)
+}
+
+// loadAPIKeys reads the "key subject [group1,group2]" lines of an API keys file
+func loadAPIKeys(path string) (map[[sha256.Size]byte]apiKey, error) {
+	keys := map[[sha256.Size]byte]apiKey{}
+	err := readLines(path, func(line int, text string) error {
+		fields := strings.Fields(text)
+		if len(fields) < 2 || len(fields) > 3 {
+			return fmt.Errorf("line %d: expected an API key, a subject and optional groups", line)
+		}
+
+		k := apiKey{subject: fields[1], groups: []string{}}
+		if len(fields) == 3 {
+			k.groups = strings.Split(fields[2], ",")
+		}
+
+		hash := sha256.Sum256([]byte(fields[0]))
+		if _, ok := keys[hash]; ok {
+			return fmt.Errorf("line %d: duplicate API key", line)
+		}
+		keys[hash] = k
+		return nil
+	})
+	return keys, err
+}
@@ -0,0 +1,172 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func newStartedAPIKeysAuthenticator(t *testing.T, file string) *apiKeysAuthenticator {
+	p, err := newAPIKeysAuthenticator(Authentication{
+		APIKeys: &APIKeys{File: file},
+	})
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background()))
+	return p
+}
+
+func TestAPIKeysAuthenticationSucceeded(t *testing.T) {
+	// prepare
+	p := newStartedAPIKeysAuthenticator(t, path.Join(".", "testdata", "api_keys"))
+
+	for _, tt := range []struct {
+		key     string
+		subject string
+		groups  []string
+	}{
+		{"0d9cb8f4a2b34f4c", "agent-1", []string{"department-1", "department-2"}},
+		{"7f3e9a1c5b2d4e6f", "agent-2", []string{}},
+	} {
+		t.Run(tt.subject, func(t *testing.T) {
+			// test
+			ctx, err := p.Authenticate(context.Background(), map[string][]string{"authorization": {"Bearer " + tt.key}})
+
+			// verify
+			require.NoError(t, err)
+			subject, ok := SubjectFromContext(ctx)
+			assert.True(t, ok)
+			assert.Equal(t, tt.subject, subject)
+			groups, ok := GroupsFromContext(ctx)
+			assert.True(t, ok)
+			assert.Equal(t, tt.groups, groups)
+		})
+	}
+}
+
+func TestAPIKeysAuthenticationFailed(t *testing.T) {
+	// prepare
+	p := newStartedAPIKeysAuthenticator(t, path.Join(".", "testdata", "api_keys"))
+
+	for _, tt := range []struct {
+		name    string
+		headers map[string][]string
+		err     error
+	}{
+		{"missing header", map[string][]string{}, errNotAuthenticated},
+		{"unknown key", map[string][]string{"authorization": {"Bearer unknown"}}, errInvalidAPIKey},
+		{"wrong scheme", map[string][]string{"authorization": {"Basic 0d9cb8f4a2b34f4c"}}, errInvalidAuthenticationHeaderFormat},
+		{"no scheme", map[string][]string{"authorization": {"0d9cb8f4a2b34f4c"}}, errInvalidAuthenticationHeaderFormat},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// test
+			_, err := p.Authenticate(context.Background(), tt.headers)
+
+			// verify
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestAPIKeysCustomAttribute(t *testing.T) {
+	// prepare
+	p, err := newAPIKeysAuthenticator(Authentication{
+		Attribute: "x-api-key",
+		APIKeys:   &APIKeys{File: path.Join(".", "testdata", "api_keys")},
+	})
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background()))
+
+	// test
+	ctx, err := p.Authenticate(context.Background(), map[string][]string{"x-api-key": {"bearer 7f3e9a1c5b2d4e6f"}})
+
+	// verify
+	assert.NoError(t, err)
+	subject, _ := SubjectFromContext(ctx)
+	assert.Equal(t, "agent-2", subject)
+}
+
+func TestAPIKeysMissingFile(t *testing.T) {
+	// test
+	p, err := newAPIKeysAuthenticator(Authentication{APIKeys: &APIKeys{}})
+
+	// verify
+	assert.Nil(t, p)
+	assert.Equal(t, errNoAPIKeysFileProvided, err)
+}
+
+func TestAPIKeysFailedToLoad(t *testing.T) {
+	for _, file := range []string{
+		path.Join(".", "testdata", "api_keys_duplicate"),
+		path.Join(".", "testdata", "htpasswd"),
+		path.Join(".", "testdata", "non-existing"),
+	} {
+		t.Run(file, func(t *testing.T) {
+			// prepare
+			p, err := newAPIKeysAuthenticator(Authentication{APIKeys: &APIKeys{File: file}})
+			require.NoError(t, err)
+
+			// test
+			err = p.Start(context.Background())
+
+			// verify
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestAPIKeysInterceptors(t *testing.T) {
+	// prepare
+	p := newStartedAPIKeysAuthenticator(t, path.Join(".", "testdata", "api_keys"))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer 0d9cb8f4a2b34f4c"))
+
+	var unarySubject string
+	unaryHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		unarySubject, _ = SubjectFromContext(ctx)
+		return nil, nil
+	}
+	streamCalled := false
+	streamHandler := func(srv interface{}, stream grpc.ServerStream) error {
+		streamCalled = true
+		return nil
+	}
+	var httpSubject string
+	httpHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httpSubject, _ = SubjectFromContext(r.Context())
+	})
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Authorization", "Bearer 0d9cb8f4a2b34f4c")
+
+	// test
+	_, unaryErr := p.UnaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{}, unaryHandler)
+	streamErr := p.StreamInterceptor(nil, &mockServerStream{ctx: ctx}, &grpc.StreamServerInfo{}, streamHandler)
+	p.HTTPInterceptor(httpHandler).ServeHTTP(httptest.NewRecorder(), req)
+
+	// verify
+	assert.NoError(t, unaryErr)
+	assert.Equal(t, "agent-1", unarySubject)
+	assert.NoError(t, streamErr)
+	assert.True(t, streamCalled)
+	assert.Equal(t, "agent-1", httpSubject)
+	assert.NoError(t, p.Close())
+}
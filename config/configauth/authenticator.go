@@ -15,18 +15,26 @@
 package configauth
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 )
 
 var (
-	errNoOIDCProvided   = errors.New("no OIDC information provided")
-	errMetadataNotFound = errors.New("no request metadata found")
-	defaultAttribute    = "authorization"
+	errNoAuthenticatorProvided        = errors.New("no authentication mechanism provided, one of oidc, api_keys, htpasswd or mtls is required")
+	errMultipleAuthenticatorsProvided = errors.New("only one of oidc, api_keys, htpasswd or mtls can be provided")
+	errMetadataNotFound               = errors.New("no request metadata found")
+	defaultAttribute                  = "authorization"
 )
 
 // Authenticator will authenticate the incoming request/RPC
@@ -44,23 +52,45 @@ type Authenticator interface {
 
 	// StreamInterceptor is a helper method to provide a gRPC-compatible StreamInterceptor, typically calling the authenticator's Authenticate method.
 	StreamInterceptor(interface{}, grpc.ServerStream, *grpc.StreamServerInfo, grpc.StreamHandler) error
+
+	// HTTPInterceptor is a helper method to provide an HTTP middleware, typically calling the authenticator's Authenticate method.
+	HTTPInterceptor(next http.Handler) http.Handler
 }
 
 type authenticateFunc func(context.Context, map[string][]string) (context.Context, error)
 type unaryInterceptorFunc func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler, authenticate authenticateFunc) (interface{}, error)
 type streamInterceptorFunc func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler, authenticate authenticateFunc) error
+type httpInterceptorFunc func(next http.Handler, authenticate authenticateFunc) http.Handler
 
 // NewAuthenticator creates an authenticator based on the given configuration
 func NewAuthenticator(cfg Authentication) (Authenticator, error) {
-	if cfg.OIDC == nil {
-		return nil, errNoOIDCProvided
+	provided := 0
+	for _, p := range []bool{cfg.OIDC != nil, cfg.APIKeys != nil, cfg.Htpasswd != nil, cfg.MTLS != nil} {
+		if p {
+			provided++
+		}
+	}
+	if provided == 0 {
+		return nil, errNoAuthenticatorProvided
+	}
+	if provided > 1 {
+		return nil, errMultipleAuthenticatorsProvided
 	}
 
 	if len(cfg.Attribute) == 0 {
 		cfg.Attribute = defaultAttribute
 	}
 
-	return newOIDCAuthenticator(cfg)
+	switch {
+	case cfg.APIKeys != nil:
+		return newAPIKeysAuthenticator(cfg)
+	case cfg.Htpasswd != nil:
+		return newHtpasswdAuthenticator(cfg)
+	case cfg.MTLS != nil:
+		return newMTLSAuthenticator(cfg)
+	default:
+		return newOIDCAuthenticator(cfg)
+	}
 }
 
 func defaultUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler, authenticate authenticateFunc) (interface{}, error) {
@@ -84,11 +114,87 @@ func defaultStreamInterceptor(srv interface{}, stream grpc.ServerStream, info *g
 		return errMetadataNotFound
 	}
 
-	// TODO: how to replace the context from the stream?
-	_, err := authenticate(ctx, headers)
+	ctx, err := authenticate(ctx, headers)
 	if err != nil {
 		return err
 	}
 
-	return handler(srv, stream)
+	return handler(srv, &authenticatedServerStream{ServerStream: stream, ctx: ctx})
+}
+
+// authenticatedServerStream replaces the context of a grpc.ServerStream with the authenticated one.
+type authenticatedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func defaultHTTPInterceptor(next http.Handler, authenticate authenticateFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the HTTP headers are canonicalized, while the gRPC metadata keys the authenticators
+		// look for are lower case
+		headers := make(map[string][]string, len(r.Header))
+		for k, v := range r.Header {
+			headers[strings.ToLower(k)] = v
+		}
+
+		ctx := r.Context()
+		if r.TLS != nil {
+			// expose the TLS connection state the same way gRPC does, for the mTLS authenticator
+			addr, _ := net.ResolveTCPAddr("tcp", r.RemoteAddr)
+			ctx = peer.NewContext(ctx, &peer.Peer{
+				Addr:     addr,
+				AuthInfo: credentials.TLSInfo{State: *r.TLS},
+			})
+		}
+
+		ctx, err := authenticate(ctx, headers)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// credentialsFromHeaders returns the credentials of the first value of the given attribute,
+// which is expected to be in the "<scheme> <credentials>" format
+func credentialsFromHeaders(headers map[string][]string, attribute, scheme string) (string, error) {
+	values := headers[attribute]
+	if len(values) == 0 {
+		return "", errNotAuthenticated
+	}
+
+	// we only use the first header, if multiple values exist
+	parts := strings.SplitN(values[0], " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], scheme) {
+		return "", errInvalidAuthenticationHeaderFormat
+	}
+
+	return parts[1], nil
+}
+
+// readLines calls the given function for each line of the file, skipping empty lines and comments
+func readLines(path string, fn func(line int, text string) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if err := fn(line, text); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
 }
@@ -17,6 +17,8 @@ package configauth
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -38,13 +40,46 @@ func TestNewAuthenticator(t *testing.T) {
 	assert.NoError(t, err)
 }
 
-func TestMissingOIDC(t *testing.T) {
+func TestNewAuthenticatorForEachMechanism(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		cfg      Authentication
+		expected Authenticator
+	}{
+		{"api_keys", Authentication{APIKeys: &APIKeys{File: "api_keys"}}, &apiKeysAuthenticator{}},
+		{"htpasswd", Authentication{Htpasswd: &Htpasswd{File: "htpasswd"}}, &htpasswdAuthenticator{}},
+		{"mtls", Authentication{MTLS: &MTLS{}}, &mtlsAuthenticator{}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// test
+			p, err := NewAuthenticator(tt.cfg)
+
+			// verify
+			assert.NoError(t, err)
+			assert.IsType(t, tt.expected, p)
+		})
+	}
+}
+
+func TestMissingAuthenticator(t *testing.T) {
 	// test
 	p, err := NewAuthenticator(Authentication{})
 
 	// verify
 	assert.Nil(t, p)
-	assert.Equal(t, errNoOIDCProvided, err)
+	assert.Equal(t, errNoAuthenticatorProvided, err)
+}
+
+func TestMultipleAuthenticators(t *testing.T) {
+	// test
+	p, err := NewAuthenticator(Authentication{
+		APIKeys: &APIKeys{File: "api_keys"},
+		MTLS:    &MTLS{},
+	})
+
+	// verify
+	assert.Nil(t, p)
+	assert.Equal(t, errMultipleAuthenticatorsProvided, err)
 }
 
 func TestDefaultUnaryInterceptorAuthSucceeded(t *testing.T) {
@@ -139,6 +174,26 @@ func TestDefaultStreamInterceptorAuthSucceeded(t *testing.T) {
 	assert.True(t, handlerCalled)
 }
 
+func TestDefaultStreamInterceptorAuthenticatedContext(t *testing.T) {
+	// prepare
+	authFunc := func(ctx context.Context, _ map[string][]string) (context.Context, error) {
+		return context.WithValue(ctx, subjectKey, "jdoe@example.com"), nil
+	}
+	var subject string
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		subject, _ = SubjectFromContext(stream.Context())
+		return nil
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "some-auth-data"))
+
+	// test
+	err := defaultStreamInterceptor(nil, &mockServerStream{ctx: ctx}, &grpc.StreamServerInfo{}, handler, authFunc)
+
+	// verify
+	assert.NoError(t, err)
+	assert.Equal(t, "jdoe@example.com", subject)
+}
+
 func TestDefaultStreamInterceptorAuthFailure(t *testing.T) {
 	// prepare
 	authCalled := false
@@ -185,6 +240,54 @@ func TestDefaultStreamInterceptorMissingMetadata(t *testing.T) {
 	assert.Equal(t, errMetadataNotFound, err)
 }
 
+func TestDefaultHTTPInterceptorAuthSucceeded(t *testing.T) {
+	// prepare
+	var authHeaders map[string][]string
+	authFunc := func(ctx context.Context, headers map[string][]string) (context.Context, error) {
+		authHeaders = headers
+		return context.WithValue(ctx, subjectKey, "jdoe@example.com"), nil
+	}
+	var subject string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		subject, _ = SubjectFromContext(r.Context())
+	})
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Authorization", "some-auth-data")
+	rec := httptest.NewRecorder()
+
+	// test
+	defaultHTTPInterceptor(handler, authFunc).ServeHTTP(rec, req)
+
+	// verify
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []string{"some-auth-data"}, authHeaders["authorization"])
+	assert.Equal(t, "jdoe@example.com", subject)
+}
+
+func TestDefaultHTTPInterceptorAuthFailure(t *testing.T) {
+	// prepare
+	authCalled := false
+	authFunc := func(context.Context, map[string][]string) (context.Context, error) {
+		authCalled = true
+		return context.Background(), fmt.Errorf("not authenticated")
+	}
+	handlerCalled := false
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerCalled = true
+	})
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	rec := httptest.NewRecorder()
+
+	// test
+	defaultHTTPInterceptor(handler, authFunc).ServeHTTP(rec, req)
+
+	// verify
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), "not authenticated")
+	assert.True(t, authCalled)
+	assert.False(t, handlerCalled)
+}
+
 type mockServerStream struct {
 	grpc.ServerStream
 	ctx context.Context
@@ -0,0 +1,42 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configauth
+
+import (
+	"errors"
+	"net/http"
+
+	"google.golang.org/grpc/credentials"
+)
+
+var errNoClientAuthProvided = errors.New("no client authentication information provided")
+
+// ClientAuthenticator adds authentication data to the outgoing requests/RPCs of a client
+type ClientAuthenticator interface {
+	// PerRPCCredentials returns the gRPC credentials adding the auth data to each RPC.
+	PerRPCCredentials() (credentials.PerRPCCredentials, error)
+
+	// RoundTripper returns an HTTP RoundTripper adding the auth data to each request before sending it with base.
+	RoundTripper(base http.RoundTripper) (http.RoundTripper, error)
+}
+
+// NewClientAuthenticator creates a client authenticator based on the given configuration
+func NewClientAuthenticator(cfg ClientAuthentication) (ClientAuthenticator, error) {
+	if cfg.OAuth2 == nil {
+		return nil, errNoClientAuthProvided
+	}
+
+	return newOAuth2ClientAuthenticator(*cfg.OAuth2)
+}
@@ -0,0 +1,45 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configauth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClientAuthenticator(t *testing.T) {
+	// test
+	p, err := NewClientAuthenticator(ClientAuthentication{
+		OAuth2: &OAuth2ClientCredentials{
+			ClientID:     "some-client",
+			ClientSecret: "some-secret",
+			TokenURL:     "http://example.com/token",
+		},
+	})
+
+	// verify
+	assert.NotNil(t, p)
+	assert.NoError(t, err)
+}
+
+func TestMissingClientAuthentication(t *testing.T) {
+	// test
+	p, err := NewClientAuthenticator(ClientAuthentication{})
+
+	// verify
+	assert.Nil(t, p)
+	assert.Equal(t, errNoClientAuthProvided, err)
+}
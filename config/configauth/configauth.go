@@ -16,6 +16,8 @@ package configauth
 
 import (
 	"context"
+	"net/http"
+	"time"
 
 	"google.golang.org/grpc"
 )
@@ -26,8 +28,17 @@ type Authentication struct {
 	Attribute string `mapstructure:"attribute"`
 
 	// OIDC configures this receiver to use the given OIDC provider as the backend for the authentication mechanism.
-	// Required.
+	// Exactly one of OIDC, APIKeys, Htpasswd and MTLS is required.
 	OIDC *OIDC `mapstructure:"oidc"`
+
+	// APIKeys configures this receiver to accept the bearer tokens listed in a file.
+	APIKeys *APIKeys `mapstructure:"api_keys"`
+
+	// Htpasswd configures this receiver to use HTTP basic authentication, verified against an htpasswd file.
+	Htpasswd *Htpasswd `mapstructure:"htpasswd"`
+
+	// MTLS configures this receiver to authenticate the clients using their verified TLS certificates.
+	MTLS *MTLS `mapstructure:"mtls"`
 }
 
 // OIDC defines the OpenID Connect properties for this processor
@@ -54,21 +65,102 @@ type OIDC struct {
 	GroupsClaim string `mapstructure:"groups_claim"`
 }
 
-// ToServerOptions builds a set of server options ready to be used by the gRPC server
-func (a *Authentication) ToServerOptions() ([]grpc.ServerOption, error) {
+// APIKeys defines the static API keys accepted by this receiver
+type APIKeys struct {
+	// File is the path to the file listing the API keys, one per line, followed by the subject they
+	// belong to and, optionally, a comma-separated list of groups. Empty lines and lines starting
+	// with '#' are ignored.
+	// Required.
+	File string `mapstructure:"file"`
+}
+
+// Htpasswd defines the users allowed to authenticate with HTTP basic authentication
+type Htpasswd struct {
+	// File is the path to the htpasswd file. Only bcrypt and SHA1 hashed passwords are supported.
+	// Required.
+	File string `mapstructure:"file"`
+
+	// GroupsFile is the path to a file listing the groups of the users, one group per line in the
+	// "group: user1 user2" format.
+	// Optional.
+	GroupsFile string `mapstructure:"groups_file"`
+}
+
+// MTLS defines how the subject is extracted from the verified client certificates. The server
+// must be configured to require and verify the client certificates, see `client_ca_file`.
+type MTLS struct {
+	// SubjectField is the field of the certificate used as the subject, one of "common_name",
+	// "dns_san", "email_san" or "uri_san". The groups are the organizational units of the certificate.
+	// Optional, default value: "common_name".
+	SubjectField string `mapstructure:"subject_field"`
+}
+
+// ToHTTPHandler wraps the handler of an HTTP server with a middleware authenticating each request.
+// The returned authenticator is started, the caller must close it once the server is shut down.
+func (a *Authentication) ToHTTPHandler(handler http.Handler) (http.Handler, Authenticator, error) {
+	auth, err := a.startAuthenticator()
+	if err != nil {
+		return nil, nil, err
+	}
+	return auth.HTTPInterceptor(handler), auth, nil
+}
+
+// ClientAuthentication defines the auth settings for the clients, such as exporters
+type ClientAuthentication struct {
+	// OAuth2 configures this client to obtain tokens using the OAuth2 client credentials flow.
+	// Required.
+	OAuth2 *OAuth2ClientCredentials `mapstructure:"oauth2"`
+}
+
+// OAuth2ClientCredentials defines the OAuth2 client credentials flow properties for this client
+type OAuth2ClientCredentials struct {
+	// ClientID is the application's ID.
+	// Required.
+	ClientID string `mapstructure:"client_id"`
+
+	// ClientSecret is the application's secret.
+	// Required.
+	ClientSecret string `mapstructure:"client_secret"`
+
+	// TokenURL is the resource server's token endpoint URL.
+	// Required.
+	TokenURL string `mapstructure:"token_url"`
+
+	// Scopes specifies the optional requested permissions.
+	// Optional.
+	Scopes []string `mapstructure:"scopes"`
+
+	// EndpointParams specifies additional parameters for requests to the token endpoint, such as "audience".
+	// Optional.
+	EndpointParams map[string]string `mapstructure:"endpoint_params"`
+
+	// Timeout of the requests to the token endpoint, bounded by the deadline of the request/RPC needing the token.
+	// Optional, 10s by default.
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// ToServerOptions builds a set of server options ready to be used by the gRPC server. The returned
+// authenticator is started, the caller must close it once the server is stopped.
+func (a *Authentication) ToServerOptions() ([]grpc.ServerOption, Authenticator, error) {
+	auth, err := a.startAuthenticator()
+	if err != nil {
+		return nil, nil, err
+	}
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(auth.UnaryInterceptor),
+		grpc.StreamInterceptor(auth.StreamInterceptor),
+	}, auth, nil
+}
+
+func (a *Authentication) startAuthenticator() (Authenticator, error) {
 	auth, err := NewAuthenticator(*a)
 	if err != nil {
 		return nil, err
 	}
 
 	// perhaps we should use a timeout here?
-	// TODO: we need a hook to call auth.Close()
 	if err := auth.Start(context.Background()); err != nil {
 		return nil, err
 	}
-
-	return []grpc.ServerOption{
-		grpc.UnaryInterceptor(auth.UnaryInterceptor),
-		grpc.StreamInterceptor(auth.StreamInterceptor),
-	}, nil
+	return auth, nil
 }
@@ -15,7 +15,12 @@
 package configauth
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -37,12 +42,14 @@ func TestToServerOptions(t *testing.T) {
 	}
 
 	// test
-	opts, err := config.ToServerOptions()
+	opts, auth, err := config.ToServerOptions()
 
 	// verify
 	assert.NoError(t, err)
 	assert.NotNil(t, opts)
 	assert.Len(t, opts, 2) // we have two interceptors
+	require.NotNil(t, auth)
+	assert.NoError(t, auth.Close())
 }
 
 func TestInvalidConfigurationFailsOnToServerOptions(t *testing.T) {
@@ -64,11 +71,79 @@ func TestInvalidConfigurationFailsOnToServerOptions(t *testing.T) {
 		},
 	} {
 		// test
-		opts, err := tt.cfg.ToServerOptions()
+		opts, auth, err := tt.cfg.ToServerOptions()
 
 		// verify
 		assert.Error(t, err)
 		assert.Nil(t, opts)
+		assert.Nil(t, auth)
 	}
 
 }
+
+func TestToHTTPHandler(t *testing.T) {
+	// prepare
+	oidcServer, err := newOIDCServer()
+	require.NoError(t, err)
+	oidcServer.Start()
+	defer oidcServer.Close()
+
+	config := Authentication{
+		OIDC: &OIDC{
+			IssuerURL:   oidcServer.URL,
+			Audience:    "unit-test",
+			GroupsClaim: "memberships",
+		},
+	}
+
+	payload, _ := json.Marshal(map[string]interface{}{
+		"sub":         "jdoe@example.com",
+		"iss":         oidcServer.URL,
+		"aud":         "unit-test",
+		"exp":         time.Now().Add(time.Minute).Unix(),
+		"memberships": []string{"department-1"},
+	})
+	token, err := oidcServer.token(payload)
+	require.NoError(t, err)
+
+	var subject string
+	var groups []string
+	handler, auth, err := config.ToHTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		subject, _ = SubjectFromContext(r.Context())
+		groups, _ = GroupsFromContext(r.Context())
+	}))
+	require.NoError(t, err)
+	defer auth.Close()
+
+	// test
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	unauthenticated := httptest.NewRecorder()
+	handler.ServeHTTP(unauthenticated, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	// verify
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "jdoe@example.com", subject)
+	assert.Equal(t, []string{"department-1"}, groups)
+	assert.Equal(t, http.StatusUnauthorized, unauthenticated.Code)
+}
+
+func TestInvalidConfigurationFailsOnToHTTPHandler(t *testing.T) {
+	cfg := Authentication{
+		OIDC: &OIDC{
+			IssuerURL: "http://oidc.acme.invalid",
+			Audience:  "unit-test",
+		},
+	}
+
+	// test
+	handler, auth, err := cfg.ToHTTPHandler(http.NotFoundHandler())
+
+	// verify
+	assert.Error(t, err)
+	assert.Nil(t, handler)
+	assert.Nil(t, auth)
+}
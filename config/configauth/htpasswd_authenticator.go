@@ -0,0 +1,210 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configauth
+
+import (
+	"context"
+	"crypto/sha1" // #nosec, SHA1 passwords are supported for compatibility with htpasswd files
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc"
+)
+
+// maxVerifiedCredentials is the number of verified credentials kept in memory, so that the
+// expensive bcrypt comparison isn't performed for every request of the same client
+const maxVerifiedCredentials = 1024
+
+type htpasswdAuthenticator struct {
+	attribute string
+	config    Htpasswd
+
+	passwords map[string]string
+	groups    map[string][]string
+
+	mu       sync.Mutex
+	verified map[[sha256.Size]byte]string
+}
+
+var (
+	_ Authenticator = (*htpasswdAuthenticator)(nil)
+
+	errNoHtpasswdFileProvided = errors.New("no file provided for the htpasswd configuration")
+	errInvalidBasicAuth       = errors.New("invalid basic authentication credentials format")
+	errInvalidUserOrPassword  = errors.New("invalid username or password")
+)
+
+func newHtpasswdAuthenticator(cfg Authentication) (*htpasswdAuthenticator, error) {
+	if cfg.Htpasswd.File == "" {
+		return nil, errNoHtpasswdFileProvided
+	}
+	if cfg.Attribute == "" {
+		cfg.Attribute = defaultAttribute
+	}
+
+	return &htpasswdAuthenticator{
+		attribute: cfg.Attribute,
+		config:    *cfg.Htpasswd,
+		verified:  map[[sha256.Size]byte]string{},
+	}, nil
+}
+
+func (h *htpasswdAuthenticator) Authenticate(ctx context.Context, headers map[string][]string) (context.Context, error) {
+	encoded, err := credentialsFromHeaders(headers, h.attribute, "Basic")
+	if err != nil {
+		return ctx, err
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return ctx, errInvalidBasicAuth
+	}
+
+	user, err := h.verify(decoded)
+	if err != nil {
+		return ctx, err
+	}
+
+	groups := h.groups[user]
+	if groups == nil {
+		groups = []string{}
+	}
+
+	ctx = context.WithValue(ctx, subjectKey, user)
+	ctx = context.WithValue(ctx, groupsKey, groups)
+	return ctx, nil
+}
+
+func (h *htpasswdAuthenticator) verify(credentials []byte) (string, error) {
+	key := sha256.Sum256(credentials)
+
+	h.mu.Lock()
+	user, ok := h.verified[key]
+	h.mu.Unlock()
+	if ok {
+		return user, nil
+	}
+
+	parts := strings.SplitN(string(credentials), ":", 2)
+	if len(parts) != 2 {
+		return "", errInvalidBasicAuth
+	}
+	user = parts[0]
+
+	hash, ok := h.passwords[user]
+	if !ok || !checkPassword(hash, parts[1]) {
+		return "", errInvalidUserOrPassword
+	}
+
+	h.mu.Lock()
+	if len(h.verified) >= maxVerifiedCredentials {
+		h.verified = map[[sha256.Size]byte]string{}
+	}
+	h.verified[key] = user
+	h.mu.Unlock()
+
+	return user, nil
+}
+
+func (h *htpasswdAuthenticator) Start(context.Context) error {
+	passwords, err := loadHtpasswd(h.config.File)
+	if err != nil {
+		return fmt.Errorf("failed to load the htpasswd file: %w", err)
+	}
+	h.passwords = passwords
+
+	if h.config.GroupsFile != "" {
+		groups, err := loadHtgroups(h.config.GroupsFile)
+		if err != nil {
+			return fmt.Errorf("failed to load the groups file: %w", err)
+		}
+		h.groups = groups
+	}
+
+	return nil
+}
+
+func (h *htpasswdAuthenticator) Close() error {
+	return nil
+}
+
+func (h *htpasswdAuthenticator) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return defaultUnaryInterceptor(ctx, req, info, handler, h.Authenticate)
+}
+
+func (h *htpasswdAuthenticator) StreamInterceptor(srv interface{}, str grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return defaultStreamInterceptor(srv, str, info, handler, h.Authenticate)
+}
+
+func (h *htpasswdAuthenticator) HTTPInterceptor(next http.Handler) http.Handler {
+	return defaultHTTPInterceptor(next, h.Authenticate)
+}
+
+func checkPassword(hash, password string) bool {
+	if strings.HasPrefix(hash, "{SHA}") {
+		sum := sha1.Sum([]byte(password)) // #nosec
+		expected := "{SHA}" + base64.StdEncoding.EncodeToString(sum[:])
+		return subtle.ConstantTimeCompare([]byte(hash), []byte(expected)) == 1
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+func isSupportedHash(hash string) bool {
+	return strings.HasPrefix(hash, "{SHA}") ||
+		strings.HasPrefix(hash, "$2a$") ||
+		strings.HasPrefix(hash, "$2b$") ||
+		strings.HasPrefix(hash, "$2y$")
+}
+
+// loadHtpasswd reads the "user:hash" lines of an htpasswd file
+func loadHtpasswd(path string) (map[string]string, error) {
+	passwords := map[string]string{}
+	err := readLines(path, func(line int, text string) error {
+		parts := strings.SplitN(text, ":", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return fmt.Errorf("line %d: expected the \"user:hash\" format", line)
+		}
+		if !isSupportedHash(parts[1]) {
+			return fmt.Errorf("line %d: unsupported password hash for user %q, only bcrypt and SHA1 are supported", line, parts[0])
+		}
+		passwords[parts[0]] = parts[1]
+		return nil
+	})
+	return passwords, err
+}
+
+// loadHtgroups reads the "group: user1 user2" lines of a groups file, returning the groups of each user
+func loadHtgroups(path string) (map[string][]string, error) {
+	groups := map[string][]string{}
+	err := readLines(path, func(line int, text string) error {
+		parts := strings.SplitN(text, ":", 2)
+		group := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || group == "" {
+			return fmt.Errorf("line %d: expected the \"group: user1 user2\" format", line)
+		}
+		for _, user := range strings.Fields(parts[1]) {
+			groups[user] = append(groups[user], group)
+		}
+		return nil
+	})
+	return groups, err
+}
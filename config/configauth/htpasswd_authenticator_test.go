@@ -0,0 +1,169 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configauth
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func basicAuth(user, password string) map[string][]string {
+	return map[string][]string{
+		"authorization": {"Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+password))},
+	}
+}
+
+func newStartedHtpasswdAuthenticator(t *testing.T, cfg Htpasswd) *htpasswdAuthenticator {
+	p, err := newHtpasswdAuthenticator(Authentication{Htpasswd: &cfg})
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background()))
+	return p
+}
+
+func TestHtpasswdAuthenticationSucceeded(t *testing.T) {
+	// prepare
+	p := newStartedHtpasswdAuthenticator(t, Htpasswd{
+		File:       path.Join(".", "testdata", "htpasswd"),
+		GroupsFile: path.Join(".", "testdata", "htgroups"),
+	})
+
+	for _, tt := range []struct {
+		user     string
+		password string
+		groups   []string
+	}{
+		{"alice", "s3cr3t", []string{"admins", "devs"}},
+		{"bob", "hunter2", []string{"devs"}},
+	} {
+		t.Run(tt.user, func(t *testing.T) {
+			// test, twice to go through the verified credentials
+			for i := 0; i < 2; i++ {
+				ctx, err := p.Authenticate(context.Background(), basicAuth(tt.user, tt.password))
+
+				// verify
+				require.NoError(t, err)
+				subject, ok := SubjectFromContext(ctx)
+				assert.True(t, ok)
+				assert.Equal(t, tt.user, subject)
+				groups, ok := GroupsFromContext(ctx)
+				assert.True(t, ok)
+				assert.Equal(t, tt.groups, groups)
+			}
+		})
+	}
+}
+
+func TestHtpasswdWithoutGroups(t *testing.T) {
+	// prepare
+	p := newStartedHtpasswdAuthenticator(t, Htpasswd{File: path.Join(".", "testdata", "htpasswd")})
+
+	// test
+	ctx, err := p.Authenticate(context.Background(), basicAuth("alice", "s3cr3t"))
+
+	// verify
+	require.NoError(t, err)
+	groups, ok := GroupsFromContext(ctx)
+	assert.True(t, ok)
+	assert.Empty(t, groups)
+}
+
+func TestHtpasswdAuthenticationFailed(t *testing.T) {
+	// prepare
+	p := newStartedHtpasswdAuthenticator(t, Htpasswd{File: path.Join(".", "testdata", "htpasswd")})
+
+	for _, tt := range []struct {
+		name    string
+		headers map[string][]string
+		err     error
+	}{
+		{"missing header", map[string][]string{}, errNotAuthenticated},
+		{"wrong password", basicAuth("alice", "hunter2"), errInvalidUserOrPassword},
+		{"wrong SHA1 password", basicAuth("bob", "s3cr3t"), errInvalidUserOrPassword},
+		{"unknown user", basicAuth("carol", "s3cr3t"), errInvalidUserOrPassword},
+		{"wrong scheme", map[string][]string{"authorization": {"Bearer abc"}}, errInvalidAuthenticationHeaderFormat},
+		{"invalid base64", map[string][]string{"authorization": {"Basic !!!"}}, errInvalidBasicAuth},
+		{"missing colon", map[string][]string{"authorization": {"Basic " + base64.StdEncoding.EncodeToString([]byte("alice"))}}, errInvalidBasicAuth},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// test
+			_, err := p.Authenticate(context.Background(), tt.headers)
+
+			// verify
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestHtpasswdMissingFile(t *testing.T) {
+	// test
+	p, err := newHtpasswdAuthenticator(Authentication{Htpasswd: &Htpasswd{}})
+
+	// verify
+	assert.Nil(t, p)
+	assert.Equal(t, errNoHtpasswdFileProvided, err)
+}
+
+func TestHtpasswdFailedToLoad(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		cfg  Htpasswd
+	}{
+		{"unsupported hash", Htpasswd{File: path.Join(".", "testdata", "htpasswd_unsupported")}},
+		{"invalid format", Htpasswd{File: path.Join(".", "testdata", "api_keys")}},
+		{"missing file", Htpasswd{File: path.Join(".", "testdata", "non-existing")}},
+		{"missing groups file", Htpasswd{File: path.Join(".", "testdata", "htpasswd"), GroupsFile: path.Join(".", "testdata", "non-existing")}},
+		{"invalid groups file", Htpasswd{File: path.Join(".", "testdata", "htpasswd"), GroupsFile: path.Join(".", "testdata", "api_keys")}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// prepare
+			cfg := tt.cfg
+			p, err := newHtpasswdAuthenticator(Authentication{Htpasswd: &cfg})
+			require.NoError(t, err)
+
+			// test
+			err = p.Start(context.Background())
+
+			// verify
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestHtpasswdHTTPInterceptor(t *testing.T) {
+	// prepare
+	p := newStartedHtpasswdAuthenticator(t, Htpasswd{File: path.Join(".", "testdata", "htpasswd")})
+	var subject string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		subject, _ = SubjectFromContext(r.Context())
+	})
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.SetBasicAuth("bob", "hunter2")
+	rec := httptest.NewRecorder()
+
+	// test
+	p.HTTPInterceptor(handler).ServeHTTP(rec, req)
+
+	// verify
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "bob", subject)
+	assert.NoError(t, p.Close())
+}
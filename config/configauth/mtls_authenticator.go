@@ -0,0 +1,150 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configauth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+const (
+	subjectFieldCommonName = "common_name"
+	subjectFieldDNSSAN     = "dns_san"
+	subjectFieldEmailSAN   = "email_san"
+	subjectFieldURISAN     = "uri_san"
+)
+
+type mtlsAuthenticator struct {
+	subjectField string
+}
+
+var (
+	_ Authenticator = (*mtlsAuthenticator)(nil)
+
+	errNoVerifiedClientCertificate = errors.New("no verified client certificate found, the server must be configured to verify the client certificates")
+	errSubjectFieldNotFound        = errors.New("the subject field from the mTLS configuration not found on the client certificate")
+)
+
+func newMTLSAuthenticator(cfg Authentication) (*mtlsAuthenticator, error) {
+	subjectField := cfg.MTLS.SubjectField
+	switch subjectField {
+	case "":
+		subjectField = subjectFieldCommonName
+	case subjectFieldCommonName, subjectFieldDNSSAN, subjectFieldEmailSAN, subjectFieldURISAN:
+	default:
+		return nil, fmt.Errorf("unknown subject field %q for the mTLS configuration", subjectField)
+	}
+
+	return &mtlsAuthenticator{
+		subjectField: subjectField,
+	}, nil
+}
+
+func (m *mtlsAuthenticator) Authenticate(ctx context.Context, _ map[string][]string) (context.Context, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ctx, errNoVerifiedClientCertificate
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return ctx, errNoVerifiedClientCertificate
+	}
+	cert := clientCertificate(tlsInfo.State)
+	if cert == nil {
+		return ctx, errNoVerifiedClientCertificate
+	}
+
+	sub, err := getSubjectFromCertificate(cert, m.subjectField)
+	if err != nil {
+		return ctx, err
+	}
+
+	groups := cert.Subject.OrganizationalUnit
+	if groups == nil {
+		groups = []string{}
+	}
+
+	ctx = context.WithValue(ctx, subjectKey, sub)
+	ctx = context.WithValue(ctx, groupsKey, groups)
+	return ctx, nil
+}
+
+func (m *mtlsAuthenticator) Start(context.Context) error {
+	return nil
+}
+
+func (m *mtlsAuthenticator) Close() error {
+	return nil
+}
+
+func (m *mtlsAuthenticator) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return defaultUnaryInterceptor(ctx, req, info, handler, m.Authenticate)
+}
+
+func (m *mtlsAuthenticator) StreamInterceptor(srv interface{}, str grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return defaultStreamInterceptor(srv, str, info, handler, m.Authenticate)
+}
+
+func (m *mtlsAuthenticator) HTTPInterceptor(next http.Handler) http.Handler {
+	return defaultHTTPInterceptor(next, m.Authenticate)
+}
+
+// clientCertificate returns the verified certificate of the client, nil if there is none.
+func clientCertificate(state tls.ConnectionState) *x509.Certificate {
+	// the first certificate of the verified chain is the client's
+	if len(state.VerifiedChains) > 0 && len(state.VerifiedChains[0]) > 0 {
+		return state.VerifiedChains[0][0]
+	}
+	// with a client_ca_file, the server requires any client certificate and verifies it itself
+	// against the current client CAs, as they may be reloaded, leaving the verified chains empty.
+	// The sessions aren't resumed then, so the peer certificate was verified by this handshake.
+	if len(state.PeerCertificates) > 0 {
+		return state.PeerCertificates[0]
+	}
+	return nil
+}
+
+func getSubjectFromCertificate(cert *x509.Certificate, field string) (string, error) {
+	var sub string
+	switch field {
+	case subjectFieldDNSSAN:
+		if len(cert.DNSNames) > 0 {
+			sub = cert.DNSNames[0]
+		}
+	case subjectFieldEmailSAN:
+		if len(cert.EmailAddresses) > 0 {
+			sub = cert.EmailAddresses[0]
+		}
+	case subjectFieldURISAN:
+		if len(cert.URIs) > 0 {
+			sub = cert.URIs[0].String()
+		}
+	default:
+		sub = cert.Subject.CommonName
+	}
+
+	if sub == "" {
+		return "", errSubjectFieldNotFound
+	}
+	return sub, nil
+}
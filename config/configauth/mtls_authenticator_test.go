@@ -0,0 +1,164 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configauth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+func newClientCertificate() *x509.Certificate {
+	return &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:         "agent-1",
+			OrganizationalUnit: []string{"department-1", "department-2"},
+		},
+		DNSNames:       []string{"agent-1.example.com", "agent.example.com"},
+		EmailAddresses: []string{"agent-1@example.com"},
+		URIs:           []*url.URL{{Scheme: "spiffe", Host: "example.com", Path: "/agent-1"}},
+	}
+}
+
+func contextWithCertificate(cert *x509.Certificate) context.Context {
+	state := tls.ConnectionState{}
+	if cert != nil {
+		state.VerifiedChains = [][]*x509.Certificate{{cert}}
+	}
+	return peer.NewContext(context.Background(), &peer.Peer{
+		Addr:     &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1234},
+		AuthInfo: credentials.TLSInfo{State: state},
+	})
+}
+
+func TestMTLSAuthenticationSucceeded(t *testing.T) {
+	for _, tt := range []struct {
+		field   string
+		subject string
+	}{
+		{"", "agent-1"},
+		{"common_name", "agent-1"},
+		{"dns_san", "agent-1.example.com"},
+		{"email_san", "agent-1@example.com"},
+		{"uri_san", "spiffe://example.com/agent-1"},
+	} {
+		t.Run(tt.field, func(t *testing.T) {
+			// prepare
+			p, err := newMTLSAuthenticator(Authentication{MTLS: &MTLS{SubjectField: tt.field}})
+			require.NoError(t, err)
+			require.NoError(t, p.Start(context.Background()))
+
+			// test
+			ctx, err := p.Authenticate(contextWithCertificate(newClientCertificate()), nil)
+
+			// verify
+			require.NoError(t, err)
+			subject, ok := SubjectFromContext(ctx)
+			assert.True(t, ok)
+			assert.Equal(t, tt.subject, subject)
+			groups, ok := GroupsFromContext(ctx)
+			assert.True(t, ok)
+			assert.Equal(t, []string{"department-1", "department-2"}, groups)
+		})
+	}
+}
+
+func TestMTLSAuthenticationPeerCertificate(t *testing.T) {
+	// prepare, the certificate verified by the server against its reloadable client CAs
+	p, err := newMTLSAuthenticator(Authentication{MTLS: &MTLS{}})
+	require.NoError(t, err)
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{newClientCertificate()}}},
+	})
+
+	// test
+	ctx, err = p.Authenticate(ctx, nil)
+
+	// verify
+	require.NoError(t, err)
+	subject, _ := SubjectFromContext(ctx)
+	assert.Equal(t, "agent-1", subject)
+}
+
+func TestMTLSAuthenticationFailed(t *testing.T) {
+	// prepare
+	p, err := newMTLSAuthenticator(Authentication{MTLS: &MTLS{SubjectField: "email_san"}})
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name string
+		ctx  context.Context
+		err  error
+	}{
+		{"no peer", context.Background(), errNoVerifiedClientCertificate},
+		{"no TLS", peer.NewContext(context.Background(), &peer.Peer{}), errNoVerifiedClientCertificate},
+		{"no verified certificate", contextWithCertificate(nil), errNoVerifiedClientCertificate},
+		{"no subject field", contextWithCertificate(&x509.Certificate{}), errSubjectFieldNotFound},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// test
+			_, err := p.Authenticate(tt.ctx, nil)
+
+			// verify
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestMTLSUnknownSubjectField(t *testing.T) {
+	// test
+	p, err := newMTLSAuthenticator(Authentication{MTLS: &MTLS{SubjectField: "serial_number"}})
+
+	// verify
+	assert.Nil(t, p)
+	assert.Error(t, err)
+}
+
+func TestMTLSHTTPInterceptor(t *testing.T) {
+	// prepare
+	p, err := newMTLSAuthenticator(Authentication{MTLS: &MTLS{}})
+	require.NoError(t, err)
+
+	var subject string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		subject, _ = SubjectFromContext(r.Context())
+	})
+
+	verified := httptest.NewRequest(http.MethodPost, "/", nil)
+	verified.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{newClientCertificate()}}}
+	verifiedRec := httptest.NewRecorder()
+	plainRec := httptest.NewRecorder()
+
+	// test
+	p.HTTPInterceptor(handler).ServeHTTP(verifiedRec, verified)
+	p.HTTPInterceptor(handler).ServeHTTP(plainRec, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	// verify
+	assert.Equal(t, http.StatusOK, verifiedRec.Code)
+	assert.Equal(t, "agent-1", subject)
+	assert.Equal(t, http.StatusUnauthorized, plainRec.Code)
+	assert.NoError(t, p.Close())
+}
@@ -0,0 +1,168 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"google.golang.org/grpc/credentials"
+)
+
+// defaultOAuth2Timeout is the timeout of the requests to the token endpoint when
+// none is configured.
+const defaultOAuth2Timeout = 10 * time.Second
+
+// oauth2ClientAuthenticator obtains tokens using the OAuth2 client credentials flow. The
+// token is cached, and a new one is fetched shortly before it expires.
+type oauth2ClientAuthenticator struct {
+	config *clientcredentials.Config
+	client *http.Client
+
+	// sem serializes the token fetches while letting the callers give up when
+	// their context is done.
+	sem   chan struct{}
+	token *oauth2.Token
+}
+
+var (
+	_ ClientAuthenticator = (*oauth2ClientAuthenticator)(nil)
+
+	errNoOAuth2ClientIDProvided     = errors.New("no ClientID provided for the OAuth2 configuration")
+	errNoOAuth2ClientSecretProvided = errors.New("no ClientSecret provided for the OAuth2 configuration")
+	errNoOAuth2TokenURLProvided     = errors.New("no TokenURL provided for the OAuth2 configuration")
+)
+
+func newOAuth2ClientAuthenticator(cfg OAuth2ClientCredentials) (*oauth2ClientAuthenticator, error) {
+	if cfg.ClientID == "" {
+		return nil, errNoOAuth2ClientIDProvided
+	}
+	if cfg.ClientSecret == "" {
+		return nil, errNoOAuth2ClientSecretProvided
+	}
+	if cfg.TokenURL == "" {
+		return nil, errNoOAuth2TokenURLProvided
+	}
+
+	ccConfig := &clientcredentials.Config{
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		TokenURL:     cfg.TokenURL,
+		Scopes:       cfg.Scopes,
+	}
+	if len(cfg.EndpointParams) > 0 {
+		ccConfig.EndpointParams = url.Values{}
+		for k, v := range cfg.EndpointParams {
+			ccConfig.EndpointParams.Set(k, v)
+		}
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultOAuth2Timeout
+	}
+	return &oauth2ClientAuthenticator{
+		config: ccConfig,
+		client: &http.Client{Timeout: timeout},
+		sem:    make(chan struct{}, 1),
+	}, nil
+}
+
+// getToken returns the cached token, or fetches a new one if it is about to
+// expire. The token request is canceled when ctx, the context of the
+// request/RPC needing the token, is done.
+func (o *oauth2ClientAuthenticator) getToken(ctx context.Context) (*oauth2.Token, error) {
+	select {
+	case o.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-o.sem }()
+
+	if o.token.Valid() {
+		return o.token, nil
+	}
+	token, err := o.config.Token(context.WithValue(ctx, oauth2.HTTPClient, o.client))
+	if err != nil {
+		return nil, err
+	}
+	o.token = token
+	return token, nil
+}
+
+// PerRPCCredentials returns gRPC credentials sending the token in the 'authorization' metadata.
+func (o *oauth2ClientAuthenticator) PerRPCCredentials() (credentials.PerRPCCredentials, error) {
+	return &oauth2PerRPCCredentials{auth: o}, nil
+}
+
+// RoundTripper returns an HTTP RoundTripper sending the token in the 'Authorization' header.
+func (o *oauth2ClientAuthenticator) RoundTripper(base http.RoundTripper) (http.RoundTripper, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &oauth2RoundTripper{auth: o, base: base}, nil
+}
+
+// oauth2RoundTripper is an http.RoundTripper setting the 'Authorization' header with the current token,
+// fetched with the context of the request.
+type oauth2RoundTripper struct {
+	auth *oauth2ClientAuthenticator
+	base http.RoundTripper
+}
+
+var _ http.RoundTripper = (*oauth2RoundTripper)(nil)
+
+func (rt *oauth2RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := rt.auth.getToken(req.Context())
+	if err != nil {
+		// A RoundTripper must always close the body.
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	// A RoundTripper must not modify the request.
+	authReq := req.Clone(req.Context())
+	token.SetAuthHeader(authReq)
+	return rt.base.RoundTrip(authReq)
+}
+
+// oauth2PerRPCCredentials is a gRPC credentials.PerRPCCredentials implementation that returns an 'authorization'
+// header with the current token.
+type oauth2PerRPCCredentials struct {
+	auth *oauth2ClientAuthenticator
+}
+
+var _ credentials.PerRPCCredentials = (*oauth2PerRPCCredentials)(nil)
+
+// GetRequestMetadata returns the request metadata to be used with the RPC, fetching a new token with the RPC's
+// context if needed.
+func (c *oauth2PerRPCCredentials) GetRequestMetadata(ctx context.Context, _ ...string) (map[string]string, error) {
+	token, err := c.auth.getToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"authorization": token.Type() + " " + token.AccessToken}, nil
+}
+
+// RequireTransportSecurity always returns true for this implementation. Passing tokens in plain-text connections is a bad idea.
+func (c *oauth2PerRPCCredentials) RequireTransportSecurity() bool {
+	return true
+}
@@ -0,0 +1,210 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tokenServer is an OAuth2 token endpoint issuing a new token on each request.
+type tokenServer struct {
+	*httptest.Server
+	mutex     sync.Mutex
+	requests  int
+	expiresIn int
+}
+
+func newTokenServer(t *testing.T, expiresIn int) *tokenServer {
+	ts := &tokenServer{expiresIn: expiresIn}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, ok := r.BasicAuth()
+		if !ok || clientID != "some-client" || clientSecret != "some-secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "read write", r.PostForm.Get("scope"))
+		assert.Equal(t, "some-audience", r.PostForm.Get("audience"))
+
+		ts.mutex.Lock()
+		ts.requests++
+		token := fmt.Sprintf("token-%d", ts.requests)
+		ts.mutex.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": token,
+			"token_type":   "Bearer",
+			"expires_in":   ts.expiresIn,
+		}))
+	}))
+	return ts
+}
+
+func (ts *tokenServer) oauth2Config() OAuth2ClientCredentials {
+	return OAuth2ClientCredentials{
+		ClientID:       "some-client",
+		ClientSecret:   "some-secret",
+		TokenURL:       ts.URL,
+		Scopes:         []string{"read", "write"},
+		EndpointParams: map[string]string{"audience": "some-audience"},
+	}
+}
+
+func TestOAuth2ClientAuthenticatorMissingSettings(t *testing.T) {
+	for _, tt := range []struct {
+		cfg OAuth2ClientCredentials
+		err error
+	}{
+		{
+			cfg: OAuth2ClientCredentials{ClientSecret: "some-secret", TokenURL: "http://example.com/token"},
+			err: errNoOAuth2ClientIDProvided,
+		},
+		{
+			cfg: OAuth2ClientCredentials{ClientID: "some-client", TokenURL: "http://example.com/token"},
+			err: errNoOAuth2ClientSecretProvided,
+		},
+		{
+			cfg: OAuth2ClientCredentials{ClientID: "some-client", ClientSecret: "some-secret"},
+			err: errNoOAuth2TokenURLProvided,
+		},
+	} {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			// test
+			p, err := newOAuth2ClientAuthenticator(tt.cfg)
+
+			// verify
+			assert.Nil(t, p)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestOAuth2ClientAuthenticatorRoundTripper(t *testing.T) {
+	// prepare
+	tokens := newTokenServer(t, 3600)
+	defer tokens.Close()
+
+	var authorizations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	auth, err := newOAuth2ClientAuthenticator(tokens.oauth2Config())
+	require.NoError(t, err)
+
+	// test
+	rt, err := auth.RoundTripper(http.DefaultTransport)
+	require.NoError(t, err)
+	client := &http.Client{Transport: rt}
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	// verify the token is reused until it expires
+	assert.Equal(t, []string{"Bearer token-1", "Bearer token-1"}, authorizations)
+	assert.Equal(t, 1, tokens.requests)
+}
+
+func TestOAuth2ClientAuthenticatorPerRPCCredentials(t *testing.T) {
+	// prepare, the tokens expiring within the refresh delay
+	tokens := newTokenServer(t, 1)
+	defer tokens.Close()
+
+	auth, err := newOAuth2ClientAuthenticator(tokens.oauth2Config())
+	require.NoError(t, err)
+
+	// test
+	creds, err := auth.PerRPCCredentials()
+	require.NoError(t, err)
+	md1, err := creds.GetRequestMetadata(context.Background())
+	require.NoError(t, err)
+	md2, err := creds.GetRequestMetadata(context.Background())
+	require.NoError(t, err)
+
+	// verify a new token is fetched when the token expires
+	assert.Equal(t, map[string]string{"authorization": "Bearer token-1"}, md1)
+	assert.Equal(t, map[string]string{"authorization": "Bearer token-2"}, md2)
+	assert.True(t, creds.RequireTransportSecurity())
+}
+
+func TestOAuth2ClientAuthenticatorTokenFailure(t *testing.T) {
+	// prepare
+	tokens := newTokenServer(t, 3600)
+	defer tokens.Close()
+
+	cfg := tokens.oauth2Config()
+	cfg.ClientSecret = "wrong-secret"
+	auth, err := newOAuth2ClientAuthenticator(cfg)
+	require.NoError(t, err)
+
+	// test
+	rt, err := auth.RoundTripper(http.DefaultTransport)
+	require.NoError(t, err)
+	_, err = (&http.Client{Transport: rt}).Get(tokens.URL)
+
+	// verify
+	assert.Error(t, err)
+}
+
+func TestOAuth2ClientAuthenticatorDefaultTimeout(t *testing.T) {
+	auth, err := newOAuth2ClientAuthenticator(OAuth2ClientCredentials{
+		ClientID:     "some-client",
+		ClientSecret: "some-secret",
+		TokenURL:     "http://example.com/token",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, defaultOAuth2Timeout, auth.client.Timeout)
+}
+
+func TestOAuth2ClientAuthenticatorRPCContext(t *testing.T) {
+	// prepare, a token endpoint never answering
+	release := make(chan struct{})
+	tokens := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer tokens.Close()
+	defer close(release)
+
+	cfg := OAuth2ClientCredentials{ClientID: "some-client", ClientSecret: "some-secret", TokenURL: tokens.URL, Timeout: time.Minute}
+	auth, err := newOAuth2ClientAuthenticator(cfg)
+	require.NoError(t, err)
+	creds, err := auth.PerRPCCredentials()
+	require.NoError(t, err)
+
+	// test
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = creds.GetRequestMetadata(ctx)
+
+	// verify the token request is bounded by the deadline of the RPC
+	assert.Error(t, err)
+	assert.Less(t, int64(time.Since(start)), int64(10*time.Second))
+}
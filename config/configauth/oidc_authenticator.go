@@ -39,6 +39,7 @@ type oidcAuthenticator struct {
 
 	unaryInterceptor  unaryInterceptorFunc
 	streamInterceptor streamInterceptorFunc
+	httpInterceptor   httpInterceptorFunc
 }
 
 var (
@@ -70,6 +71,7 @@ func newOIDCAuthenticator(cfg Authentication) (*oidcAuthenticator, error) {
 		config:            *cfg.OIDC,
 		unaryInterceptor:  defaultUnaryInterceptor,
 		streamInterceptor: defaultStreamInterceptor,
+		httpInterceptor:   defaultHTTPInterceptor,
 	}, nil
 }
 
@@ -144,6 +146,10 @@ func (o *oidcAuthenticator) StreamInterceptor(srv interface{}, str grpc.ServerSt
 	return o.streamInterceptor(srv, str, info, handler, o.Authenticate)
 }
 
+func (o *oidcAuthenticator) HTTPInterceptor(next http.Handler) http.Handler {
+	return o.httpInterceptor(next, o.Authenticate)
+}
+
 func getSubjectFromClaims(claims map[string]interface{}, usernameClaim string, fallback string) (string, error) {
 	if len(usernameClaim) > 0 {
 		username, found := claims[usernameClaim]
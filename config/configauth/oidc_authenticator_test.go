@@ -27,6 +27,8 @@ import (
 	"io/ioutil"
 	"math/big"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
@@ -546,3 +548,29 @@ func TestStreamInterceptor(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, interceptorCalled)
 }
+
+func TestHTTPInterceptor(t *testing.T) {
+	// prepare
+	config := Authentication{
+		OIDC: &OIDC{
+			Audience:  "some-audience",
+			IssuerURL: "http://example.com/",
+		},
+	}
+	p, err := newOIDCAuthenticator(config)
+	require.NoError(t, err)
+	require.NotNil(t, p)
+
+	interceptorCalled := false
+	p.httpInterceptor = func(next http.Handler, authenticate authenticateFunc) http.Handler {
+		interceptorCalled = true
+		return next
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	// test
+	p.HTTPInterceptor(handler).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// verify
+	assert.True(t, interceptorCalled)
+}
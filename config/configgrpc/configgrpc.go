@@ -16,7 +16,10 @@
 package configgrpc
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"strings"
 	"time"
@@ -27,6 +30,8 @@ import (
 	"google.golang.org/grpc/encoding/gzip"
 	"google.golang.org/grpc/keepalive"
 
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/config/configadmission"
 	"go.opentelemetry.io/collector/config/configauth"
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtls"
@@ -47,6 +52,9 @@ var (
 	}
 )
 
+// errInsecureAuth is returned when the tokens of the auth would be sent in plain text.
+var errInsecureAuth = errors.New(`"auth" requires a secure connection to send its tokens, it can't be used with "insecure: true"`)
+
 // Allowed balancer names to be set in grpclb_policy to discover the servers
 var allowedBalancerNames = []string{roundrobin.Name, grpc.PickFirstBalancerName}
 
@@ -95,6 +103,10 @@ type GRPCClientSettings struct {
 	// PerRPCAuth parameter configures the client to send authentication data on a per-RPC basis.
 	PerRPCAuth *PerRPCAuthConfig `mapstructure:"per_rpc_auth"`
 
+	// Auth configures the client to authenticate each RPC, e.g. with tokens obtained using the OAuth2
+	// client credentials flow.
+	Auth *configauth.ClientAuthentication `mapstructure:"auth,omitempty"`
+
 	// Sets the balancer in grpclb_policy to discover the servers. Default is pick_first
 	// https://github.com/grpc/grpc-go/blob/master/examples/features/load_balancing/README.md
 	BalancerName string `mapstructure:"balancer_name"`
@@ -161,10 +173,30 @@ type GRPCServerSettings struct {
 
 	// Auth for this receiver
 	Auth *configauth.Authentication `mapstructure:"auth,omitempty"`
+
+	// Admission limits the size of the requests being processed by this server. (optional)
+	Admission *configadmission.Admission `mapstructure:"admission,omitempty"`
+
+	// IncludeMetadata lists the gRPC metadata keys whose values are kept in the client.Client
+	// of the requests, available to the processors and exporters. (optional)
+	IncludeMetadata []string `mapstructure:"include_metadata,omitempty"`
+}
+
+// Validate checks that the settings can be used together: the tokens of the auth can't be sent
+// over an insecure connection.
+func (gcs *GRPCClientSettings) Validate() error {
+	if gcs.Auth != nil && gcs.TLSSetting.Insecure && gcs.TLSSetting.CAFile == "" {
+		return errInsecureAuth
+	}
+	return nil
 }
 
 // ToDialOptions maps configgrpc.GRPCClientSettings to a slice of dial options for gRPC
 func (gcs *GRPCClientSettings) ToDialOptions() ([]grpc.DialOption, error) {
+	if err := gcs.Validate(); err != nil {
+		return nil, err
+	}
+
 	var opts []grpc.DialOption
 	if gcs.Compression != "" {
 		if compressionKey := GetGRPCCompressionKey(gcs.Compression); compressionKey != CompressionUnsupported {
@@ -174,13 +206,13 @@ func (gcs *GRPCClientSettings) ToDialOptions() ([]grpc.DialOption, error) {
 		}
 	}
 
-	tlsCfg, err := gcs.TLSSetting.LoadTLSConfig()
+	getTLSConfig, err := gcs.TLSSetting.LoadReloadableTLSConfig()
 	if err != nil {
 		return nil, err
 	}
 	tlsDialOption := grpc.WithInsecure()
-	if tlsCfg != nil {
-		tlsDialOption = grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg))
+	if getTLSConfig != nil {
+		tlsDialOption = grpc.WithTransportCredentials(newReloadableTLSCredentials(getTLSConfig))
 	}
 	opts = append(opts, tlsDialOption)
 
@@ -211,6 +243,18 @@ func (gcs *GRPCClientSettings) ToDialOptions() ([]grpc.DialOption, error) {
 		}
 	}
 
+	if gcs.Auth != nil {
+		auth, err := configauth.NewClientAuthenticator(*gcs.Auth)
+		if err != nil {
+			return nil, err
+		}
+		creds, err := auth.PerRPCCredentials()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithPerRPCCredentials(creds))
+	}
+
 	if gcs.BalancerName != "" {
 		valid := validateBalancerName(gcs.BalancerName)
 		if !valid {
@@ -235,14 +279,37 @@ func (gss *GRPCServerSettings) ToListener() (net.Listener, error) {
 	return gss.NetAddr.Listen()
 }
 
-// ToServerOption maps configgrpc.GRPCServerSettings to a slice of server options for gRPC
-func (gss *GRPCServerSettings) ToServerOption() ([]grpc.ServerOption, error) {
+// toServerOptions has options that change the behavior of the gRPC server options
+// returned by GRPCServerSettings.ToServerOption().
+type toServerOptions struct {
+	admissionController *configadmission.Controller
+}
+
+type ToServerOption func(opts *toServerOptions)
+
+// WithAdmissionController admits the requests with the given controller, shared with the
+// other servers of the receiver, instead of the Admission settings of the server.
+func WithAdmissionController(c *configadmission.Controller) ToServerOption {
+	return func(opts *toServerOptions) {
+		opts.admissionController = c
+	}
+}
+
+// ToServerOption maps configgrpc.GRPCServerSettings to a slice of server options for gRPC. When
+// authentication is configured, the returned io.Closer stops the authenticator: it must be closed
+// once the server is stopped. It is nil otherwise.
+func (gss *GRPCServerSettings) ToServerOption(serverOpts ...ToServerOption) ([]grpc.ServerOption, io.Closer, error) {
+	toOpts := &toServerOptions{}
+	for _, o := range serverOpts {
+		o(toOpts)
+	}
+
 	var opts []grpc.ServerOption
 
 	if gss.TLSSetting != nil {
 		tlsCfg, err := gss.TLSSetting.LoadTLSConfig()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsCfg)))
 	}
@@ -291,15 +358,67 @@ func (gss *GRPCServerSettings) ToServerOption() ([]grpc.ServerOption, error) {
 		}
 	}
 
+	var auth configauth.Authenticator
 	if gss.Auth != nil {
-		authOpts, err := gss.Auth.ToServerOptions()
+		var authOpts []grpc.ServerOption
+		var err error
+		authOpts, auth, err = gss.Auth.ToServerOptions()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		opts = append(opts, authOpts...)
 	}
 
-	return opts, nil
+	// chained interceptors run after the authentication ones, so that unauthenticated RPCs
+	// are rejected before being admitted
+	if toOpts.admissionController != nil {
+		opts = append(opts, toOpts.admissionController.ToServerOptions()...)
+	} else if gss.Admission != nil {
+		admissionOpts, err := gss.Admission.ToServerOptions()
+		if err != nil {
+			if auth != nil {
+				auth.Close()
+			}
+			return nil, nil, err
+		}
+		opts = append(opts, admissionOpts...)
+	}
+
+	if len(gss.IncludeMetadata) > 0 {
+		// chained interceptors run after the authentication ones, so that the authenticated
+		// subject is available
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(gss.clientUnaryInterceptor),
+			grpc.ChainStreamInterceptor(gss.clientStreamInterceptor),
+		)
+	}
+
+	return opts, auth, nil
+}
+
+func (gss *GRPCServerSettings) clientContext(ctx context.Context) context.Context {
+	if c, ok := client.FromGRPC(ctx, client.WithMetadata(gss.IncludeMetadata...)); ok {
+		ctx = client.NewContext(ctx, c)
+	}
+	return ctx
+}
+
+func (gss *GRPCServerSettings) clientUnaryInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(gss.clientContext(ctx), req)
+}
+
+func (gss *GRPCServerSettings) clientStreamInterceptor(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &serverStreamWithContext{ServerStream: stream, ctx: gss.clientContext(stream.Context())})
+}
+
+// serverStreamWithContext overrides the context of a grpc.ServerStream.
+type serverStreamWithContext struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStreamWithContext) Context() context.Context {
+	return s.ctx
 }
 
 // GetGRPCCompressionKey returns the grpc registered compression key if the
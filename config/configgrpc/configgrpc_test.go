@@ -16,19 +16,28 @@ package configgrpc
 
 import (
 	"context"
+	"net"
 	"path"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/config/configadmission"
 	"go.opentelemetry.io/collector/config/configauth"
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtls"
 	otelcol "go.opentelemetry.io/collector/internal/data/protogen/collector/trace/v1"
+	otlptrace "go.opentelemetry.io/collector/internal/data/protogen/trace/v1"
 	"go.opentelemetry.io/collector/testutil"
 )
 
@@ -71,11 +80,54 @@ func TestAllGrpcClientSettings(t *testing.T) {
 
 func TestDefaultGrpcServerSettings(t *testing.T) {
 	gss := &GRPCServerSettings{}
-	opts, err := gss.ToServerOption()
+	opts, _, err := gss.ToServerOption()
 	assert.NoError(t, err)
 	assert.Len(t, opts, 0)
 }
 
+func TestGrpcServerIncludeMetadata(t *testing.T) {
+	gss := &GRPCServerSettings{
+		IncludeMetadata: []string{"X-Tenant"},
+	}
+	opts, _, err := gss.ToServerOption()
+	assert.NoError(t, err)
+	assert.Len(t, opts, 2)
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("192.168.1.1"), Port: 80},
+	})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-tenant", "acme", "x-other", "value"))
+	expected := &client.Client{
+		IP:       "192.168.1.1",
+		Metadata: map[string][]string{"x-tenant": {"acme"}},
+	}
+
+	var unaryClient *client.Client
+	_, err = gss.clientUnaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		unaryClient, _ = client.FromContext(ctx)
+		return nil, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, unaryClient)
+
+	var streamClient *client.Client
+	err = gss.clientStreamInterceptor(nil, &mockServerStream{ctx: ctx}, &grpc.StreamServerInfo{}, func(srv interface{}, stream grpc.ServerStream) error {
+		streamClient, _ = client.FromContext(stream.Context())
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, streamClient)
+}
+
+type mockServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (m *mockServerStream) Context() context.Context {
+	return m.ctx
+}
+
 func TestAllGrpcServerSettingsExceptAuth(t *testing.T) {
 	gss := &GRPCServerSettings{
 		NetAddr: confignet.NetAddr{
@@ -104,7 +156,7 @@ func TestAllGrpcServerSettingsExceptAuth(t *testing.T) {
 			},
 		},
 	}
-	opts, err := gss.ToServerOption()
+	opts, _, err := gss.ToServerOption()
 	assert.NoError(t, err)
 	assert.Len(t, opts, 7)
 }
@@ -113,19 +165,85 @@ func TestGrpcServerAuthSettings(t *testing.T) {
 	gss := &GRPCServerSettings{}
 
 	// sanity check
-	_, err := gss.ToServerOption()
+	_, auth, err := gss.ToServerOption()
 	require.NoError(t, err)
+	assert.Nil(t, auth)
 
 	// test
 	gss.Auth = &configauth.Authentication{
 		OIDC: &configauth.OIDC{},
 	}
-	opts, err := gss.ToServerOption()
+	opts, auth, err := gss.ToServerOption()
 
 	// verify
 	// an error here is a positive confirmation that Auth kicked in
 	assert.Error(t, err)
 	assert.Nil(t, opts)
+	assert.Nil(t, auth)
+
+	// the started authenticator is returned to be closed
+	gss.Auth = &configauth.Authentication{
+		APIKeys: &configauth.APIKeys{File: path.Join("..", "configauth", "testdata", "api_keys")},
+	}
+	opts, auth, err = gss.ToServerOption()
+	require.NoError(t, err)
+	assert.Len(t, opts, 2)
+	require.NotNil(t, auth)
+	assert.NoError(t, auth.Close())
+}
+
+func TestGrpcServerAdmissionSettings(t *testing.T) {
+	gss := &GRPCServerSettings{
+		Admission: &configadmission.Admission{LimitMiB: 1},
+	}
+	opts, _, err := gss.ToServerOption()
+	assert.NoError(t, err)
+	assert.Len(t, opts, 3)
+
+	gss.Admission = &configadmission.Admission{}
+	opts, _, err = gss.ToServerOption()
+	assert.Error(t, err)
+	assert.Nil(t, opts)
+}
+
+func TestGrpcServerAdmission(t *testing.T) {
+	gss := &GRPCServerSettings{
+		NetAddr: confignet.NetAddr{
+			Endpoint:  "localhost:0",
+			Transport: "tcp",
+		},
+		Admission: &configadmission.Admission{LimitMiB: 1},
+	}
+	ln, err := gss.ToListener()
+	require.NoError(t, err)
+	opts, _, err := gss.ToServerOption()
+	require.NoError(t, err)
+	s := grpc.NewServer(opts...)
+	otelcol.RegisterTraceServiceServer(s, &grpcTraceServer{})
+	go func() {
+		_ = s.Serve(ln)
+	}()
+	defer s.Stop()
+
+	grpcClientConn, err := grpc.Dial(ln.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer grpcClientConn.Close()
+	client := otelcol.NewTraceServiceClient(grpcClientConn)
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancelFunc()
+
+	_, err = client.Export(ctx, &otelcol.ExportTraceServiceRequest{}, grpc.WaitForReady(true))
+	assert.NoError(t, err)
+
+	// larger than the whole limit
+	_, err = client.Export(ctx, &otelcol.ExportTraceServiceRequest{
+		ResourceSpans: []*otlptrace.ResourceSpans{{
+			InstrumentationLibrarySpans: []*otlptrace.InstrumentationLibrarySpans{{
+				Spans: []*otlptrace.Span{{Name: strings.Repeat("a", 1024*1024)}},
+			}},
+		}},
+	})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
 }
 
 func TestGRPCClientSettingsError(t *testing.T) {
@@ -260,7 +378,7 @@ func TestGRPCServerSettingsError(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.err, func(t *testing.T) {
-			_, err := test.settings.ToServerOption()
+			_, _, err := test.settings.ToServerOption()
 			assert.Regexp(t, test.err, err)
 		})
 	}
@@ -413,7 +531,7 @@ func TestHttpReception(t *testing.T) {
 			}
 			ln, err := gss.ToListener()
 			assert.NoError(t, err)
-			opts, err := gss.ToServerOption()
+			opts, _, err := gss.ToServerOption()
 			assert.NoError(t, err)
 			s := grpc.NewServer(opts...)
 			otelcol.RegisterTraceServiceServer(s, &grpcTraceServer{})
@@ -458,7 +576,7 @@ func TestReceiveOnUnixDomainSocket(t *testing.T) {
 	}
 	ln, err := gss.ToListener()
 	assert.NoError(t, err)
-	opts, err := gss.ToServerOption()
+	opts, _, err := gss.ToServerOption()
 	assert.NoError(t, err)
 	s := grpc.NewServer(opts...)
 	otelcol.RegisterTraceServiceServer(s, &grpcTraceServer{})
@@ -505,7 +623,33 @@ func TestWithPerRPCAuthBearerToken(t *testing.T) {
 
 	// verify
 	assert.NoError(t, err)
-	assert.Len(t, dialOpts, 2) // WithInsecure and WithPerRPCCredentials
+	assert.Len(t, dialOpts, 2) // WithTransportCredentials and WithPerRPCCredentials
+}
+
+func TestWithClientAuthInsecure(t *testing.T) {
+	// test
+	gcs := &GRPCClientSettings{
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+		Auth: &configauth.ClientAuthentication{
+			OAuth2: &configauth.OAuth2ClientCredentials{
+				ClientID:     "some-client",
+				ClientSecret: "some-secret",
+				TokenURL:     "https://example.com/token",
+			},
+		},
+	}
+	assert.Equal(t, errInsecureAuth, gcs.Validate())
+	dialOpts, err := gcs.ToDialOptions()
+
+	// verify
+	assert.Equal(t, errInsecureAuth, err)
+	assert.Nil(t, dialOpts)
+
+	// the connection is secure when a CA is set
+	gcs.TLSSetting.CAFile = "testdata/ca.crt"
+	assert.NoError(t, gcs.Validate())
 }
 
 func TestWithPerRPCAuthInvalidAuthType(t *testing.T) {
@@ -521,3 +665,33 @@ func TestWithPerRPCAuthInvalidAuthType(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, dialOpts)
 }
+
+func TestWithClientAuthOAuth2(t *testing.T) {
+	// test
+	gcs := &GRPCClientSettings{
+		Auth: &configauth.ClientAuthentication{
+			OAuth2: &configauth.OAuth2ClientCredentials{
+				ClientID:     "some-client",
+				ClientSecret: "some-secret",
+				TokenURL:     "https://example.com/token",
+			},
+		},
+	}
+	dialOpts, err := gcs.ToDialOptions()
+
+	// verify
+	assert.NoError(t, err)
+	assert.Len(t, dialOpts, 2) // WithInsecure and WithPerRPCCredentials
+}
+
+func TestWithClientAuthMissingSettings(t *testing.T) {
+	// test
+	gcs := &GRPCClientSettings{
+		Auth: &configauth.ClientAuthentication{},
+	}
+	dialOpts, err := gcs.ToDialOptions()
+
+	// verify
+	assert.Error(t, err)
+	assert.Nil(t, dialOpts)
+}
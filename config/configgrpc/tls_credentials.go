@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configgrpc
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// reloadableTLSCredentials are gRPC transport credentials using the current TLS config of the
+// client for each handshake, so that the server certificate is verified with the reloaded CAs
+// while keeping the verification of crypto/tls.
+type reloadableTLSCredentials struct {
+	getConfig func() *tls.Config
+
+	mu                 sync.Mutex
+	config             *tls.Config
+	creds              credentials.TransportCredentials
+	serverNameOverride string
+}
+
+var _ credentials.TransportCredentials = (*reloadableTLSCredentials)(nil)
+
+func newReloadableTLSCredentials(getConfig func() *tls.Config) *reloadableTLSCredentials {
+	return &reloadableTLSCredentials{getConfig: getConfig}
+}
+
+// current returns the credentials of the current TLS config, only created again when it changes.
+func (c *reloadableTLSCredentials) current() credentials.TransportCredentials {
+	cfg := c.getConfig()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cfg != c.config {
+		c.config = cfg
+		if c.serverNameOverride != "" {
+			cfg = cfg.Clone()
+			cfg.ServerName = c.serverNameOverride
+		}
+		c.creds = credentials.NewTLS(cfg)
+	}
+	return c.creds
+}
+
+func (c *reloadableTLSCredentials) ClientHandshake(ctx context.Context, authority string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return c.current().ClientHandshake(ctx, authority, rawConn)
+}
+
+func (c *reloadableTLSCredentials) ServerHandshake(rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return c.current().ServerHandshake(rawConn)
+}
+
+func (c *reloadableTLSCredentials) Info() credentials.ProtocolInfo {
+	return c.current().Info()
+}
+
+func (c *reloadableTLSCredentials) Clone() credentials.TransportCredentials {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return &reloadableTLSCredentials{getConfig: c.getConfig, serverNameOverride: c.serverNameOverride}
+}
+
+func (c *reloadableTLSCredentials) OverrideServerName(serverNameOverride string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.serverNameOverride = serverNameOverride
+	// the credentials are created again with the override
+	c.config = nil
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configgrpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCertificate returns a certificate signed by the parent, or self-signed if the parent is nil.
+func newCertificate(t *testing.T, tmpl *x509.Certificate, parent *tls.Certificate) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl.SerialNumber = big.NewInt(time.Now().UnixNano())
+	tmpl.NotBefore = time.Now().Add(-time.Hour)
+	tmpl.NotAfter = time.Now().Add(time.Hour)
+
+	parentCert, parentKey := tmpl, interface{}(key)
+	if parent != nil {
+		parentCert, parentKey = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parentCert, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+// tlsServer accepts TLS connections on a loopback address, completing their handshake, and
+// returns the pool of the CA of its certificate.
+func tlsServer(t *testing.T) (net.Listener, *x509.CertPool) {
+	ca := newCertificate(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "ca"},
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}, nil)
+	server := newCertificate(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "server"},
+		DNSNames:    []string{"localhost"},
+		IPAddresses: []net.IP{net.IPv4(127, 0, 0, 1)},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, &ca)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Leaf)
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{server}})
+	require.NoError(t, err)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+	return ln, pool
+}
+
+func clientHandshake(t *testing.T, creds *reloadableTLSCredentials, ln net.Listener, authority string) error {
+	rawConn, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	conn, _, err := creds.ClientHandshake(context.Background(), authority, rawConn)
+	if err != nil {
+		rawConn.Close()
+		return err
+	}
+	return conn.Close()
+}
+
+func TestReloadableTLSCredentials(t *testing.T) {
+	ln, pool := tlsServer(t)
+	defer ln.Close()
+
+	trusted := &tls.Config{RootCAs: pool}
+	untrusted := &tls.Config{RootCAs: x509.NewCertPool()}
+
+	current := trusted
+	creds := newReloadableTLSCredentials(func() *tls.Config { return current })
+	assert.Equal(t, "tls", creds.Info().SecurityProtocol)
+
+	// the server certificate is verified against the host of the authority, including IP addresses
+	assert.NoError(t, clientHandshake(t, creds, ln, "localhost:4317"))
+	assert.NoError(t, clientHandshake(t, creds, ln, ln.Addr().String()))
+	assert.Error(t, clientHandshake(t, creds, ln, "example.com:4317"))
+
+	// the server name can be overridden, also in the clones
+	clone := creds.Clone().(*reloadableTLSCredentials)
+	require.NoError(t, clone.OverrideServerName("localhost"))
+	assert.NoError(t, clientHandshake(t, clone, ln, "example.com:4317"))
+	assert.NoError(t, clientHandshake(t, clone.Clone().(*reloadableTLSCredentials), ln, "example.com:4317"))
+
+	// the next handshakes use the new config
+	current = untrusted
+	assert.Error(t, clientHandshake(t, creds, ln, "localhost:4317"))
+	current = trusted
+	assert.NoError(t, clientHandshake(t, creds, ln, "localhost:4317"))
+}
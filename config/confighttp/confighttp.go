@@ -16,12 +16,16 @@ package confighttp
 
 import (
 	"crypto/tls"
+	"io"
 	"net"
 	"net/http"
 	"time"
 
 	"github.com/rs/cors"
 
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/config/configadmission"
+	"go.opentelemetry.io/collector/config/configauth"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/internal/middleware"
 )
@@ -46,18 +50,22 @@ type HTTPClientSettings struct {
 	// Existing header values are overwritten if collision happens.
 	Headers map[string]string `mapstructure:"headers,omitempty"`
 
+	// Auth configures the client to authenticate each HTTP request, e.g. with tokens obtained
+	// using the OAuth2 client credentials flow.
+	Auth *configauth.ClientAuthentication `mapstructure:"auth,omitempty"`
+
 	// Custom Round Tripper to allow for individual components to intercept HTTP requests
 	CustomRoundTripper func(next http.RoundTripper) (http.RoundTripper, error)
 }
 
 func (hcs *HTTPClientSettings) ToClient() (*http.Client, error) {
-	tlsCfg, err := hcs.TLSSetting.LoadTLSConfig()
+	getTLSConfig, err := hcs.TLSSetting.LoadReloadableTLSConfig()
 	if err != nil {
 		return nil, err
 	}
 	transport := http.DefaultTransport.(*http.Transport).Clone()
-	if tlsCfg != nil {
-		transport.TLSClientConfig = tlsCfg
+	if getTLSConfig != nil {
+		transport.TLSClientConfig = getTLSConfig()
 	}
 	if hcs.ReadBufferSize > 0 {
 		transport.ReadBufferSize = hcs.ReadBufferSize
@@ -67,13 +75,28 @@ func (hcs *HTTPClientSettings) ToClient() (*http.Client, error) {
 	}
 
 	clientTransport := (http.RoundTripper)(transport)
+	if getTLSConfig != nil && hcs.TLSSetting.CAFile != "" {
+		// the CAs verifying the server certificate may be reloaded
+		clientTransport = newReloadableTLSTransport(transport, getTLSConfig)
+	}
 	if len(hcs.Headers) > 0 {
 		clientTransport = &headerRoundTripper{
-			transport: transport,
+			transport: clientTransport,
 			headers:   hcs.Headers,
 		}
 	}
 
+	if hcs.Auth != nil {
+		auth, err := configauth.NewClientAuthenticator(*hcs.Auth)
+		if err != nil {
+			return nil, err
+		}
+		clientTransport, err = auth.RoundTripper(clientTransport)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if hcs.CustomRoundTripper != nil {
 		clientTransport, err = hcs.CustomRoundTripper(clientTransport)
 		if err != nil {
@@ -114,6 +137,16 @@ type HTTPServerSettings struct {
 	// An empty list means that CORS is not enabled at all. A wildcard (*) can be
 	// used to match any origin or one or more characters of an origin.
 	CorsOrigins []string `mapstructure:"cors_allowed_origins"`
+
+	// Auth for this receiver
+	Auth *configauth.Authentication `mapstructure:"auth,omitempty"`
+
+	// Admission limits the size of the requests being processed by this server. (optional)
+	Admission *configadmission.Admission `mapstructure:"admission,omitempty"`
+
+	// IncludeMetadata lists the request headers whose values are kept in the client.Client
+	// of the requests, available to the processors and exporters. (optional)
+	IncludeMetadata []string `mapstructure:"include_metadata,omitempty"`
 }
 
 func (hss *HTTPServerSettings) ToListener() (net.Listener, error) {
@@ -136,7 +169,8 @@ func (hss *HTTPServerSettings) ToListener() (net.Listener, error) {
 // toServerOptions has options that change the behavior of the HTTP server
 // returned by HTTPServerSettings.ToServer().
 type toServerOptions struct {
-	errorHandler middleware.ErrorHandler
+	errorHandler        middleware.ErrorHandler
+	admissionController *configadmission.Controller
 }
 
 type ToServerOption func(opts *toServerOptions)
@@ -149,11 +183,46 @@ func WithErrorHandler(e middleware.ErrorHandler) ToServerOption {
 	}
 }
 
-func (hss *HTTPServerSettings) ToServer(handler http.Handler, opts ...ToServerOption) *http.Server {
+// WithAdmissionController admits the requests with the given controller, shared with the
+// other servers of the receiver, instead of the Admission settings of the server.
+func WithAdmissionController(c *configadmission.Controller) ToServerOption {
+	return func(opts *toServerOptions) {
+		opts.admissionController = c
+	}
+}
+
+// ToServer creates an http.Server from settings object. When authentication is configured,
+// every request is authenticated before reaching the given handler, and the returned io.Closer
+// stops the authenticator: it must be closed once the server is shut down. It is nil otherwise.
+// When admission control is configured, every request is admitted after being authenticated.
+func (hss *HTTPServerSettings) ToServer(handler http.Handler, opts ...ToServerOption) (*http.Server, io.Closer, error) {
 	serverOpts := &toServerOptions{}
 	for _, o := range opts {
 		o(serverOpts)
 	}
+	if len(hss.IncludeMetadata) > 0 {
+		// wrapped before the authentication, so that it runs after it and sees the authenticated subject
+		handler = clientHandler(handler, hss.IncludeMetadata)
+	}
+	// wrapped before the authentication, so that unauthenticated requests are rejected before
+	// being admitted
+	if serverOpts.admissionController != nil {
+		handler = serverOpts.admissionController.ToHTTPHandler(handler)
+	} else if hss.Admission != nil {
+		var err error
+		handler, err = hss.Admission.ToHTTPHandler(handler)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	var auth configauth.Authenticator
+	if hss.Auth != nil {
+		var err error
+		handler, auth, err = hss.Auth.ToHTTPHandler(handler)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
 	if len(hss.CorsOrigins) > 0 {
 		co := cors.Options{AllowedOrigins: hss.CorsOrigins}
 		handler = cors.New(co).Handler(handler)
@@ -164,5 +233,15 @@ func (hss *HTTPServerSettings) ToServer(handler http.Handler, opts ...ToServerOp
 	)
 	return &http.Server{
 		Handler: handler,
-	}
+	}, auth, nil
+}
+
+// clientHandler stores the client.Client of each request, with the given headers, in the request's context.
+func clientHandler(next http.Handler, headers []string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c, ok := client.FromHTTP(r, client.WithMetadata(headers...)); ok {
+			r = r.WithContext(client.NewContext(r.Context(), c))
+		}
+		next.ServeHTTP(w, r)
+	})
 }
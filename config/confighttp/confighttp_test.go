@@ -22,12 +22,16 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"path"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/config/configadmission"
+	"go.opentelemetry.io/collector/config/configauth"
 	"go.opentelemetry.io/collector/config/configtls"
 )
 
@@ -278,10 +282,11 @@ func TestHttpReception(t *testing.T) {
 			}
 			ln, err := hss.ToListener()
 			assert.NoError(t, err)
-			s := hss.ToServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s, _, err := hss.ToServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				_, errWrite := fmt.Fprint(w, "test")
 				assert.NoError(t, errWrite)
 			}))
+			require.NoError(t, err)
 
 			go func() {
 				_ = s.Serve(ln)
@@ -323,7 +328,8 @@ func TestHttpCors(t *testing.T) {
 
 	ln, err := hss.ToListener()
 	assert.NoError(t, err)
-	s := hss.ToServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	s, _, err := hss.ToServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	require.NoError(t, err)
 	go func() {
 		_ = s.Serve(ln)
 	}()
@@ -372,11 +378,113 @@ func verifyCorsResp(t *testing.T, url string, origin string, wantStatus int, wan
 	assert.Equal(t, wantAllowMethods, gotAllowMethods)
 }
 
+func TestHttpServerAuthFailure(t *testing.T) {
+	hss := HTTPServerSettings{
+		Endpoint: "localhost:0",
+		Auth: &configauth.Authentication{
+			OIDC: &configauth.OIDC{
+				IssuerURL: "http://oidc.acme.invalid",
+				Audience:  "unit-test",
+			},
+		},
+	}
+
+	s, _, err := hss.ToServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	assert.Error(t, err)
+	assert.Nil(t, s)
+}
+
+func TestHttpServerIncludeMetadata(t *testing.T) {
+	hss := HTTPServerSettings{
+		IncludeMetadata: []string{"x-tenant"},
+	}
+
+	var c *client.Client
+	s, auth, err := hss.ToServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, _ = client.FromContext(r.Context())
+	}))
+	require.NoError(t, err)
+	assert.Nil(t, auth)
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.RemoteAddr = "192.168.1.2:1234"
+	req.Header.Set("X-Tenant", "acme")
+	req.Header.Set("X-Other", "value")
+	s.Handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, &client.Client{
+		IP:       "192.168.1.2",
+		Metadata: map[string][]string{"x-tenant": {"acme"}},
+	}, c)
+}
+
+func TestHttpServerAdmission(t *testing.T) {
+	hss := HTTPServerSettings{
+		Auth: &configauth.Authentication{
+			APIKeys: &configauth.APIKeys{File: path.Join("..", "configauth", "testdata", "api_keys")},
+		},
+		Admission: &configadmission.Admission{LimitMiB: 1},
+	}
+
+	s, auth, err := hss.ToServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	require.NoError(t, err)
+	require.NotNil(t, auth, "the authenticator must be returned to be closed")
+	defer auth.Close()
+
+	for _, tt := range []struct {
+		name         string
+		size         int
+		authorized   bool
+		expectedCode int
+	}{
+		{
+			name:         "admitted",
+			size:         1024,
+			authorized:   true,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "too large",
+			size:         1024*1024 + 1,
+			authorized:   true,
+			expectedCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:         "unauthenticated",
+			size:         1024*1024 + 1,
+			expectedCode: http.StatusUnauthorized,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat("a", tt.size)))
+			if tt.authorized {
+				req.Header.Set("Authorization", "Bearer 0d9cb8f4a2b34f4c")
+			}
+			rec := httptest.NewRecorder()
+			s.Handler.ServeHTTP(rec, req)
+			assert.Equal(t, tt.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestHttpServerAdmissionFailure(t *testing.T) {
+	hss := HTTPServerSettings{
+		Admission: &configadmission.Admission{},
+	}
+
+	s, _, err := hss.ToServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	assert.Error(t, err)
+	assert.Nil(t, s)
+}
+
 func ExampleHTTPServerSettings() {
 	settings := HTTPServerSettings{
 		Endpoint: ":443",
 	}
-	s := settings.ToServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	s, _, err := settings.ToServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	if err != nil {
+		panic(err)
+	}
 	l, err := settings.ToListener()
 	if err != nil {
 		panic(err)
@@ -425,3 +533,47 @@ func TestHttpHeaders(t *testing.T) {
 		})
 	}
 }
+
+func TestHttpClientAuth(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"some-token","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer tokenServer.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer some-token", r.Header.Get("Authorization"))
+		assert.Equal(t, "value1", r.Header.Get("header1"))
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	setting := HTTPClientSettings{
+		Endpoint: server.URL,
+		Headers: map[string]string{
+			"header1": "value1",
+		},
+		Auth: &configauth.ClientAuthentication{
+			OAuth2: &configauth.OAuth2ClientCredentials{
+				ClientID:     "some-client",
+				ClientSecret: "some-secret",
+				TokenURL:     tokenServer.URL,
+			},
+		},
+	}
+	client, err := setting.ToClient()
+	require.NoError(t, err)
+	resp, err := client.Get(setting.Endpoint)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+}
+
+func TestHttpClientAuthMissingSettings(t *testing.T) {
+	setting := HTTPClientSettings{
+		Auth: &configauth.ClientAuthentication{},
+	}
+	client, err := setting.ToClient()
+	assert.Error(t, err)
+	assert.Nil(t, client)
+}
@@ -0,0 +1,70 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package confighttp
+
+import (
+	"crypto/tls"
+	"net/http"
+	"sync"
+)
+
+// reloadableTLSTransport sends the requests with a transport using the current TLS config of
+// the client. The TLS config of a transport can't be replaced once in use, so the transport is
+// replaced by a new one when the config changes, e.g. when the CAs are reloaded, its idle
+// connections being closed.
+type reloadableTLSTransport struct {
+	base      *http.Transport
+	getConfig func() *tls.Config
+
+	mu        sync.Mutex
+	config    *tls.Config
+	transport *http.Transport
+}
+
+var _ http.RoundTripper = (*reloadableTLSTransport)(nil)
+
+func newReloadableTLSTransport(base *http.Transport, getConfig func() *tls.Config) *reloadableTLSTransport {
+	return &reloadableTLSTransport{base: base, getConfig: getConfig}
+}
+
+// current returns the transport of the current TLS config.
+func (t *reloadableTLSTransport) current() *http.Transport {
+	cfg := t.getConfig()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if cfg != t.config {
+		previous := t.transport
+		t.config = cfg
+		t.transport = t.base.Clone()
+		t.transport.TLSClientConfig = cfg
+		if previous != nil {
+			previous.CloseIdleConnections()
+		}
+	}
+	return t.transport
+}
+
+func (t *reloadableTLSTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.current().RoundTrip(req)
+}
+
+// CloseIdleConnections closes the idle connections of the current transport.
+func (t *reloadableTLSTransport) CloseIdleConnections() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.transport != nil {
+		t.transport.CloseIdleConnections()
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package confighttp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReloadableTLSTransport(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	trusted := &tls.Config{RootCAs: pool}
+	untrusted := &tls.Config{RootCAs: x509.NewCertPool()}
+
+	current := trusted
+	transport := newReloadableTLSTransport(http.DefaultTransport.(*http.Transport).Clone(), func() *tls.Config { return current })
+	client := &http.Client{Transport: transport}
+	get := func() error {
+		// the server certificate is verified against the IP address of the URL
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	require.NoError(t, get())
+	first := transport.current()
+	require.NoError(t, get())
+	assert.Same(t, first, transport.current(), "the transport is kept while the config doesn't change")
+
+	// the new connections use the new config, the idle ones being closed
+	current = untrusted
+	assert.Error(t, get())
+	current = trusted
+	assert.NoError(t, get())
+	transport.CloseIdleConnections()
+}
@@ -17,9 +17,12 @@ package configtls
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"sync"
+	"time"
 )
 
 // TLSSetting exposes the common client and server TLS configurations.
@@ -34,6 +37,28 @@ type TLSSetting struct {
 	CertFile string `mapstructure:"cert_file"`
 	// Path to the TLS key to use for TLS required connections. (optional)
 	KeyFile string `mapstructure:"key_file"`
+
+	// MinVersion is the minimum TLS version accepted, one of "1.0", "1.1", "1.2" or "1.3".
+	// If empty, the default minimum version of crypto/tls is used. (optional)
+	MinVersion string `mapstructure:"min_version"`
+	// MaxVersion is the maximum TLS version accepted, one of "1.0", "1.1", "1.2" or "1.3".
+	// If empty, the default maximum version of crypto/tls is used. (optional)
+	MaxVersion string `mapstructure:"max_version"`
+	// CipherSuites is the list of enabled TLS 1.0-1.2 cipher suites, by their crypto/tls names,
+	// e.g. "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256". The TLS 1.3 cipher suites aren't configurable.
+	// If empty, the default cipher suites of crypto/tls are used. (optional)
+	CipherSuites []string `mapstructure:"cipher_suites"`
+
+	// The files are reloaded when they change. ReloadInterval additionally reloads them once
+	// this duration has elapsed since the previous load, even if they didn't change. (optional)
+	ReloadInterval time.Duration `mapstructure:"reload_interval"`
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
 }
 
 // TLSClientSetting contains TLS configurations that are specific to client
@@ -68,7 +93,8 @@ type TLSServerSetting struct {
 	// These are config options specific to server connections.
 
 	// Path to the TLS cert to use by the server to verify a client certificate. (optional)
-	// This sets the ClientCAs and ClientAuth to RequireAndVerifyClientCert in the TLSConfig. Please refer to
+	// This sets the ClientCAs and requires a client certificate, verified against the current client CAs
+	// in VerifyPeerCertificate as they may be reloaded. Please refer to
 	// https://godoc.org/crypto/tls#Config for more information. (optional)
 	ClientCAFile string `mapstructure:"client_ca_file"`
 }
@@ -76,38 +102,80 @@ type TLSServerSetting struct {
 // LoadTLSConfig loads TLS certificates and returns a tls.Config.
 // This will set the RootCAs and Certificates of a tls.Config.
 func (c TLSSetting) loadTLSConfig() (*tls.Config, error) {
-	// There is no need to load the System Certs for RootCAs because
-	// if the value is nil, it will default to checking against th System Certs.
-	var err error
-	var certPool *x509.CertPool
-	if len(c.CAFile) != 0 {
-		// setup user specified truststore
-		certPool, err = c.loadCert(c.CAFile)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load CA CertPool: %w", err)
-		}
+	tlsCfg, _, err := c.loadReloadableTLSConfig("")
+	return tlsCfg, err
+}
+
+// loadReloadableTLSConfig returns the tls.Config along with the certReloader keeping the
+// certificates up to date, also loading the given client CA file.
+func (c TLSSetting) loadReloadableTLSConfig(clientCAFile string) (*tls.Config, *certReloader, error) {
+	minVersion, err := parseTLSVersion(c.MinVersion)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid min_version: %w", err)
+	}
+	maxVersion, err := parseTLSVersion(c.MaxVersion)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid max_version: %w", err)
+	}
+	if minVersion != 0 && maxVersion != 0 && minVersion > maxVersion {
+		return nil, nil, fmt.Errorf("min_version %q is greater than max_version %q", c.MinVersion, c.MaxVersion)
+	}
+	cipherSuites, err := parseCipherSuites(c.CipherSuites)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if (c.CertFile == "" && c.KeyFile != "") || (c.CertFile != "" && c.KeyFile == "") {
-		return nil, fmt.Errorf("for auth via TLS, either both certificate and key must be supplied, or neither")
+	reloader, err := newCertReloader(c, clientCAFile)
+	if err != nil {
+		return nil, nil, err
 	}
+	current := reloader.get()
 
-	var certificates []tls.Certificate
-	if c.CertFile != "" && c.KeyFile != "" {
-		tlsCert, err := tls.LoadX509KeyPair(filepath.Clean(c.CertFile), filepath.Clean(c.KeyFile))
-		if err != nil {
-			return nil, fmt.Errorf("failed to load TLS cert and key: %w", err)
-		}
-		certificates = append(certificates, tlsCert)
+	tlsCfg := &tls.Config{
+		RootCAs:      current.caPool,
+		MinVersion:   minVersion,
+		MaxVersion:   maxVersion,
+		CipherSuites: cipherSuites,
+	}
+	if current.certificate != nil {
+		tlsCfg.Certificates = []tls.Certificate{*current.certificate}
 	}
+	return tlsCfg, reloader, nil
+}
 
-	return &tls.Config{
-		RootCAs:      certPool,
-		Certificates: certificates,
-	}, nil
+func parseTLSVersion(version string) (uint16, error) {
+	if version == "" {
+		return 0, nil
+	}
+	if v, ok := tlsVersions[version]; ok {
+		return v, nil
+	}
+	return 0, fmt.Errorf("unsupported TLS version %q", version)
+}
+
+func parseCipherSuites(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	// only the cipher suites considered secure by crypto/tls can be enabled
+	supported := map[string]uint16{}
+	for _, suite := range tls.CipherSuites() {
+		supported[suite.Name] = suite.ID
+	}
+
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := supported[name]
+		if !ok {
+			return nil, fmt.Errorf("unsupported or insecure cipher suite %q", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
 }
 
-func (c TLSSetting) loadCert(caPath string) (*x509.CertPool, error) {
+func loadCertPool(caPath string) (*x509.CertPool, error) {
 	caPEM, err := ioutil.ReadFile(filepath.Clean(caPath))
 	if err != nil {
 		return nil, fmt.Errorf("failed to load CA %s: %w", caPath, err)
@@ -120,32 +188,110 @@ func (c TLSSetting) loadCert(caPath string) (*x509.CertPool, error) {
 	return certPool, nil
 }
 
+// LoadTLSConfig loads the TLS config of the client connections. The client certificate is reloaded
+// when its files change, but the server certificate is verified with the CAs loaded now: use
+// LoadReloadableTLSConfig to also follow the changes of the CA file.
 func (c TLSClientSetting) LoadTLSConfig() (*tls.Config, error) {
+	getConfig, err := c.LoadReloadableTLSConfig()
+	if err != nil || getConfig == nil {
+		return nil, err
+	}
+	return getConfig(), nil
+}
+
+// LoadReloadableTLSConfig returns a function providing the TLS config of the client connections,
+// or nil if TLS is disabled. The function returns the same config until the CA file is reloaded,
+// and then a new config verifying the server certificate with the reloaded CAs, as the RootCAs
+// of a config can't be replaced once it is in use. The callers use the config returned at the
+// time of each new connection.
+func (c TLSClientSetting) LoadReloadableTLSConfig() (func() *tls.Config, error) {
 	if c.Insecure && c.CAFile == "" {
 		return nil, nil
 	}
 
-	tlsCfg, err := c.TLSSetting.loadTLSConfig()
+	base, reloader, err := c.TLSSetting.loadReloadableTLSConfig("")
 	if err != nil {
 		return nil, fmt.Errorf("failed to load TLS config: %w", err)
 	}
-	tlsCfg.ServerName = c.ServerName
-	tlsCfg.InsecureSkipVerify = c.InsecureSkipVerify
-	return tlsCfg, nil
+	base.ServerName = c.ServerName
+	base.InsecureSkipVerify = c.InsecureSkipVerify
+
+	if c.CertFile != "" {
+		base.Certificates = nil
+		base.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return reloader.get().certificate, nil
+		}
+	}
+
+	var mu sync.Mutex
+	caPool := base.RootCAs
+	tlsCfg := base
+	return func() *tls.Config {
+		current := reloader.get().caPool
+		mu.Lock()
+		defer mu.Unlock()
+		if current != caPool {
+			caPool = current
+			tlsCfg = base.Clone()
+			tlsCfg.RootCAs = caPool
+		}
+		return tlsCfg
+	}, nil
 }
 
+// LoadTLSConfig loads the TLS config of the server. The server certificate is reloaded when its
+// files change, and the client certificates are verified with the current client CAs.
 func (c TLSServerSetting) LoadTLSConfig() (*tls.Config, error) {
-	tlsCfg, err := c.loadTLSConfig()
+	tlsCfg, reloader, err := c.loadReloadableTLSConfig(c.ClientCAFile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load TLS config: %w", err)
 	}
+
+	if c.CertFile != "" {
+		tlsCfg.Certificates = nil
+		tlsCfg.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return reloader.get().certificate, nil
+		}
+	}
+
 	if c.ClientCAFile != "" {
-		certPool, err := c.loadCert(c.ClientCAFile)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load TLS config: failed to load client CA CertPool: %w", err)
+		// the ClientCAs can't be replaced once the config is in use: a certificate is required
+		// from the clients, and verified against the current client CA pool instead. The resumed
+		// sessions skip this verification, and would keep a certificate the reloaded CAs don't
+		// trust anymore, so the sessions aren't resumed.
+		tlsCfg.ClientCAs = reloader.get().clientCAPool
+		tlsCfg.ClientAuth = tls.RequireAnyClientCert
+		tlsCfg.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return verifyClientCertificate(rawCerts, reloader.get().clientCAPool)
 		}
-		tlsCfg.ClientCAs = certPool
-		tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
+		tlsCfg.SessionTicketsDisabled = true
 	}
 	return tlsCfg, nil
 }
+
+func verifyClientCertificate(rawCerts [][]byte, roots *x509.CertPool) error {
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("tls: failed to parse client certificate: %w", err)
+		}
+		certs[i] = cert
+	}
+	if len(certs) == 0 {
+		return errors.New("tls: client didn't provide a certificate")
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	if _, err := certs[0].Verify(opts); err != nil {
+		return fmt.Errorf("tls: failed to verify client certificate: %w", err)
+	}
+	return nil
+}
@@ -15,6 +15,7 @@
 package configtls
 
 import (
+	"crypto/tls"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -107,6 +108,39 @@ func TestOptionsToConfig(t *testing.T) {
 				CAFile: "testdata/testCA.pem",
 			},
 		},
+		{
+			name: "should load TLS versions and cipher suites",
+			options: TLSSetting{
+				MinVersion:   "1.1",
+				MaxVersion:   "1.2",
+				CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+			},
+		},
+		{
+			name:        "should fail with invalid min version",
+			options:     TLSSetting{MinVersion: "1.4"},
+			expectError: "invalid min_version",
+		},
+		{
+			name:        "should fail with invalid max version",
+			options:     TLSSetting{MaxVersion: "TLSv1.2"},
+			expectError: "invalid max_version",
+		},
+		{
+			name:        "should fail with min version greater than max version",
+			options:     TLSSetting{MinVersion: "1.3", MaxVersion: "1.2"},
+			expectError: "is greater than max_version",
+		},
+		{
+			name:        "should fail with unknown cipher suite",
+			options:     TLSSetting{CipherSuites: []string{"TLS_UNKNOWN"}},
+			expectError: "unsupported or insecure cipher suite",
+		},
+		{
+			name:        "should fail with insecure cipher suite",
+			options:     TLSSetting{CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}},
+			expectError: "unsupported or insecure cipher suite",
+		},
 	}
 
 	for _, test := range tests {
@@ -179,3 +213,24 @@ func TestLoadTLSServerConfig(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, tlsCfg)
 }
+
+func TestTLSVersionsAndCipherSuites(t *testing.T) {
+	tlsSetting := TLSServerSetting{
+		TLSSetting: TLSSetting{
+			MinVersion:   "1.2",
+			MaxVersion:   "1.3",
+			CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384"},
+		},
+	}
+	tlsCfg, err := tlsSetting.LoadTLSConfig()
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), tlsCfg.MinVersion)
+	assert.Equal(t, uint16(tls.VersionTLS13), tlsCfg.MaxVersion)
+	assert.Equal(t, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384}, tlsCfg.CipherSuites)
+
+	tlsCfg, err = TLSClientSetting{}.LoadTLSConfig()
+	require.NoError(t, err)
+	assert.Zero(t, tlsCfg.MinVersion)
+	assert.Zero(t, tlsCfg.MaxVersion)
+	assert.Nil(t, tlsCfg.CipherSuites)
+}
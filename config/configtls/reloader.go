@@ -0,0 +1,165 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// fileCheckInterval is the minimum time between two checks of the files' modification times,
+// so that the files aren't looked up on every handshake.
+var fileCheckInterval = time.Second
+
+// certificates holds the certificate and CA pools loaded from the files of a TLS setting.
+type certificates struct {
+	certificate  *tls.Certificate
+	caPool       *x509.CertPool
+	clientCAPool *x509.CertPool
+}
+
+// certReloader loads the certificates, and reloads them when any of the files changes or when
+// the reload interval elapses. A failed reload keeps the previously loaded certificates.
+type certReloader struct {
+	caFile         string
+	certFile       string
+	keyFile        string
+	clientCAFile   string
+	reloadInterval time.Duration
+
+	mu        sync.Mutex
+	current   *certificates
+	modTimes  []time.Time
+	lastCheck time.Time
+	lastLoad  time.Time
+}
+
+func newCertReloader(c TLSSetting, clientCAFile string) (*certReloader, error) {
+	if (c.CertFile == "" && c.KeyFile != "") || (c.CertFile != "" && c.KeyFile == "") {
+		return nil, fmt.Errorf("for auth via TLS, either both certificate and key must be supplied, or neither")
+	}
+
+	r := &certReloader{
+		caFile:         c.CAFile,
+		certFile:       c.CertFile,
+		keyFile:        c.KeyFile,
+		clientCAFile:   clientCAFile,
+		reloadInterval: c.ReloadInterval,
+	}
+
+	// the modification times are read before loading the files, so that a change during the
+	// loading is picked up by the next check
+	modTimes := r.readModTimes()
+	current, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	r.current = current
+	r.modTimes = modTimes
+	r.lastCheck = now
+	r.lastLoad = now
+	return r, nil
+}
+
+// get returns the current certificates, reloading them first if needed.
+func (r *certReloader) get() *certificates {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(r.lastCheck) < fileCheckInterval {
+		return r.current
+	}
+	r.lastCheck = now
+
+	modTimes := r.readModTimes()
+	if !r.changed(modTimes) && (r.reloadInterval <= 0 || now.Sub(r.lastLoad) < r.reloadInterval) {
+		return r.current
+	}
+
+	if current, err := r.load(); err == nil {
+		r.current = current
+		r.modTimes = modTimes
+		r.lastLoad = now
+	}
+	return r.current
+}
+
+func (r *certReloader) files() []string {
+	return []string{r.caFile, r.certFile, r.keyFile, r.clientCAFile}
+}
+
+func (r *certReloader) readModTimes() []time.Time {
+	files := r.files()
+	modTimes := make([]time.Time, len(files))
+	for i, f := range files {
+		if f == "" {
+			continue
+		}
+		// a missing file is reported when loading it, the zero time is compared until then
+		if info, err := os.Stat(filepath.Clean(f)); err == nil {
+			modTimes[i] = info.ModTime()
+		}
+	}
+	return modTimes
+}
+
+func (r *certReloader) changed(modTimes []time.Time) bool {
+	for i := range modTimes {
+		if !modTimes[i].Equal(r.modTimes[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *certReloader) load() (*certificates, error) {
+	var err error
+	c := &certificates{}
+
+	// There is no need to load the System Certs for RootCAs because
+	// if the value is nil, it will default to checking against th System Certs.
+	if r.caFile != "" {
+		// setup user specified truststore
+		c.caPool, err = loadCertPool(r.caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load CA CertPool: %w", err)
+		}
+	}
+
+	if r.certFile != "" && r.keyFile != "" {
+		tlsCert, err := tls.LoadX509KeyPair(filepath.Clean(r.certFile), filepath.Clean(r.keyFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS cert and key: %w", err)
+		}
+		c.certificate = &tlsCert
+	}
+
+	if r.clientCAFile != "" {
+		c.clientCAPool, err = loadCertPool(r.clientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client CA CertPool: %w", err)
+		}
+	}
+
+	return c, nil
+}
@@ -0,0 +1,467 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configtls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key}
+}
+
+// writeCA writes the CA certificate to the given file
+func (ca *testCA) writeCA(t *testing.T, path string) {
+	writeFile(t, path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw}))
+}
+
+// writeLeaf writes a certificate for localhost and 127.0.0.1 signed by the CA, and its key, to the given files
+func (ca *testCA) writeLeaf(t *testing.T, name, certPath, keyPath string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	writeFile(t, certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	writeFile(t, keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+// writeFile writes the file with a modification time different from the previous one, as the
+// file system's resolution may be too coarse for successive writes to be detected
+func writeFile(t *testing.T, path string, content []byte) {
+	modTime := time.Now()
+	if info, err := os.Stat(path); err == nil && !info.ModTime().Before(modTime) {
+		modTime = info.ModTime().Add(time.Second)
+	}
+	require.NoError(t, ioutil.WriteFile(path, content, 0600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func newTestDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "configtls")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func withFileCheckInterval(t *testing.T, interval time.Duration) {
+	previous := fileCheckInterval
+	fileCheckInterval = interval
+	t.Cleanup(func() { fileCheckInterval = previous })
+}
+
+func leafCommonName(t *testing.T, c *tls.Certificate) string {
+	leaf, err := x509.ParseCertificate(c.Certificate[0])
+	require.NoError(t, err)
+	return leaf.Subject.CommonName
+}
+
+func TestCertReloaderReloadsChangedFiles(t *testing.T) {
+	withFileCheckInterval(t, 0)
+	dir := newTestDir(t)
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	ca := newTestCA(t, "ca")
+	ca.writeLeaf(t, "first", certFile, keyFile)
+
+	r, err := newCertReloader(TLSSetting{CertFile: certFile, KeyFile: keyFile}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "first", leafCommonName(t, r.get().certificate))
+
+	ca.writeLeaf(t, "second", certFile, keyFile)
+	assert.Equal(t, "second", leafCommonName(t, r.get().certificate))
+}
+
+func TestCertReloaderKeepsCertificatesOnFailure(t *testing.T) {
+	withFileCheckInterval(t, 0)
+	dir := newTestDir(t)
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	ca := newTestCA(t, "ca")
+	ca.writeLeaf(t, "first", certFile, keyFile)
+
+	r, err := newCertReloader(TLSSetting{CertFile: certFile, KeyFile: keyFile}, "")
+	require.NoError(t, err)
+
+	// only the certificate was written, it doesn't match the key
+	writeFile(t, certFile, []byte("invalid"))
+	assert.Equal(t, "first", leafCommonName(t, r.get().certificate))
+
+	// the reload is attempted again at the next check
+	ca.writeLeaf(t, "second", certFile, keyFile)
+	assert.Equal(t, "second", leafCommonName(t, r.get().certificate))
+}
+
+func TestCertReloaderChecksFilesAtMostOncePerInterval(t *testing.T) {
+	withFileCheckInterval(t, time.Hour)
+	dir := newTestDir(t)
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	ca := newTestCA(t, "ca")
+	ca.writeLeaf(t, "first", certFile, keyFile)
+
+	r, err := newCertReloader(TLSSetting{CertFile: certFile, KeyFile: keyFile}, "")
+	require.NoError(t, err)
+
+	ca.writeLeaf(t, "second", certFile, keyFile)
+	assert.Equal(t, "first", leafCommonName(t, r.get().certificate))
+}
+
+func TestCertReloaderReloadInterval(t *testing.T) {
+	withFileCheckInterval(t, 0)
+	dir := newTestDir(t)
+	caFile := filepath.Join(dir, "ca.pem")
+	newTestCA(t, "ca").writeCA(t, caFile)
+
+	r, err := newCertReloader(TLSSetting{CAFile: caFile}, "")
+	require.NoError(t, err)
+	first := r.get()
+	assert.Same(t, first, r.get(), "the files didn't change, they shouldn't be reloaded")
+
+	r.reloadInterval = time.Nanosecond
+	assert.NotSame(t, first, r.get(), "the files should be reloaded once the reload interval elapsed")
+}
+
+// handshake connects a client and a server over a loopback connection, buffered so that the
+// server can reject the client once it completed its side of the handshake
+func handshake(serverCfg, clientCfg *tls.Config) (tls.ConnectionState, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return tls.ConnectionState{}, err
+	}
+	defer ln.Close()
+
+	serverErr := make(chan error, 1)
+	serverState := make(chan tls.ConnectionState, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		defer conn.Close()
+		server := tls.Server(conn, serverCfg)
+		err = server.Handshake()
+		serverState <- server.ConnectionState()
+		serverErr <- err
+	}()
+
+	clientConn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		return tls.ConnectionState{}, err
+	}
+	defer clientConn.Close()
+	clientErr := tls.Client(clientConn, clientCfg).Handshake()
+	if clientErr != nil {
+		// unblock the server
+		clientConn.Close()
+	}
+	err = <-serverErr
+	if clientErr != nil {
+		return tls.ConnectionState{}, clientErr
+	}
+	return <-serverState, err
+}
+
+func TestHandshakeAfterRotation(t *testing.T) {
+	withFileCheckInterval(t, 0)
+	dir := newTestDir(t)
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	first := newTestCA(t, "first-ca")
+	first.writeCA(t, path("ca.pem"))
+	first.writeLeaf(t, "server", path("server.pem"), path("server-key.pem"))
+	first.writeLeaf(t, "client", path("client.pem"), path("client-key.pem"))
+
+	serverCfg, err := TLSServerSetting{
+		TLSSetting: TLSSetting{
+			CertFile: path("server.pem"),
+			KeyFile:  path("server-key.pem"),
+		},
+		ClientCAFile: path("ca.pem"),
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+	clientCfg, err := TLSClientSetting{
+		TLSSetting: TLSSetting{
+			CAFile:   path("ca.pem"),
+			CertFile: path("client.pem"),
+			KeyFile:  path("client-key.pem"),
+		},
+		ServerName: "localhost",
+	}.LoadReloadableTLSConfig()
+	require.NoError(t, err)
+	firstClientCfg := clientCfg()
+
+	state, err := handshake(serverCfg, firstClientCfg)
+	require.NoError(t, err)
+	require.Len(t, state.PeerCertificates, 1)
+	assert.Equal(t, "client", state.PeerCertificates[0].Subject.CommonName)
+
+	// all the certificates are replaced by ones issued by a new CA
+	second := newTestCA(t, "second-ca")
+	second.writeCA(t, path("ca.pem"))
+	second.writeLeaf(t, "server", path("server.pem"), path("server-key.pem"))
+	second.writeLeaf(t, "rotated-client", path("client.pem"), path("client-key.pem"))
+
+	// the config in use keeps verifying the server with the previous CA
+	_, err = handshake(serverCfg, firstClientCfg)
+	assert.Error(t, err)
+
+	state, err = handshake(serverCfg, clientCfg())
+	require.NoError(t, err)
+	require.Len(t, state.PeerCertificates, 1)
+	assert.Equal(t, "rotated-client", state.PeerCertificates[0].Subject.CommonName)
+	assert.Equal(t, "second-ca", state.PeerCertificates[0].Issuer.CommonName)
+}
+
+func TestHandshakeFailsWithUntrustedServer(t *testing.T) {
+	dir := newTestDir(t)
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	newTestCA(t, "trusted-ca").writeCA(t, path("ca.pem"))
+	newTestCA(t, "other-ca").writeLeaf(t, "server", path("server.pem"), path("server-key.pem"))
+
+	serverCfg, err := TLSServerSetting{
+		TLSSetting: TLSSetting{CertFile: path("server.pem"), KeyFile: path("server-key.pem")},
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name       string
+		serverName string
+	}{
+		{"untrusted CA", "localhost"},
+		{"no server name", ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			clientCfg, err := TLSClientSetting{
+				TLSSetting: TLSSetting{CAFile: path("ca.pem")},
+				ServerName: tt.serverName,
+			}.LoadTLSConfig()
+			require.NoError(t, err)
+
+			_, err = handshake(serverCfg, clientCfg)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestHandshakeFailsWithWrongServerName(t *testing.T) {
+	dir := newTestDir(t)
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	ca := newTestCA(t, "ca")
+	ca.writeCA(t, path("ca.pem"))
+	ca.writeLeaf(t, "server", path("server.pem"), path("server-key.pem"))
+
+	serverCfg, err := TLSServerSetting{
+		TLSSetting: TLSSetting{CertFile: path("server.pem"), KeyFile: path("server-key.pem")},
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+	clientCfg, err := TLSClientSetting{
+		TLSSetting: TLSSetting{CAFile: path("ca.pem")},
+		ServerName: "example.com",
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+
+	_, err = handshake(serverCfg, clientCfg)
+	assert.Error(t, err)
+}
+
+func TestHandshakeWithIPAddress(t *testing.T) {
+	dir := newTestDir(t)
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	ca := newTestCA(t, "ca")
+	ca.writeCA(t, path("ca.pem"))
+	ca.writeLeaf(t, "server", path("server.pem"), path("server-key.pem"))
+	ca.writeLeaf(t, "client", path("client.pem"), path("client-key.pem"))
+
+	serverCfg, err := TLSServerSetting{
+		TLSSetting:   TLSSetting{CertFile: path("server.pem"), KeyFile: path("server-key.pem")},
+		ClientCAFile: path("ca.pem"),
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+	clientCfg, err := TLSClientSetting{
+		TLSSetting: TLSSetting{CAFile: path("ca.pem"), CertFile: path("client.pem"), KeyFile: path("client-key.pem")},
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", serverCfg)
+	require.NoError(t, err)
+	defer ln.Close()
+	serverErr := make(chan error, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		defer conn.Close()
+		serverErr <- conn.(*tls.Conn).Handshake()
+	}()
+
+	// the server certificate is verified against the IP address dialed, which isn't sent as SNI
+	conn, err := tls.Dial("tcp", ln.Addr().String(), clientCfg)
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, "", conn.ConnectionState().ServerName)
+	assert.NoError(t, <-serverErr)
+}
+
+func TestHandshakeFailsWithUntrustedClient(t *testing.T) {
+	dir := newTestDir(t)
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	ca := newTestCA(t, "ca")
+	ca.writeCA(t, path("ca.pem"))
+	ca.writeLeaf(t, "server", path("server.pem"), path("server-key.pem"))
+	newTestCA(t, "other-ca").writeLeaf(t, "client", path("client.pem"), path("client-key.pem"))
+
+	serverCfg, err := TLSServerSetting{
+		TLSSetting:   TLSSetting{CertFile: path("server.pem"), KeyFile: path("server-key.pem")},
+		ClientCAFile: path("ca.pem"),
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name    string
+		setting TLSSetting
+	}{
+		{"untrusted CA", TLSSetting{CAFile: path("ca.pem"), CertFile: path("client.pem"), KeyFile: path("client-key.pem")}},
+		{"no certificate", TLSSetting{CAFile: path("ca.pem")}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			clientCfg, err := TLSClientSetting{TLSSetting: tt.setting, ServerName: "localhost"}.LoadTLSConfig()
+			require.NoError(t, err)
+
+			_, err = handshake(serverCfg, clientCfg)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestServerKeepsNextProtos(t *testing.T) {
+	dir := newTestDir(t)
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	ca := newTestCA(t, "ca")
+	ca.writeCA(t, path("ca.pem"))
+	ca.writeLeaf(t, "server", path("server.pem"), path("server-key.pem"))
+	ca.writeLeaf(t, "client", path("client.pem"), path("client-key.pem"))
+
+	serverCfg, err := TLSServerSetting{
+		TLSSetting:   TLSSetting{CertFile: path("server.pem"), KeyFile: path("server-key.pem")},
+		ClientCAFile: path("ca.pem"),
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+	serverCfg.NextProtos = []string{"h2"}
+	clientCfg, err := TLSClientSetting{
+		TLSSetting: TLSSetting{CAFile: path("ca.pem"), CertFile: path("client.pem"), KeyFile: path("client-key.pem")},
+		ServerName: "localhost",
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+
+	// only the protocols of the server are negotiated
+	clientCfg.NextProtos = []string{"unknown", "h2"}
+	state, err := handshake(serverCfg, clientCfg)
+	require.NoError(t, err)
+	assert.Equal(t, "h2", state.NegotiatedProtocol)
+
+	clientCfg.NextProtos = []string{"unknown"}
+	state, err = handshake(serverCfg, clientCfg)
+	if err == nil {
+		assert.Equal(t, "", state.NegotiatedProtocol)
+	}
+}
+
+func TestHandshakeDoesNotResumeSessionsOfUntrustedClients(t *testing.T) {
+	withFileCheckInterval(t, 0)
+	dir := newTestDir(t)
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	ca := newTestCA(t, "ca")
+	ca.writeCA(t, path("ca.pem"))
+	ca.writeCA(t, path("client-ca.pem"))
+	ca.writeLeaf(t, "server", path("server.pem"), path("server-key.pem"))
+	ca.writeLeaf(t, "client", path("client.pem"), path("client-key.pem"))
+
+	serverCfg, err := TLSServerSetting{
+		TLSSetting:   TLSSetting{CertFile: path("server.pem"), KeyFile: path("server-key.pem")},
+		ClientCAFile: path("client-ca.pem"),
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+	clientCfg, err := TLSClientSetting{
+		TLSSetting: TLSSetting{CAFile: path("ca.pem"), CertFile: path("client.pem"), KeyFile: path("client-key.pem")},
+		ServerName: "localhost",
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+	// the session tickets of TLS 1.2 are sent during the handshake
+	clientCfg.MaxVersion = tls.VersionTLS12
+	clientCfg.ClientSessionCache = tls.NewLRUClientSessionCache(1)
+
+	state, err := handshake(serverCfg, clientCfg)
+	require.NoError(t, err)
+	assert.False(t, state.DidResume)
+
+	// the client CA is replaced, the session of the client can't be resumed
+	newTestCA(t, "other-ca").writeCA(t, path("client-ca.pem"))
+	_, err = handshake(serverCfg, clientCfg)
+	assert.Error(t, err)
+}
@@ -24,7 +24,9 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenthelper"
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtelemetry"
 	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/obsreport"
 )
 
 var (
@@ -55,8 +57,13 @@ type request interface {
 	onPartialError(consumererror.PartialError) request
 	// Returns the count of spans/metric points or log records.
 	count() int
+	// marshal serializes the data of the request, used to persist the request in the sending queue.
+	marshal() ([]byte, error)
 }
 
+// requestUnmarshaler recreates a request from the bytes returned by request.marshal.
+type requestUnmarshaler func(bytes []byte) (request, error)
+
 // requestSender is an abstraction of a sender for a request independent of the type of the data (traces, metrics, logs).
 type requestSender interface {
 	send(req request) (int, error)
@@ -161,6 +168,7 @@ type baseExporter struct {
 	cfg                        configmodels.Exporter
 	sender                     requestSender
 	qrSender                   *queuedRetrySender
+	obsrep                     *obsreport.ExporterObsReport
 	convertResourceToTelemetry bool
 }
 
@@ -169,6 +177,7 @@ func newBaseExporter(cfg configmodels.Exporter, logger *zap.Logger, options ...O
 	be := &baseExporter{
 		Component:                  componenthelper.NewComponent(bs.ComponentSettings),
 		cfg:                        cfg,
+		obsrep:                     obsreport.NewExporterObsReport(configtelemetry.GetMetricsLevelFlagValue(), cfg.Name()),
 		convertResourceToTelemetry: bs.ResourceToTelemetrySettings.Enabled,
 	}
 
@@ -178,6 +187,11 @@ func newBaseExporter(cfg configmodels.Exporter, logger *zap.Logger, options ...O
 	return be
 }
 
+// setRequestUnmarshaler sets the function used to recreate requests read from the persistent sending queue.
+func (be *baseExporter) setRequestUnmarshaler(unmarshaler requestUnmarshaler) {
+	be.qrSender.unmarshaler = unmarshaler
+}
+
 // wrapConsumerSender wraps the consumer sender (the sender that uses retries and timeout) with the given wrapper.
 // This can be used to wrap with observability (create spans, record metrics) the consumer sender.
 func (be *baseExporter) wrapConsumerSender(f func(consumer requestSender) requestSender) {
@@ -186,14 +200,16 @@ func (be *baseExporter) wrapConsumerSender(f func(consumer requestSender) reques
 
 // Start all senders and exporter and is invoked during service start.
 func (be *baseExporter) Start(ctx context.Context, host component.Host) error {
+	// The results of the exports are reported to the host before any of them starts.
+	be.obsrep.ReportExportsTo(host)
+
 	// First start the wrapped exporter.
 	if err := be.Component.Start(ctx, host); err != nil {
 		return err
 	}
 
 	// If no error then start the queuedRetrySender.
-	be.qrSender.start()
-	return nil
+	return be.qrSender.start()
 }
 
 // Shutdown all senders and exporter and is invoked during service shutdown.
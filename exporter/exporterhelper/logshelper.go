@@ -21,7 +21,6 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
-	"go.opentelemetry.io/collector/config/configtelemetry"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/obsreport"
@@ -57,6 +56,21 @@ func (req *logsRequest) count() int {
 	return req.ld.LogRecordCount()
 }
 
+func (req *logsRequest) marshal() ([]byte, error) {
+	return req.ld.ToOtlpProtoBytes()
+}
+
+// newLogsRequestUnmarshaler returns a requestUnmarshaler that recreates logs requests for the given exporter.
+func newLogsRequestUnmarshaler(exporterName string, pusher PushLogs) requestUnmarshaler {
+	return func(bytes []byte) (request, error) {
+		ld := pdata.NewLogs()
+		if err := ld.FromOtlpProtoBytes(bytes); err != nil {
+			return nil, err
+		}
+		return newLogsRequest(obsreport.ExporterContext(context.Background(), exporterName), ld, pusher), nil
+	}
+}
+
 type logsExporter struct {
 	*baseExporter
 	pusher PushLogs
@@ -90,10 +104,11 @@ func NewLogsExporter(
 	be := newBaseExporter(cfg, logger, options...)
 	be.wrapConsumerSender(func(nextSender requestSender) requestSender {
 		return &logsExporterWithObservability{
-			obsrep:     obsreport.NewExporterObsReport(configtelemetry.GetMetricsLevelFlagValue(), cfg.Name()),
+			obsrep:     be.obsrep,
 			nextSender: nextSender,
 		}
 	})
+	be.setRequestUnmarshaler(newLogsRequestUnmarshaler(cfg.Name(), pusher))
 
 	return &logsExporter{
 		baseExporter: be,
@@ -21,7 +21,6 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
-	"go.opentelemetry.io/collector/config/configtelemetry"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/obsreport"
@@ -58,6 +57,21 @@ func (req *metricsRequest) count() int {
 	return numPoints
 }
 
+func (req *metricsRequest) marshal() ([]byte, error) {
+	return req.md.ToOtlpProtoBytes()
+}
+
+// newMetricsRequestUnmarshaler returns a requestUnmarshaler that recreates metrics requests for the given exporter.
+func newMetricsRequestUnmarshaler(exporterName string, pusher PushMetrics) requestUnmarshaler {
+	return func(bytes []byte) (request, error) {
+		md := pdata.NewMetrics()
+		if err := md.FromOtlpProtoBytes(bytes); err != nil {
+			return nil, err
+		}
+		return newMetricsRequest(obsreport.ExporterContext(context.Background(), exporterName), md, pusher), nil
+	}
+}
+
 type metricsExporter struct {
 	*baseExporter
 	pusher PushMetrics
@@ -95,10 +109,11 @@ func NewMetricsExporter(
 	be := newBaseExporter(cfg, logger, options...)
 	be.wrapConsumerSender(func(nextSender requestSender) requestSender {
 		return &metricsSenderWithObservability{
-			obsrep:     obsreport.NewExporterObsReport(configtelemetry.GetMetricsLevelFlagValue(), cfg.Name()),
+			obsrep:     be.obsrep,
 			nextSender: nextSender,
 		}
 	})
+	be.setRequestUnmarshaler(newMetricsRequestUnmarshaler(cfg.Name(), pusher))
 
 	return &metricsExporter{
 		baseExporter: be,
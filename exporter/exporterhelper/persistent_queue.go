@@ -0,0 +1,670 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterhelper
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// SyncPolicyAlways fsyncs the write-ahead log after every enqueued request.
+	SyncPolicyAlways = "always"
+	// SyncPolicyInterval fsyncs the write-ahead log periodically, see StorageSettings.SyncInterval.
+	SyncPolicyInterval = "interval"
+	// SyncPolicyNever never fsyncs the write-ahead log explicitly and relies on the OS to flush the data.
+	SyncPolicyNever = "never"
+
+	// CorruptionPolicyTruncate drops the corrupted record and the rest of the segment containing it.
+	CorruptionPolicyTruncate = "truncate"
+	// CorruptionPolicyFail fails the start of the exporter when a corrupted record is found.
+	CorruptionPolicyFail = "fail"
+
+	defaultSegmentSizeMiB = 16
+	defaultSyncInterval   = time.Second
+
+	segmentFileSuffix  = ".wal"
+	checkpointFileName = "checkpoint"
+	// Every record is prefixed by the length of the payload and its CRC32 (Castagnoli) checksum.
+	recordHeaderSize = 8
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// StorageSettings defines configuration for persisting the sending queue to a write-ahead log on local disk.
+// Requests enqueued but not yet sent are replayed when the exporter starts. Delivery is at-least-once:
+// requests that were being sent when the collector stopped may be sent again after a restart.
+type StorageSettings struct {
+	// Enabled indicates whether to persist enqueued batches on disk. Ignored if the sending queue is not enabled.
+	Enabled bool `mapstructure:"enabled"`
+	// Directory is the base directory of the write-ahead logs. Every exporter uses its own subdirectory.
+	Directory string `mapstructure:"directory"`
+	// MaxSizeMiB is the maximum size on disk of the write-ahead log, new batches are dropped once it is reached.
+	// Zero means that the size is only bounded by QueueSize.
+	MaxSizeMiB int64 `mapstructure:"max_size_mib"`
+	// SegmentSizeMiB is the size after which a new segment file is started. Segments are deleted once all
+	// their batches are sent. Default is 16 MiB.
+	SegmentSizeMiB int64 `mapstructure:"segment_size_mib"`
+	// SyncPolicy controls when the write-ahead log is fsynced: "always", "interval" or "never".
+	// Default is "interval".
+	SyncPolicy string `mapstructure:"sync_policy"`
+	// SyncInterval is the interval between fsyncs when SyncPolicy is "interval". Default is 1s.
+	SyncInterval time.Duration `mapstructure:"sync_interval"`
+	// OnCorruption controls what happens when a corrupted record is found while replaying the write-ahead log:
+	// "truncate" drops the rest of the affected segment, "fail" refuses to start the exporter. Default is "truncate".
+	OnCorruption string `mapstructure:"on_corruption"`
+}
+
+// segment is a single file of the write-ahead log.
+type segment struct {
+	id   uint64
+	path string
+	// size is the number of bytes written in the file.
+	size int64
+	// records is the number of records in the file that need to be sent.
+	records int
+	// read is the number of records handed to consumers.
+	read int
+	// acked is the number of records that were processed by consumers.
+	acked int
+	// readOffset is the offset of the next record to read.
+	readOffset int64
+	// inflight contains the offsets of the records handed to consumers but not yet processed.
+	inflight map[int64]struct{}
+	// sealed is true if no more records will be written to the segment.
+	sealed bool
+	reader *os.File
+}
+
+func (s *segment) done() bool {
+	return s.sealed && s.acked == s.records
+}
+
+// persistentQueue is a consumersQueue that stores all the items in a write-ahead log made of segment files.
+// Items are read back from disk by the consumers, so the memory usage does not depend on the queue size.
+type persistentQueue struct {
+	dir          string
+	capacity     int
+	maxBytes     int64
+	segmentBytes int64
+	syncPolicy   string
+	syncInterval time.Duration
+	onCorruption string
+	unmarshaler  requestUnmarshaler
+	logger       *zap.Logger
+
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	segments []*segment
+	writer   *os.File
+	nextID   uint64
+	// size is the number of records not yet handed to consumers.
+	size      int
+	diskBytes int64
+	dirty     bool
+	stopped   bool
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// newPersistentQueue opens, or creates, the write-ahead log of the given exporter and loads the requests
+// that were not sent by the previous run.
+func newPersistentQueue(exporterName string, capacity int, cfg StorageSettings, unmarshaler requestUnmarshaler, logger *zap.Logger) (*persistentQueue, error) {
+	if cfg.Directory == "" {
+		return nil, errors.New("sending_queue.storage.directory must be specified when storage is enabled")
+	}
+	if unmarshaler == nil {
+		return nil, errors.New("sending_queue.storage is not supported by this exporter")
+	}
+
+	pq := &persistentQueue{
+		dir:          filepath.Join(cfg.Directory, sanitizeExporterName(exporterName)),
+		capacity:     capacity,
+		maxBytes:     cfg.MaxSizeMiB * 1024 * 1024,
+		segmentBytes: cfg.SegmentSizeMiB * 1024 * 1024,
+		syncPolicy:   cfg.SyncPolicy,
+		syncInterval: cfg.SyncInterval,
+		onCorruption: cfg.OnCorruption,
+		unmarshaler:  unmarshaler,
+		logger:       logger,
+		stopCh:       make(chan struct{}),
+	}
+	pq.notEmpty = sync.NewCond(&pq.mu)
+	if pq.segmentBytes <= 0 {
+		pq.segmentBytes = defaultSegmentSizeMiB * 1024 * 1024
+	}
+	if pq.syncInterval <= 0 {
+		pq.syncInterval = defaultSyncInterval
+	}
+
+	switch pq.syncPolicy {
+	case "":
+		pq.syncPolicy = SyncPolicyInterval
+	case SyncPolicyAlways, SyncPolicyInterval, SyncPolicyNever:
+	default:
+		return nil, fmt.Errorf("unsupported sending_queue.storage.sync_policy %q", pq.syncPolicy)
+	}
+
+	switch pq.onCorruption {
+	case "":
+		pq.onCorruption = CorruptionPolicyTruncate
+	case CorruptionPolicyTruncate, CorruptionPolicyFail:
+	default:
+		return nil, fmt.Errorf("unsupported sending_queue.storage.on_corruption %q", pq.onCorruption)
+	}
+
+	if err := os.MkdirAll(pq.dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create sending queue directory: %w", err)
+	}
+	if err := pq.load(); err != nil {
+		pq.closeFiles()
+		return nil, err
+	}
+	if err := pq.openWriteSegment(); err != nil {
+		pq.closeFiles()
+		return nil, err
+	}
+	if pq.size > 0 {
+		pq.logger.Info("Replaying requests from persistent sending queue.",
+			zap.String("directory", pq.dir),
+			zap.Int("requests", pq.size))
+	}
+	return pq, nil
+}
+
+// sanitizeExporterName makes the full name of an exporter (e.g. "otlp/backend") usable as a directory name.
+func sanitizeExporterName(name string) string {
+	return strings.NewReplacer("/", "_", "\\", "_", ":", "_").Replace(name)
+}
+
+// load scans the existing segments, verifying every record, and resumes from the checkpoint if any.
+func (pq *persistentQueue) load() error {
+	ids, err := pq.listSegments()
+	if err != nil {
+		return err
+	}
+
+	checkpointID, checkpointOffset, hasCheckpoint := pq.readCheckpoint()
+	for _, id := range ids {
+		seg := &segment{
+			id:       id,
+			path:     pq.segmentPath(id),
+			sealed:   true,
+			inflight: map[int64]struct{}{},
+		}
+		start := int64(0)
+		if hasCheckpoint && id == checkpointID {
+			start = checkpointOffset
+		}
+		if err = pq.scanSegment(seg, start); err != nil {
+			return err
+		}
+		if id >= pq.nextID {
+			pq.nextID = id + 1
+		}
+		if seg.records == 0 {
+			if err = os.Remove(seg.path); err != nil {
+				return fmt.Errorf("failed to remove sent segment %q: %w", seg.path, err)
+			}
+			continue
+		}
+		pq.segments = append(pq.segments, seg)
+		pq.size += seg.records
+		pq.diskBytes += seg.size
+	}
+	return nil
+}
+
+func (pq *persistentQueue) listSegments() ([]uint64, error) {
+	files, err := ioutil.ReadDir(pq.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sending queue directory: %w", err)
+	}
+	var ids []uint64
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), segmentFileSuffix) {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimSuffix(f.Name(), segmentFileSuffix), 10, 64)
+		if err != nil {
+			pq.logger.Warn("Ignoring unknown file in sending queue directory.", zap.String("file", f.Name()))
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+// readCheckpoint returns the position, written during the last clean shutdown, before which all the records
+// were sent. The checkpoint is removed, since it is not maintained while running.
+func (pq *persistentQueue) readCheckpoint() (uint64, int64, bool) {
+	path := filepath.Join(pq.dir, checkpointFileName)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, 0, false
+	}
+	_ = os.Remove(path)
+	if len(data) != 16 {
+		pq.logger.Warn("Ignoring invalid sending queue checkpoint.")
+		return 0, 0, false
+	}
+	return binary.LittleEndian.Uint64(data[:8]), int64(binary.LittleEndian.Uint64(data[8:])), true
+}
+
+// scanSegment counts and verifies the records of the segment starting at the given offset.
+func (pq *persistentQueue) scanSegment(seg *segment, start int64) error {
+	f, err := os.OpenFile(seg.path, os.O_RDWR, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open segment %q: %w", seg.path, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to open segment %q: %w", seg.path, err)
+	}
+	seg.size = info.Size()
+	if start > seg.size {
+		start = 0
+	}
+	seg.readOffset = start
+
+	offset := start
+	for offset < seg.size {
+		n, err := readRecordLength(f, offset, seg.size)
+		if err != nil {
+			if pq.onCorruption == CorruptionPolicyFail {
+				return fmt.Errorf("corrupted record in segment %q at offset %d: %w", seg.path, offset, err)
+			}
+			pq.logger.Warn("Truncating corrupted segment of the persistent sending queue.",
+				zap.String("segment", seg.path),
+				zap.Int64("offset", offset),
+				zap.Error(err))
+			if err = f.Truncate(offset); err != nil {
+				return fmt.Errorf("failed to truncate segment %q: %w", seg.path, err)
+			}
+			seg.size = offset
+			break
+		}
+		offset += n
+		seg.records++
+	}
+	return nil
+}
+
+// readRecordLength verifies the record at the given offset and returns its total length, including the header.
+func readRecordLength(f *os.File, offset int64, size int64) (int64, error) {
+	if size-offset < recordHeaderSize {
+		return 0, errors.New("incomplete record header")
+	}
+	header := make([]byte, recordHeaderSize)
+	if _, err := f.ReadAt(header, offset); err != nil {
+		return 0, err
+	}
+	length := int64(binary.LittleEndian.Uint32(header[:4]))
+	if size-offset-recordHeaderSize < length {
+		return 0, errors.New("incomplete record")
+	}
+	payload := make([]byte, length)
+	if _, err := f.ReadAt(payload, offset+recordHeaderSize); err != nil {
+		return 0, err
+	}
+	if crc32.Checksum(payload, crcTable) != binary.LittleEndian.Uint32(header[4:]) {
+		return 0, errors.New("checksum mismatch")
+	}
+	return recordHeaderSize + length, nil
+}
+
+func (pq *persistentQueue) segmentPath(id uint64) string {
+	return filepath.Join(pq.dir, fmt.Sprintf("%020d%s", id, segmentFileSuffix))
+}
+
+// openWriteSegment creates a new segment that receives all new records. Must be called with the lock held.
+func (pq *persistentQueue) openWriteSegment() error {
+	seg := &segment{
+		id:       pq.nextID,
+		path:     pq.segmentPath(pq.nextID),
+		inflight: map[int64]struct{}{},
+	}
+	f, err := os.OpenFile(seg.path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create segment %q: %w", seg.path, err)
+	}
+	pq.nextID++
+	pq.writer = f
+	pq.segments = append(pq.segments, seg)
+	return nil
+}
+
+// rotate seals the current write segment and starts a new one. Must be called with the lock held.
+func (pq *persistentQueue) rotate() error {
+	if err := pq.writer.Sync(); err != nil {
+		return err
+	}
+	if err := pq.writer.Close(); err != nil {
+		return err
+	}
+	pq.dirty = false
+	ws := pq.segments[len(pq.segments)-1]
+	ws.sealed = true
+	if err := pq.openWriteSegment(); err != nil {
+		return err
+	}
+	pq.removeIfDone(ws)
+	return nil
+}
+
+// StartConsumers implements the consumersQueue interface.
+func (pq *persistentQueue) StartConsumers(num int, callback func(item interface{})) {
+	for i := 0; i < num; i++ {
+		pq.wg.Add(1)
+		go func() {
+			defer pq.wg.Done()
+			pq.consume(callback)
+		}()
+	}
+
+	if pq.syncPolicy == SyncPolicyInterval {
+		pq.wg.Add(1)
+		go func() {
+			defer pq.wg.Done()
+			pq.syncPeriodically()
+		}()
+	}
+}
+
+// Produce implements the consumersQueue interface.
+func (pq *persistentQueue) Produce(item interface{}) bool {
+	payload, err := item.(request).marshal()
+	if err != nil {
+		pq.logger.Error("Failed to serialize request for the persistent sending queue.", zap.Error(err))
+		return false
+	}
+
+	record := make([]byte, recordHeaderSize+len(payload))
+	binary.LittleEndian.PutUint32(record[:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(record[4:8], crc32.Checksum(payload, crcTable))
+	copy(record[recordHeaderSize:], payload)
+
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+
+	if pq.stopped || pq.size >= pq.capacity {
+		return false
+	}
+	if pq.maxBytes > 0 && pq.diskBytes+int64(len(record)) > pq.maxBytes {
+		return false
+	}
+
+	ws := pq.segments[len(pq.segments)-1]
+	if ws.size > 0 && ws.size+int64(len(record)) > pq.segmentBytes {
+		if err = pq.rotate(); err != nil {
+			pq.logger.Error("Failed to rotate segment of the persistent sending queue.", zap.Error(err))
+			return false
+		}
+		ws = pq.segments[len(pq.segments)-1]
+	}
+
+	if _, err = pq.writer.Write(record); err != nil {
+		pq.logger.Error("Failed to write to the persistent sending queue.", zap.Error(err))
+		// Drop a possibly partially written record, so the segment stays readable.
+		_ = pq.writer.Truncate(ws.size)
+		_, _ = pq.writer.Seek(ws.size, io.SeekStart)
+		return false
+	}
+	if pq.syncPolicy == SyncPolicyAlways {
+		if err = pq.writer.Sync(); err != nil {
+			pq.logger.Error("Failed to sync the persistent sending queue.", zap.Error(err))
+		}
+	} else {
+		pq.dirty = true
+	}
+
+	ws.size += int64(len(record))
+	ws.records++
+	pq.diskBytes += int64(len(record))
+	pq.size++
+	pq.notEmpty.Signal()
+	return true
+}
+
+func (pq *persistentQueue) consume(callback func(item interface{})) {
+	for {
+		pq.mu.Lock()
+		for pq.size == 0 && !pq.stopped {
+			pq.notEmpty.Wait()
+		}
+		if pq.stopped {
+			pq.mu.Unlock()
+			return
+		}
+		seg, offset, payload, err := pq.readNext()
+		pq.mu.Unlock()
+
+		if err != nil {
+			pq.logger.Error("Failed to read from the persistent sending queue. Dropping data.", zap.Error(err))
+			pq.ack(seg, offset)
+			continue
+		}
+
+		req, err := pq.unmarshaler(payload)
+		if err != nil {
+			pq.logger.Error("Failed to deserialize request from the persistent sending queue. Dropping data.", zap.Error(err))
+			pq.ack(seg, offset)
+			continue
+		}
+
+		callback(req)
+		pq.ack(seg, offset)
+	}
+}
+
+// readNext reads the oldest record not yet handed to consumers. Must be called with the lock held and size > 0.
+func (pq *persistentQueue) readNext() (*segment, int64, []byte, error) {
+	var seg *segment
+	for _, s := range pq.segments {
+		if s.read < s.records {
+			seg = s
+			break
+		}
+	}
+
+	offset := seg.readOffset
+	seg.inflight[offset] = struct{}{}
+	seg.read++
+	pq.size--
+
+	if seg.reader == nil {
+		f, err := os.Open(seg.path)
+		if err != nil {
+			pq.dropUnread(seg)
+			return seg, offset, nil, err
+		}
+		seg.reader = f
+	}
+
+	header := make([]byte, recordHeaderSize)
+	if _, err := seg.reader.ReadAt(header, offset); err != nil {
+		pq.dropUnread(seg)
+		return seg, offset, nil, err
+	}
+	// The header is not trusted until the checksum is verified, so the length is checked
+	// before allocating the payload.
+	length := int64(binary.LittleEndian.Uint32(header[:4]))
+	if length > seg.size-offset-recordHeaderSize {
+		pq.dropUnread(seg)
+		return seg, offset, nil, fmt.Errorf("invalid record length %d in segment %q at offset %d", length, seg.path, offset)
+	}
+	payload := make([]byte, length)
+	if _, err := seg.reader.ReadAt(payload, offset+recordHeaderSize); err != nil {
+		pq.dropUnread(seg)
+		return seg, offset, nil, err
+	}
+	if crc32.Checksum(payload, crcTable) != binary.LittleEndian.Uint32(header[4:]) {
+		// the offset of the next record can't be trusted either
+		pq.dropUnread(seg)
+		return seg, offset, nil, fmt.Errorf("checksum mismatch in segment %q at offset %d", seg.path, offset)
+	}
+	seg.readOffset = offset + recordHeaderSize + length
+	return seg, offset, payload, nil
+}
+
+// dropUnread drops the records of the segment that were not yet read, used when the segment
+// cannot be read anymore. If it is the segment being written, it is sealed, so that the new
+// records go to a readable one. Must be called with the lock held.
+func (pq *persistentQueue) dropUnread(seg *segment) {
+	unread := seg.records - seg.read
+	pq.size -= unread
+	seg.read += unread
+	seg.acked += unread
+	if !seg.sealed {
+		if err := pq.rotate(); err != nil {
+			pq.logger.Error("Failed to rotate segment of the persistent sending queue.", zap.Error(err))
+		}
+	}
+}
+
+// ack marks the record as processed, deleting the segment once all its records are processed.
+// Records processed after Stop is called are kept, because the consumers may have been interrupted
+// by the shutdown, and are sent again by the next run.
+func (pq *persistentQueue) ack(seg *segment, offset int64) {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	if pq.stopped {
+		return
+	}
+	delete(seg.inflight, offset)
+	seg.acked++
+	pq.removeIfDone(seg)
+}
+
+// removeIfDone deletes the segment if all its records were processed. Must be called with the lock held.
+func (pq *persistentQueue) removeIfDone(seg *segment) {
+	if !seg.done() {
+		return
+	}
+	if seg.reader != nil {
+		_ = seg.reader.Close()
+		seg.reader = nil
+	}
+	if err := os.Remove(seg.path); err != nil {
+		pq.logger.Error("Failed to remove sent segment of the persistent sending queue.", zap.Error(err))
+		return
+	}
+	pq.diskBytes -= seg.size
+	for i, s := range pq.segments {
+		if s == seg {
+			pq.segments = append(pq.segments[:i], pq.segments[i+1:]...)
+			break
+		}
+	}
+}
+
+func (pq *persistentQueue) syncPeriodically() {
+	ticker := time.NewTicker(pq.syncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			pq.mu.Lock()
+			if pq.dirty && !pq.stopped {
+				if err := pq.writer.Sync(); err != nil {
+					pq.logger.Error("Failed to sync the persistent sending queue.", zap.Error(err))
+				}
+				pq.dirty = false
+			}
+			pq.mu.Unlock()
+		case <-pq.stopCh:
+			return
+		}
+	}
+}
+
+// Stop implements the consumersQueue interface. Unlike the in-memory queue the remaining items are not
+// drained, they stay on disk and are sent by the next run.
+func (pq *persistentQueue) Stop() {
+	pq.mu.Lock()
+	pq.stopped = true
+	pq.notEmpty.Broadcast()
+	pq.mu.Unlock()
+	close(pq.stopCh)
+	pq.wg.Wait()
+
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	if err := pq.writer.Sync(); err != nil {
+		pq.logger.Error("Failed to sync the persistent sending queue.", zap.Error(err))
+	}
+	pq.writeCheckpoint()
+	pq.closeFiles()
+}
+
+// writeCheckpoint records the position before which all the records of the oldest segment were processed,
+// so the next run does not send them again. Must be called with the lock held.
+func (pq *persistentQueue) writeCheckpoint() {
+	if len(pq.segments) == 0 {
+		return
+	}
+	seg := pq.segments[0]
+	offset := seg.readOffset
+	for o := range seg.inflight {
+		if o < offset {
+			offset = o
+		}
+	}
+	if offset == 0 {
+		return
+	}
+	data := make([]byte, 16)
+	binary.LittleEndian.PutUint64(data[:8], seg.id)
+	binary.LittleEndian.PutUint64(data[8:], uint64(offset))
+	if err := ioutil.WriteFile(filepath.Join(pq.dir, checkpointFileName), data, 0600); err != nil {
+		pq.logger.Error("Failed to write checkpoint of the persistent sending queue.", zap.Error(err))
+	}
+}
+
+func (pq *persistentQueue) closeFiles() {
+	if pq.writer != nil {
+		_ = pq.writer.Close()
+	}
+	for _, seg := range pq.segments {
+		if seg.reader != nil {
+			_ = seg.reader.Close()
+			seg.reader = nil
+		}
+	}
+}
+
+// Size implements the consumersQueue interface.
+func (pq *persistentQueue) Size() int {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	return pq.size
+}
@@ -0,0 +1,233 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterhelper
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/testdata"
+)
+
+func newStorageQueueSettings(t *testing.T) QueueSettings {
+	dir, err := ioutil.TempDir("", "sending_queue")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	qCfg := DefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	qCfg.Storage = StorageSettings{
+		Enabled:    true,
+		Directory:  dir,
+		SyncPolicy: SyncPolicyAlways,
+	}
+	return qCfg
+}
+
+func TestPersistentQueue_ReplayAfterRestart(t *testing.T) {
+	qCfg := newStorageQueueSettings(t)
+	rCfg := DefaultRetrySettings()
+	rCfg.InitialInterval = 10 * time.Millisecond
+
+	var attempts int64
+	failing, err := NewTraceExporter(defaultExporterCfg, zap.NewNop(), func(ctx context.Context, td pdata.Traces) (int, error) {
+		atomic.AddInt64(&attempts, 1)
+		return td.SpanCount(), errors.New("backend unavailable")
+	}, WithQueue(qCfg), WithRetry(rCfg))
+	require.NoError(t, err)
+	require.NoError(t, failing.Start(context.Background(), componenttest.NewNopHost()))
+	for i := 0; i < 3; i++ {
+		require.NoError(t, failing.ConsumeTraces(context.Background(), testdata.GenerateTraceDataTwoSpansSameResource()))
+	}
+	assert.Eventually(t, func() bool { return atomic.LoadInt64(&attempts) > 0 }, time.Second, time.Millisecond)
+	require.NoError(t, failing.Shutdown(context.Background()))
+
+	var spans int64
+	working, err := NewTraceExporter(defaultExporterCfg, zap.NewNop(), func(ctx context.Context, td pdata.Traces) (int, error) {
+		atomic.AddInt64(&spans, int64(td.SpanCount()))
+		return 0, nil
+	}, WithQueue(qCfg), WithRetry(rCfg))
+	require.NoError(t, err)
+	require.NoError(t, working.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Eventually(t, func() bool { return atomic.LoadInt64(&spans) == 6 }, time.Second, time.Millisecond)
+	require.NoError(t, working.Shutdown(context.Background()))
+}
+
+func TestPersistentQueue_DeletesSentSegments(t *testing.T) {
+	qCfg := newStorageQueueSettings(t)
+	unmarshaler := newTracesRequestUnmarshaler("test", func(context.Context, pdata.Traces) (int, error) { return 0, nil })
+	pq, err := newPersistentQueue("test", qCfg.QueueSize, qCfg.Storage, unmarshaler, zap.NewNop())
+	require.NoError(t, err)
+	// Start a new segment for every request.
+	pq.segmentBytes = 1
+
+	for i := 0; i < 5; i++ {
+		req := newTracesRequest(context.Background(), testdata.GenerateTraceDataOneSpan(), nil)
+		require.True(t, pq.Produce(req))
+	}
+	assert.Equal(t, 5, pq.Size())
+
+	var consumed int64
+	pq.StartConsumers(2, func(item interface{}) {
+		atomic.AddInt64(&consumed, int64(item.(request).count()))
+	})
+	assert.Eventually(t, func() bool { return atomic.LoadInt64(&consumed) == 5 }, time.Second, time.Millisecond)
+	assert.Eventually(t, func() bool {
+		files, err := filepath.Glob(filepath.Join(pq.dir, "*"+segmentFileSuffix))
+		require.NoError(t, err)
+		// Only the segment that is being written remains.
+		return len(files) == 1
+	}, time.Second, time.Millisecond)
+	pq.Stop()
+}
+
+func TestPersistentQueue_Corruption(t *testing.T) {
+	qCfg := newStorageQueueSettings(t)
+	unmarshaler := newTracesRequestUnmarshaler("test", func(context.Context, pdata.Traces) (int, error) { return 0, nil })
+	pq, err := newPersistentQueue("test", qCfg.QueueSize, qCfg.Storage, unmarshaler, zap.NewNop())
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		require.True(t, pq.Produce(newTracesRequest(context.Background(), testdata.GenerateTraceDataOneSpan(), nil)))
+	}
+	pq.Stop()
+
+	// Simulate a crash in the middle of a write.
+	f, err := os.OpenFile(pq.segmentPath(0), os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{0xff, 0xff, 0x00})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	qCfg.Storage.OnCorruption = CorruptionPolicyFail
+	_, err = newPersistentQueue("test", qCfg.QueueSize, qCfg.Storage, unmarshaler, zap.NewNop())
+	require.Error(t, err)
+
+	qCfg.Storage.OnCorruption = CorruptionPolicyTruncate
+	pq, err = newPersistentQueue("test", qCfg.QueueSize, qCfg.Storage, unmarshaler, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, 2, pq.Size())
+	pq.Stop()
+}
+
+func TestPersistentQueue_CorruptionWhileReading(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		offset int64
+		data   []byte
+	}{
+		{
+			name: "invalid length",
+			data: []byte{0xff, 0xff, 0xff, 0xff},
+		},
+		{
+			name:   "checksum mismatch",
+			offset: recordHeaderSize,
+			data:   []byte{0xff},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			qCfg := newStorageQueueSettings(t)
+			unmarshaler := newTracesRequestUnmarshaler("test", func(context.Context, pdata.Traces) (int, error) { return 0, nil })
+			pq, err := newPersistentQueue("test", qCfg.QueueSize, qCfg.Storage, unmarshaler, zap.NewNop())
+			require.NoError(t, err)
+			defer pq.Stop()
+			for i := 0; i < 3; i++ {
+				require.True(t, pq.Produce(newTracesRequest(context.Background(), testdata.GenerateTraceDataOneSpan(), nil)))
+			}
+
+			// Corrupt the first record after it was written.
+			f, err := os.OpenFile(pq.segmentPath(0), os.O_WRONLY, 0600)
+			require.NoError(t, err)
+			_, err = f.WriteAt(tt.data, tt.offset)
+			require.NoError(t, err)
+			require.NoError(t, f.Close())
+
+			pq.mu.Lock()
+			_, _, payload, err := pq.readNext()
+			pq.mu.Unlock()
+
+			// The following records are dropped, since their offsets can't be trusted.
+			assert.Error(t, err)
+			assert.Nil(t, payload)
+			assert.Equal(t, 0, pq.Size())
+		})
+	}
+}
+
+func TestPersistentQueue_CorruptionOfWriteSegment(t *testing.T) {
+	qCfg := newStorageQueueSettings(t)
+	unmarshaler := newTracesRequestUnmarshaler("test", func(context.Context, pdata.Traces) (int, error) { return 0, nil })
+	pq, err := newPersistentQueue("test", qCfg.QueueSize, qCfg.Storage, unmarshaler, zap.NewNop())
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		require.True(t, pq.Produce(newTracesRequest(context.Background(), testdata.GenerateTraceDataOneSpan(), nil)))
+	}
+
+	// Corrupt the first record of the segment still being written.
+	f, err := os.OpenFile(pq.segmentPath(0), os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = f.WriteAt([]byte{0xff}, recordHeaderSize)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	var consumed int64
+	pq.StartConsumers(1, func(item interface{}) {
+		atomic.AddInt64(&consumed, int64(item.(request).count()))
+	})
+	assert.Eventually(t, func() bool { return pq.Size() == 0 }, time.Second, time.Millisecond)
+
+	// The records written after the corruption go to a new segment and are delivered.
+	for i := 0; i < 3; i++ {
+		require.True(t, pq.Produce(newTracesRequest(context.Background(), testdata.GenerateTraceDataOneSpan(), nil)))
+	}
+	assert.Eventually(t, func() bool { return atomic.LoadInt64(&consumed) == 3 }, time.Second, time.Millisecond)
+	pq.Stop()
+}
+
+func TestPersistentQueue_InvalidSettings(t *testing.T) {
+	qCfg := newStorageQueueSettings(t)
+	unmarshaler := newTracesRequestUnmarshaler("test", nil)
+
+	cfg := qCfg.Storage
+	cfg.Directory = ""
+	_, err := newPersistentQueue("test", qCfg.QueueSize, cfg, unmarshaler, zap.NewNop())
+	assert.Error(t, err)
+
+	cfg = qCfg.Storage
+	cfg.SyncPolicy = "sometimes"
+	_, err = newPersistentQueue("test", qCfg.QueueSize, cfg, unmarshaler, zap.NewNop())
+	assert.Error(t, err)
+
+	cfg = qCfg.Storage
+	cfg.OnCorruption = "ignore"
+	_, err = newPersistentQueue("test", qCfg.QueueSize, cfg, unmarshaler, zap.NewNop())
+	assert.Error(t, err)
+}
+
+func TestSanitizeExporterName(t *testing.T) {
+	assert.Equal(t, "otlp_backend", sanitizeExporterName("otlp/backend"))
+}
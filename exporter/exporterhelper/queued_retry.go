@@ -38,6 +38,8 @@ type QueueSettings struct {
 	NumConsumers int `mapstructure:"num_consumers"`
 	// QueueSize is the maximum number of batches allowed in queue at a given time.
 	QueueSize int `mapstructure:"queue_size"`
+	// Storage configures persisting the queue to local disk, so that enqueued batches survive restarts.
+	Storage StorageSettings `mapstructure:"storage"`
 }
 
 // DefaultQueueSettings returns the default settings for QueueSettings.
@@ -78,10 +80,25 @@ func DefaultRetrySettings() RetrySettings {
 	}
 }
 
+// consumersQueue is the queue used by the queuedRetrySender, it is satisfied by
+// the in-memory queue.BoundedQueue and by the persistentQueue.
+type consumersQueue interface {
+	// StartConsumers starts the given number of consumers, each of them calling the callback for every item.
+	StartConsumers(num int, callback func(item interface{}))
+	// Produce adds an item to the queue, returns false if the item was dropped.
+	Produce(item interface{}) bool
+	// Stop stops all consumers.
+	Stop()
+	// Size returns the current number of items in the queue.
+	Size() int
+}
+
 type queuedRetrySender struct {
+	fullName        string
 	cfg             QueueSettings
 	consumerSender  requestSender
-	queue           *queue.BoundedQueue
+	queue           consumersQueue
+	unmarshaler     requestUnmarshaler
 	retryStopCh     chan struct{}
 	traceAttributes []trace.Attribute
 	logger          *zap.Logger
@@ -111,7 +128,8 @@ func newQueuedRetrySender(fullName string, qCfg QueueSettings, rCfg RetrySetting
 	sampledLogger := createSampledLogger(logger)
 	traceAttr := trace.StringAttribute(obsreport.ExporterKey, fullName)
 	return &queuedRetrySender{
-		cfg: qCfg,
+		fullName: fullName,
+		cfg:      qCfg,
 		consumerSender: &retrySender{
 			traceAttribute: traceAttr,
 			cfg:            rCfg,
@@ -127,11 +145,19 @@ func newQueuedRetrySender(fullName string, qCfg QueueSettings, rCfg RetrySetting
 }
 
 // start is invoked during service startup.
-func (qrs *queuedRetrySender) start() {
+func (qrs *queuedRetrySender) start() error {
+	if qrs.cfg.Enabled && qrs.cfg.Storage.Enabled {
+		pq, err := newPersistentQueue(qrs.fullName, qrs.cfg.QueueSize, qrs.cfg.Storage, qrs.unmarshaler, qrs.logger)
+		if err != nil {
+			return err
+		}
+		qrs.queue = pq
+	}
 	qrs.queue.StartConsumers(qrs.cfg.NumConsumers, func(item interface{}) {
 		req := item.(request)
 		_, _ = qrs.consumerSender.send(req)
 	})
+	return nil
 }
 
 // send implements the requestSender interface
@@ -370,6 +370,10 @@ func (mer *mockErrorRequest) count() int {
 	return 7
 }
 
+func (mer *mockErrorRequest) marshal() ([]byte, error) {
+	return nil, errors.New("not supported")
+}
+
 func newErrorRequest(ctx context.Context) request {
 	return &mockErrorRequest{
 		baseRequest: baseRequest{ctx: ctx},
@@ -416,6 +420,10 @@ func (m *mockRequest) count() int {
 	return m.cnt
 }
 
+func (m *mockRequest) marshal() ([]byte, error) {
+	return nil, errors.New("not supported")
+}
+
 func newMockRequest(ctx context.Context, cnt int, consumeError error) *mockRequest {
 	return &mockRequest{
 		baseRequest:  baseRequest{ctx: ctx},
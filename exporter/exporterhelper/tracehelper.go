@@ -21,7 +21,6 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
-	"go.opentelemetry.io/collector/config/configtelemetry"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/obsreport"
@@ -57,6 +56,21 @@ func (req *tracesRequest) count() int {
 	return req.td.SpanCount()
 }
 
+func (req *tracesRequest) marshal() ([]byte, error) {
+	return req.td.ToOtlpProtoBytes()
+}
+
+// newTracesRequestUnmarshaler returns a requestUnmarshaler that recreates traces requests for the given exporter.
+func newTracesRequestUnmarshaler(exporterName string, pusher PushTraces) requestUnmarshaler {
+	return func(bytes []byte) (request, error) {
+		td := pdata.NewTraces()
+		if err := td.FromOtlpProtoBytes(bytes); err != nil {
+			return nil, err
+		}
+		return newTracesRequest(obsreport.ExporterContext(context.Background(), exporterName), td, pusher), nil
+	}
+}
+
 type traceExporter struct {
 	*baseExporter
 	pusher PushTraces
@@ -92,10 +106,11 @@ func NewTraceExporter(
 	be := newBaseExporter(cfg, logger, options...)
 	be.wrapConsumerSender(func(nextSender requestSender) requestSender {
 		return &tracesExporterWithObservability{
-			obsrep:     obsreport.NewExporterObsReport(configtelemetry.GetMetricsLevelFlagValue(), cfg.Name()),
+			obsrep:     be.obsrep,
 			nextSender: nextSender,
 		}
 	})
+	be.setRequestUnmarshaler(newTracesRequestUnmarshaler(cfg.Name(), pusher))
 
 	return &traceExporter{
 		baseExporter: be,
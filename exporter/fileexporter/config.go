@@ -15,13 +15,65 @@
 package fileexporter
 
 import (
+	"time"
+
 	"go.opentelemetry.io/collector/config/configmodels"
 )
 
+const (
+	// FormatJSON writes each request as a line of Protobuf JSON.
+	FormatJSON = "json"
+	// FormatProto writes each request as binary Protobuf, prefixed by the key
+	// of its data type and its size, encoded as varints. The file is then a
+	// Protobuf message whose fields of number TracesFieldNumber,
+	// MetricsFieldNumber and LogsFieldNumber are the requests of each type.
+	FormatProto = "proto"
+)
+
+// The field numbers identifying the data type of the requests written in the
+// proto format, so that the traces, metrics and logs written to the same file
+// can be told apart.
+const (
+	TracesFieldNumber  = 1
+	MetricsFieldNumber = 2
+	LogsFieldNumber    = 3
+)
+
 // Config defines configuration for file exporter.
 type Config struct {
 	configmodels.ExporterSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
 
 	// Path of the file to write to. Path is relative to current directory.
 	Path string `mapstructure:"path"`
+
+	// Format is the encoding of the requests written to the file, json (the
+	// default) or proto.
+	Format string `mapstructure:"format"`
+
+	// Rotation enables the rotation of the file. The file is truncated when
+	// the exporter starts if not set.
+	Rotation *Rotation `mapstructure:"rotation"`
+
+	// FlushInterval enables buffering the writes, flushed at this interval. The
+	// requests are written directly to the file if not set.
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+}
+
+// Rotation defines when the file is rotated, and how the rotated files, named
+// after the file with the time of the rotation, are kept.
+type Rotation struct {
+	// MaxMegabytes is the size of the file, in megabytes, above which it is
+	// rotated. No size limit if not set.
+	MaxMegabytes int `mapstructure:"max_megabytes"`
+
+	// Interval is the time after which the file is rotated. No time limit if
+	// not set.
+	Interval time.Duration `mapstructure:"interval"`
+
+	// MaxBackups is the number of rotated files kept. All the rotated files
+	// are kept if not set.
+	MaxBackups int `mapstructure:"max_backups"`
+
+	// Compress enables compressing the rotated files with gzip.
+	Compress bool `mapstructure:"compress"`
 }
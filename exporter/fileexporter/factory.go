@@ -16,7 +16,9 @@ package fileexporter
 
 import (
 	"context"
-	"os"
+	"fmt"
+
+	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
@@ -44,34 +46,35 @@ func createDefaultConfig() configmodels.Exporter {
 			TypeVal: typeStr,
 			NameVal: typeStr,
 		},
+		Format: FormatJSON,
 	}
 }
 
 func createTraceExporter(
 	_ context.Context,
-	_ component.ExporterCreateParams,
+	params component.ExporterCreateParams,
 	cfg configmodels.Exporter,
 ) (component.TracesExporter, error) {
-	return createExporter(cfg)
+	return createExporter(params.Logger, cfg)
 }
 
 func createMetricsExporter(
 	_ context.Context,
-	_ component.ExporterCreateParams,
+	params component.ExporterCreateParams,
 	cfg configmodels.Exporter,
 ) (component.MetricsExporter, error) {
-	return createExporter(cfg)
+	return createExporter(params.Logger, cfg)
 }
 
 func createLogsExporter(
 	_ context.Context,
-	_ component.ExporterCreateParams,
+	params component.ExporterCreateParams,
 	cfg configmodels.Exporter,
 ) (component.LogsExporter, error) {
-	return createExporter(cfg)
+	return createExporter(params.Logger, cfg)
 }
 
-func createExporter(config configmodels.Exporter) (*fileExporter, error) {
+func createExporter(logger *zap.Logger, config configmodels.Exporter) (*fileExporter, error) {
 	cfg := config.(*Config)
 
 	// There must be one exporter for metrics, traces, and logs. We maintain a
@@ -81,11 +84,17 @@ func createExporter(config configmodels.Exporter) (*fileExporter, error) {
 	exporter, ok := exporters[cfg]
 
 	if !ok {
-		file, err := os.OpenFile(cfg.Path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+		switch cfg.Format {
+		case "", FormatJSON, FormatProto:
+		default:
+			return nil, fmt.Errorf("unknown format %q, must be %q or %q", cfg.Format, FormatJSON, FormatProto)
+		}
+
+		file, err := newFileWriter(logger, cfg)
 		if err != nil {
 			return nil, err
 		}
-		exporter = &fileExporter{file: file}
+		exporter = &fileExporter{file: file, format: cfg.Format}
 
 		// Remember the receiver in the map
 		exporters[cfg] = exporter
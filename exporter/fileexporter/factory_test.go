@@ -62,3 +62,16 @@ func TestCreateLogsExporter(t *testing.T) {
 	assert.Error(t, err)
 	require.Nil(t, exp)
 }
+
+func TestCreateExporterInvalidFormat(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Path = "unused.txt"
+	cfg.Format = "yaml"
+
+	exp, err := createTraceExporter(
+		context.Background(),
+		component.ExporterCreateParams{Logger: zap.NewNop()},
+		cfg)
+	assert.Error(t, err)
+	require.Nil(t, exp)
+}
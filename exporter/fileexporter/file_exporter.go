@@ -15,7 +15,9 @@
 package fileexporter
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
 	"io"
 	"sync"
 
@@ -34,44 +36,76 @@ import (
 var marshaler = &jsonpb.Marshaler{}
 
 // fileExporter is the implementation of file exporter that writes telemetry data to a file
-// in Protobuf-JSON format.
+// in Protobuf-JSON or length-delimited Protobuf format.
 type fileExporter struct {
-	file  io.WriteCloser
-	mutex sync.Mutex
+	file   io.WriteCloser
+	format string
+	mutex  sync.Mutex
 }
 
 func (e *fileExporter) ConsumeTraces(_ context.Context, td pdata.Traces) error {
 	request := otlptrace.ExportTraceServiceRequest{
 		ResourceSpans: pdata.TracesToOtlp(td),
 	}
-	return exportMessageAsLine(e, &request)
+	return e.exportMessage(TracesFieldNumber, &request)
 }
 
 func (e *fileExporter) ConsumeMetrics(_ context.Context, md pdata.Metrics) error {
 	request := otlpmetrics.ExportMetricsServiceRequest{
 		ResourceMetrics: pdata.MetricsToOtlp(md),
 	}
-	return exportMessageAsLine(e, &request)
+	return e.exportMessage(MetricsFieldNumber, &request)
 }
 
 func (e *fileExporter) ConsumeLogs(_ context.Context, ld pdata.Logs) error {
 	request := otlplogs.ExportLogsServiceRequest{
 		ResourceLogs: internal.LogsToOtlp(ld.InternalRep()),
 	}
-	return exportMessageAsLine(e, &request)
+	return e.exportMessage(LogsFieldNumber, &request)
 }
 
-func exportMessageAsLine(e *fileExporter, message proto.Message) error {
+// exportMessage writes the message, the field number identifying its data type
+// in the proto format.
+func (e *fileExporter) exportMessage(fieldNumber int, message proto.Message) error {
+	var buf []byte
+	var err error
+	if e.format == FormatProto {
+		buf, err = marshalMessageDelimited(fieldNumber, message)
+	} else {
+		buf, err = marshalMessageAsLine(message)
+	}
+	if err != nil {
+		return err
+	}
+
 	// Ensure only one write operation happens at a time.
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
-	if err := marshaler.Marshal(e.file, message); err != nil {
-		return err
+	_, err = e.file.Write(buf)
+	return err
+}
+
+// marshalMessageAsLine encodes the message as a line of Protobuf JSON.
+func marshalMessageAsLine(message proto.Message) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := marshaler.Marshal(&buf, message); err != nil {
+		return nil, err
 	}
-	if _, err := io.WriteString(e.file, "\n"); err != nil {
-		return err
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// marshalMessageDelimited encodes the message as binary Protobuf, prefixed by
+// the key of the given field and its size, like a length-delimited field.
+func marshalMessageDelimited(fieldNumber int, message proto.Message) ([]byte, error) {
+	data, err := proto.Marshal(message)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	buf := make([]byte, 2*binary.MaxVarintLen64, 2*binary.MaxVarintLen64+len(data))
+	n := binary.PutUvarint(buf, uint64(fieldNumber)<<3|proto.WireBytes)
+	n += binary.PutUvarint(buf[n:], uint64(len(data)))
+	return append(buf[:n], data...), nil
 }
 
 func (e *fileExporter) Start(ctx context.Context, host component.Host) error {
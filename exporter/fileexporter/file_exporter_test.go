@@ -14,11 +14,15 @@
 package fileexporter
 
 import (
+	"bufio"
 	"context"
+	"encoding/binary"
+	"io"
 	"testing"
 	"time"
 
 	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -51,6 +55,35 @@ func TestFileTraceExporterNoErrors(t *testing.T) {
 	assert.EqualValues(t, pdata.TracesToOtlp(td), j.ResourceSpans)
 }
 
+func TestFileTraceExporterProtoFormat(t *testing.T) {
+	mf := &testutil.LimitedWriter{}
+	lte := &fileExporter{file: mf, format: FormatProto}
+
+	td := testdata.GenerateTraceDataTwoSpansSameResource()
+	assert.NoError(t, lte.ConsumeTraces(context.Background(), td))
+	assert.NoError(t, lte.ConsumeTraces(context.Background(), td))
+	assert.NoError(t, lte.Shutdown(context.Background()))
+
+	// Each request is prefixed by the key of the traces field and its size.
+	buf := bufio.NewReader(mf)
+	for i := 0; i < 2; i++ {
+		key, err := binary.ReadUvarint(buf)
+		require.NoError(t, err)
+		assert.EqualValues(t, TracesFieldNumber<<3|proto.WireBytes, key)
+		size, err := binary.ReadUvarint(buf)
+		require.NoError(t, err)
+		data := make([]byte, size)
+		_, err = io.ReadFull(buf, data)
+		require.NoError(t, err)
+
+		var j collectortrace.ExportTraceServiceRequest
+		require.NoError(t, j.Unmarshal(data))
+		assert.EqualValues(t, pdata.TracesToOtlp(td), j.ResourceSpans)
+	}
+	_, err := buf.ReadByte()
+	assert.Equal(t, io.EOF, err)
+}
+
 func TestFileMetricsExporterNoErrors(t *testing.T) {
 	mf := &testutil.LimitedWriter{}
 	lme := &fileExporter{file: mf}
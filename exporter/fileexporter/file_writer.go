@@ -0,0 +1,311 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileexporter
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	megabyte = 1024 * 1024
+
+	// backupTimeFormat is the format of the time of rotation in the name of
+	// the rotated files, sorting them in order of rotation.
+	backupTimeFormat = "2006-01-02T15-04-05.000"
+
+	compressSuffix = ".gz"
+)
+
+// fileWriter writes the requests to the file, rotating it if configured. Each
+// request is written by a single call to Write, a request is never split over
+// two files.
+type fileWriter struct {
+	logger        *zap.Logger
+	path          string
+	rotation      *Rotation
+	flushInterval time.Duration
+	now           func() time.Time
+
+	mutex    sync.Mutex
+	file     *os.File
+	buf      *bufio.Writer
+	size     int64
+	openedAt time.Time
+	closed   bool
+
+	stopCh chan struct{}
+	// background tracks the flushing and the processing of the rotated files.
+	background sync.WaitGroup
+	// millMutex serializes the processing of the rotated files.
+	millMutex sync.Mutex
+}
+
+var _ io.WriteCloser = (*fileWriter)(nil)
+
+func newFileWriter(logger *zap.Logger, cfg *Config) (*fileWriter, error) {
+	w := &fileWriter{
+		logger:        logger,
+		path:          cfg.Path,
+		rotation:      cfg.Rotation,
+		flushInterval: cfg.FlushInterval,
+		now:           time.Now,
+		stopCh:        make(chan struct{}),
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+
+	if w.flushInterval > 0 {
+		w.background.Add(1)
+		go w.flushPeriodically()
+	}
+	return w, nil
+}
+
+// open opens the file. Without rotation the file is truncated, otherwise the
+// requests are appended to the current file.
+func (w *fileWriter) open() error {
+	flags := os.O_RDWR | os.O_CREATE | os.O_TRUNC
+	if w.rotation != nil {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	file, err := os.OpenFile(w.path, flags, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	w.file = file
+	w.size = info.Size()
+	w.openedAt = w.now()
+	if w.flushInterval > 0 {
+		w.buf = bufio.NewWriter(file)
+	}
+	return nil
+}
+
+// Write writes a request, after rotating the file if needed.
+func (w *fileWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+
+	if w.shouldRotate(len(p)) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	var n int
+	var err error
+	if w.buf != nil {
+		n, err = w.buf.Write(p)
+	} else {
+		n, err = w.file.Write(p)
+	}
+	w.size += int64(n)
+	return n, err
+}
+
+// shouldRotate returns true if the file must be rotated before writing n
+// bytes. An empty file is never rotated, even if the request is larger than
+// the maximum size.
+func (w *fileWriter) shouldRotate(n int) bool {
+	if w.rotation == nil || w.size == 0 {
+		return false
+	}
+	if w.rotation.MaxMegabytes > 0 && w.size+int64(n) > int64(w.rotation.MaxMegabytes)*megabyte {
+		return true
+	}
+	return w.rotation.Interval > 0 && w.now().Sub(w.openedAt) >= w.rotation.Interval
+}
+
+// rotate renames the current file after the time of rotation and opens a new
+// one. The rotated files are compressed and cleaned up in the background.
+func (w *fileWriter) rotate() error {
+	if err := w.closeFile(); err != nil {
+		return err
+	}
+
+	ext := filepath.Ext(w.path)
+	prefix := strings.TrimSuffix(w.path, ext)
+	backup := fmt.Sprintf("%s-%s%s", prefix, w.now().UTC().Format(backupTimeFormat), ext)
+	if err := os.Rename(w.path, backup); err != nil {
+		return err
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+
+	w.background.Add(1)
+	go func() {
+		defer w.background.Done()
+		w.mill(backup)
+	}()
+	return nil
+}
+
+// mill compresses the rotated file if configured, and removes the oldest
+// rotated files above the maximum number of backups.
+func (w *fileWriter) mill(backup string) {
+	w.millMutex.Lock()
+	defer w.millMutex.Unlock()
+
+	if w.rotation.Compress {
+		if err := compressFile(backup); err != nil {
+			w.logger.Error("Failed to compress the rotated file", zap.String("file", backup), zap.Error(err))
+		}
+	}
+	if w.rotation.MaxBackups <= 0 {
+		return
+	}
+
+	backups, err := w.backups()
+	if err != nil {
+		w.logger.Error("Failed to list the rotated files", zap.Error(err))
+		return
+	}
+	for len(backups) > w.rotation.MaxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			w.logger.Error("Failed to remove a rotated file", zap.String("file", backups[0]), zap.Error(err))
+		}
+		backups = backups[1:]
+	}
+}
+
+// backups returns the rotated files, the oldest first.
+func (w *fileWriter) backups() ([]string, error) {
+	ext := filepath.Ext(w.path)
+	prefix := strings.TrimSuffix(filepath.Base(w.path), ext) + "-"
+	entries, err := ioutil.ReadDir(filepath.Dir(w.path))
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		timestamp := strings.TrimSuffix(strings.TrimSuffix(name, compressSuffix), ext)
+		timestamp = strings.TrimPrefix(timestamp, prefix)
+		if _, err := time.Parse(backupTimeFormat, timestamp); err != nil {
+			continue
+		}
+		backups = append(backups, filepath.Join(filepath.Dir(w.path), name))
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return filepath.Base(backups[i]) < filepath.Base(backups[j])
+	})
+	return backups, nil
+}
+
+// compressFile replaces the file by its gzip compressed version.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+compressSuffix, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err = io.Copy(gz, src); err != nil {
+		dst.Close()
+		return err
+	}
+	if err = gz.Close(); err != nil {
+		dst.Close()
+		return err
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+func (w *fileWriter) flushPeriodically() {
+	defer w.background.Done()
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.Flush(); err != nil {
+				w.logger.Error("Failed to flush the file", zap.Error(err))
+			}
+		case <-w.stopCh:
+			return
+		}
+	}
+}
+
+// Flush writes the buffered requests to the file.
+func (w *fileWriter) Flush() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.closed || w.buf == nil {
+		return nil
+	}
+	return w.buf.Flush()
+}
+
+func (w *fileWriter) closeFile() error {
+	if w.buf != nil {
+		if err := w.buf.Flush(); err != nil {
+			return err
+		}
+	}
+	return w.file.Close()
+}
+
+// Close flushes and closes the file, and waits for the rotated files to be
+// processed.
+func (w *fileWriter) Close() error {
+	w.mutex.Lock()
+	if w.closed {
+		w.mutex.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.stopCh)
+	err := w.closeFile()
+	w.mutex.Unlock()
+
+	w.background.Wait()
+	return err
+}
@@ -0,0 +1,176 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileexporter
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestFileWriter(t *testing.T, cfg *Config) *fileWriter {
+	w, err := newFileWriter(zap.NewNop(), cfg)
+	require.NoError(t, err)
+	return w
+}
+
+// listFiles returns the names of the files in the directory, sorted.
+func listFiles(t *testing.T, dir string) []string {
+	infos, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func readFile(t *testing.T, path string) string {
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestFileWriterTruncatesWithoutRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fileexporter")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "data.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte("previous\n"), 0600))
+
+	w := newTestFileWriter(t, &Config{Path: path})
+	_, err = w.Write([]byte("request\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, "request\n", readFile(t, path))
+}
+
+func TestFileWriterRotateBySize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fileexporter")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "data.json")
+	// The current file is appended to when rotating.
+	require.NoError(t, ioutil.WriteFile(path, []byte("previous\n"), 0600))
+
+	w := newTestFileWriter(t, &Config{Path: path, Rotation: &Rotation{MaxMegabytes: 1}})
+	now := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	w.now = func() time.Time { return now }
+
+	large := strings.Repeat("a", megabyte/3) + "\n"
+	for i := 0; i < 3; i++ {
+		_, err = w.Write([]byte(large))
+		require.NoError(t, err)
+		now = now.Add(time.Second)
+	}
+	require.NoError(t, w.Close())
+
+	// The requests aren't split over two files.
+	assert.Equal(t, []string{"data-2021-01-02T03-04-07.000.json", "data.json"}, listFiles(t, dir))
+	assert.Equal(t, "previous\n"+large+large, readFile(t, filepath.Join(dir, "data-2021-01-02T03-04-07.000.json")))
+	assert.Equal(t, large, readFile(t, path))
+}
+
+func TestFileWriterRotateByInterval(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fileexporter")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "data.json")
+
+	now := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	w := newTestFileWriter(t, &Config{Path: path, Rotation: &Rotation{Interval: time.Hour, MaxBackups: 2}})
+	w.now = func() time.Time { return now }
+	w.openedAt = now
+
+	for i := 0; i < 5; i++ {
+		_, err = w.Write([]byte("request\n"))
+		require.NoError(t, err)
+		now = now.Add(time.Hour)
+	}
+	require.NoError(t, w.Close())
+
+	// Only the most recent rotated files are kept.
+	assert.Equal(t, []string{
+		"data-2021-01-02T06-04-05.000.json",
+		"data-2021-01-02T07-04-05.000.json",
+		"data.json",
+	}, listFiles(t, dir))
+}
+
+func TestFileWriterCompressRotatedFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fileexporter")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "data.pb")
+
+	now := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	w := newTestFileWriter(t, &Config{Path: path, Rotation: &Rotation{Interval: time.Minute, MaxBackups: 1, Compress: true}})
+	w.now = func() time.Time { return now }
+	w.openedAt = now
+
+	for _, request := range []string{"first\n", "second\n", "third\n"} {
+		_, err = w.Write([]byte(request))
+		require.NoError(t, err)
+		now = now.Add(time.Minute)
+	}
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, []string{"data-2021-01-02T03-06-05.000.pb.gz", "data.pb"}, listFiles(t, dir))
+	file, err := os.Open(filepath.Join(dir, "data-2021-01-02T03-06-05.000.pb.gz"))
+	require.NoError(t, err)
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(gz)
+	require.NoError(t, err)
+	assert.Equal(t, "second\n", string(data))
+	assert.Equal(t, "third\n", readFile(t, path))
+}
+
+func TestFileWriterFlushInterval(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fileexporter")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "data.json")
+
+	w := newTestFileWriter(t, &Config{Path: path, FlushInterval: 20 * time.Millisecond})
+	_, err = w.Write([]byte("request\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "", readFile(t, path), "the write is buffered")
+
+	assert.Eventually(t, func() bool {
+		return readFile(t, path) == "request\n"
+	}, time.Second, 10*time.Millisecond)
+
+	// Closing flushes the buffered writes.
+	_, err = w.Write([]byte("last\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	assert.Equal(t, "request\nlast\n", readFile(t, path))
+
+	_, err = w.Write([]byte("closed\n"))
+	assert.Error(t, err)
+}
@@ -32,7 +32,8 @@ type Config struct {
 	Brokers []string `mapstructure:"brokers"`
 	// Kafka protocol version
 	ProtocolVersion string `mapstructure:"protocol_version"`
-	// The name of the kafka topic to export to (default otlp_spans for traces, otlp_metrics for metrics)
+	// The name of the kafka topic to export to (default otlp_spans for traces, otlp_metrics for metrics,
+	// otlp_logs for logs)
 	Topic string `mapstructure:"topic"`
 
 	// Encoding of messages (default "otlp_proto")
@@ -44,6 +45,23 @@ type Config struct {
 
 	// Authentication defines used authentication mechanism.
 	Authentication Authentication `mapstructure:"auth"`
+
+	// Partitioning defines how messages are keyed, and therefore distributed across partitions.
+	Partitioning Partitioning `mapstructure:"partitioning"`
+}
+
+// Partitioning defines configuration for the key of the produced messages.
+type Partitioning struct {
+	// Strategy used to key the messages (default "none"):
+	// - "none": messages are not keyed, the partition is chosen randomly.
+	// - "trace_id": data is split by trace and keyed by trace ID, so all the spans of a
+	//   trace are sent to the same partition. Not supported for metrics.
+	// - "resource_attribute": data is split by the value of a resource attribute, and keyed by it.
+	// - "round_robin": messages are not keyed and are distributed across partitions in turn.
+	Strategy string `mapstructure:"strategy"`
+	// Attribute is the resource attribute used as key by the "resource_attribute"
+	// strategy, e.g. "service.name".
+	Attribute string `mapstructure:"attribute"`
 }
 
 // Metadata defines configuration for retrieving metadata from the broker.
@@ -67,6 +67,10 @@ func TestLoadConfig(t *testing.T) {
 				Password: "pass",
 			},
 		},
+		Partitioning: Partitioning{
+			Strategy:  "resource_attribute",
+			Attribute: "service.name",
+		},
 		Metadata: Metadata{
 			Full: false,
 			Retry: MetadataRetry{
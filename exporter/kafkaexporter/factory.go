@@ -27,6 +27,7 @@ const (
 	typeStr             = "kafka"
 	defaultTracesTopic  = "otlp_spans"
 	defaultMetricsTopic = "otlp_metrics"
+	defaultLogsTopic    = "otlp_logs"
 	defaultEncoding     = "otlp_proto"
 	defaultBroker       = "localhost:9092"
 	// default from sarama.NewConfig()
@@ -49,11 +50,21 @@ func WithAddTracesMarshallers(encodingMarshaller map[string]TracesMarshaller) Fa
 	}
 }
 
+// WithAddLogsMarshallers adds logsMarshallers.
+func WithAddLogsMarshallers(encodingMarshaller map[string]LogsMarshaller) FactoryOption {
+	return func(factory *kafkaExporterFactory) {
+		for encoding, marshaller := range encodingMarshaller {
+			factory.logsMarshallers[encoding] = marshaller
+		}
+	}
+}
+
 // NewFactory creates Kafka exporter factory.
 func NewFactory(options ...FactoryOption) component.ExporterFactory {
 	f := &kafkaExporterFactory{
 		tracesMarshallers:  tracesMarshallers(),
 		metricsMarshallers: metricsMarshallers(),
+		logsMarshallers:    logsMarshallers(),
 	}
 	for _, o := range options {
 		o(f)
@@ -62,7 +73,8 @@ func NewFactory(options ...FactoryOption) component.ExporterFactory {
 		typeStr,
 		createDefaultConfig,
 		exporterhelper.WithTraces(f.createTraceExporter),
-		exporterhelper.WithMetrics(f.createMetricsExporter))
+		exporterhelper.WithMetrics(f.createMetricsExporter),
+		exporterhelper.WithLogs(f.createLogsExporter))
 }
 
 func createDefaultConfig() configmodels.Exporter {
@@ -75,9 +87,12 @@ func createDefaultConfig() configmodels.Exporter {
 		RetrySettings:   exporterhelper.DefaultRetrySettings(),
 		QueueSettings:   exporterhelper.DefaultQueueSettings(),
 		Brokers:         []string{defaultBroker},
-		// using an empty topic to track when it has not been set by user, default is based on traces or metrics.
+		// using an empty topic to track when it has not been set by user, default is based on traces, metrics or logs.
 		Topic:    "",
 		Encoding: defaultEncoding,
+		Partitioning: Partitioning{
+			Strategy: partitioningNone,
+		},
 		Metadata: Metadata{
 			Full: defaultMetadataFull,
 			Retry: MetadataRetry{
@@ -88,9 +103,20 @@ func createDefaultConfig() configmodels.Exporter {
 	}
 }
 
+// withDefaultTopic returns a copy of the config using the given topic when none is set, so that the
+// exporters of the other signals created from the same config keep their own default topic.
+func withDefaultTopic(cfg *Config, topic string) Config {
+	c := *cfg
+	if c.Topic == "" {
+		c.Topic = topic
+	}
+	return c
+}
+
 type kafkaExporterFactory struct {
 	tracesMarshallers  map[string]TracesMarshaller
 	metricsMarshallers map[string]MetricsMarshaller
+	logsMarshallers    map[string]LogsMarshaller
 }
 
 func (f *kafkaExporterFactory) createTraceExporter(
@@ -98,11 +124,8 @@ func (f *kafkaExporterFactory) createTraceExporter(
 	params component.ExporterCreateParams,
 	cfg configmodels.Exporter,
 ) (component.TracesExporter, error) {
-	oCfg := cfg.(*Config)
-	if oCfg.Topic == "" {
-		oCfg.Topic = defaultTracesTopic
-	}
-	exp, err := newTracesExporter(*oCfg, params, f.tracesMarshallers)
+	oCfg := withDefaultTopic(cfg.(*Config), defaultTracesTopic)
+	exp, err := newTracesExporter(oCfg, params, f.tracesMarshallers)
 	if err != nil {
 		return nil, err
 	}
@@ -123,11 +146,8 @@ func (f *kafkaExporterFactory) createMetricsExporter(
 	params component.ExporterCreateParams,
 	cfg configmodels.Exporter,
 ) (component.MetricsExporter, error) {
-	oCfg := cfg.(*Config)
-	if oCfg.Topic == "" {
-		oCfg.Topic = defaultMetricsTopic
-	}
-	exp, err := newMetricsExporter(*oCfg, params, f.metricsMarshallers)
+	oCfg := withDefaultTopic(cfg.(*Config), defaultMetricsTopic)
+	exp, err := newMetricsExporter(oCfg, params, f.metricsMarshallers)
 	if err != nil {
 		return nil, err
 	}
@@ -142,3 +162,25 @@ func (f *kafkaExporterFactory) createMetricsExporter(
 		exporterhelper.WithQueue(oCfg.QueueSettings),
 		exporterhelper.WithShutdown(exp.Close))
 }
+
+func (f *kafkaExporterFactory) createLogsExporter(
+	_ context.Context,
+	params component.ExporterCreateParams,
+	cfg configmodels.Exporter,
+) (component.LogsExporter, error) {
+	oCfg := withDefaultTopic(cfg.(*Config), defaultLogsTopic)
+	exp, err := newLogsExporter(oCfg, params, f.logsMarshallers)
+	if err != nil {
+		return nil, err
+	}
+	return exporterhelper.NewLogsExporter(
+		cfg,
+		params.Logger,
+		exp.logsDataPusher,
+		// Disable exporterhelper Timeout, because we cannot pass a Context to the Producer,
+		// and will rely on the sarama Producer Timeout logic.
+		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 0}),
+		exporterhelper.WithRetry(oCfg.RetrySettings),
+		exporterhelper.WithQueue(oCfg.QueueSettings),
+		exporterhelper.WithShutdown(exp.Close))
+}
@@ -59,6 +59,51 @@ func TestCreateMetricsExport(t *testing.T) {
 	assert.NotNil(t, mr)
 }
 
+func TestCreateLogsExporter(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Brokers = []string{"invalid:9092"}
+	cfg.ProtocolVersion = "2.0.0"
+	// this disables contacting the broker so we can successfully create the exporter
+	cfg.Metadata.Full = false
+	lf := kafkaExporterFactory{logsMarshallers: logsMarshallers()}
+	lr, err := lf.createLogsExporter(context.Background(), component.ExporterCreateParams{Logger: zap.NewNop()}, cfg)
+	require.NoError(t, err)
+	assert.NotNil(t, lr)
+}
+
+func TestCreateAllExportersFromOneConfig(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Brokers = []string{"invalid:9092"}
+	cfg.ProtocolVersion = "2.0.0"
+	// this disables contacting the broker so we can successfully create the exporter
+	cfg.Metadata.Full = false
+	f := NewFactory()
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+
+	tr, err := f.CreateTracesExporter(context.Background(), params, cfg)
+	require.NoError(t, err)
+	assert.NotNil(t, tr)
+	mr, err := f.CreateMetricsExporter(context.Background(), params, cfg)
+	require.NoError(t, err)
+	assert.NotNil(t, mr)
+	lr, err := f.CreateLogsExporter(context.Background(), params, cfg)
+	require.NoError(t, err)
+	assert.NotNil(t, lr)
+
+	// every exporter resolves its default topic without changing the shared config
+	assert.Equal(t, "", cfg.Topic)
+}
+
+func TestWithDefaultTopic(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.Equal(t, defaultTracesTopic, withDefaultTopic(cfg, defaultTracesTopic).Topic)
+	assert.Equal(t, defaultLogsTopic, withDefaultTopic(cfg, defaultLogsTopic).Topic)
+	assert.Equal(t, "", cfg.Topic)
+
+	cfg.Topic = "custom"
+	assert.Equal(t, "custom", withDefaultTopic(cfg, defaultLogsTopic).Topic)
+}
+
 func TestCreateTracesExporter_err(t *testing.T) {
 	cfg := createDefaultConfig().(*Config)
 	cfg.Brokers = []string{"invalid:9092"}
@@ -80,6 +125,16 @@ func TestCreateMetricsExporter_err(t *testing.T) {
 	assert.Nil(t, mr)
 }
 
+func TestCreateLogsExporter_err(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Brokers = []string{"invalid:9092"}
+	cfg.ProtocolVersion = "2.0.0"
+	lf := kafkaExporterFactory{logsMarshallers: logsMarshallers()}
+	lr, err := lf.createLogsExporter(context.Background(), component.ExporterCreateParams{Logger: zap.NewNop()}, cfg)
+	require.Error(t, err)
+	assert.Nil(t, lr)
+}
+
 func TestWithMarshallers(t *testing.T) {
 	cm := &customMarshaller{}
 	f := NewFactory(WithAddTracesMarshallers(map[string]TracesMarshaller{cm.Encoding(): cm}))
@@ -101,6 +156,19 @@ func TestWithMarshallers(t *testing.T) {
 	})
 }
 
+func TestWithLogsMarshallers(t *testing.T) {
+	cm := &customLogsMarshaller{}
+	f := NewFactory(WithAddLogsMarshallers(map[string]LogsMarshaller{cm.Encoding(): cm}))
+	cfg := createDefaultConfig().(*Config)
+	// disable contacting broker
+	cfg.Metadata.Full = false
+	cfg.Encoding = cm.Encoding()
+
+	exporter, err := f.CreateLogsExporter(context.Background(), component.ExporterCreateParams{Logger: zap.NewNop()}, cfg)
+	require.NoError(t, err)
+	require.NotNil(t, exporter)
+}
+
 type customMarshaller struct {
 }
 
@@ -113,3 +181,16 @@ func (c customMarshaller) Marshal(_ pdata.Traces) ([]Message, error) {
 func (c customMarshaller) Encoding() string {
 	return "custom"
 }
+
+type customLogsMarshaller struct {
+}
+
+var _ LogsMarshaller = (*customLogsMarshaller)(nil)
+
+func (c customLogsMarshaller) Marshal(_ pdata.Logs) ([]Message, error) {
+	panic("implement me")
+}
+
+func (c customLogsMarshaller) Encoding() string {
+	return "custom"
+}
@@ -30,18 +30,23 @@ var errUnrecognizedEncoding = fmt.Errorf("unrecognized encoding")
 
 // kafkaTracesProducer uses sarama to produce trace messages to Kafka.
 type kafkaTracesProducer struct {
-	producer   sarama.SyncProducer
-	topic      string
-	marshaller TracesMarshaller
-	logger     *zap.Logger
+	producer    sarama.SyncProducer
+	topic       string
+	marshaller  TracesMarshaller
+	partitioner partitioner
+	logger      *zap.Logger
 }
 
 func (e *kafkaTracesProducer) traceDataPusher(_ context.Context, td pdata.Traces) (int, error) {
-	messages, err := e.marshaller.Marshal(td)
-	if err != nil {
-		return td.SpanCount(), consumererror.Permanent(err)
+	var messages []*sarama.ProducerMessage
+	for _, batch := range e.partitioner.partitionTraces(td) {
+		batchMessages, err := e.marshaller.Marshal(batch.traces)
+		if err != nil {
+			return td.SpanCount(), consumererror.Permanent(err)
+		}
+		messages = append(messages, producerMessages(batchMessages, e.topic, batch.key)...)
 	}
-	err = e.producer.SendMessages(producerMessages(messages, e.topic))
+	err := e.producer.SendMessages(messages)
 	if err != nil {
 		return td.SpanCount(), err
 	}
@@ -54,18 +59,23 @@ func (e *kafkaTracesProducer) Close(context.Context) error {
 
 // kafkaMetricsProducer uses sarama to produce metrics messages to kafka
 type kafkaMetricsProducer struct {
-	producer   sarama.SyncProducer
-	topic      string
-	marshaller MetricsMarshaller
-	logger     *zap.Logger
+	producer    sarama.SyncProducer
+	topic       string
+	marshaller  MetricsMarshaller
+	partitioner partitioner
+	logger      *zap.Logger
 }
 
 func (e *kafkaMetricsProducer) metricsDataPusher(_ context.Context, md pdata.Metrics) (int, error) {
-	messages, err := e.marshaller.Marshal(md)
-	if err != nil {
-		return md.MetricCount(), consumererror.Permanent(err)
+	var messages []*sarama.ProducerMessage
+	for _, batch := range e.partitioner.partitionMetrics(md) {
+		batchMessages, err := e.marshaller.Marshal(batch.metrics)
+		if err != nil {
+			return md.MetricCount(), consumererror.Permanent(err)
+		}
+		messages = append(messages, producerMessages(batchMessages, e.topic, batch.key)...)
 	}
-	err = e.producer.SendMessages(producerMessages(messages, e.topic))
+	err := e.producer.SendMessages(messages)
 	if err != nil {
 		return md.MetricCount(), err
 	}
@@ -76,6 +86,35 @@ func (e *kafkaMetricsProducer) Close(context.Context) error {
 	return e.producer.Close()
 }
 
+// kafkaLogsProducer uses sarama to produce logs messages to kafka
+type kafkaLogsProducer struct {
+	producer    sarama.SyncProducer
+	topic       string
+	marshaller  LogsMarshaller
+	partitioner partitioner
+	logger      *zap.Logger
+}
+
+func (e *kafkaLogsProducer) logsDataPusher(_ context.Context, ld pdata.Logs) (int, error) {
+	var messages []*sarama.ProducerMessage
+	for _, batch := range e.partitioner.partitionLogs(ld) {
+		batchMessages, err := e.marshaller.Marshal(batch.logs)
+		if err != nil {
+			return ld.LogRecordCount(), consumererror.Permanent(err)
+		}
+		messages = append(messages, producerMessages(batchMessages, e.topic, batch.key)...)
+	}
+	err := e.producer.SendMessages(messages)
+	if err != nil {
+		return ld.LogRecordCount(), err
+	}
+	return 0, nil
+}
+
+func (e *kafkaLogsProducer) Close(context.Context) error {
+	return e.producer.Close()
+}
+
 func newSaramaProducer(config Config) (sarama.SyncProducer, error) {
 	c := sarama.NewConfig()
 	// These setting are required by the sarama.SyncProducer implementation.
@@ -88,6 +127,9 @@ func newSaramaProducer(config Config) (sarama.SyncProducer, error) {
 	c.Metadata.Full = config.Metadata.Full
 	c.Metadata.Retry.Max = config.Metadata.Retry.Max
 	c.Metadata.Retry.Backoff = config.Metadata.Retry.Backoff
+	if config.Partitioning.Strategy == partitioningRoundRobin {
+		c.Producer.Partitioner = sarama.NewRoundRobinPartitioner
+	}
 	if config.ProtocolVersion != "" {
 		version, err := sarama.ParseKafkaVersion(config.ProtocolVersion)
 		if err != nil {
@@ -110,16 +152,24 @@ func newMetricsExporter(config Config, params component.ExporterCreateParams, ma
 	if marshaller == nil {
 		return nil, errUnrecognizedEncoding
 	}
+	if config.Partitioning.Strategy == partitioningTraceID {
+		return nil, errTraceIDPartitioningMetrics
+	}
+	partitioner, err := newPartitioner(config.Partitioning)
+	if err != nil {
+		return nil, err
+	}
 	producer, err := newSaramaProducer(config)
 	if err != nil {
 		return nil, err
 	}
 
 	return &kafkaMetricsProducer{
-		producer:   producer,
-		topic:      config.Topic,
-		marshaller: marshaller,
-		logger:     params.Logger,
+		producer:    producer,
+		topic:       config.Topic,
+		marshaller:  marshaller,
+		partitioner: partitioner,
+		logger:      params.Logger,
 	}, nil
 
 }
@@ -130,25 +180,55 @@ func newTracesExporter(config Config, params component.ExporterCreateParams, mar
 	if marshaller == nil {
 		return nil, errUnrecognizedEncoding
 	}
+	partitioner, err := newPartitioner(config.Partitioning)
+	if err != nil {
+		return nil, err
+	}
 	producer, err := newSaramaProducer(config)
 	if err != nil {
 		return nil, err
 	}
 	return &kafkaTracesProducer{
-		producer:   producer,
-		topic:      config.Topic,
-		marshaller: marshaller,
-		logger:     params.Logger,
+		producer:    producer,
+		topic:       config.Topic,
+		marshaller:  marshaller,
+		partitioner: partitioner,
+		logger:      params.Logger,
 	}, nil
 }
 
-func producerMessages(messages []Message, topic string) []*sarama.ProducerMessage {
+func newLogsExporter(config Config, params component.ExporterCreateParams, marshallers map[string]LogsMarshaller) (*kafkaLogsProducer, error) {
+	marshaller := marshallers[config.Encoding]
+	if marshaller == nil {
+		return nil, errUnrecognizedEncoding
+	}
+	partitioner, err := newPartitioner(config.Partitioning)
+	if err != nil {
+		return nil, err
+	}
+	producer, err := newSaramaProducer(config)
+	if err != nil {
+		return nil, err
+	}
+	return &kafkaLogsProducer{
+		producer:    producer,
+		topic:       config.Topic,
+		marshaller:  marshaller,
+		partitioner: partitioner,
+		logger:      params.Logger,
+	}, nil
+}
+
+func producerMessages(messages []Message, topic string, key []byte) []*sarama.ProducerMessage {
 	producerMessages := make([]*sarama.ProducerMessage, len(messages))
 	for i := range messages {
 		producerMessages[i] = &sarama.ProducerMessage{
 			Topic: topic,
 			Value: sarama.ByteEncoder(messages[i].Value),
 		}
+		if key != nil {
+			producerMessages[i].Key = sarama.ByteEncoder(key)
+		}
 	}
 	return producerMessages
 }
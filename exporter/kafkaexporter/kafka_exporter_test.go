@@ -66,6 +66,30 @@ func TestNewMetricsExporter_err_traces_encoding(t *testing.T) {
 	assert.Nil(t, mexp)
 }
 
+func TestNewMetricsExporter_err_trace_id_partitioning(t *testing.T) {
+	c := Config{Encoding: defaultEncoding, Partitioning: Partitioning{Strategy: partitioningTraceID}}
+	mexp, err := newMetricsExporter(c, component.ExporterCreateParams{Logger: zap.NewNop()}, metricsMarshallers())
+	assert.EqualError(t, err, errTraceIDPartitioningMetrics.Error())
+	assert.Nil(t, mexp)
+}
+
+func TestNewExporter_err_partitioning(t *testing.T) {
+	c := Config{Encoding: defaultEncoding, Partitioning: Partitioning{Strategy: partitioningResourceAttribute}}
+	texp, err := newTracesExporter(c, component.ExporterCreateParams{Logger: zap.NewNop()}, tracesMarshallers())
+	assert.Error(t, err)
+	assert.Nil(t, texp)
+	lexp, err := newLogsExporter(c, component.ExporterCreateParams{Logger: zap.NewNop()}, logsMarshallers())
+	assert.Error(t, err)
+	assert.Nil(t, lexp)
+}
+
+func TestNewLogsExporter_err_encoding(t *testing.T) {
+	c := Config{Encoding: "bar"}
+	lexp, err := newLogsExporter(c, component.ExporterCreateParams{Logger: zap.NewNop()}, logsMarshallers())
+	assert.EqualError(t, err, errUnrecognizedEncoding.Error())
+	assert.Nil(t, lexp)
+}
+
 func TestNewExporter_err_auth_type(t *testing.T) {
 	c := Config{
 		ProtocolVersion: "2.0.0",
@@ -108,6 +132,29 @@ func TestTraceDataPusher(t *testing.T) {
 	assert.Equal(t, 0, droppedSpans)
 }
 
+func TestTraceDataPusher_partitioned(t *testing.T) {
+	c := sarama.NewConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	producer.ExpectSendMessageAndSucceed()
+	producer.ExpectSendMessageAndSucceed()
+
+	p := kafkaTracesProducer{
+		producer:    producer,
+		marshaller:  &otlpTracesPbMarshaller{},
+		partitioner: partitioner{strategy: partitioningTraceID},
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	td := testdata.GenerateTraceDataTwoSpansSameResource()
+	spans := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	spans.At(0).SetTraceID(pdata.NewTraceID([16]byte{1}))
+	spans.At(1).SetTraceID(pdata.NewTraceID([16]byte{2}))
+	droppedSpans, err := p.traceDataPusher(context.Background(), td)
+	require.NoError(t, err)
+	assert.Equal(t, 0, droppedSpans)
+}
+
 func TestTraceDataPusher_err(t *testing.T) {
 	c := sarama.NewConfig()
 	producer := mocks.NewSyncProducer(t, c)
@@ -191,6 +238,67 @@ func TestMetricsDataPusher_marshal_error(t *testing.T) {
 	assert.Equal(t, md.MetricCount(), dropped)
 }
 
+func TestLogsDataPusher(t *testing.T) {
+	c := sarama.NewConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	producer.ExpectSendMessageAndSucceed()
+
+	p := kafkaLogsProducer{
+		producer:   producer,
+		marshaller: &otlpLogsPbMarshaller{},
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	dropped, err := p.logsDataPusher(context.Background(), testdata.GenerateLogDataTwoLogsSameResource())
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+}
+
+func TestLogsDataPusher_err(t *testing.T) {
+	c := sarama.NewConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	expErr := fmt.Errorf("failed to send")
+	producer.ExpectSendMessageAndFail(expErr)
+
+	p := kafkaLogsProducer{
+		producer:   producer,
+		marshaller: &otlpLogsPbMarshaller{},
+		logger:     zap.NewNop(),
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	ld := testdata.GenerateLogDataTwoLogsSameResource()
+	dropped, err := p.logsDataPusher(context.Background(), ld)
+	assert.EqualError(t, err, expErr.Error())
+	assert.Equal(t, ld.LogRecordCount(), dropped)
+}
+
+func TestLogsDataPusher_marshal_error(t *testing.T) {
+	expErr := fmt.Errorf("failed to marshall")
+	p := kafkaLogsProducer{
+		marshaller: &logsErrorMarshaller{err: expErr},
+		logger:     zap.NewNop(),
+	}
+	ld := testdata.GenerateLogDataTwoLogsSameResource()
+	dropped, err := p.logsDataPusher(context.Background(), ld)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), expErr.Error())
+	assert.Equal(t, ld.LogRecordCount(), dropped)
+}
+
+func TestProducerMessages(t *testing.T) {
+	messages := producerMessages([]Message{{Value: []byte("a")}, {Value: []byte("b")}}, "topic", []byte("key"))
+	require.Len(t, messages, 2)
+	for _, m := range messages {
+		assert.Equal(t, "topic", m.Topic)
+		assert.Equal(t, sarama.ByteEncoder("key"), m.Key)
+	}
+	messages = producerMessages([]Message{{Value: []byte("a")}}, "topic", nil)
+	assert.Nil(t, messages[0].Key)
+}
+
 type tracesErrorMarshaller struct {
 	err error
 }
@@ -216,3 +324,17 @@ func (e tracesErrorMarshaller) Marshal(_ pdata.Traces) ([]Message, error) {
 func (e tracesErrorMarshaller) Encoding() string {
 	panic("implement me")
 }
+
+type logsErrorMarshaller struct {
+	err error
+}
+
+var _ LogsMarshaller = (*logsErrorMarshaller)(nil)
+
+func (e logsErrorMarshaller) Marshal(_ pdata.Logs) ([]Message, error) {
+	return nil, e.err
+}
+
+func (e logsErrorMarshaller) Encoding() string {
+	panic("implement me")
+}
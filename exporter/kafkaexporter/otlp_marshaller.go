@@ -16,12 +16,15 @@ package kafkaexporter
 
 import (
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal"
+	otlplogs "go.opentelemetry.io/collector/internal/data/protogen/collector/logs/v1"
 	otlpmetric "go.opentelemetry.io/collector/internal/data/protogen/collector/metrics/v1"
 	otlptrace "go.opentelemetry.io/collector/internal/data/protogen/collector/trace/v1"
 )
 
 var _ TracesMarshaller = (*otlpTracesPbMarshaller)(nil)
 var _ MetricsMarshaller = (*otlpMetricsPbMarshaller)(nil)
+var _ LogsMarshaller = (*otlpLogsPbMarshaller)(nil)
 
 type otlpTracesPbMarshaller struct {
 }
@@ -58,3 +61,21 @@ func (m *otlpMetricsPbMarshaller) Marshal(metrics pdata.Metrics) ([]Message, err
 	}
 	return []Message{{Value: bts}}, nil
 }
+
+type otlpLogsPbMarshaller struct {
+}
+
+func (m *otlpLogsPbMarshaller) Encoding() string {
+	return defaultEncoding
+}
+
+func (m *otlpLogsPbMarshaller) Marshal(logs pdata.Logs) ([]Message, error) {
+	request := otlplogs.ExportLogsServiceRequest{
+		ResourceLogs: internal.LogsToOtlp(logs.InternalRep()),
+	}
+	bts, err := request.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return []Message{{Value: bts}}, nil
+}
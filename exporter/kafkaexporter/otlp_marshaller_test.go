@@ -21,6 +21,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal"
+	otlplogs "go.opentelemetry.io/collector/internal/data/protogen/collector/logs/v1"
 	otlpmetric "go.opentelemetry.io/collector/internal/data/protogen/collector/metrics/v1"
 	otlptrace "go.opentelemetry.io/collector/internal/data/protogen/collector/trace/v1"
 	"go.opentelemetry.io/collector/internal/testdata"
@@ -57,3 +59,19 @@ func TestOTLPMetricsPbMarshaller(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, []Message{{Value: expected}}, messages)
 }
+
+func TestOTLPLogsPbMarshaller(t *testing.T) {
+	ld := testdata.GenerateLogDataTwoLogsSameResource()
+	request := &otlplogs.ExportLogsServiceRequest{
+		ResourceLogs: internal.LogsToOtlp(ld.InternalRep()),
+	}
+	expected, err := request.Marshal()
+	require.NoError(t, err)
+	require.NotNil(t, expected)
+
+	m := otlpLogsPbMarshaller{}
+	assert.Equal(t, "otlp_proto", m.Encoding())
+	messages, err := m.Marshal(ld)
+	require.NoError(t, err)
+	assert.Equal(t, []Message{{Value: expected}}, messages)
+}
@@ -0,0 +1,167 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/batchpersignal"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+const (
+	partitioningNone              = "none"
+	partitioningTraceID           = "trace_id"
+	partitioningResourceAttribute = "resource_attribute"
+	partitioningRoundRobin        = "round_robin"
+)
+
+var errTraceIDPartitioningMetrics = fmt.Errorf("%q partitioning is not supported for metrics", partitioningTraceID)
+
+// partitioner splits the data into batches that are sent with the same message key.
+type partitioner struct {
+	strategy  string
+	attribute string
+}
+
+type keyedTraces struct {
+	key    []byte
+	traces pdata.Traces
+}
+
+type keyedMetrics struct {
+	key     []byte
+	metrics pdata.Metrics
+}
+
+type keyedLogs struct {
+	key  []byte
+	logs pdata.Logs
+}
+
+func newPartitioner(cfg Partitioning) (partitioner, error) {
+	switch cfg.Strategy {
+	case "", partitioningNone, partitioningTraceID, partitioningRoundRobin:
+	case partitioningResourceAttribute:
+		if cfg.Attribute == "" {
+			return partitioner{}, fmt.Errorf("%q partitioning requires an attribute", partitioningResourceAttribute)
+		}
+	default:
+		return partitioner{}, fmt.Errorf("unknown partitioning strategy %q", cfg.Strategy)
+	}
+	return partitioner{strategy: cfg.Strategy, attribute: cfg.Attribute}, nil
+}
+
+func (p partitioner) partitionTraces(td pdata.Traces) []keyedTraces {
+	switch p.strategy {
+	case partitioningTraceID:
+		var result []keyedTraces
+		for _, trace := range batchpersignal.SplitTraces(td) {
+			traceID := trace.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).TraceID()
+			result = append(result, keyedTraces{key: traceIDKey(traceID), traces: trace})
+		}
+		return result
+	case partitioningResourceAttribute:
+		var result []keyedTraces
+		index := make(map[string]int)
+		rss := td.ResourceSpans()
+		for i := 0; i < rss.Len(); i++ {
+			rs := rss.At(i)
+			key := p.resourceKey(rs.Resource())
+			idx, ok := index[string(key)]
+			if !ok {
+				idx = len(result)
+				index[string(key)] = idx
+				result = append(result, keyedTraces{key: key, traces: pdata.NewTraces()})
+			}
+			result[idx].traces.ResourceSpans().Append(rs)
+		}
+		return result
+	default:
+		return []keyedTraces{{traces: td}}
+	}
+}
+
+func (p partitioner) partitionMetrics(md pdata.Metrics) []keyedMetrics {
+	if p.strategy != partitioningResourceAttribute {
+		return []keyedMetrics{{metrics: md}}
+	}
+
+	var result []keyedMetrics
+	index := make(map[string]int)
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		key := p.resourceKey(rm.Resource())
+		idx, ok := index[string(key)]
+		if !ok {
+			idx = len(result)
+			index[string(key)] = idx
+			result = append(result, keyedMetrics{key: key, metrics: pdata.NewMetrics()})
+		}
+		result[idx].metrics.ResourceMetrics().Append(rm)
+	}
+	return result
+}
+
+func (p partitioner) partitionLogs(ld pdata.Logs) []keyedLogs {
+	switch p.strategy {
+	case partitioningTraceID:
+		var result []keyedLogs
+		for _, logs := range batchpersignal.SplitLogs(ld) {
+			traceID := logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).TraceID()
+			result = append(result, keyedLogs{key: traceIDKey(traceID), logs: logs})
+		}
+		return result
+	case partitioningResourceAttribute:
+		var result []keyedLogs
+		index := make(map[string]int)
+		rls := ld.ResourceLogs()
+		for i := 0; i < rls.Len(); i++ {
+			rl := rls.At(i)
+			key := p.resourceKey(rl.Resource())
+			idx, ok := index[string(key)]
+			if !ok {
+				idx = len(result)
+				index[string(key)] = idx
+				result = append(result, keyedLogs{key: key, logs: pdata.NewLogs()})
+			}
+			result[idx].logs.ResourceLogs().Append(rl)
+		}
+		return result
+	default:
+		return []keyedLogs{{logs: ld}}
+	}
+}
+
+// resourceKey returns the value of the partitioning attribute of the resource, or nil
+// if the resource does not have it so the message is not keyed.
+func (p partitioner) resourceKey(resource pdata.Resource) []byte {
+	value, ok := resource.Attributes().Get(p.attribute)
+	if !ok {
+		return nil
+	}
+	return []byte(tracetranslator.AttributeValueToString(value, false))
+}
+
+// traceIDKey returns the key for the trace ID, or nil for the empty trace ID so
+// data without a trace is not keyed.
+func traceIDKey(traceID pdata.TraceID) []byte {
+	if traceID.IsEmpty() {
+		return nil
+	}
+	return []byte(traceID.HexString())
+}
@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/testdata"
+)
+
+func TestNewPartitioner(t *testing.T) {
+	for _, strategy := range []string{"", partitioningNone, partitioningTraceID, partitioningRoundRobin} {
+		_, err := newPartitioner(Partitioning{Strategy: strategy})
+		assert.NoError(t, err, strategy)
+	}
+	_, err := newPartitioner(Partitioning{Strategy: partitioningResourceAttribute, Attribute: "service.name"})
+	assert.NoError(t, err)
+	_, err = newPartitioner(Partitioning{Strategy: partitioningResourceAttribute})
+	assert.Error(t, err)
+	_, err = newPartitioner(Partitioning{Strategy: "random"})
+	assert.Error(t, err)
+}
+
+func TestPartitionTraces_None(t *testing.T) {
+	td := testdata.GenerateTraceDataTwoSpansSameResourceOneDifferent()
+	for _, strategy := range []string{partitioningNone, partitioningRoundRobin} {
+		batches := partitioner{strategy: strategy}.partitionTraces(td)
+		require.Len(t, batches, 1)
+		assert.Nil(t, batches[0].key)
+		assert.Equal(t, td, batches[0].traces)
+	}
+}
+
+func TestPartitionTraces_TraceID(t *testing.T) {
+	td := testdata.GenerateTraceDataTwoSpansSameResourceOneDifferent()
+	first := pdata.NewTraceID([16]byte{1})
+	second := pdata.NewTraceID([16]byte{2})
+	spans := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	spans.At(0).SetTraceID(first)
+	spans.At(1).SetTraceID(second)
+	td.ResourceSpans().At(1).InstrumentationLibrarySpans().At(0).Spans().At(0).SetTraceID(first)
+
+	batches := partitioner{strategy: partitioningTraceID}.partitionTraces(td)
+	require.Len(t, batches, 2)
+	assert.Equal(t, []byte(first.HexString()), batches[0].key)
+	assert.Equal(t, 2, batches[0].traces.SpanCount())
+	assert.Equal(t, 2, batches[0].traces.ResourceSpans().Len())
+	assert.Equal(t, []byte(second.HexString()), batches[1].key)
+	assert.Equal(t, 1, batches[1].traces.SpanCount())
+}
+
+func TestPartitionTraces_ResourceAttribute(t *testing.T) {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(3)
+	td.ResourceSpans().At(0).Resource().Attributes().InsertString("service.name", "frontend")
+	td.ResourceSpans().At(1).Resource().Attributes().InsertString("service.name", "backend")
+	td.ResourceSpans().At(2).Resource().Attributes().InsertString("service.name", "frontend")
+
+	batches := partitioner{strategy: partitioningResourceAttribute, attribute: "service.name"}.partitionTraces(td)
+	require.Len(t, batches, 2)
+	assert.Equal(t, []byte("frontend"), batches[0].key)
+	assert.Equal(t, 2, batches[0].traces.ResourceSpans().Len())
+	assert.Equal(t, []byte("backend"), batches[1].key)
+	assert.Equal(t, 1, batches[1].traces.ResourceSpans().Len())
+
+	batches = partitioner{strategy: partitioningResourceAttribute, attribute: "host.name"}.partitionTraces(td)
+	require.Len(t, batches, 1)
+	assert.Nil(t, batches[0].key)
+}
+
+func TestPartitionMetrics(t *testing.T) {
+	md := testdata.GenerateMetricsTwoMetrics()
+	batches := partitioner{strategy: partitioningNone}.partitionMetrics(md)
+	require.Len(t, batches, 1)
+	assert.Equal(t, md, batches[0].metrics)
+
+	md.ResourceMetrics().At(0).Resource().Attributes().UpsertString("service.name", "frontend")
+	batches = partitioner{strategy: partitioningResourceAttribute, attribute: "service.name"}.partitionMetrics(md)
+	require.Len(t, batches, 1)
+	assert.Equal(t, []byte("frontend"), batches[0].key)
+	assert.Equal(t, md.MetricCount(), batches[0].metrics.MetricCount())
+}
+
+func TestPartitionLogs(t *testing.T) {
+	ld := testdata.GenerateLogDataTwoLogsSameResource()
+	traceID := pdata.NewTraceID([16]byte{1})
+	ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).SetTraceID(traceID)
+
+	batches := partitioner{strategy: partitioningTraceID}.partitionLogs(ld)
+	require.Len(t, batches, 2)
+	assert.Equal(t, []byte(traceID.HexString()), batches[0].key)
+	assert.Equal(t, 1, batches[0].logs.LogRecordCount())
+	// Log records without a trace ID are not keyed.
+	assert.Nil(t, batches[1].key)
+	assert.Equal(t, 1, batches[1].logs.LogRecordCount())
+
+	ld.ResourceLogs().At(0).Resource().Attributes().UpsertString("service.name", "frontend")
+	batches = partitioner{strategy: partitioningResourceAttribute, attribute: "service.name"}.partitionLogs(ld)
+	require.Len(t, batches, 1)
+	assert.Equal(t, []byte("frontend"), batches[0].key)
+}
@@ -0,0 +1,75 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/exporter/otlpexporter"
+)
+
+// Config defines configuration for the load-balancing exporter.
+type Config struct {
+	configmodels.ExporterSettings `mapstructure:",squash"`
+
+	// Protocol is the configuration of the exporters sending the data to the
+	// backends.
+	Protocol Protocol `mapstructure:"protocol"`
+
+	// Resolver is how the list of backends is obtained.
+	Resolver ResolverSettings `mapstructure:"resolver"`
+}
+
+// Protocol holds the configuration of the exporter created for each backend.
+type Protocol struct {
+	// OTLP is the configuration of the OTLP exporters, whose endpoint is set to
+	// the address of the backend. Their queue and retry settings apply to each
+	// backend separately.
+	OTLP otlpexporter.Config `mapstructure:"otlp"`
+}
+
+// ResolverSettings defines the resolver of the backends, only one of them can
+// be set.
+type ResolverSettings struct {
+	// Static is a fixed list of backends.
+	Static *StaticResolver `mapstructure:"static"`
+
+	// DNS periodically resolves a hostname into the list of backends.
+	DNS *DNSResolver `mapstructure:"dns"`
+}
+
+// StaticResolver defines a fixed list of backends.
+type StaticResolver struct {
+	// Hostnames are the addresses of the backends, with an optional port
+	// defaulting to the OTLP gRPC port.
+	Hostnames []string `mapstructure:"hostnames"`
+}
+
+// DNSResolver defines a hostname resolved periodically, each of its IP
+// addresses being a backend.
+type DNSResolver struct {
+	// Hostname is the name resolved into the backend IP addresses.
+	Hostname string `mapstructure:"hostname"`
+
+	// Port is the port of the backends, defaulting to the OTLP gRPC port.
+	Port string `mapstructure:"port"`
+
+	// Interval is the time between two resolutions, 5s by default.
+	Interval time.Duration `mapstructure:"interval"`
+
+	// Timeout is the timeout of a resolution, 1s by default.
+	Timeout time.Duration `mapstructure:"timeout"`
+}
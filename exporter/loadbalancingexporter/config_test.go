@@ -0,0 +1,61 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/exporter/otlpexporter"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Exporters[typeStr] = factory
+	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	e0 := cfg.Exporters["loadbalancing"].(*Config)
+	assert.Equal(t, "loadbalancing", e0.Name())
+	assert.Equal(t, &StaticResolver{Hostnames: []string{"backend-1:4317", "backend-2"}}, e0.Resolver.Static)
+	assert.Nil(t, e0.Resolver.DNS)
+	assert.Equal(t, time.Second, e0.Protocol.OTLP.Timeout)
+	assert.True(t, e0.Protocol.OTLP.TLSSetting.Insecure)
+	assert.Equal(t, 100, e0.Protocol.OTLP.QueueSettings.QueueSize)
+	// The other settings keep the defaults of the OTLP exporter.
+	otlpDefaultCfg := otlpexporter.NewFactory().CreateDefaultConfig().(*otlpexporter.Config)
+	assert.Equal(t, otlpDefaultCfg.RetrySettings, e0.Protocol.OTLP.RetrySettings)
+	assert.Equal(t, otlpDefaultCfg.QueueSettings.NumConsumers, e0.Protocol.OTLP.QueueSettings.NumConsumers)
+
+	e1 := cfg.Exporters["loadbalancing/dns"].(*Config)
+	assert.Nil(t, e1.Resolver.Static)
+	assert.Equal(t, &DNSResolver{
+		Hostname: "collectors.example.com",
+		Port:     "55690",
+		Interval: 10 * time.Second,
+		Timeout:  2 * time.Second,
+	}, e1.Resolver.DNS)
+}
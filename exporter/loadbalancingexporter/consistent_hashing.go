@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+)
+
+// positionsPerEndpoint is the number of positions of each endpoint on the ring,
+// spreading the keys evenly over the endpoints.
+const positionsPerEndpoint = 100
+
+type ringItem struct {
+	position uint32
+	endpoint string
+}
+
+// hashRing assigns keys to endpoints by consistent hashing: when an endpoint
+// is added or removed, only the keys of its positions on the ring move to
+// another endpoint.
+type hashRing struct {
+	items []ringItem
+}
+
+func newHashRing(endpoints []string) *hashRing {
+	items := make([]ringItem, 0, len(endpoints)*positionsPerEndpoint)
+	for _, endpoint := range endpoints {
+		for i := 0; i < positionsPerEndpoint; i++ {
+			position := crc32.ChecksumIEEE([]byte(endpoint + "-" + strconv.Itoa(i)))
+			items = append(items, ringItem{position: position, endpoint: endpoint})
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].position == items[j].position {
+			return items[i].endpoint < items[j].endpoint
+		}
+		return items[i].position < items[j].position
+	})
+	return &hashRing{items: items}
+}
+
+// endpointFor returns the endpoint of the key, or an empty string if the ring
+// has no endpoints.
+func (r *hashRing) endpointFor(key []byte) string {
+	if len(r.items) == 0 {
+		return ""
+	}
+	hash := crc32.ChecksumIEEE(key)
+	i := sort.Search(len(r.items), func(i int) bool {
+		return r.items[i].position >= hash
+	})
+	if i == len(r.items) {
+		// The ring wraps around.
+		i = 0
+	}
+	return r.items[i].endpoint
+}
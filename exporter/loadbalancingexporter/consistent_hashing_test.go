@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashRingEmpty(t *testing.T) {
+	ring := newHashRing(nil)
+	assert.Equal(t, "", ring.endpointFor([]byte("key")))
+}
+
+func TestHashRingSpreadsKeys(t *testing.T) {
+	endpoints := []string{"endpoint-1:4317", "endpoint-2:4317", "endpoint-3:4317"}
+	ring := newHashRing(endpoints)
+
+	counts := make(map[string]int)
+	for i := 0; i < 3000; i++ {
+		counts[ring.endpointFor([]byte(fmt.Sprintf("key-%d", i)))]++
+	}
+	for _, endpoint := range endpoints {
+		// Each endpoint gets a reasonable share of the keys.
+		assert.Greater(t, counts[endpoint], 500, endpoint)
+	}
+}
+
+func TestHashRingRebalance(t *testing.T) {
+	before := newHashRing([]string{"endpoint-1:4317", "endpoint-2:4317", "endpoint-3:4317"})
+	after := newHashRing([]string{"endpoint-1:4317", "endpoint-2:4317", "endpoint-3:4317", "endpoint-4:4317"})
+
+	moved := 0
+	for i := 0; i < 4000; i++ {
+		key := []byte(fmt.Sprintf("key-%d", i))
+		endpoint := after.endpointFor(key)
+		if endpoint != before.endpointFor(key) {
+			// Only the keys moving to the new endpoint change.
+			assert.Equal(t, "endpoint-4:4317", endpoint)
+			moved++
+		}
+	}
+	assert.Greater(t, moved, 500)
+	assert.Less(t, moved, 1500)
+}
@@ -0,0 +1,61 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	cfg := createDefaultConfig()
+	assert.NotNil(t, cfg, "failed to create default config")
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+}
+
+func TestCreateTraceExporter(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+
+	// The exporter requires a resolver.
+	exp, err := createTraceExporter(context.Background(), params, cfg)
+	assert.Nil(t, exp)
+	assert.Equal(t, errNoResolver, err)
+
+	cfg.Resolver.Static = &StaticResolver{Hostnames: []string{"backend-1"}}
+	exp, err = createTraceExporter(context.Background(), params, cfg)
+	assert.NotNil(t, exp)
+	assert.NoError(t, err)
+
+	cfg.Resolver.DNS = &DNSResolver{Hostname: "backends"}
+	exp, err = createTraceExporter(context.Background(), params, cfg)
+	assert.Nil(t, exp)
+	assert.Equal(t, errMultipleResolvers, err)
+}
+
+func TestCreateMetricsExporter(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+
+	exp, err := NewFactory().CreateMetricsExporter(context.Background(), params, cfg)
+	assert.Nil(t, exp)
+	assert.Error(t, err)
+}
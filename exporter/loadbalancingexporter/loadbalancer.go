@@ -0,0 +1,158 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// exporterFactory creates the exporter of a backend.
+type exporterFactory func(ctx context.Context, endpoint string) (component.TracesExporter, error)
+
+// backendExporter is the exporter of a backend, counting the calls in flight
+// so that it is only shut down once they are done.
+type backendExporter struct {
+	component.TracesExporter
+	inFlight sync.WaitGroup
+}
+
+// loadBalancer keeps an exporter for each backend given by the resolver, and
+// the hash ring assigning the keys to the backends.
+type loadBalancer struct {
+	logger          *zap.Logger
+	resolver        resolver
+	exporterFactory exporterFactory
+	host            component.Host
+
+	// updateLock serializes the updates of the backends.
+	updateLock sync.Mutex
+
+	lock      sync.RWMutex
+	ring      *hashRing
+	exporters map[string]*backendExporter
+
+	// shutdowns tracks the exporters of the removed backends being shut down.
+	shutdowns sync.WaitGroup
+}
+
+func newLoadBalancer(logger *zap.Logger, res resolver, factory exporterFactory) *loadBalancer {
+	return &loadBalancer{
+		logger:          logger,
+		resolver:        res,
+		exporterFactory: factory,
+		ring:            newHashRing(nil),
+		exporters:       make(map[string]*backendExporter),
+	}
+}
+
+func (lb *loadBalancer) Start(ctx context.Context, host component.Host) error {
+	lb.host = host
+	lb.resolver.onChange(lb.onBackendChanges)
+	return lb.resolver.start(ctx)
+}
+
+func (lb *loadBalancer) Shutdown(ctx context.Context) error {
+	err := lb.resolver.shutdown(ctx)
+
+	lb.updateLock.Lock()
+	defer lb.updateLock.Unlock()
+	lb.lock.Lock()
+	exporters := lb.exporters
+	lb.ring = newHashRing(nil)
+	lb.exporters = make(map[string]*backendExporter)
+	lb.lock.Unlock()
+
+	for endpoint, exp := range exporters {
+		exp.inFlight.Wait()
+		if shutdownErr := exp.Shutdown(ctx); shutdownErr != nil {
+			lb.logger.Warn("Failed to shut down the exporter of a backend", zap.String("endpoint", endpoint), zap.Error(shutdownErr))
+		}
+	}
+	lb.shutdowns.Wait()
+	return err
+}
+
+// onBackendChanges creates the exporters of the new backends, rebalances the
+// keys over the backends, and shuts down the exporters of the removed ones in
+// the background once their calls in flight are done.
+func (lb *loadBalancer) onBackendChanges(endpoints []string) {
+	lb.updateLock.Lock()
+	defer lb.updateLock.Unlock()
+
+	lb.lock.RLock()
+	current := lb.exporters
+	lb.lock.RUnlock()
+
+	exporters := make(map[string]*backendExporter, len(endpoints))
+	available := make([]string, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		if exp, ok := current[endpoint]; ok {
+			exporters[endpoint] = exp
+			available = append(available, endpoint)
+			continue
+		}
+
+		exp, err := lb.exporterFactory(context.Background(), endpoint)
+		if err != nil {
+			lb.logger.Error("Failed to create the exporter of a backend", zap.String("endpoint", endpoint), zap.Error(err))
+			continue
+		}
+		if err = exp.Start(context.Background(), lb.host); err != nil {
+			lb.logger.Error("Failed to start the exporter of a backend", zap.String("endpoint", endpoint), zap.Error(err))
+			continue
+		}
+		exporters[endpoint] = &backendExporter{TracesExporter: exp}
+		available = append(available, endpoint)
+	}
+
+	lb.lock.Lock()
+	lb.ring = newHashRing(available)
+	lb.exporters = exporters
+	lb.lock.Unlock()
+	lb.logger.Info("Backends updated", zap.Strings("endpoints", available))
+
+	for endpoint, exp := range current {
+		if _, ok := exporters[endpoint]; ok {
+			continue
+		}
+		lb.shutdowns.Add(1)
+		go func(endpoint string, exp *backendExporter) {
+			defer lb.shutdowns.Done()
+			exp.inFlight.Wait()
+			if err := exp.Shutdown(context.Background()); err != nil {
+				lb.logger.Warn("Failed to shut down the exporter of a removed backend", zap.String("endpoint", endpoint), zap.Error(err))
+			}
+		}(endpoint, exp)
+	}
+}
+
+// exporterFor returns the endpoint and the exporter of the backend of the key,
+// or false if there are no backends. The exporter isn't shut down until the
+// caller calls inFlight.Done, once it sent the data to it.
+func (lb *loadBalancer) exporterFor(key []byte) (string, *backendExporter, bool) {
+	lb.lock.RLock()
+	defer lb.lock.RUnlock()
+	endpoint := lb.ring.endpointFor(key)
+	exp, ok := lb.exporters[endpoint]
+	if ok {
+		exp.inFlight.Add(1)
+	}
+	return endpoint, exp, ok
+}
@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"go.uber.org/zap"
+)
+
+// defaultPort is the port of the backends without an explicit one, the OTLP
+// gRPC port.
+const defaultPort = "4317"
+
+var (
+	errNoResolver        = errors.New("no resolver of the backends configured")
+	errMultipleResolvers = errors.New("only one resolver of the backends can be configured")
+)
+
+// resolver provides the list of backends, and notifies its changes.
+type resolver interface {
+	// start resolves the backends and starts watching them.
+	start(ctx context.Context) error
+
+	// shutdown stops watching the backends.
+	shutdown(ctx context.Context) error
+
+	// onChange registers a callback called with the sorted endpoints of the
+	// backends when they change, including when first resolved.
+	onChange(f func(endpoints []string))
+}
+
+func newResolver(cfg ResolverSettings, logger *zap.Logger) (resolver, error) {
+	switch {
+	case cfg.Static != nil && cfg.DNS != nil:
+		return nil, errMultipleResolvers
+	case cfg.Static != nil:
+		return newStaticResolver(*cfg.Static)
+	case cfg.DNS != nil:
+		return newDNSResolver(*cfg.DNS, logger)
+	default:
+		return nil, errNoResolver
+	}
+}
+
+// sortedEndpoints returns the endpoints sorted and without duplicates.
+func sortedEndpoints(endpoints []string) []string {
+	result := make([]string, 0, len(endpoints))
+	seen := make(map[string]bool, len(endpoints))
+	for _, endpoint := range endpoints {
+		if !seen[endpoint] {
+			seen[endpoint] = true
+			result = append(result, endpoint)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func equalEndpoints(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
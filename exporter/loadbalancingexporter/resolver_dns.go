@@ -0,0 +1,161 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultResolveInterval = 5 * time.Second
+	defaultResolveTimeout  = time.Second
+)
+
+var errNoHostname = errors.New("the DNS resolver requires a hostname")
+
+// dnsResolver periodically resolves a hostname, each of its IP addresses
+// being a backend.
+type dnsResolver struct {
+	logger   *zap.Logger
+	hostname string
+	port     string
+	interval time.Duration
+	timeout  time.Duration
+
+	// lookupIPAddr resolves the hostname, replaced in tests.
+	lookupIPAddr func(ctx context.Context, host string) ([]net.IPAddr, error)
+
+	lock      sync.Mutex
+	endpoints []string
+	callbacks []func(endpoints []string)
+
+	stopCh chan struct{}
+	// doneCh is closed when the periodic resolution stops, nil if it never
+	// started.
+	doneCh chan struct{}
+}
+
+func newDNSResolver(cfg DNSResolver, logger *zap.Logger) (*dnsResolver, error) {
+	if cfg.Hostname == "" {
+		return nil, errNoHostname
+	}
+
+	r := &dnsResolver{
+		logger:       logger,
+		hostname:     cfg.Hostname,
+		port:         cfg.Port,
+		interval:     cfg.Interval,
+		timeout:      cfg.Timeout,
+		lookupIPAddr: net.DefaultResolver.LookupIPAddr,
+		stopCh:       make(chan struct{}),
+	}
+	if r.port == "" {
+		r.port = defaultPort
+	}
+	if r.interval <= 0 {
+		r.interval = defaultResolveInterval
+	}
+	if r.timeout <= 0 {
+		r.timeout = defaultResolveTimeout
+	}
+	return r, nil
+}
+
+// start resolves the hostname a first time, a failure not preventing the
+// exporter from starting as the next resolutions may succeed.
+func (r *dnsResolver) start(ctx context.Context) error {
+	if err := r.resolve(ctx); err != nil {
+		r.logger.Warn("Failed to resolve the backends", zap.String("hostname", r.hostname), zap.Error(err))
+	}
+	doneCh := make(chan struct{})
+	r.lock.Lock()
+	r.doneCh = doneCh
+	r.lock.Unlock()
+	go r.periodicallyResolve(doneCh)
+	return nil
+}
+
+func (r *dnsResolver) shutdown(ctx context.Context) error {
+	close(r.stopCh)
+	r.lock.Lock()
+	doneCh := r.doneCh
+	r.lock.Unlock()
+	if doneCh == nil {
+		return nil
+	}
+	select {
+	case <-doneCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *dnsResolver) onChange(f func(endpoints []string)) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.callbacks = append(r.callbacks, f)
+}
+
+func (r *dnsResolver) periodicallyResolve(doneCh chan struct{}) {
+	defer close(doneCh)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := r.resolve(context.Background()); err != nil {
+				r.logger.Warn("Failed to resolve the backends, keeping the current ones",
+					zap.String("hostname", r.hostname), zap.Error(err))
+			}
+		case <-r.stopCh:
+			return
+		}
+	}
+}
+
+// resolve resolves the hostname, and calls the callbacks if the backends
+// changed.
+func (r *dnsResolver) resolve(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	addrs, err := r.lookupIPAddr(ctx, r.hostname)
+	if err != nil {
+		return err
+	}
+
+	endpoints := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		endpoints = append(endpoints, net.JoinHostPort(addr.IP.String(), r.port))
+	}
+	endpoints = sortedEndpoints(endpoints)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.endpoints != nil && equalEndpoints(r.endpoints, endpoints) {
+		return nil
+	}
+	r.endpoints = endpoints
+	for _, callback := range r.callbacks {
+		callback(endpoints)
+	}
+	return nil
+}
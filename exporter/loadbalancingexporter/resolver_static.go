@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+var errNoHostnames = errors.New("the static resolver requires at least one hostname")
+
+// staticResolver provides a fixed list of backends.
+type staticResolver struct {
+	endpoints []string
+	callbacks []func(endpoints []string)
+}
+
+func newStaticResolver(cfg StaticResolver) (*staticResolver, error) {
+	if len(cfg.Hostnames) == 0 {
+		return nil, errNoHostnames
+	}
+
+	endpoints := make([]string, 0, len(cfg.Hostnames))
+	for _, hostname := range cfg.Hostnames {
+		if _, _, err := net.SplitHostPort(hostname); err != nil {
+			hostname = net.JoinHostPort(hostname, defaultPort)
+		}
+		endpoints = append(endpoints, hostname)
+	}
+	return &staticResolver{endpoints: sortedEndpoints(endpoints)}, nil
+}
+
+func (r *staticResolver) start(context.Context) error {
+	for _, callback := range r.callbacks {
+		callback(r.endpoints)
+	}
+	return nil
+}
+
+func (r *staticResolver) shutdown(context.Context) error {
+	return nil
+}
+
+func (r *staticResolver) onChange(f func(endpoints []string)) {
+	r.callbacks = append(r.callbacks, f)
+}
@@ -0,0 +1,133 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNewResolver(t *testing.T) {
+	_, err := newResolver(ResolverSettings{}, zap.NewNop())
+	assert.Equal(t, errNoResolver, err)
+
+	_, err = newResolver(ResolverSettings{Static: &StaticResolver{}}, zap.NewNop())
+	assert.Equal(t, errNoHostnames, err)
+
+	_, err = newResolver(ResolverSettings{DNS: &DNSResolver{}}, zap.NewNop())
+	assert.Equal(t, errNoHostname, err)
+}
+
+func TestStaticResolver(t *testing.T) {
+	res, err := newStaticResolver(StaticResolver{Hostnames: []string{"backend-2", "backend-1:55680", "backend-2:4317"}})
+	require.NoError(t, err)
+
+	var resolved []string
+	res.onChange(func(endpoints []string) {
+		resolved = endpoints
+	})
+	require.NoError(t, res.start(context.Background()))
+	assert.Equal(t, []string{"backend-1:55680", "backend-2:4317"}, resolved)
+	assert.NoError(t, res.shutdown(context.Background()))
+}
+
+type fakeLookup struct {
+	lock  sync.Mutex
+	addrs []net.IPAddr
+	err   error
+}
+
+func (f *fakeLookup) set(err error, ips ...string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.err = err
+	f.addrs = nil
+	for _, ip := range ips {
+		f.addrs = append(f.addrs, net.IPAddr{IP: net.ParseIP(ip)})
+	}
+}
+
+func (f *fakeLookup) lookupIPAddr(context.Context, string) ([]net.IPAddr, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.addrs, f.err
+}
+
+func TestDNSResolver(t *testing.T) {
+	res, err := newDNSResolver(DNSResolver{Hostname: "backends", Interval: 10 * time.Millisecond}, zap.NewNop())
+	require.NoError(t, err)
+	lookup := &fakeLookup{}
+	lookup.set(nil, "10.0.0.2", "10.0.0.1")
+	res.lookupIPAddr = lookup.lookupIPAddr
+
+	changes := make(chan []string, 10)
+	res.onChange(func(endpoints []string) {
+		changes <- endpoints
+	})
+	require.NoError(t, res.start(context.Background()))
+	assert.Equal(t, []string{"10.0.0.1:4317", "10.0.0.2:4317"}, <-changes)
+
+	// Failures keep the current backends.
+	lookup.set(errors.New("lookup failed"))
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(t, changes, 0)
+
+	lookup.set(nil, "10.0.0.3")
+	select {
+	case endpoints := <-changes:
+		assert.Equal(t, []string{"10.0.0.3:4317"}, endpoints)
+	case <-time.After(time.Second):
+		t.Fatal("the change of backends wasn't notified")
+	}
+
+	assert.NoError(t, res.shutdown(context.Background()))
+}
+
+func TestDNSResolverStartFailure(t *testing.T) {
+	res, err := newDNSResolver(DNSResolver{Hostname: "backends", Port: "55680"}, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, defaultResolveInterval, res.interval)
+	assert.Equal(t, defaultResolveTimeout, res.timeout)
+	lookup := &fakeLookup{}
+	lookup.set(errors.New("lookup failed"))
+	res.lookupIPAddr = lookup.lookupIPAddr
+
+	// The exporter starts, waiting for the backends to be resolved.
+	assert.NoError(t, res.start(context.Background()))
+
+	lookup.set(nil, "10.0.0.1")
+	var resolved []string
+	res.onChange(func(endpoints []string) {
+		resolved = endpoints
+	})
+	require.NoError(t, res.resolve(context.Background()))
+	assert.Equal(t, []string{"10.0.0.1:55680"}, resolved)
+
+	assert.NoError(t, res.shutdown(context.Background()))
+}
+
+func TestDNSResolverShutdownWithoutStart(t *testing.T) {
+	res, err := newDNSResolver(DNSResolver{Hostname: "backends"}, zap.NewNop())
+	require.NoError(t, err)
+	assert.NoError(t, res.shutdown(context.Background()))
+}
@@ -0,0 +1,123 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/otlpexporter"
+	"go.opentelemetry.io/collector/internal/batchpersignal"
+)
+
+var errNoBackends = errors.New("no backends available")
+
+// traceExporter sends all the spans of a trace to the same backend, chosen by
+// consistent hashing of the trace ID.
+type traceExporter struct {
+	loadBalancer *loadBalancer
+}
+
+var _ component.TracesExporter = (*traceExporter)(nil)
+
+func newTracesExporter(params component.ExporterCreateParams, cfg *Config) (*traceExporter, error) {
+	res, err := newResolver(cfg.Resolver, params.Logger)
+	if err != nil {
+		return nil, err
+	}
+
+	otlpFactory := otlpexporter.NewFactory()
+	factory := func(ctx context.Context, endpoint string) (component.TracesExporter, error) {
+		otlpCfg := cfg.Protocol.OTLP
+		otlpCfg.TypeVal = otlpFactory.Type()
+		otlpCfg.SetName(fmt.Sprintf("%s/%s", cfg.Name(), endpoint))
+		otlpCfg.Endpoint = endpoint
+		return otlpFactory.CreateTracesExporter(ctx, params, &otlpCfg)
+	}
+
+	return &traceExporter{loadBalancer: newLoadBalancer(params.Logger, res, factory)}, nil
+}
+
+func (e *traceExporter) Start(ctx context.Context, host component.Host) error {
+	return e.loadBalancer.Start(ctx, host)
+}
+
+func (e *traceExporter) Shutdown(ctx context.Context) error {
+	return e.loadBalancer.Shutdown(ctx)
+}
+
+// ConsumeTraces splits the traces by trace ID, and sends each backend the
+// traces assigned to it in a single batch. If some backends fail, the returned
+// error is a partial error with the traces of their batches only, so that only
+// those are retried.
+func (e *traceExporter) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	var endpoints []string
+	batches := make(map[string]pdata.Traces)
+	exporters := make(map[string]*backendExporter)
+	defer func() {
+		for _, exp := range exporters {
+			exp.inFlight.Done()
+		}
+	}()
+	for _, trace := range batchpersignal.SplitTraces(td) {
+		traceID := trace.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).TraceID().Bytes()
+		endpoint, exp, ok := e.loadBalancer.exporterFor(traceID[:])
+		if !ok {
+			// the backends are not resolved yet, or are changing: the data may be retried
+			return errNoBackends
+		}
+
+		batch, ok := batches[endpoint]
+		if ok {
+			// The exporter of the batch is already in flight.
+			exp.inFlight.Done()
+		} else {
+			batch = pdata.NewTraces()
+			batches[endpoint] = batch
+			exporters[endpoint] = exp
+			endpoints = append(endpoints, endpoint)
+		}
+		rss := trace.ResourceSpans()
+		for i := 0; i < rss.Len(); i++ {
+			batch.ResourceSpans().Append(rss.At(i))
+		}
+	}
+
+	var errs []error
+	failed := pdata.NewTraces()
+	for _, endpoint := range endpoints {
+		err := exporters[endpoint].ConsumeTraces(ctx, batches[endpoint])
+		if err == nil {
+			continue
+		}
+		errs = append(errs, fmt.Errorf("failed to export to %s: %w", endpoint, err))
+		if !consumererror.IsPermanent(err) {
+			batches[endpoint].ResourceSpans().MoveAndAppendTo(failed.ResourceSpans())
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	err := componenterror.CombineErrors(errs)
+	if failed.ResourceSpans().Len() == 0 {
+		return consumererror.Permanent(err)
+	}
+	return consumererror.PartialTracesError(err, failed)
+}
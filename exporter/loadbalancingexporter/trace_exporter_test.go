@@ -0,0 +1,304 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// mockBackend records the traces sent to a backend.
+type mockBackend struct {
+	consumertest.TracesSink
+	lock     sync.Mutex
+	started  bool
+	shutdown bool
+	err      error
+	// consuming, if set, is notified when a call starts, which then waits for
+	// release to be closed.
+	consuming chan struct{}
+	release   chan struct{}
+}
+
+func (b *mockBackend) Start(context.Context, component.Host) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.started = true
+	return nil
+}
+
+func (b *mockBackend) Shutdown(context.Context) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.shutdown = true
+	return nil
+}
+
+func (b *mockBackend) isShutdown() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.shutdown
+}
+
+func (b *mockBackend) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	if b.consuming != nil {
+		b.consuming <- struct{}{}
+		<-b.release
+	}
+	if b.err != nil {
+		return b.err
+	}
+	return b.TracesSink.ConsumeTraces(ctx, td)
+}
+
+// mockResolver notifies the backends set by the test.
+type mockResolver struct {
+	callbacks []func(endpoints []string)
+	endpoints []string
+}
+
+func (r *mockResolver) start(context.Context) error {
+	r.set(r.endpoints...)
+	return nil
+}
+
+func (r *mockResolver) shutdown(context.Context) error {
+	return nil
+}
+
+func (r *mockResolver) onChange(f func(endpoints []string)) {
+	r.callbacks = append(r.callbacks, f)
+}
+
+func (r *mockResolver) set(endpoints ...string) {
+	r.endpoints = endpoints
+	for _, callback := range r.callbacks {
+		callback(endpoints)
+	}
+}
+
+func newTestExporter(endpoints ...string) (*traceExporter, *mockResolver, map[string]*mockBackend) {
+	res := &mockResolver{endpoints: endpoints}
+	backends := make(map[string]*mockBackend)
+	factory := func(_ context.Context, endpoint string) (component.TracesExporter, error) {
+		backend := &mockBackend{}
+		backends[endpoint] = backend
+		return backend, nil
+	}
+	return &traceExporter{loadBalancer: newLoadBalancer(zap.NewNop(), res, factory)}, res, backends
+}
+
+func newTraces(traceIDs ...byte) pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	td.ResourceSpans().At(0).Resource().Attributes().InsertString("service.name", "test")
+	td.ResourceSpans().At(0).InstrumentationLibrarySpans().Resize(1)
+	spans := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	spans.Resize(len(traceIDs))
+	for i, id := range traceIDs {
+		spans.At(i).SetTraceID(pdata.NewTraceID([16]byte{id}))
+		spans.At(i).SetName("span")
+	}
+	return td
+}
+
+// receivedTraceIDs returns the first byte of the trace IDs of the spans
+// received by the backend.
+func receivedTraceIDs(backend *mockBackend) []byte {
+	return traceIDs(backend.AllTraces()...)
+}
+
+// traceIDs returns the first byte of the trace IDs of the spans.
+func traceIDs(tds ...pdata.Traces) []byte {
+	var ids []byte
+	for _, td := range tds {
+		rss := td.ResourceSpans()
+		for i := 0; i < rss.Len(); i++ {
+			ilss := rss.At(i).InstrumentationLibrarySpans()
+			for j := 0; j < ilss.Len(); j++ {
+				spans := ilss.At(j).Spans()
+				for k := 0; k < spans.Len(); k++ {
+					ids = append(ids, spans.At(k).TraceID().Bytes()[0])
+				}
+			}
+		}
+	}
+	return ids
+}
+
+func TestConsumeTraces(t *testing.T) {
+	exp, _, backends := newTestExporter("backend-1:4317", "backend-2:4317", "backend-3:4317")
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+
+	var ids []byte
+	for i := 0; i < 30; i++ {
+		// Each trace has two spans.
+		ids = append(ids, byte(i), byte(i))
+	}
+	require.NoError(t, exp.ConsumeTraces(context.Background(), newTraces(ids...)))
+
+	total := 0
+	for endpoint, backend := range backends {
+		// Each backend got a single batch, with all the spans of its traces.
+		assert.Len(t, backend.AllTraces(), 1, endpoint)
+		received := receivedTraceIDs(backend)
+		total += len(received)
+		for _, id := range received {
+			traceID := [16]byte{id}
+			owner, backend, ok := exp.loadBalancer.exporterFor(traceID[:])
+			require.True(t, ok)
+			backend.inFlight.Done()
+			assert.Equal(t, endpoint, owner)
+		}
+		// The resource is kept.
+		serviceName, _ := backend.AllTraces()[0].ResourceSpans().At(0).Resource().Attributes().Get("service.name")
+		assert.Equal(t, "test", serviceName.StringVal())
+	}
+	assert.Equal(t, 60, total)
+
+	require.NoError(t, exp.Shutdown(context.Background()))
+	for _, backend := range backends {
+		assert.True(t, backend.started)
+		assert.True(t, backend.shutdown)
+	}
+}
+
+func TestConsumeTracesNoBackends(t *testing.T) {
+	exp, _, _ := newTestExporter()
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+
+	err := exp.ConsumeTraces(context.Background(), newTraces(1))
+	assert.Equal(t, errNoBackends, err)
+	assert.False(t, consumererror.IsPermanent(err))
+
+	// Empty batches don't need a backend.
+	assert.NoError(t, exp.ConsumeTraces(context.Background(), pdata.NewTraces()))
+}
+
+func TestConsumeTracesBackendFailure(t *testing.T) {
+	exp, _, backends := newTestExporter("backend-1:4317", "backend-2:4317")
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	backends["backend-1:4317"].err = errors.New("unavailable")
+
+	ids := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	err := exp.ConsumeTraces(context.Background(), newTraces(ids...))
+	assert.EqualError(t, err, "failed to export to backend-1:4317: unavailable")
+
+	// Only the traces of the failed backend are to be retried.
+	partialErr, ok := err.(consumererror.PartialError)
+	require.True(t, ok)
+	failed := traceIDs(partialErr.GetTraces())
+	sent := receivedTraceIDs(backends["backend-2:4317"])
+	assert.NotEmpty(t, failed)
+	assert.NotEmpty(t, sent)
+	assert.ElementsMatch(t, ids, append(failed, sent...))
+
+	// The traces of backends failing permanently are dropped.
+	backends["backend-1:4317"].err = consumererror.Permanent(errors.New("invalid data"))
+	err = exp.ConsumeTraces(context.Background(), newTraces(ids...))
+	assert.True(t, consumererror.IsPermanent(err))
+}
+
+func TestBackendChanges(t *testing.T) {
+	exp, res, backends := newTestExporter("backend-1:4317", "backend-2:4317")
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	first1 := backends["backend-1:4317"]
+	first2 := backends["backend-2:4317"]
+
+	res.set("backend-1:4317", "backend-3:4317")
+
+	// The exporter of the kept backend is reused, the one of the removed
+	// backend is shut down.
+	assert.Same(t, first1, backends["backend-1:4317"])
+	assert.False(t, first1.isShutdown())
+	assert.Eventually(t, first2.isShutdown, time.Second, time.Millisecond)
+	assert.True(t, backends["backend-3:4317"].started)
+
+	for i := 0; i < 20; i++ {
+		endpoint, backend, ok := exp.loadBalancer.exporterFor([]byte{byte(i)})
+		require.True(t, ok)
+		backend.inFlight.Done()
+		assert.Contains(t, []string{"backend-1:4317", "backend-3:4317"}, endpoint)
+	}
+	require.NoError(t, exp.Shutdown(context.Background()))
+}
+
+func TestBackendRemovedWhileExporting(t *testing.T) {
+	exp, res, backends := newTestExporter("backend-1:4317")
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	backend := backends["backend-1:4317"]
+	backend.consuming = make(chan struct{})
+	backend.release = make(chan struct{})
+
+	done := make(chan error)
+	go func() {
+		done <- exp.ConsumeTraces(context.Background(), newTraces(1))
+	}()
+	<-backend.consuming
+
+	// The exporter of the removed backend is only shut down once the call in
+	// flight is done.
+	res.set("backend-2:4317")
+	time.Sleep(20 * time.Millisecond)
+	assert.False(t, backend.isShutdown())
+
+	close(backend.release)
+	assert.NoError(t, <-done)
+	assert.Eventually(t, backend.isShutdown, time.Second, time.Millisecond)
+	assert.Equal(t, []byte{1}, receivedTraceIDs(backend))
+	require.NoError(t, exp.Shutdown(context.Background()))
+}
+
+func TestBackendExporterCreationFailure(t *testing.T) {
+	res := &mockResolver{endpoints: []string{"backend-1:4317", "backend-2:4317"}}
+	backend := &mockBackend{}
+	factory := func(_ context.Context, endpoint string) (component.TracesExporter, error) {
+		if endpoint == "backend-1:4317" {
+			return nil, errors.New("invalid endpoint")
+		}
+		return backend, nil
+	}
+	exp := &traceExporter{loadBalancer: newLoadBalancer(zap.NewNop(), res, factory)}
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+
+	// The backends without exporter aren't used.
+	require.NoError(t, exp.ConsumeTraces(context.Background(), newTraces(1, 2, 3, 4, 5, 6)))
+	assert.Len(t, receivedTraceIDs(backend), 6)
+}
+
+func TestOTLPBackends(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Resolver.Static = &StaticResolver{Hostnames: []string{"localhost:14317", "localhost:24317"}}
+	exp, err := createTraceExporter(context.Background(), component.ExporterCreateParams{Logger: zap.NewNop()}, cfg)
+	require.NoError(t, err)
+
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	lb := exp.(*traceExporter).loadBalancer
+	assert.Len(t, lb.exporters, 2)
+	assert.NoError(t, exp.Shutdown(context.Background()))
+}
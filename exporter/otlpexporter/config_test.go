@@ -93,3 +93,14 @@ func TestLoadConfig(t *testing.T) {
 			},
 		})
 }
+
+func TestLoadConfigInsecureAuth(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	assert.NoError(t, err)
+
+	factories.Exporters[typeStr] = NewFactory()
+	_, err = configtest.LoadConfigFile(t, path.Join(".", "testdata", "config_insecure_auth.yaml"), factories)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `exporter "otlp" has invalid configuration`)
+	assert.Contains(t, err.Error(), `"insecure: true"`)
+}
@@ -15,6 +15,8 @@
 package healthcheckextension
 
 import (
+	"time"
+
 	"go.opentelemetry.io/collector/config/configmodels"
 )
 
@@ -26,4 +28,30 @@ type Config struct {
 	// Port is the port used to publish the health check status.
 	// The default value is 13133.
 	Port uint16 `mapstructure:"port"`
+
+	// LivenessPath is the path of the liveness endpoint, healthy as long as
+	// the process is running. The default value is "/live".
+	LivenessPath string `mapstructure:"liveness_path"`
+
+	// ReadinessPath is the path of the readiness endpoint, healthy when the
+	// pipelines are ready and none of their exporters is failing. The default
+	// value is "/ready".
+	ReadinessPath string `mapstructure:"readiness_path"`
+
+	// ExporterFailures configures when an exporter is considered failing.
+	ExporterFailures ExporterFailuresSettings `mapstructure:"exporter_failures"`
+}
+
+// ExporterFailuresSettings defines when an exporter is considered failing,
+// from the items it sent and failed to send recently.
+type ExporterFailuresSettings struct {
+	// Window is the duration over which the failure rate of each exporter is
+	// computed. The exporter failures are ignored if set to 0. The default
+	// value is 5m.
+	Window time.Duration `mapstructure:"window"`
+
+	// Threshold is the ratio, between 0 and 1, of the items that failed to be
+	// sent over the window above which the exporter is failing. The default
+	// value is 0.5.
+	Threshold float64 `mapstructure:"threshold"`
 }
@@ -17,6 +17,7 @@ package healthcheckextension
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -47,7 +48,13 @@ func TestLoadConfig(t *testing.T) {
 				TypeVal: "health_check",
 				NameVal: "health_check/1",
 			},
-			Port: 13,
+			Port:          13,
+			LivenessPath:  "/healthz",
+			ReadinessPath: "/readyz",
+			ExporterFailures: ExporterFailuresSettings{
+				Window:    time.Minute,
+				Threshold: 0.2,
+			},
 		},
 		ext1)
 
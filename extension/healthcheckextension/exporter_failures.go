@@ -0,0 +1,129 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheckextension
+
+import (
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// numBuckets is the number of buckets the window is divided into. The failure
+// rate is computed over the last numBuckets buckets, so the window slides by
+// increments of window/numBuckets.
+const numBuckets = 10
+
+// exporterKey identifies the data type exported by an exporter, an exporter
+// used in pipelines of different types reporting each type separately.
+type exporterKey struct {
+	name     string
+	dataType configmodels.DataType
+}
+
+// exporterFailures tracks the items sent and failed to be sent by each
+// exporter over a sliding window.
+type exporterFailures struct {
+	mutex          sync.Mutex
+	bucketDuration time.Duration
+	exporters      map[exporterKey]*exporterWindow
+	now            func() time.Time
+}
+
+type exporterWindow struct {
+	buckets [numBuckets]failureBucket
+	// lastError is the error of the last failed export, and lastErrorBucket
+	// the index of the bucket it was recorded in.
+	lastError       string
+	lastErrorBucket int64
+}
+
+type failureBucket struct {
+	index  int64
+	sent   int64
+	failed int64
+}
+
+func newExporterFailures(window time.Duration) *exporterFailures {
+	bucketDuration := window / numBuckets
+	if bucketDuration <= 0 {
+		bucketDuration = 1
+	}
+	return &exporterFailures{
+		bucketDuration: bucketDuration,
+		exporters:      make(map[exporterKey]*exporterWindow),
+		now:            time.Now,
+	}
+}
+
+// record adds the result of an export operation to the window of the exporter.
+func (ef *exporterFailures) record(result component.ExportResult) {
+	ef.mutex.Lock()
+	defer ef.mutex.Unlock()
+
+	key := exporterKey{name: result.ExporterName, dataType: result.DataType}
+	w := ef.exporters[key]
+	if w == nil {
+		w = &exporterWindow{}
+		ef.exporters[key] = w
+	}
+
+	index := ef.currentBucket()
+	b := &w.buckets[index%numBuckets]
+	if b.index != index {
+		*b = failureBucket{index: index}
+	}
+	b.sent += result.NumSent
+	b.failed += result.NumFailedToSend
+	if result.Err != nil {
+		w.lastError = result.Err.Error()
+		w.lastErrorBucket = index
+	}
+}
+
+// status returns the ratio of the items that the exporter failed to send over
+// the window, and the last error it returned in the window.
+func (ef *exporterFailures) status(name string, dataType configmodels.DataType) (float64, string) {
+	ef.mutex.Lock()
+	defer ef.mutex.Unlock()
+
+	w := ef.exporters[exporterKey{name: name, dataType: dataType}]
+	if w == nil {
+		return 0, ""
+	}
+
+	index := ef.currentBucket()
+	var sent, failed int64
+	for _, b := range w.buckets {
+		if index-b.index < numBuckets {
+			sent += b.sent
+			failed += b.failed
+		}
+	}
+
+	var lastError string
+	if index-w.lastErrorBucket < numBuckets {
+		lastError = w.lastError
+	}
+	if sent+failed == 0 {
+		return 0, lastError
+	}
+	return float64(failed) / float64(sent+failed), lastError
+}
+
+func (ef *exporterFailures) currentBucket() int64 {
+	return ef.now().UnixNano() / int64(ef.bucketDuration)
+}
@@ -0,0 +1,64 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheckextension
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+func TestExporterFailures(t *testing.T) {
+	now := time.Unix(1000, 0)
+	ef := newExporterFailures(10 * time.Second)
+	ef.now = func() time.Time { return now }
+
+	rate, lastError := ef.status("otlp", configmodels.TracesDataType)
+	assert.Equal(t, 0.0, rate)
+	assert.Equal(t, "", lastError)
+
+	ef.record(component.ExportResult{ExporterName: "otlp", DataType: configmodels.TracesDataType, NumSent: 6})
+	ef.record(component.ExportResult{ExporterName: "otlp", DataType: configmodels.TracesDataType, NumFailedToSend: 2, Err: errors.New("unavailable")})
+	ef.record(component.ExportResult{ExporterName: "otlp", DataType: configmodels.MetricsDataType, NumSent: 5})
+
+	rate, lastError = ef.status("otlp", configmodels.TracesDataType)
+	assert.Equal(t, 0.25, rate)
+	assert.Equal(t, "unavailable", lastError)
+	rate, lastError = ef.status("otlp", configmodels.MetricsDataType)
+	assert.Equal(t, 0.0, rate)
+	assert.Equal(t, "", lastError)
+
+	// The window slides by buckets of a second.
+	now = now.Add(5 * time.Second)
+	ef.record(component.ExportResult{ExporterName: "otlp", DataType: configmodels.TracesDataType, NumFailedToSend: 8, Err: errors.New("timeout")})
+	rate, lastError = ef.status("otlp", configmodels.TracesDataType)
+	assert.Equal(t, 10.0/16.0, rate)
+	assert.Equal(t, "timeout", lastError)
+
+	now = now.Add(5 * time.Second)
+	rate, lastError = ef.status("otlp", configmodels.TracesDataType)
+	assert.Equal(t, 1.0, rate)
+	assert.Equal(t, "timeout", lastError)
+
+	now = now.Add(5 * time.Second)
+	rate, lastError = ef.status("otlp", configmodels.TracesDataType)
+	assert.Equal(t, 0.0, rate)
+	assert.Equal(t, "", lastError)
+}
@@ -17,7 +17,10 @@ package healthcheckextension
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"sync/atomic"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
@@ -27,6 +30,11 @@ import (
 const (
 	// The value of extension "type" in configuration.
 	typeStr = "health_check"
+
+	defaultLivenessPath              = "/live"
+	defaultReadinessPath             = "/ready"
+	defaultExporterFailuresWindow    = 5 * time.Minute
+	defaultExporterFailuresThreshold = 0.5
 )
 
 // NewFactory creates a factory for HealthCheck extension.
@@ -43,12 +51,21 @@ func createDefaultConfig() configmodels.Extension {
 			TypeVal: typeStr,
 			NameVal: typeStr,
 		},
-		Port: 13133,
+		Port:          13133,
+		LivenessPath:  defaultLivenessPath,
+		ReadinessPath: defaultReadinessPath,
+		ExporterFailures: ExporterFailuresSettings{
+			Window:    defaultExporterFailuresWindow,
+			Threshold: defaultExporterFailuresThreshold,
+		},
 	}
 }
 
 func createExtension(_ context.Context, params component.ExtensionCreateParams, cfg configmodels.Extension) (component.ServiceExtension, error) {
 	config := cfg.(*Config)
+	if err := validateConfig(config); err != nil {
+		return nil, err
+	}
 
 	// The runtime settings are global to the application, so while in principle it
 	// is possible to have more than one instance, running multiple does not bring
@@ -64,6 +81,24 @@ func createExtension(_ context.Context, params component.ExtensionCreateParams,
 	return newServer(*config, params.Logger), nil
 }
 
+func validateConfig(config *Config) error {
+	for _, path := range []string{config.LivenessPath, config.ReadinessPath} {
+		if path != "" && !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("invalid path %q, must start with /", path)
+		}
+	}
+	if config.LivenessPath != "" && config.LivenessPath == config.ReadinessPath {
+		return errors.New("liveness_path and readiness_path must be different")
+	}
+	if config.ExporterFailures.Window < 0 {
+		return errors.New("exporter_failures window must not be negative")
+	}
+	if config.ExporterFailures.Threshold < 0 || config.ExporterFailures.Threshold > 1 {
+		return errors.New("exporter_failures threshold must be between 0 and 1")
+	}
+	return nil
+}
+
 // See comment in createExtension how these are used.
 var instanceState int32
 
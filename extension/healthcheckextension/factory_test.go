@@ -18,6 +18,7 @@ import (
 	"context"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -36,7 +37,13 @@ func TestFactory_CreateDefaultConfig(t *testing.T) {
 			NameVal: typeStr,
 			TypeVal: typeStr,
 		},
-		Port: 13133,
+		Port:          13133,
+		LivenessPath:  "/live",
+		ReadinessPath: "/ready",
+		ExporterFailures: ExporterFailuresSettings{
+			Window:    5 * time.Minute,
+			Threshold: 0.5,
+		},
 	},
 		cfg)
 
@@ -76,3 +83,44 @@ func TestFactory_CreateExtensionOnlyOnce(t *testing.T) {
 	// Restore instance tracking from factory, for other tests.
 	atomic.StoreInt32(&instanceState, instanceNotCreated)
 }
+
+func TestFactory_CreateExtensionInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+	}{
+		{
+			name: "relative path",
+			modify: func(cfg *Config) {
+				cfg.ReadinessPath = "ready"
+			},
+		},
+		{
+			name: "same paths",
+			modify: func(cfg *Config) {
+				cfg.ReadinessPath = cfg.LivenessPath
+			},
+		},
+		{
+			name: "negative window",
+			modify: func(cfg *Config) {
+				cfg.ExporterFailures.Window = -time.Second
+			},
+		},
+		{
+			name: "threshold above 1",
+			modify: func(cfg *Config) {
+				cfg.ExporterFailures.Threshold = 1.5
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tt.modify(cfg)
+			ext, err := createExtension(context.Background(), component.ExtensionCreateParams{Logger: zap.NewNop()}, cfg)
+			assert.Error(t, err)
+			assert.Nil(t, ext)
+		})
+	}
+}
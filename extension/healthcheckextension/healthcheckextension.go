@@ -16,24 +16,64 @@ package healthcheckextension
 
 import (
 	"context"
+	"encoding/json"
 	"net"
 	"net/http"
+	"sort"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
 	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
 )
 
 type healthCheckExtension struct {
-	config Config
-	logger *zap.Logger
-	state  *healthcheck.HealthCheck
-	server http.Server
+	config    Config
+	logger    *zap.Logger
+	state     *healthcheck.HealthCheck
+	server    http.Server
+	startTime time.Time
+
+	// failures is nil if the exporter failures are ignored.
+	failures *exporterFailures
+
+	mutex     sync.Mutex
+	ready     bool
+	pipelines configmodels.Pipelines
 }
 
 var _ component.PipelineWatcher = (*healthCheckExtension)(nil)
+var _ component.PipelineConfigWatcher = (*healthCheckExtension)(nil)
+var _ component.ExportWatcher = (*healthCheckExtension)(nil)
+
+// livenessStatus is the body of the liveness endpoint.
+type livenessStatus struct {
+	Alive   bool      `json:"alive"`
+	UpSince time.Time `json:"up_since"`
+	Uptime  string    `json:"uptime"`
+}
+
+// readinessStatus is the body of the readiness endpoint.
+type readinessStatus struct {
+	Ready     bool                      `json:"ready"`
+	Pipelines map[string]pipelineStatus `json:"pipelines,omitempty"`
+}
+
+type pipelineStatus struct {
+	Healthy             bool              `json:"healthy"`
+	UnhealthyComponents []componentStatus `json:"unhealthy_components,omitempty"`
+}
+
+type componentStatus struct {
+	Kind        string  `json:"kind"`
+	Name        string  `json:"name"`
+	FailureRate float64 `json:"failure_rate"`
+	LastError   string  `json:"last_error,omitempty"`
+}
 
 func (hc *healthCheckExtension) Start(_ context.Context, host component.Host) error {
 
@@ -47,8 +87,17 @@ func (hc *healthCheckExtension) Start(_ context.Context, host component.Host) er
 		return nil
 	}
 
-	// Mount HC handler
-	hc.server.Handler = hc.state.Handler()
+	// Mount HC handlers, the status of the pipelines being served on any
+	// other path.
+	mux := http.NewServeMux()
+	if hc.config.LivenessPath != "" {
+		mux.HandleFunc(hc.config.LivenessPath, hc.handleLiveness)
+	}
+	if hc.config.ReadinessPath != "" {
+		mux.HandleFunc(hc.config.ReadinessPath, hc.handleReadiness)
+	}
+	mux.Handle("/", hc.state.Handler())
+	hc.server.Handler = mux
 
 	go func() {
 		// The listener ownership goes to the server.
@@ -65,21 +114,106 @@ func (hc *healthCheckExtension) Shutdown(context.Context) error {
 }
 
 func (hc *healthCheckExtension) Ready() error {
+	hc.mutex.Lock()
+	hc.ready = true
+	hc.mutex.Unlock()
 	hc.state.Set(healthcheck.Ready)
 	return nil
 }
 
 func (hc *healthCheckExtension) NotReady() error {
+	hc.mutex.Lock()
+	hc.ready = false
+	hc.mutex.Unlock()
 	hc.state.Set(healthcheck.Unavailable)
 	return nil
 }
 
+func (hc *healthCheckExtension) PipelinesConfigured(pipelines configmodels.Pipelines) error {
+	hc.mutex.Lock()
+	defer hc.mutex.Unlock()
+	hc.pipelines = pipelines
+	return nil
+}
+
+func (hc *healthCheckExtension) ExportCompleted(result component.ExportResult) {
+	if hc.failures != nil {
+		hc.failures.record(result)
+	}
+}
+
+func (hc *healthCheckExtension) handleLiveness(w http.ResponseWriter, _ *http.Request) {
+	hc.writeStatus(w, http.StatusOK, livenessStatus{
+		Alive:   true,
+		UpSince: hc.startTime,
+		Uptime:  time.Since(hc.startTime).String(),
+	})
+}
+
+func (hc *healthCheckExtension) handleReadiness(w http.ResponseWriter, _ *http.Request) {
+	status := hc.readinessStatus()
+	code := http.StatusOK
+	if !status.Ready {
+		code = http.StatusServiceUnavailable
+	}
+	hc.writeStatus(w, code, status)
+}
+
+// readinessStatus returns the status of each pipeline, unhealthy if any of its
+// exporters is failing. The service is ready if the pipelines are ready and
+// healthy.
+func (hc *healthCheckExtension) readinessStatus() readinessStatus {
+	hc.mutex.Lock()
+	defer hc.mutex.Unlock()
+
+	status := readinessStatus{
+		Ready:     hc.ready,
+		Pipelines: make(map[string]pipelineStatus, len(hc.pipelines)),
+	}
+	for name, pipeline := range hc.pipelines {
+		ps := pipelineStatus{Healthy: true}
+		if hc.failures != nil {
+			exporters := append([]string(nil), pipeline.Exporters...)
+			sort.Strings(exporters)
+			for _, exporter := range exporters {
+				rate, lastError := hc.failures.status(exporter, pipeline.InputType)
+				if rate > hc.config.ExporterFailures.Threshold {
+					ps.Healthy = false
+					ps.UnhealthyComponents = append(ps.UnhealthyComponents, componentStatus{
+						Kind:        "exporter",
+						Name:        exporter,
+						FailureRate: rate,
+						LastError:   lastError,
+					})
+				}
+			}
+		}
+		if !ps.Healthy {
+			status.Ready = false
+		}
+		status.Pipelines[name] = ps
+	}
+	return status
+}
+
+func (hc *healthCheckExtension) writeStatus(w http.ResponseWriter, code int, status interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		hc.logger.Debug("Failed to write health status", zap.Error(err))
+	}
+}
+
 func newServer(config Config, logger *zap.Logger) *healthCheckExtension {
 	hc := &healthCheckExtension{
-		config: config,
-		logger: logger,
-		state:  healthcheck.New(),
-		server: http.Server{},
+		config:    config,
+		logger:    logger,
+		state:     healthcheck.New(),
+		server:    http.Server{},
+		startTime: time.Now(),
+	}
+	if config.ExporterFailures.Window > 0 {
+		hc.failures = newExporterFailures(config.ExporterFailures.Window)
 	}
 
 	hc.state.SetLogger(logger)
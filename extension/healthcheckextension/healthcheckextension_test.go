@@ -16,6 +16,8 @@ package healthcheckextension
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"net"
 	"net/http"
 	"runtime"
@@ -23,10 +25,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/testutil"
 )
 
@@ -65,6 +70,93 @@ func TestHealthCheckExtensionUsage(t *testing.T) {
 	require.Equal(t, http.StatusServiceUnavailable, resp2.StatusCode)
 }
 
+func getStatus(t *testing.T, url string, status interface{}) int {
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(status))
+	return resp.StatusCode
+}
+
+func TestHealthCheckExtensionLivenessReadiness(t *testing.T) {
+	config := Config{
+		Port:          testutil.GetAvailablePort(t),
+		LivenessPath:  "/live",
+		ReadinessPath: "/ready",
+		ExporterFailures: ExporterFailuresSettings{
+			Window:    time.Minute,
+			Threshold: 0.5,
+		},
+	}
+
+	hcExt := newServer(config, zap.NewNop())
+	require.NotNil(t, hcExt)
+
+	require.NoError(t, hcExt.Start(context.Background(), componenttest.NewNopHost()))
+	defer hcExt.Shutdown(context.Background())
+
+	require.NoError(t, hcExt.PipelinesConfigured(configmodels.Pipelines{
+		"traces": {
+			Name:      "traces",
+			InputType: configmodels.TracesDataType,
+			Exporters: []string{"otlp", "logging"},
+		},
+		"metrics": {
+			Name:      "metrics",
+			InputType: configmodels.MetricsDataType,
+			Exporters: []string{"otlp"},
+		},
+	}))
+
+	url := "http://localhost:" + strconv.Itoa(int(config.Port))
+	var live livenessStatus
+	require.Equal(t, http.StatusOK, getStatus(t, url+"/live", &live))
+	assert.True(t, live.Alive)
+
+	// Not ready until the pipelines are.
+	var ready readinessStatus
+	require.Equal(t, http.StatusServiceUnavailable, getStatus(t, url+"/ready", &ready))
+	assert.False(t, ready.Ready)
+
+	require.NoError(t, hcExt.Ready())
+	ready = readinessStatus{}
+	require.Equal(t, http.StatusOK, getStatus(t, url+"/ready", &ready))
+	assert.Equal(t, readinessStatus{
+		Ready: true,
+		Pipelines: map[string]pipelineStatus{
+			"traces":  {Healthy: true},
+			"metrics": {Healthy: true},
+		},
+	}, ready)
+
+	// The otlp exporter fails to send most of the spans.
+	hcExt.ExportCompleted(component.ExportResult{ExporterName: "otlp", DataType: configmodels.TracesDataType, NumSent: 2})
+	hcExt.ExportCompleted(component.ExportResult{ExporterName: "otlp", DataType: configmodels.TracesDataType, NumFailedToSend: 6, Err: errors.New("connection refused")})
+	hcExt.ExportCompleted(component.ExportResult{ExporterName: "otlp", DataType: configmodels.MetricsDataType, NumSent: 10})
+
+	ready = readinessStatus{}
+	require.Equal(t, http.StatusServiceUnavailable, getStatus(t, url+"/ready", &ready))
+	assert.Equal(t, readinessStatus{
+		Ready: false,
+		Pipelines: map[string]pipelineStatus{
+			"traces": {
+				Healthy: false,
+				UnhealthyComponents: []componentStatus{{
+					Kind:        "exporter",
+					Name:        "otlp",
+					FailureRate: 0.75,
+					LastError:   "connection refused",
+				}},
+			},
+			"metrics": {Healthy: true},
+		},
+	}, ready)
+
+	// Liveness isn't affected by the exporter failures.
+	require.Equal(t, http.StatusOK, getStatus(t, url+"/live", &live))
+}
+
 func TestHealthCheckExtensionPortAlreadyInUse(t *testing.T) {
 	endpoint := testutil.GetAvailableLocalAddress(t)
 	_, portStr, err := net.SplitHostPort(endpoint)
@@ -0,0 +1,123 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package batchpersignal splits batches of telemetry data by signal, e.g. by trace.
+package batchpersignal
+
+import "go.opentelemetry.io/collector/consumer/pdata"
+
+// SplitTraces returns one pdata.Traces for each trace in the given batch, in the
+// order the traces first appear in the batch. The spans keep their resource and
+// instrumentation library, which are copied to each trace that needs them.
+func SplitTraces(batch pdata.Traces) []pdata.Traces {
+	var result []pdata.Traces
+	byTrace := make(map[pdata.TraceID]pdata.Traces)
+
+	rss := batch.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		rsByTrace := make(map[pdata.TraceID]pdata.ResourceSpans)
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			ilsByTrace := make(map[pdata.TraceID]pdata.InstrumentationLibrarySpans)
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				traceID := span.TraceID()
+
+				destIls, ok := ilsByTrace[traceID]
+				if !ok {
+					destRs, ok := rsByTrace[traceID]
+					if !ok {
+						trace, ok := byTrace[traceID]
+						if !ok {
+							trace = pdata.NewTraces()
+							byTrace[traceID] = trace
+							result = append(result, trace)
+						}
+						traceRss := trace.ResourceSpans()
+						traceRss.Resize(traceRss.Len() + 1)
+						destRs = traceRss.At(traceRss.Len() - 1)
+						rs.Resource().CopyTo(destRs.Resource())
+						rsByTrace[traceID] = destRs
+					}
+					destIlss := destRs.InstrumentationLibrarySpans()
+					destIlss.Resize(destIlss.Len() + 1)
+					destIls = destIlss.At(destIlss.Len() - 1)
+					ils.InstrumentationLibrary().CopyTo(destIls.InstrumentationLibrary())
+					ilsByTrace[traceID] = destIls
+				}
+
+				destSpans := destIls.Spans()
+				destSpans.Resize(destSpans.Len() + 1)
+				span.CopyTo(destSpans.At(destSpans.Len() - 1))
+			}
+		}
+	}
+	return result
+}
+
+// SplitLogs returns one pdata.Logs for each trace ID found in the log records of
+// the given batch, in the order the trace IDs first appear in the batch. Log records
+// without a trace ID are grouped together under the empty trace ID. The log records
+// keep their resource and instrumentation library.
+func SplitLogs(batch pdata.Logs) []pdata.Logs {
+	var result []pdata.Logs
+	byTrace := make(map[pdata.TraceID]pdata.Logs)
+
+	rls := batch.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		rlByTrace := make(map[pdata.TraceID]pdata.ResourceLogs)
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			illByTrace := make(map[pdata.TraceID]pdata.InstrumentationLibraryLogs)
+			logs := ill.Logs()
+			for k := 0; k < logs.Len(); k++ {
+				log := logs.At(k)
+				traceID := log.TraceID()
+
+				destIll, ok := illByTrace[traceID]
+				if !ok {
+					destRl, ok := rlByTrace[traceID]
+					if !ok {
+						trace, ok := byTrace[traceID]
+						if !ok {
+							trace = pdata.NewLogs()
+							byTrace[traceID] = trace
+							result = append(result, trace)
+						}
+						traceRls := trace.ResourceLogs()
+						traceRls.Resize(traceRls.Len() + 1)
+						destRl = traceRls.At(traceRls.Len() - 1)
+						rl.Resource().CopyTo(destRl.Resource())
+						rlByTrace[traceID] = destRl
+					}
+					destIlls := destRl.InstrumentationLibraryLogs()
+					destIlls.Resize(destIlls.Len() + 1)
+					destIll = destIlls.At(destIlls.Len() - 1)
+					ill.InstrumentationLibrary().CopyTo(destIll.InstrumentationLibrary())
+					illByTrace[traceID] = destIll
+				}
+
+				destLogs := destIll.Logs()
+				destLogs.Resize(destLogs.Len() + 1)
+				log.CopyTo(destLogs.At(destLogs.Len() - 1))
+			}
+		}
+	}
+	return result
+}
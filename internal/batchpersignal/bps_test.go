@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batchpersignal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestSplitTraces(t *testing.T) {
+	first := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+	second := pdata.NewTraceID([16]byte{2, 3, 4, 5})
+
+	batch := pdata.NewTraces()
+	batch.ResourceSpans().Resize(2)
+	for i, service := range []string{"frontend", "backend"} {
+		rs := batch.ResourceSpans().At(i)
+		rs.Resource().Attributes().InsertString("service.name", service)
+		rs.InstrumentationLibrarySpans().Resize(1)
+		rs.InstrumentationLibrarySpans().At(0).InstrumentationLibrary().SetName("lib")
+		spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+		spans.Resize(3)
+		spans.At(0).SetTraceID(first)
+		spans.At(1).SetTraceID(second)
+		spans.At(2).SetTraceID(first)
+	}
+
+	out := SplitTraces(batch)
+	require.Len(t, out, 2)
+	assert.Equal(t, 4, out[0].SpanCount())
+	assert.Equal(t, 2, out[1].SpanCount())
+
+	require.Equal(t, 2, out[0].ResourceSpans().Len())
+	rs := out[0].ResourceSpans().At(1)
+	service, _ := rs.Resource().Attributes().Get("service.name")
+	assert.Equal(t, "backend", service.StringVal())
+	assert.Equal(t, "lib", rs.InstrumentationLibrarySpans().At(0).InstrumentationLibrary().Name())
+	assert.Equal(t, first, rs.InstrumentationLibrarySpans().At(0).Spans().At(1).TraceID())
+	assert.Equal(t, second, out[1].ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).TraceID())
+
+	// The original batch is not modified.
+	assert.Equal(t, 6, batch.SpanCount())
+}
+
+func TestSplitTracesEmpty(t *testing.T) {
+	assert.Len(t, SplitTraces(pdata.NewTraces()), 0)
+}
+
+func TestSplitLogs(t *testing.T) {
+	first := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+
+	batch := pdata.NewLogs()
+	batch.ResourceLogs().Resize(1)
+	rl := batch.ResourceLogs().At(0)
+	rl.Resource().Attributes().InsertString("service.name", "frontend")
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	logs.Resize(3)
+	logs.At(0).SetTraceID(first)
+	logs.At(2).SetTraceID(first)
+
+	out := SplitLogs(batch)
+	require.Len(t, out, 2)
+	assert.Equal(t, 2, out[0].LogRecordCount())
+	assert.Equal(t, first, out[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).TraceID())
+	assert.Equal(t, 1, out[1].LogRecordCount())
+	assert.True(t, out[1].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).TraceID().IsEmpty())
+	service, _ := out[1].ResourceLogs().At(0).Resource().Attributes().Get("service.name")
+	assert.Equal(t, "frontend", service.StringVal())
+}
@@ -20,6 +20,10 @@ import (
 	"go.opentelemetry.io/collector/internal/processor/filterset"
 )
 
+// Expr is the match type evaluating the expr expressions of the Expressions
+// field against spans and log records, see the filterexpr package.
+const Expr filterset.MatchType = "expr"
+
 // MatchConfig has two optional MatchProperties one to define what is processed
 // by the processor, captured under the 'include' and the second, exclude, to
 // define what is excluded from the processor.
@@ -106,9 +110,21 @@ type MatchProperties struct {
 	// A match occurs if the span's implementation library matches at least one item in this list.
 	// This is an optional field.
 	Libraries []InstrumentationLibrary `mapstructure:"libraries"`
+
+	// Expressions specifies the list of expr expressions to match against, only
+	// with match_type=expr which doesn't allow any of the other properties.
+	// A match occurs if at least one expression evaluates to true.
+	Expressions []string `mapstructure:"expressions"`
 }
 
 func (mp *MatchProperties) ValidateForSpans() error {
+	if mp.MatchType == Expr {
+		return mp.validateForExpr()
+	}
+	if len(mp.Expressions) > 0 {
+		return errors.New(`expressions should only be specified with match_type "expr"`)
+	}
+
 	if len(mp.LogNames) > 0 {
 		return errors.New("log_names should not be specified for trace spans")
 	}
@@ -122,6 +138,13 @@ func (mp *MatchProperties) ValidateForSpans() error {
 }
 
 func (mp *MatchProperties) ValidateForLogs() error {
+	if mp.MatchType == Expr {
+		return mp.validateForExpr()
+	}
+	if len(mp.Expressions) > 0 {
+		return errors.New(`expressions should only be specified with match_type "expr"`)
+	}
+
 	if len(mp.SpanNames) > 0 || len(mp.Services) > 0 {
 		return errors.New("neither services nor span_names should be specified for log records")
 	}
@@ -133,6 +156,19 @@ func (mp *MatchProperties) ValidateForLogs() error {
 	return nil
 }
 
+func (mp *MatchProperties) validateForExpr() error {
+	if len(mp.Expressions) == 0 {
+		return errors.New(`"expressions" must be specified with match_type "expr"`)
+	}
+
+	if len(mp.Services) > 0 || len(mp.SpanNames) > 0 || len(mp.LogNames) > 0 || len(mp.Attributes) > 0 ||
+		len(mp.Libraries) > 0 || len(mp.Resources) > 0 {
+		return errors.New(`only "expressions" should be specified with match_type "expr"`)
+	}
+
+	return nil
+}
+
 // MatchTypeFieldName is the mapstructure field name for MatchProperties.Attributes field.
 const AttributesFieldName = "attributes"
 
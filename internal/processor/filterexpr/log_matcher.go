@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filterexpr
+
+import (
+	"github.com/antonmedv/expr"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+// logEnv is the environment of the expressions evaluated against log records.
+type logEnv struct {
+	// Name is the name of the log record.
+	Name string
+	// Severity is the severity text of the log record.
+	Severity string
+	// SeverityNumber is the numerical severity of the log record.
+	SeverityNumber int
+	// Body is the body of the log record as a string.
+	Body string
+
+	HasAttribute         func(key string) bool
+	Attribute            func(key string) interface{}
+	HasResourceAttribute func(key string) bool
+	ResourceAttribute    func(key string) interface{}
+}
+
+// NewLogMatcher compiles an expression to be evaluated against log records. The
+// expression must return a boolean.
+func NewLogMatcher(expression string) (*Matcher, error) {
+	program, err := expr.Compile(expression, expr.Env(logEnv{}), expr.AsBool())
+	if err != nil {
+		return nil, err
+	}
+	return &Matcher{program: program}, nil
+}
+
+// MatchLogRecord evaluates the expression against the log record and its resource.
+func (m *Matcher) MatchLogRecord(lr pdata.LogRecord, resource pdata.Resource) (bool, error) {
+	hasAttribute, attribute := attributeFuncs(lr.Attributes())
+	hasResourceAttribute, resourceAttribute := attributeFuncs(resource.Attributes())
+	return m.match(logEnv{
+		Name:                 lr.Name(),
+		Severity:             lr.SeverityText(),
+		SeverityNumber:       int(lr.SeverityNumber()),
+		Body:                 tracetranslator.AttributeValueToString(lr.Body(), false),
+		HasAttribute:         hasAttribute,
+		Attribute:            attribute,
+		HasResourceAttribute: hasResourceAttribute,
+		ResourceAttribute:    resourceAttribute,
+	})
+}
@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filterexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestNewLogMatcher_CompileError(t *testing.T) {
+	for _, expression := range []string{"", `Body`, `Kind == "SERVER"`} {
+		_, err := NewLogMatcher(expression)
+		assert.Error(t, err, expression)
+	}
+}
+
+func TestMatchLogRecord(t *testing.T) {
+	lr := pdata.NewLogRecord()
+	lr.SetName("access")
+	lr.SetSeverityText("WARN")
+	lr.SetSeverityNumber(pdata.SeverityNumberWARN)
+	lr.Body().SetIntVal(42)
+	lr.Attributes().InsertString("path", "/health")
+	resource := pdata.NewResource()
+
+	tests := []struct {
+		expression string
+		want       bool
+	}{
+		{expression: `Name == "access"`, want: true},
+		{expression: `Severity in ["WARN", "ERROR"]`, want: true},
+		{expression: `SeverityNumber < 13`, want: false},
+		{expression: `Body == "42"`, want: true},
+		{expression: `Attribute("path") == "/health"`, want: true},
+		{expression: `HasResourceAttribute("service.name")`, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			matcher, err := NewLogMatcher(tt.expression)
+			require.NoError(t, err)
+			matched, err := matcher.MatchLogRecord(lr, resource)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, matched)
+		})
+	}
+}
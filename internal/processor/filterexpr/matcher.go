@@ -21,9 +21,10 @@ import (
 	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
+// Matcher evaluates a compiled expression against metrics, spans or log
+// records. A Matcher is safe for concurrent use.
 type Matcher struct {
 	program *vm.Program
-	v       vm.VM
 }
 
 type env struct {
@@ -38,7 +39,7 @@ func NewMatcher(expression string) (*Matcher, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Matcher{program: program, v: vm.VM{}}, nil
+	return &Matcher{program: program}, nil
 }
 
 func (m *Matcher) MatchMetric(metric pdata.Metric) (bool, error) {
@@ -163,8 +164,8 @@ func createEnv(metricName string, labelsMap pdata.StringMap) env {
 	}
 }
 
-func (m *Matcher) match(env env) (bool, error) {
-	result, err := m.v.Run(m.program, env)
+func (m *Matcher) match(env interface{}) (bool, error) {
+	result, err := vm.Run(m.program, env)
 	if err != nil {
 		return false, err
 	}
@@ -0,0 +1,104 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filterexpr
+
+import (
+	"strings"
+
+	"github.com/antonmedv/expr"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// spanEnv is the environment of the expressions evaluated against spans.
+type spanEnv struct {
+	// Name is the name of the span.
+	Name string
+	// Kind is the kind of the span without its prefix, e.g. "SERVER".
+	Kind string
+	// Status is the status code of the span without its prefix, e.g. "ERROR".
+	Status string
+	// Duration is the duration of the span in milliseconds.
+	Duration float64
+
+	HasAttribute         func(key string) bool
+	Attribute            func(key string) interface{}
+	HasResourceAttribute func(key string) bool
+	ResourceAttribute    func(key string) interface{}
+}
+
+// NewSpanMatcher compiles an expression to be evaluated against spans. The
+// expression must return a boolean.
+func NewSpanMatcher(expression string) (*Matcher, error) {
+	program, err := expr.Compile(expression, expr.Env(spanEnv{}), expr.AsBool())
+	if err != nil {
+		return nil, err
+	}
+	return &Matcher{program: program}, nil
+}
+
+// MatchSpan evaluates the expression against the span and its resource.
+func (m *Matcher) MatchSpan(span pdata.Span, resource pdata.Resource) (bool, error) {
+	var duration float64
+	if span.EndTime() > span.StartTime() {
+		duration = float64(span.EndTime()-span.StartTime()) / 1e6
+	}
+	hasAttribute, attribute := attributeFuncs(span.Attributes())
+	hasResourceAttribute, resourceAttribute := attributeFuncs(resource.Attributes())
+	return m.match(spanEnv{
+		Name:                 span.Name(),
+		Kind:                 strings.TrimPrefix(span.Kind().String(), "SPAN_KIND_"),
+		Status:               strings.TrimPrefix(span.Status().Code().String(), "STATUS_CODE_"),
+		Duration:             duration,
+		HasAttribute:         hasAttribute,
+		Attribute:            attribute,
+		HasResourceAttribute: hasResourceAttribute,
+		ResourceAttribute:    resourceAttribute,
+	})
+}
+
+// attributeFuncs returns the functions of the environment checking the presence
+// and returning the value of the attributes.
+func attributeFuncs(attrs pdata.AttributeMap) (func(key string) bool, func(key string) interface{}) {
+	has := func(key string) bool {
+		_, ok := attrs.Get(key)
+		return ok
+	}
+	get := func(key string) interface{} {
+		v, ok := attrs.Get(key)
+		if !ok {
+			return nil
+		}
+		return attributeValue(v)
+	}
+	return has, get
+}
+
+// attributeValue returns the Go value of a scalar attribute, nil for other
+// types.
+func attributeValue(v pdata.AttributeValue) interface{} {
+	switch v.Type() {
+	case pdata.AttributeValueSTRING:
+		return v.StringVal()
+	case pdata.AttributeValueINT:
+		return v.IntVal()
+	case pdata.AttributeValueDOUBLE:
+		return v.DoubleVal()
+	case pdata.AttributeValueBOOL:
+		return v.BoolVal()
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filterexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestNewSpanMatcher_CompileError(t *testing.T) {
+	for _, expression := range []string{"", `Name`, `MetricName == "abc"`, `Severity == "DEBUG"`} {
+		_, err := NewSpanMatcher(expression)
+		assert.Error(t, err, expression)
+	}
+}
+
+func TestMatchSpan(t *testing.T) {
+	span := pdata.NewSpan()
+	span.SetName("checkout")
+	span.SetKind(pdata.SpanKindCLIENT)
+	span.SetStartTime(1000000)
+	span.SetEndTime(3500000)
+	span.Attributes().InsertBool("cache.hit", true)
+	span.Attributes().InsertDouble("ratio", 0.5)
+	resource := pdata.NewResource()
+	resource.Attributes().InsertString("service.name", "svc")
+
+	tests := []struct {
+		expression string
+		want       bool
+	}{
+		{expression: `Name == "checkout"`, want: true},
+		{expression: `Kind == "CLIENT"`, want: true},
+		{expression: `Status == "UNSET"`, want: true},
+		{expression: `Duration == 2.5`, want: true},
+		{expression: `Attribute("cache.hit") == true && Attribute("ratio") < 1`, want: true},
+		{expression: `HasAttribute("missing")`, want: false},
+		{expression: `Attribute("missing") == nil`, want: true},
+		{expression: `ResourceAttribute("service.name") matches "^s.c$"`, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			matcher, err := NewSpanMatcher(tt.expression)
+			require.NoError(t, err)
+			matched, err := matcher.MatchSpan(span, resource)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, matched)
+		})
+	}
+}
@@ -49,6 +49,10 @@ func NewMatcher(mp *filterconfig.MatchProperties) (Matcher, error) {
 		return nil, err
 	}
 
+	if mp.MatchType == filterconfig.Expr {
+		return newExprMatcher(mp.Expressions)
+	}
+
 	rm, err := filtermatcher.NewMatcher(mp)
 	if err != nil {
 		return nil, err
@@ -87,6 +87,31 @@ func TestLogRecord_validateMatchesConfiguration_InvalidConfig(t *testing.T) {
 			},
 			errorString: "error creating log record name filters: error parsing regexp: missing closing ]: `[`",
 		},
+		{
+			name: "expr_without_expressions",
+			property: filterconfig.MatchProperties{
+				Config: *createConfig(filterconfig.Expr),
+			},
+			errorString: "\"expressions\" must be specified with match_type \"expr\"",
+		},
+		{
+			name: "expr_with_log_names",
+			property: filterconfig.MatchProperties{
+				Config:      *createConfig(filterconfig.Expr),
+				LogNames:    []string{"abc"},
+				Expressions: []string{`Name == "abc"`},
+			},
+			errorString: "only \"expressions\" should be specified with match_type \"expr\"",
+		},
+		{
+			name: "expressions_without_expr",
+			property: filterconfig.MatchProperties{
+				Config:      *createConfig(filterset.Strict),
+				LogNames:    []string{"abc"},
+				Expressions: []string{`Name == "abc"`},
+			},
+			errorString: "expressions should only be specified with match_type \"expr\"",
+		},
 	}
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -181,3 +206,45 @@ func TestLogRecord_Matching_True(t *testing.T) {
 		})
 	}
 }
+
+func TestLogRecord_MatchingExpr(t *testing.T) {
+	lr := pdata.NewLogRecord()
+	lr.SetName("logName")
+	lr.SetSeverityText("DEBUG")
+	lr.SetSeverityNumber(pdata.SeverityNumberDEBUG)
+	lr.Body().SetStringVal("cache miss")
+	lr.Attributes().InsertInt("retries", 3)
+	resource := pdata.NewResource()
+	resource.Attributes().InsertString("service.name", "noisy")
+
+	testcases := []struct {
+		name        string
+		expressions []string
+		want        bool
+	}{
+		{name: "severity", expressions: []string{`Severity == "DEBUG"`}, want: true},
+		{name: "severity_number", expressions: []string{`SeverityNumber >= 9`}, want: false},
+		{name: "body_and_resource", expressions: []string{`Body contains "miss" && ResourceAttribute("service.name") == "noisy"`}, want: true},
+		{name: "attribute", expressions: []string{`HasAttribute("retries") && Attribute("retries") > 5`}, want: false},
+		{name: "any_expression", expressions: []string{`Name == "other"`, `Attribute("retries") == 3`}, want: true},
+		{name: "evaluation_error", expressions: []string{`Attribute("missing") > 5`}, want: false},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			matcher, err := NewMatcher(&filterconfig.MatchProperties{
+				Config:      *createConfig(filterconfig.Expr),
+				Expressions: tc.expressions,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, matcher.MatchLogRecord(lr, resource, pdata.NewInstrumentationLibrary()))
+		})
+	}
+}
+
+func TestLogRecord_InvalidExpr(t *testing.T) {
+	_, err := NewMatcher(&filterconfig.MatchProperties{
+		Config:      *createConfig(filterconfig.Expr),
+		Expressions: []string{`Unknown == "abc"`},
+	})
+	assert.Error(t, err)
+}
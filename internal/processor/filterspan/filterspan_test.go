@@ -89,6 +89,30 @@ func TestSpan_validateMatchesConfiguration_InvalidConfig(t *testing.T) {
 			},
 			errorString: "error creating span name filters: error parsing regexp: missing closing ]: `[`",
 		},
+		{
+			name: "expr_without_expressions",
+			property: filterconfig.MatchProperties{
+				Config: *createConfig(filterconfig.Expr),
+			},
+			errorString: "\"expressions\" must be specified with match_type \"expr\"",
+		},
+		{
+			name: "expr_with_services",
+			property: filterconfig.MatchProperties{
+				Config:      *createConfig(filterconfig.Expr),
+				Services:    []string{"svcA"},
+				Expressions: []string{`Name == "abc"`},
+			},
+			errorString: "only \"expressions\" should be specified with match_type \"expr\"",
+		},
+		{
+			name: "invalid_expression",
+			property: filterconfig.MatchProperties{
+				Config:      *createConfig(filterconfig.Expr),
+				Expressions: []string{`Name`},
+			},
+			errorString: "expected bool, but got string",
+		},
 	}
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -259,3 +283,40 @@ func TestServiceNameForResource(t *testing.T) {
 	resource.Attributes().InsertString(conventions.AttributeServiceName, "test-service")
 	require.Equal(t, serviceNameForResource(resource), "test-service")
 }
+
+func TestSpan_MatchingExpr(t *testing.T) {
+	span := pdata.NewSpan()
+	span.SetName("GET /health")
+	span.SetKind(pdata.SpanKindSERVER)
+	span.Status().SetCode(pdata.StatusCodeError)
+	span.SetStartTime(1000000000)
+	span.SetEndTime(1750000000)
+	span.Attributes().InsertString("http.target", "/health")
+	span.Attributes().InsertInt("http.status_code", 503)
+	resource := pdata.NewResource()
+	resource.Attributes().InsertString(conventions.AttributeServiceName, "svcA")
+
+	testcases := []struct {
+		name        string
+		expressions []string
+		want        bool
+	}{
+		{name: "name_and_kind", expressions: []string{`Name startsWith "GET" && Kind == "SERVER"`}, want: true},
+		{name: "status", expressions: []string{`Status == "OK"`}, want: false},
+		{name: "duration", expressions: []string{`Duration > 500`}, want: true},
+		{name: "attribute", expressions: []string{`Attribute("http.status_code") >= 500`}, want: true},
+		{name: "resource_attribute", expressions: []string{`HasResourceAttribute("service.namespace")`}, want: false},
+		{name: "any_expression", expressions: []string{`Duration > 1000`, `ResourceAttribute("service.name") == "svcA"`}, want: true},
+		{name: "evaluation_error", expressions: []string{`Attribute("missing") > 5`}, want: false},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			matcher, err := NewMatcher(&filterconfig.MatchProperties{
+				Config:      *createConfig(filterconfig.Expr),
+				Expressions: tc.expressions,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, matcher.MatchSpan(span, resource, pdata.NewInstrumentationLibrary()))
+		})
+	}
+}
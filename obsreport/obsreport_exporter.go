@@ -21,6 +21,8 @@ import (
 	"go.opencensus.io/tag"
 	"go.opencensus.io/trace"
 
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/configtelemetry"
 )
 
@@ -96,6 +98,7 @@ type ExporterObsReport struct {
 	level        configtelemetry.Level
 	exporterName string
 	mutators     []tag.Mutator
+	reporter     component.ExportReporter
 }
 
 func NewExporterObsReport(level configtelemetry.Level, exporterName string) *ExporterObsReport {
@@ -106,6 +109,13 @@ func NewExporterObsReport(level configtelemetry.Level, exporterName string) *Exp
 	}
 }
 
+// ReportExportsTo notifies the host, if it is a component.ExportReporter, of the result of each
+// export operation completed with this ExporterObsReport, whatever the telemetry level. It must
+// be called before the export operations start, e.g. when the exporter is started.
+func (eor *ExporterObsReport) ReportExportsTo(host component.Host) {
+	eor.reporter, _ = host.(component.ExportReporter)
+}
+
 // StartTracesExportOp is called at the start of an Export operation.
 // The returned context should be used in other calls to the ExporterObsReport functions
 // dealing with the same export operation.
@@ -117,6 +127,7 @@ func (eor *ExporterObsReport) StartTracesExportOp(ctx context.Context) context.C
 func (eor *ExporterObsReport) EndTracesExportOp(ctx context.Context, numSpans int, err error) {
 	numSent, numFailedToSend := toNumItems(numSpans, err)
 	recordMetrics(ctx, numSent, numFailedToSend, mExporterSentSpans, mExporterFailedToSendSpans)
+	eor.reportExport(configmodels.TracesDataType, numSent, numFailedToSend, err)
 	endSpan(ctx, err, numSent, numFailedToSend, SentSpansKey, FailedToSendSpansKey)
 }
 
@@ -132,6 +143,7 @@ func (eor *ExporterObsReport) StartMetricsExportOp(ctx context.Context) context.
 func (eor *ExporterObsReport) EndMetricsExportOp(ctx context.Context, numMetricPoints int, err error) {
 	numSent, numFailedToSend := toNumItems(numMetricPoints, err)
 	recordMetrics(ctx, numSent, numFailedToSend, mExporterSentMetricPoints, mExporterFailedToSendMetricPoints)
+	eor.reportExport(configmodels.MetricsDataType, numSent, numFailedToSend, err)
 	endSpan(ctx, err, numSent, numFailedToSend, SentMetricPointsKey, FailedToSendMetricPointsKey)
 }
 
@@ -146,6 +158,7 @@ func (eor *ExporterObsReport) StartLogsExportOp(ctx context.Context) context.Con
 func (eor *ExporterObsReport) EndLogsExportOp(ctx context.Context, numLogRecords int, err error) {
 	numSent, numFailedToSend := toNumItems(numLogRecords, err)
 	recordMetrics(ctx, numSent, numFailedToSend, mExporterSentLogRecords, mExporterFailedToSendLogRecords)
+	eor.reportExport(configmodels.LogsDataType, numSent, numFailedToSend, err)
 	endSpan(ctx, err, numSent, numFailedToSend, SentLogRecordsKey, FailedToSendLogRecordsKey)
 }
 
@@ -182,6 +195,20 @@ func endSpan(ctx context.Context, err error, numSent, numFailedToSend int64, sen
 	span.End()
 }
 
+// reportExport notifies the host, if it is an ExportReporter, of the result of an export operation.
+func (eor *ExporterObsReport) reportExport(dataType configmodels.DataType, numSent, numFailedToSend int64, err error) {
+	if eor.reporter == nil {
+		return
+	}
+	eor.reporter.ReportExport(component.ExportResult{
+		ExporterName:    eor.exporterName,
+		DataType:        dataType,
+		NumSent:         numSent,
+		NumFailedToSend: numFailedToSend,
+		Err:             err,
+	})
+}
+
 func toNumItems(numExportedItems int, err error) (int64, int64) {
 	if err != nil {
 		return 0, int64(numExportedItems)
@@ -28,6 +28,9 @@ import (
 	"go.opencensus.io/stats/view"
 	"go.opencensus.io/trace"
 
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/configtelemetry"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/obsreport"
@@ -473,6 +476,36 @@ func TestExportLogsOp(t *testing.T) {
 	obsreporttest.CheckExporterLogsViews(t, exporter, int64(sentLogRecords), int64(failedToSendLogRecords))
 }
 
+type exportReporterHost struct {
+	component.Host
+	results []component.ExportResult
+}
+
+func (h *exportReporterHost) ReportExport(result component.ExportResult) {
+	h.results = append(h.results, result)
+}
+
+func TestReportExportsTo(t *testing.T) {
+	host := &exportReporterHost{Host: componenttest.NewNopHost()}
+	obsrep := obsreport.NewExporterObsReport(configtelemetry.LevelNone, exporter)
+	obsrep.ReportExportsTo(host)
+
+	ctx := obsreport.ExporterContext(context.Background(), exporter)
+	obsrep.EndTracesExportOp(obsrep.StartTracesExportOp(ctx), 3, nil)
+	obsrep.EndMetricsExportOp(obsrep.StartMetricsExportOp(ctx), 5, errFake)
+	obsrep.EndLogsExportOp(obsrep.StartLogsExportOp(ctx), 7, nil)
+
+	// The exports aren't reported to the hosts that aren't an ExportReporter.
+	obsrep.ReportExportsTo(componenttest.NewNopHost())
+	obsrep.EndTracesExportOp(obsrep.StartTracesExportOp(ctx), 11, nil)
+
+	assert.Equal(t, []component.ExportResult{
+		{ExporterName: exporter, DataType: configmodels.TracesDataType, NumSent: 3},
+		{ExporterName: exporter, DataType: configmodels.MetricsDataType, NumFailedToSend: 5, Err: errFake},
+		{ExporterName: exporter, DataType: configmodels.LogsDataType, NumSent: 7},
+	}, host.results)
+}
+
 func TestReceiveWithLongLivedCtx(t *testing.T) {
 	ss := &spanStore{}
 	trace.RegisterExporter(ss)
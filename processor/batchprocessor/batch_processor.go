@@ -16,13 +16,20 @@ package batchprocessor
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.opencensus.io/stats"
 	"go.opencensus.io/tag"
 	"go.uber.org/zap"
 
+	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configtelemetry"
 	"go.opentelemetry.io/collector/consumer"
@@ -37,23 +44,58 @@ import (
 //
 // Batches are sent out with any of the following conditions:
 // - batch size reaches cfg.SendBatchSize
+// - batch size in bytes reaches cfg.SendBatchBytes, or would exceed cfg.SendBatchMaxBytes
 // - cfg.Timeout is elapsed since the timestamp when the previous batch was sent out.
+//
+// When cfg.MetadataKeys or cfg.ResourceAttributes are set, a separate batch, or shard,
+// is kept for every combination of their values. Each shard is exported with its own
+// context and is removed once it stayed empty for cfg.Timeout.
 type batchProcessor struct {
 	name           string
 	logger         *zap.Logger
 	telemetryLevel configtelemetry.Level
 
-	sendBatchSize    uint32
-	timeout          time.Duration
-	sendBatchMaxSize uint32
+	sendBatchSize     uint32
+	timeout           time.Duration
+	sendBatchMaxSize  uint32
+	sendBatchBytes    int
+	sendBatchMaxBytes int
+
+	metadataKeys       []string
+	resourceAttributes []string
+	metadataLimit      int
+
+	newBatch func() batch
+
+	// shard is the only shard when the batches aren't keyed.
+	shard *shard
+
+	// shards holds the keyed shards, it is nil when the batches aren't keyed.
+	lock   sync.Mutex
+	shards map[string]*shard
+
+	goroutines sync.WaitGroup
+	ctx        context.Context
+	cancel     context.CancelFunc
+}
+
+// shard is a batch with the context it is exported with, filled by its own goroutine.
+type shard struct {
+	// pending is the number of items sent, or about to be sent, to newItem and not received yet.
+	// A keyed shard is only removed, and the shutdown only completes, once there are no pending
+	// items. It is first to keep it 64-bit aligned.
+	pending int64
+
+	processor *batchProcessor
+	key       string
+	exportCtx context.Context
 
 	timer   *time.Timer
-	done    chan struct{}
 	newItem chan interface{}
 	batch   batch
 
-	ctx    context.Context
-	cancel context.CancelFunc
+	// bytes is the serialized size of the batch, only tracked when batching by size in bytes.
+	bytes int
 }
 
 type batch interface {
@@ -77,21 +119,76 @@ var _ consumer.TracesConsumer = (*batchProcessor)(nil)
 var _ consumer.MetricsConsumer = (*batchProcessor)(nil)
 var _ consumer.LogsConsumer = (*batchProcessor)(nil)
 
-func newBatchProcessor(params component.ProcessorCreateParams, cfg *Config, batch batch, telemetryLevel configtelemetry.Level) *batchProcessor {
+var errTooManyBatches = errors.New("data refused due to too many batches for different metadata values")
+
+func newBatchProcessor(params component.ProcessorCreateParams, cfg *Config, newBatch func() batch, telemetryLevel configtelemetry.Level) (*batchProcessor, error) {
+	if cfg.SendBatchMaxBytes > 0 && cfg.SendBatchBytes > cfg.SendBatchMaxBytes {
+		return nil, fmt.Errorf("send_batch_bytes (%d) must not be greater than send_batch_max_bytes (%d)", cfg.SendBatchBytes, cfg.SendBatchMaxBytes)
+	}
+	metadataKeys, err := uniqueKeys("metadata_keys", cfg.MetadataKeys, strings.ToLower)
+	if err != nil {
+		return nil, err
+	}
+	resourceAttributes, err := uniqueKeys("resource_attributes", cfg.ResourceAttributes, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
-	return &batchProcessor{
+	bp := &batchProcessor{
 		name:           cfg.Name(),
 		logger:         params.Logger,
 		telemetryLevel: telemetryLevel,
 
-		sendBatchSize:    cfg.SendBatchSize,
-		sendBatchMaxSize: cfg.SendBatchMaxSize,
-		timeout:          cfg.Timeout,
-		done:             make(chan struct{}, 1),
-		newItem:          make(chan interface{}, runtime.NumCPU()),
-		batch:            batch,
-		ctx:              ctx,
-		cancel:           cancel,
+		sendBatchSize:     cfg.SendBatchSize,
+		sendBatchMaxSize:  cfg.SendBatchMaxSize,
+		sendBatchBytes:    int(cfg.SendBatchBytes),
+		sendBatchMaxBytes: int(cfg.SendBatchMaxBytes),
+		timeout:           cfg.Timeout,
+
+		metadataKeys:       metadataKeys,
+		resourceAttributes: resourceAttributes,
+		metadataLimit:      int(cfg.MetadataCardinalityLimit),
+
+		newBatch: newBatch,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	if len(metadataKeys) > 0 || len(resourceAttributes) > 0 {
+		bp.shards = map[string]*shard{}
+	} else {
+		bp.shard = bp.newShard("", context.Background())
+	}
+	return bp, nil
+}
+
+// uniqueKeys returns keys, normalized with normalize if not nil, and fails if a key is empty or repeated.
+func uniqueKeys(setting string, keys []string, normalize func(string) string) ([]string, error) {
+	result := make([]string, 0, len(keys))
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if normalize != nil {
+			key = normalize(key)
+		}
+		if key == "" {
+			return nil, fmt.Errorf("%s must not contain empty keys", setting)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate key %q in %s", key, setting)
+		}
+		seen[key] = true
+		result = append(result, key)
+	}
+	return result, nil
+}
+
+func (bp *batchProcessor) newShard(key string, exportCtx context.Context) *shard {
+	return &shard{
+		processor: bp,
+		key:       key,
+		exportCtx: exportCtx,
+		newItem:   make(chan interface{}, runtime.NumCPU()),
+		batch:     bp.newBatch(),
 	}
 }
 
@@ -101,136 +198,334 @@ func (bp *batchProcessor) GetCapabilities() component.ProcessorCapabilities {
 
 // Start is invoked during service startup.
 func (bp *batchProcessor) Start(context.Context, component.Host) error {
-	go bp.startProcessingCycle()
+	if bp.shard != nil {
+		bp.goroutines.Add(1)
+		go bp.shard.startProcessingCycle()
+	}
 	return nil
 }
 
 // Shutdown is invoked during service shutdown.
 func (bp *batchProcessor) Shutdown(context.Context) error {
 	bp.cancel()
-	<-bp.done
+	bp.goroutines.Wait()
 	return nil
 }
 
-func (bp *batchProcessor) startProcessingCycle() {
-	bp.timer = time.NewTimer(bp.timeout)
+func (s *shard) startProcessingCycle() {
+	defer s.processor.goroutines.Done()
+	s.timer = time.NewTimer(s.processor.timeout)
 	for {
 		select {
-		case <-bp.ctx.Done():
-		DONE:
-			for {
-				select {
-				case item := <-bp.newItem:
-					bp.processItem(item)
-				default:
-					break DONE
-				}
+		case <-s.processor.ctx.Done():
+			// Wait for the pending items, including the remainders of split items.
+			for atomic.LoadInt64(&s.pending) > 0 {
+				s.processItem(s.receive(<-s.newItem))
 			}
 			// This is the close of the channel
-			if bp.batch.itemCount() > 0 {
+			if s.batch.itemCount() > 0 {
 				// TODO: Set a timeout on sendTraces or
 				// make it cancellable using the context that Shutdown gets as a parameter
-				bp.sendItems(statTimeoutTriggerSend)
+				s.sendItems(statTimeoutTriggerSend)
 			}
-			close(bp.done)
 			return
-		case item := <-bp.newItem:
-			if item == nil {
+		case item := <-s.newItem:
+			if s.receive(item) == nil {
 				continue
 			}
-			bp.processItem(item)
-		case <-bp.timer.C:
-			if bp.batch.itemCount() > 0 {
-				bp.sendItems(statTimeoutTriggerSend)
+			s.processItem(item)
+		case <-s.timer.C:
+			if s.batch.itemCount() > 0 {
+				s.sendItems(statTimeoutTriggerSend)
+			} else if s.processor.removeIdleShard(s) {
+				return
 			}
-			bp.resetTimer()
+			s.resetTimer()
 		}
 	}
 }
 
-func (bp *batchProcessor) processItem(item interface{}) {
+// removeIdleShard removes the keyed shard s, unless items are still being sent to it.
+func (bp *batchProcessor) removeIdleShard(s *shard) bool {
+	if bp.shards == nil {
+		return false
+	}
+	bp.lock.Lock()
+	defer bp.lock.Unlock()
+	if atomic.LoadInt64(&s.pending) > 0 {
+		return false
+	}
+	delete(bp.shards, s.key)
+	return true
+}
+
+// enqueue sends item to the shard goroutine, it must already be counted in pending.
+func (s *shard) enqueue(item interface{}) {
+	s.newItem <- item
+}
+
+// receive returns an item received from newItem, after removing it from pending.
+func (s *shard) receive(item interface{}) interface{} {
+	atomic.AddInt64(&s.pending, -1)
+	return item
+}
+
+func (s *shard) processItem(item interface{}) {
+	bp := s.processor
 	if bp.sendBatchMaxSize > 0 {
 		if td, ok := item.(pdata.Traces); ok {
-			itemCount := bp.batch.itemCount()
+			itemCount := s.batch.itemCount()
 			if itemCount+uint32(td.SpanCount()) > bp.sendBatchMaxSize {
 				tdRemainSize := splitTrace(int(bp.sendBatchSize-itemCount), td)
 				item = tdRemainSize
-				go func() {
-					bp.newItem <- td
-				}()
+				atomic.AddInt64(&s.pending, 1)
+				go s.enqueue(td)
 			}
 		}
 		if td, ok := item.(pdata.Metrics); ok {
-			itemCount := bp.batch.itemCount()
+			itemCount := s.batch.itemCount()
 			if itemCount+uint32(td.MetricCount()) > bp.sendBatchMaxSize {
 				tdRemainSize := splitMetrics(int(bp.sendBatchSize-itemCount), td)
 				item = tdRemainSize
-				go func() {
-					bp.newItem <- td
-				}()
+				atomic.AddInt64(&s.pending, 1)
+				go s.enqueue(td)
 			}
 		}
+		if ld, ok := item.(pdata.Logs); ok {
+			itemCount := s.batch.itemCount()
+			if itemCount+uint32(ld.LogRecordCount()) > bp.sendBatchMaxSize {
+				ldRemainSize := splitLogs(int(bp.sendBatchSize-itemCount), ld)
+				item = ldRemainSize
+				atomic.AddInt64(&s.pending, 1)
+				go s.enqueue(ld)
+			}
+		}
+	}
+
+	if bp.sendBatchBytes == 0 && bp.sendBatchMaxBytes == 0 {
+		s.batch.add(item)
+	} else {
+		s.addBytes(item)
 	}
 
-	bp.batch.add(item)
-	if bp.batch.itemCount() >= bp.sendBatchSize {
-		bp.timer.Stop()
-		bp.sendItems(statBatchSizeTriggerSend)
-		bp.resetTimer()
+	if s.batch.itemCount() >= bp.sendBatchSize {
+		s.timer.Stop()
+		s.sendItems(statBatchSizeTriggerSend)
+		s.resetTimer()
+	} else if bp.sendBatchBytes > 0 && s.bytes >= bp.sendBatchBytes {
+		s.timer.Stop()
+		s.sendItems(statBatchBytesTriggerSend)
+		s.resetTimer()
 	}
 }
 
-func (bp *batchProcessor) resetTimer() {
-	bp.timer.Reset(bp.timeout)
+// addBytes adds item to the batch keeping track of its size in bytes, and sends the batch
+// whenever adding more of item would make it exceed sendBatchMaxBytes.
+func (s *shard) addBytes(item interface{}) {
+	maxBytes := s.processor.sendBatchMaxBytes
+	itemBytes := sizeBytes(item)
+	for maxBytes > 0 && s.bytes+itemBytes > maxBytes {
+		if s.batch.itemCount() == 0 {
+			// Even an empty batch can't hold all of item, send as much of it as fits.
+			part := splitBytes(maxBytes, item)
+			partBytes := sizeBytes(part)
+			s.batch.add(part)
+			if s.batch.itemCount() == 0 {
+				// There is nothing in item that could be split.
+				break
+			}
+			s.bytes += partBytes
+		}
+		s.sendItems(statBatchBytesTriggerSend)
+		itemBytes = sizeBytes(item)
+	}
+	s.batch.add(item)
+	s.bytes += itemBytes
+}
+
+// sizeBytes returns the size of the traces, metrics or logs item serialized as an OTLP request.
+func sizeBytes(item interface{}) int {
+	switch data := item.(type) {
+	case pdata.Traces:
+		return tracesBytes(data)
+	case pdata.Metrics:
+		return metricsBytes(data)
+	case pdata.Logs:
+		return logsBytes(data)
+	}
+	return 0
+}
+
+// splitBytes removes data from the traces, metrics or logs item and returns it as a new item
+// of at most maxBytes, or with a single span, metric or log record if that is larger.
+func splitBytes(maxBytes int, item interface{}) interface{} {
+	switch data := item.(type) {
+	case pdata.Traces:
+		return splitTraceBytes(maxBytes, data)
+	case pdata.Metrics:
+		return splitMetricsBytes(maxBytes, data)
+	case pdata.Logs:
+		return splitLogsBytes(maxBytes, data)
+	}
+	return item
 }
 
-func (bp *batchProcessor) sendItems(measure *stats.Int64Measure) {
+func (s *shard) resetTimer() {
+	s.timer.Reset(s.processor.timeout)
+}
+
+func (s *shard) sendItems(measure *stats.Int64Measure) {
+	bp := s.processor
 	// Add that it came form the trace pipeline?
 	statsTags := []tag.Mutator{tag.Insert(processor.TagProcessorNameKey, bp.name)}
-	_ = stats.RecordWithTags(context.Background(), statsTags, measure.M(1), statBatchSendSize.M(int64(bp.batch.itemCount())))
+	_ = stats.RecordWithTags(context.Background(), statsTags, measure.M(1), statBatchSendSize.M(int64(s.batch.itemCount())))
 
 	if bp.telemetryLevel == configtelemetry.LevelDetailed {
-		_ = stats.RecordWithTags(context.Background(), statsTags, statBatchSendSizeBytes.M(int64(bp.batch.size())))
+		_ = stats.RecordWithTags(context.Background(), statsTags, statBatchSendSizeBytes.M(int64(s.batch.size())))
 	}
 
-	if err := bp.batch.export(context.Background()); err != nil {
+	if err := s.batch.export(s.exportCtx); err != nil {
 		bp.logger.Warn("Sender failed", zap.Error(err))
 	}
-	bp.batch.reset()
+	s.batch.reset()
+	s.bytes = 0
 }
 
 // ConsumeTraces implements TracesProcessor
-func (bp *batchProcessor) ConsumeTraces(_ context.Context, td pdata.Traces) error {
-	bp.newItem <- td
-	return nil
+func (bp *batchProcessor) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	if bp.shards == nil || len(bp.resourceAttributes) == 0 {
+		return bp.consume(ctx, []string{""}, []interface{}{td})
+	}
+	keys, groups := groupTraces(td, bp.resourceAttributes)
+	items := make([]interface{}, len(groups))
+	for i, group := range groups {
+		items[i] = group
+	}
+	return bp.consume(ctx, keys, items)
 }
 
 // ConsumeTraces implements MetricsProcessor
-func (bp *batchProcessor) ConsumeMetrics(_ context.Context, md pdata.Metrics) error {
-	// First thing is convert into a different internal format
-	bp.newItem <- md
-	return nil
+func (bp *batchProcessor) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	if bp.shards == nil || len(bp.resourceAttributes) == 0 {
+		return bp.consume(ctx, []string{""}, []interface{}{md})
+	}
+	keys, groups := groupMetrics(md, bp.resourceAttributes)
+	items := make([]interface{}, len(groups))
+	for i, group := range groups {
+		items[i] = group
+	}
+	return bp.consume(ctx, keys, items)
 }
 
 // ConsumeLogs implements LogsProcessor
-func (bp *batchProcessor) ConsumeLogs(_ context.Context, ld pdata.Logs) error {
-	bp.newItem <- ld
+func (bp *batchProcessor) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
+	if bp.shards == nil || len(bp.resourceAttributes) == 0 {
+		return bp.consume(ctx, []string{""}, []interface{}{ld})
+	}
+	keys, groups := groupLogs(ld, bp.resourceAttributes)
+	items := make([]interface{}, len(groups))
+	for i, group := range groups {
+		items[i] = group
+	}
+	return bp.consume(ctx, keys, items)
+}
+
+// consume sends each item to the shard of its metadata values from ctx and its resource key,
+// creating the shards if needed. The items are all refused if creating their shards would
+// exceed the limit, so that none of them is batched when an error is returned.
+func (bp *batchProcessor) consume(ctx context.Context, resourceKeys []string, items []interface{}) error {
+	if bp.shards == nil {
+		for _, item := range items {
+			atomic.AddInt64(&bp.shard.pending, 1)
+			bp.shard.enqueue(item)
+		}
+		return nil
+	}
+
+	var md map[string][]string
+	if c, ok := client.FromContext(ctx); ok {
+		md = c.Metadata
+	}
+	var b strings.Builder
+	for _, key := range bp.metadataKeys {
+		values := md[key]
+		appendKeyValue(&b, strconv.Itoa(len(values)))
+		for _, v := range values {
+			appendKeyValue(&b, v)
+		}
+	}
+	metadataKey := b.String()
+
+	bp.lock.Lock()
+	if bp.metadataLimit > 0 {
+		newShards := make(map[string]bool)
+		for _, resourceKey := range resourceKeys {
+			if key := metadataKey + resourceKey; bp.shards[key] == nil {
+				newShards[key] = true
+			}
+		}
+		if len(bp.shards)+len(newShards) > bp.metadataLimit {
+			bp.lock.Unlock()
+			return errTooManyBatches
+		}
+	}
+	shards := make([]*shard, len(items))
+	for i, resourceKey := range resourceKeys {
+		key := metadataKey + resourceKey
+		s, ok := bp.shards[key]
+		if !ok {
+			s = bp.newShard(key, bp.exportContext(md))
+			bp.shards[key] = s
+			bp.goroutines.Add(1)
+			go s.startProcessingCycle()
+		}
+		atomic.AddInt64(&s.pending, 1)
+		shards[i] = s
+	}
+	bp.lock.Unlock()
+
+	for i, s := range shards {
+		s.enqueue(items[i])
+	}
 	return nil
 }
 
+// exportContext returns the context to export a shard with, carrying the values of the
+// metadata keys in md.
+func (bp *batchProcessor) exportContext(md map[string][]string) context.Context {
+	exportMD := map[string][]string{}
+	for _, key := range bp.metadataKeys {
+		if values, ok := md[key]; ok {
+			exportMD[key] = append([]string(nil), values...)
+		}
+	}
+	if len(exportMD) == 0 {
+		return context.Background()
+	}
+	return client.NewContext(context.Background(), &client.Client{Metadata: exportMD})
+}
+
+// appendKeyValue appends v to a shard key, prefixed by its length so that different
+// combinations of values never result in the same key.
+func appendKeyValue(b *strings.Builder, v string) {
+	b.WriteString(strconv.Itoa(len(v)))
+	b.WriteByte(':')
+	b.WriteString(v)
+}
+
 // newBatchTracesProcessor creates a new batch processor that batches traces by size or with timeout
-func newBatchTracesProcessor(params component.ProcessorCreateParams, trace consumer.TracesConsumer, cfg *Config, telemetryLevel configtelemetry.Level) *batchProcessor {
-	return newBatchProcessor(params, cfg, newBatchTraces(trace), telemetryLevel)
+func newBatchTracesProcessor(params component.ProcessorCreateParams, trace consumer.TracesConsumer, cfg *Config, telemetryLevel configtelemetry.Level) (*batchProcessor, error) {
+	return newBatchProcessor(params, cfg, func() batch { return newBatchTraces(trace) }, telemetryLevel)
 }
 
 // newBatchMetricsProcessor creates a new batch processor that batches metrics by size or with timeout
-func newBatchMetricsProcessor(params component.ProcessorCreateParams, metrics consumer.MetricsConsumer, cfg *Config, telemetryLevel configtelemetry.Level) *batchProcessor {
-	return newBatchProcessor(params, cfg, newBatchMetrics(metrics), telemetryLevel)
+func newBatchMetricsProcessor(params component.ProcessorCreateParams, metrics consumer.MetricsConsumer, cfg *Config, telemetryLevel configtelemetry.Level) (*batchProcessor, error) {
+	return newBatchProcessor(params, cfg, func() batch { return newBatchMetrics(metrics) }, telemetryLevel)
 }
 
 // newBatchLogsProcessor creates a new batch processor that batches logs by size or with timeout
-func newBatchLogsProcessor(params component.ProcessorCreateParams, logs consumer.LogsConsumer, cfg *Config, telemetryLevel configtelemetry.Level) *batchProcessor {
-	return newBatchProcessor(params, cfg, newBatchLogs(logs), telemetryLevel)
+func newBatchLogsProcessor(params component.ProcessorCreateParams, logs consumer.LogsConsumer, cfg *Config, telemetryLevel configtelemetry.Level) (*batchProcessor, error) {
+	return newBatchProcessor(params, cfg, func() batch { return newBatchLogs(logs) }, telemetryLevel)
 }
 
 type batchTraces struct {
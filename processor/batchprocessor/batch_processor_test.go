@@ -17,6 +17,8 @@ package batchprocessor
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -25,6 +27,7 @@ import (
 	"go.opencensus.io/stats/view"
 	"go.uber.org/zap"
 
+	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configtelemetry"
@@ -38,7 +41,8 @@ func TestBatchProcessorSpansDelivered(t *testing.T) {
 	cfg := createDefaultConfig().(*Config)
 	cfg.SendBatchSize = 128
 	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
-	batcher := newBatchTracesProcessor(creationParams, sink, cfg, configtelemetry.LevelDetailed)
+	batcher, err := newBatchTracesProcessor(creationParams, sink, cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
 	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
 
 	requestCount := 1000
@@ -79,7 +83,8 @@ func TestBatchProcessorSpansDeliveredEnforceBatchSize(t *testing.T) {
 	cfg.SendBatchSize = 128
 	cfg.SendBatchMaxSize = 128
 	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
-	batcher := newBatchTracesProcessor(creationParams, sink, cfg, configtelemetry.LevelBasic)
+	batcher, err := newBatchTracesProcessor(creationParams, sink, cfg, configtelemetry.LevelBasic)
+	require.NoError(t, err)
 	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
 
 	requestCount := 1000
@@ -126,7 +131,8 @@ func TestBatchProcessorSentBySize(t *testing.T) {
 	cfg.SendBatchSize = uint32(sendBatchSize)
 	cfg.Timeout = 500 * time.Millisecond
 	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
-	batcher := newBatchTracesProcessor(creationParams, sink, cfg, configtelemetry.LevelDetailed)
+	batcher, err := newBatchTracesProcessor(creationParams, sink, cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
 	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
 
 	requestCount := 100
@@ -188,7 +194,8 @@ func TestBatchProcessorSentByTimeout(t *testing.T) {
 	spansPerRequest := 10
 	start := time.Now()
 
-	batcher := newBatchTracesProcessor(creationParams, sink, cfg, configtelemetry.LevelDetailed)
+	batcher, err := newBatchTracesProcessor(creationParams, sink, cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
 	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
 
 	for requestNum := 0; requestNum < requestCount; requestNum++ {
@@ -233,7 +240,8 @@ func TestBatchProcessorTraceSendWhenClosing(t *testing.T) {
 	sink := new(consumertest.TracesSink)
 
 	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
-	batcher := newBatchTracesProcessor(creationParams, sink, &cfg, configtelemetry.LevelDetailed)
+	batcher, err := newBatchTracesProcessor(creationParams, sink, &cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
 	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
 
 	requestCount := 10
@@ -262,7 +270,8 @@ func TestBatchMetricProcessor_ReceivingData(t *testing.T) {
 	sink := new(consumertest.MetricsSink)
 
 	createParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
-	batcher := newBatchMetricsProcessor(createParams, sink, &cfg, configtelemetry.LevelDetailed)
+	batcher, err := newBatchMetricsProcessor(createParams, sink, &cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
 	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
 
 	metricDataSlice := make([]pdata.Metrics, 0, requestCount)
@@ -313,7 +322,8 @@ func TestBatchMetricProcessor_BatchSize(t *testing.T) {
 	sink := new(consumertest.MetricsSink)
 
 	createParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
-	batcher := newBatchMetricsProcessor(createParams, sink, &cfg, configtelemetry.LevelDetailed)
+	batcher, err := newBatchMetricsProcessor(createParams, sink, &cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
 	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
 
 	start := time.Now()
@@ -368,7 +378,8 @@ func TestBatchMetricsProcessor_Timeout(t *testing.T) {
 	sink := new(consumertest.MetricsSink)
 
 	createParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
-	batcher := newBatchMetricsProcessor(createParams, sink, &cfg, configtelemetry.LevelDetailed)
+	batcher, err := newBatchMetricsProcessor(createParams, sink, &cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
 	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
 
 	start := time.Now()
@@ -415,7 +426,8 @@ func TestBatchMetricProcessor_Shutdown(t *testing.T) {
 	sink := new(consumertest.MetricsSink)
 
 	createParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
-	batcher := newBatchMetricsProcessor(createParams, sink, &cfg, configtelemetry.LevelDetailed)
+	batcher, err := newBatchMetricsProcessor(createParams, sink, &cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
 	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
 
 	for requestNum := 0; requestNum < requestCount; requestNum++ {
@@ -500,7 +512,8 @@ func TestBatchLogProcessor_ReceivingData(t *testing.T) {
 	sink := new(consumertest.LogsSink)
 
 	createParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
-	batcher := newBatchLogsProcessor(createParams, sink, &cfg, configtelemetry.LevelDetailed)
+	batcher, err := newBatchLogsProcessor(createParams, sink, &cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
 	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
 
 	logDataSlice := make([]pdata.Logs, 0, requestCount)
@@ -551,7 +564,8 @@ func TestBatchLogProcessor_BatchSize(t *testing.T) {
 	sink := new(consumertest.LogsSink)
 
 	createParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
-	batcher := newBatchLogsProcessor(createParams, sink, &cfg, configtelemetry.LevelDetailed)
+	batcher, err := newBatchLogsProcessor(createParams, sink, &cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
 	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
 
 	start := time.Now()
@@ -606,7 +620,8 @@ func TestBatchLogsProcessor_Timeout(t *testing.T) {
 	sink := new(consumertest.LogsSink)
 
 	createParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
-	batcher := newBatchLogsProcessor(createParams, sink, &cfg, configtelemetry.LevelDetailed)
+	batcher, err := newBatchLogsProcessor(createParams, sink, &cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
 	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
 
 	start := time.Now()
@@ -653,7 +668,8 @@ func TestBatchLogProcessor_Shutdown(t *testing.T) {
 	sink := new(consumertest.LogsSink)
 
 	createParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
-	batcher := newBatchLogsProcessor(createParams, sink, &cfg, configtelemetry.LevelDetailed)
+	batcher, err := newBatchLogsProcessor(createParams, sink, &cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
 	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
 
 	for requestNum := 0; requestNum < requestCount; requestNum++ {
@@ -689,3 +705,310 @@ func logsReceivedByName(lds []pdata.Logs) map[string]pdata.LogRecord {
 	}
 	return logsReceivedByName
 }
+
+// tenantTracesSink counts the spans exported per value of the X-Tenant metadata.
+type tenantTracesSink struct {
+	mu      sync.Mutex
+	batches int
+	spans   map[string]int
+}
+
+func (s *tenantTracesSink) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tenant := "<none>"
+	if c, ok := client.FromContext(ctx); ok {
+		tenant = strings.Join(c.Metadata["x-tenant"], ",")
+	}
+	if s.spans == nil {
+		s.spans = map[string]int{}
+	}
+	s.batches++
+	s.spans[tenant] += td.SpanCount()
+	return nil
+}
+
+func tenantContext(tenant string) context.Context {
+	return client.NewContext(context.Background(), &client.Client{
+		IP:       "10.0.0.1",
+		Metadata: map[string][]string{"x-tenant": {tenant}, "authorization": {"secret"}},
+	})
+}
+
+func TestBatchProcessorMetadataKeys(t *testing.T) {
+	sink := &tenantTracesSink{}
+	cfg := createDefaultConfig().(*Config)
+	cfg.SendBatchSize = 1000
+	cfg.Timeout = 10 * time.Second
+	cfg.MetadataKeys = []string{"X-Tenant"}
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	batcher, err := newBatchTracesProcessor(creationParams, sink, cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, batcher.ConsumeTraces(tenantContext("a"), testdata.GenerateTraceDataManySpansSameResource(10)))
+		assert.NoError(t, batcher.ConsumeTraces(tenantContext("b"), testdata.GenerateTraceDataManySpansSameResource(5)))
+		assert.NoError(t, batcher.ConsumeTraces(context.Background(), testdata.GenerateTraceDataManySpansSameResource(1)))
+	}
+
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	assert.Equal(t, 3, sink.batches)
+	assert.Equal(t, map[string]int{"a": 100, "b": 50, "<none>": 10}, sink.spans)
+}
+
+func TestBatchProcessorMetadataKeysExportContext(t *testing.T) {
+	var exported []*client.Client
+	sink := consumerFunc(func(ctx context.Context) {
+		c, _ := client.FromContext(ctx)
+		exported = append(exported, c)
+	})
+	cfg := createDefaultConfig().(*Config)
+	cfg.MetadataKeys = []string{"x-tenant"}
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	batcher, err := newBatchMetricsProcessor(creationParams, sink, cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, batcher.ConsumeMetrics(tenantContext("a"), testdata.GenerateMetricsManyMetricsSameResource(1)))
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	// Only the configured keys are kept, the other client information doesn't apply to a whole batch.
+	require.Len(t, exported, 1)
+	assert.Equal(t, &client.Client{Metadata: map[string][]string{"x-tenant": {"a"}}}, exported[0])
+}
+
+func TestBatchProcessorResourceAttributes(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.ResourceAttributes = []string{"service.name"}
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	batcher, err := newBatchMetricsProcessor(creationParams, sink, cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	for i := 0; i < 5; i++ {
+		md := pdata.NewMetrics()
+		for _, service := range []string{"svc-a", "svc-b", "svc-a", ""} {
+			rm := testdata.GenerateMetricsManyMetricsSameResource(2).ResourceMetrics().At(0)
+			if service != "" {
+				rm.Resource().Attributes().UpsertString("service.name", service)
+			}
+			md.ResourceMetrics().Append(rm)
+		}
+		assert.NoError(t, batcher.ConsumeMetrics(context.Background(), md))
+	}
+
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	require.Equal(t, 40, sink.MetricsCount())
+	metricsByService := map[string]int{}
+	for _, md := range sink.AllMetrics() {
+		services := map[string]bool{}
+		rms := md.ResourceMetrics()
+		for i := 0; i < rms.Len(); i++ {
+			service := "<none>"
+			if v, ok := rms.At(i).Resource().Attributes().Get("service.name"); ok {
+				service = v.StringVal()
+			}
+			services[service] = true
+		}
+		require.Len(t, services, 1)
+		for service := range services {
+			metricsByService[service] += md.MetricCount()
+		}
+	}
+	assert.Equal(t, map[string]int{"svc-a": 20, "svc-b": 10, "<none>": 10}, metricsByService)
+}
+
+func TestBatchProcessorMetadataCardinalityLimit(t *testing.T) {
+	sink := new(consumertest.LogsSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.MetadataKeys = []string{"x-tenant"}
+	cfg.MetadataCardinalityLimit = 2
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	batcher, err := newBatchLogsProcessor(creationParams, sink, cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	assert.NoError(t, batcher.ConsumeLogs(tenantContext("a"), testdata.GenerateLogDataManyLogsSameResource(1)))
+	assert.NoError(t, batcher.ConsumeLogs(tenantContext("b"), testdata.GenerateLogDataManyLogsSameResource(1)))
+	assert.Equal(t, errTooManyBatches, batcher.ConsumeLogs(tenantContext("c"), testdata.GenerateLogDataManyLogsSameResource(1)))
+	assert.NoError(t, batcher.ConsumeLogs(tenantContext("a"), testdata.GenerateLogDataManyLogsSameResource(1)))
+
+	require.NoError(t, batcher.Shutdown(context.Background()))
+	assert.Equal(t, 3, sink.LogRecordsCount())
+}
+
+func TestBatchProcessorResourceAttributesCardinalityLimit(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.ResourceAttributes = []string{"service.name"}
+	cfg.MetadataCardinalityLimit = 2
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	batcher, err := newBatchMetricsProcessor(creationParams, sink, cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	metrics := func(services ...string) pdata.Metrics {
+		md := pdata.NewMetrics()
+		for _, service := range services {
+			rm := testdata.GenerateMetricsManyMetricsSameResource(1).ResourceMetrics().At(0)
+			rm.Resource().Attributes().UpsertString("service.name", service)
+			md.ResourceMetrics().Append(rm)
+		}
+		return md
+	}
+
+	// None of the resources is batched when one of them is refused, so that the data can be
+	// retried without duplicates.
+	assert.Equal(t, errTooManyBatches, batcher.ConsumeMetrics(context.Background(), metrics("svc-a", "svc-b", "svc-c")))
+	assert.NoError(t, batcher.ConsumeMetrics(context.Background(), metrics("svc-a", "svc-b", "svc-a")))
+	assert.Equal(t, errTooManyBatches, batcher.ConsumeMetrics(context.Background(), metrics("svc-a", "svc-c")))
+
+	require.NoError(t, batcher.Shutdown(context.Background()))
+	assert.Equal(t, 3, sink.MetricsCount())
+}
+
+func TestBatchProcessorRemovesIdleShards(t *testing.T) {
+	sink := &tenantTracesSink{}
+	cfg := createDefaultConfig().(*Config)
+	cfg.Timeout = 10 * time.Millisecond
+	cfg.MetadataKeys = []string{"x-tenant"}
+	cfg.MetadataCardinalityLimit = 1
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	batcher, err := newBatchTracesProcessor(creationParams, sink, cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	shardCount := func() int {
+		batcher.lock.Lock()
+		defer batcher.lock.Unlock()
+		return len(batcher.shards)
+	}
+
+	assert.NoError(t, batcher.ConsumeTraces(tenantContext("a"), testdata.GenerateTraceDataManySpansSameResource(1)))
+	assert.Eventually(t, func() bool { return shardCount() == 0 }, time.Second, 5*time.Millisecond)
+
+	// The limit applies to the shards that are kept at the same time.
+	assert.NoError(t, batcher.ConsumeTraces(tenantContext("b"), testdata.GenerateTraceDataManySpansSameResource(1)))
+	require.NoError(t, batcher.Shutdown(context.Background()))
+	assert.Equal(t, map[string]int{"a": 1, "b": 1}, sink.spans)
+}
+
+func TestBatchProcessorInvalidKeys(t *testing.T) {
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.MetadataKeys = []string{"X-Tenant", "x-tenant"}
+	_, err := newBatchTracesProcessor(creationParams, consumertest.NewTracesNop(), cfg, configtelemetry.LevelDetailed)
+	assert.EqualError(t, err, `duplicate key "x-tenant" in metadata_keys`)
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.ResourceAttributes = []string{""}
+	_, err = newBatchTracesProcessor(creationParams, consumertest.NewTracesNop(), cfg, configtelemetry.LevelDetailed)
+	assert.EqualError(t, err, "resource_attributes must not contain empty keys")
+}
+
+// consumerFunc is a metrics consumer calling a function with the context of every batch.
+type consumerFunc func(ctx context.Context)
+
+func (f consumerFunc) ConsumeMetrics(ctx context.Context, _ pdata.Metrics) error {
+	f(ctx)
+	return nil
+}
+
+func TestBatchProcessorSendBatchMaxBytes(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.Timeout = 10 * time.Second
+	cfg.SendBatchMaxBytes = 4000
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	batcher, err := newBatchTracesProcessor(creationParams, sink, cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	requestCount := 100
+	spansPerRequest := 10
+	for requestNum := 0; requestNum < requestCount; requestNum++ {
+		assert.NoError(t, batcher.ConsumeTraces(context.Background(), testdata.GenerateTraceDataManySpansSameResource(spansPerRequest)))
+	}
+	// A single span larger than the limit is sent on its own.
+	td := testdata.GenerateTraceDataManySpansSameResource(1)
+	td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).SetName(strings.Repeat("x", 5000))
+	assert.NoError(t, batcher.ConsumeTraces(context.Background(), td))
+
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	require.Equal(t, requestCount*spansPerRequest+1, sink.SpansCount())
+	receivedTraces := sink.AllTraces()
+	require.Greater(t, len(receivedTraces), 1)
+	for _, td := range receivedTraces {
+		if td.SpanCount() > 1 {
+			assert.LessOrEqual(t, tracesBytes(td), 4000)
+		}
+	}
+	assert.Greater(t, tracesBytes(receivedTraces[len(receivedTraces)-1]), 5000)
+}
+
+func TestBatchProcessorSendBatchBytes(t *testing.T) {
+	sink := new(consumertest.LogsSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.Timeout = 10 * time.Second
+	cfg.SendBatchBytes = 2000
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	batcher, err := newBatchLogsProcessor(creationParams, sink, cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	requestCount := 100
+	logsPerRequest := 5
+	for requestNum := 0; requestNum < requestCount; requestNum++ {
+		assert.NoError(t, batcher.ConsumeLogs(context.Background(), testdata.GenerateLogDataManyLogsSameResource(logsPerRequest)))
+	}
+
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	require.Equal(t, requestCount*logsPerRequest, sink.LogRecordsCount())
+	receivedLogs := sink.AllLogs()
+	require.Greater(t, len(receivedLogs), 1)
+	// All batches but the one sent on shutdown reached the size in bytes.
+	for _, ld := range receivedLogs[:len(receivedLogs)-1] {
+		assert.GreaterOrEqual(t, logsBytes(ld), 2000)
+	}
+}
+
+func TestBatchLogProcessor_SendBatchMaxSize(t *testing.T) {
+	sink := new(consumertest.LogsSink)
+	cfg := Config{
+		Timeout:          10 * time.Second,
+		SendBatchSize:    50,
+		SendBatchMaxSize: 50,
+	}
+	createParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	batcher, err := newBatchLogsProcessor(createParams, sink, &cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	requestCount := 10
+	logsPerRequest := 30
+	for requestNum := 0; requestNum < requestCount; requestNum++ {
+		assert.NoError(t, batcher.ConsumeLogs(context.Background(), testdata.GenerateLogDataManyLogsSameResource(logsPerRequest)))
+	}
+
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	require.Equal(t, requestCount*logsPerRequest, sink.LogRecordsCount())
+	for _, ld := range sink.AllLogs() {
+		assert.LessOrEqual(t, ld.LogRecordCount(), 50)
+	}
+}
+
+func TestBatchProcessorInvalidBytes(t *testing.T) {
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	cfg := createDefaultConfig().(*Config)
+	cfg.SendBatchBytes = 2000
+	cfg.SendBatchMaxBytes = 1000
+	_, err := newBatchTracesProcessor(creationParams, consumertest.NewTracesNop(), cfg, configtelemetry.LevelDetailed)
+	assert.EqualError(t, err, "send_batch_bytes (2000) must not be greater than send_batch_max_bytes (1000)")
+}
@@ -33,4 +33,28 @@ type Config struct {
 	// SendBatchMaxSize is the maximum size of a batch. Larger batches are split into smaller units.
 	// Default value is 0, that means no maximum size.
 	SendBatchMaxSize uint32 `mapstructure:"send_batch_max_size,omitempty"`
+
+	// SendBatchBytes is the size in bytes of a batch, serialized as an OTLP request, which after hit
+	// will trigger it to be sent. Default value is 0, that means batches are not sent based on their size in bytes.
+	SendBatchBytes uint32 `mapstructure:"send_batch_bytes,omitempty"`
+
+	// SendBatchMaxBytes is the maximum size in bytes of a batch, serialized as an OTLP request.
+	// Larger batches are split into smaller units, a single span, metric or log record larger than
+	// this is still sent on its own. Default value is 0, that means no maximum size in bytes.
+	SendBatchMaxBytes uint32 `mapstructure:"send_batch_max_bytes,omitempty"`
+
+	// MetadataKeys is a list of client metadata keys, as kept by the receivers' include_metadata
+	// setting, to batch by. Data with different values for these keys is kept in separate batches,
+	// and every batch is exported with a client context carrying its metadata values.
+	// Keys are case-insensitive.
+	MetadataKeys []string `mapstructure:"metadata_keys,omitempty"`
+
+	// ResourceAttributes is a list of resource attributes to batch by. Resources with different values
+	// for these attributes are kept in separate batches.
+	ResourceAttributes []string `mapstructure:"resource_attributes,omitempty"`
+
+	// MetadataCardinalityLimit is the maximum number of batches kept at the same time when batching
+	// by MetadataKeys or ResourceAttributes. Data for new batches is refused once the limit is reached.
+	// Default value is 1000, 0 means no limit.
+	MetadataCardinalityLimit uint32 `mapstructure:"metadata_cardinality_limit,omitempty"`
 }
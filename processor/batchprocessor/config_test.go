@@ -56,5 +56,25 @@ func TestLoadConfig(t *testing.T) {
 			SendBatchSize:    sendBatchSize,
 			SendBatchMaxSize: sendBatchMaxSize,
 			Timeout:          timeout,
+
+			SendBatchBytes:    1000000,
+			SendBatchMaxBytes: 4000000,
+
+			MetadataCardinalityLimit: defaultMetadataCardinalityLimit,
+		})
+
+	p2 := cfg.Processors["batch/3"]
+	assert.Equal(t, p2,
+		&Config{
+			ProcessorSettings: configmodels.ProcessorSettings{
+				TypeVal: "batch",
+				NameVal: "batch/3",
+			},
+			SendBatchSize: defaultSendBatchSize,
+			Timeout:       defaultTimeout,
+
+			MetadataKeys:             []string{"X-Tenant"},
+			ResourceAttributes:       []string{"service.name"},
+			MetadataCardinalityLimit: 100,
 		})
 }
@@ -31,6 +31,8 @@ const (
 
 	defaultSendBatchSize = uint32(8192)
 	defaultTimeout       = 200 * time.Millisecond
+
+	defaultMetadataCardinalityLimit = uint32(1000)
 )
 
 // NewFactory returns a new factory for the Batch processor.
@@ -51,6 +53,8 @@ func createDefaultConfig() configmodels.Processor {
 		},
 		SendBatchSize: defaultSendBatchSize,
 		Timeout:       defaultTimeout,
+
+		MetadataCardinalityLimit: defaultMetadataCardinalityLimit,
 	}
 }
 
@@ -62,7 +66,11 @@ func createTraceProcessor(
 ) (component.TracesProcessor, error) {
 	oCfg := cfg.(*Config)
 	level := configtelemetry.GetMetricsLevelFlagValue()
-	return newBatchTracesProcessor(params, nextConsumer, oCfg, level), nil
+	bp, err := newBatchTracesProcessor(params, nextConsumer, oCfg, level)
+	if err != nil {
+		return nil, err
+	}
+	return bp, nil
 }
 
 func createMetricsProcessor(
@@ -73,7 +81,11 @@ func createMetricsProcessor(
 ) (component.MetricsProcessor, error) {
 	oCfg := cfg.(*Config)
 	level := configtelemetry.GetMetricsLevelFlagValue()
-	return newBatchMetricsProcessor(params, nextConsumer, oCfg, level), nil
+	bp, err := newBatchMetricsProcessor(params, nextConsumer, oCfg, level)
+	if err != nil {
+		return nil, err
+	}
+	return bp, nil
 }
 
 func createLogsProcessor(
@@ -84,5 +96,9 @@ func createLogsProcessor(
 ) (component.LogsProcessor, error) {
 	oCfg := cfg.(*Config)
 	level := configtelemetry.GetMetricsLevelFlagValue()
-	return newBatchLogsProcessor(params, nextConsumer, oCfg, level), nil
+	bp, err := newBatchLogsProcessor(params, nextConsumer, oCfg, level)
+	if err != nil {
+		return nil, err
+	}
+	return bp, nil
 }
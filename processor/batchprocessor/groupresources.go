@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batchprocessor
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+// resourceKey returns the shard key of the values of the given attributes of resource.
+func resourceKey(resource pdata.Resource, attributes []string) string {
+	var b strings.Builder
+	attrs := resource.Attributes()
+	for _, attr := range attributes {
+		v, ok := attrs.Get(attr)
+		if !ok {
+			// A missing attribute must not share its key with an empty one.
+			b.WriteByte('-')
+			continue
+		}
+		appendKeyValue(&b, tracetranslator.AttributeValueToString(v, false))
+	}
+	return b.String()
+}
+
+// keyGroups keeps the keys used by a batch in the order they were first seen.
+type keyGroups struct {
+	keys    []string
+	indexes map[string]int
+}
+
+// group returns the index of the group of key, and true if it is a new group.
+func (kg *keyGroups) group(key string) (int, bool) {
+	if i, ok := kg.indexes[key]; ok {
+		return i, false
+	}
+	if kg.indexes == nil {
+		kg.indexes = make(map[string]int)
+	}
+	kg.indexes[key] = len(kg.keys)
+	kg.keys = append(kg.keys, key)
+	return len(kg.keys) - 1, true
+}
+
+// groupTraces splits td by the values of the given resource attributes.
+func groupTraces(td pdata.Traces, attributes []string) ([]string, []pdata.Traces) {
+	var kg keyGroups
+	var groups []pdata.Traces
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		idx, isNew := kg.group(resourceKey(rs.Resource(), attributes))
+		if isNew {
+			groups = append(groups, pdata.NewTraces())
+		}
+		groups[idx].ResourceSpans().Append(rs)
+	}
+	return kg.keys, groups
+}
+
+// groupMetrics splits md by the values of the given resource attributes.
+func groupMetrics(md pdata.Metrics, attributes []string) ([]string, []pdata.Metrics) {
+	var kg keyGroups
+	var groups []pdata.Metrics
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		idx, isNew := kg.group(resourceKey(rm.Resource(), attributes))
+		if isNew {
+			groups = append(groups, pdata.NewMetrics())
+		}
+		groups[idx].ResourceMetrics().Append(rm)
+	}
+	return kg.keys, groups
+}
+
+// groupLogs splits ld by the values of the given resource attributes.
+func groupLogs(ld pdata.Logs, attributes []string) ([]string, []pdata.Logs) {
+	var kg keyGroups
+	var groups []pdata.Logs
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		idx, isNew := kg.group(resourceKey(rl.Resource(), attributes))
+		if isNew {
+			groups = append(groups, pdata.NewLogs())
+		}
+		groups[idx].ResourceLogs().Append(rl)
+	}
+	return kg.keys, groups
+}
@@ -24,10 +24,11 @@ import (
 )
 
 var (
-	statBatchSizeTriggerSend = stats.Int64("batch_size_trigger_send", "Number of times the batch was sent due to a size trigger", stats.UnitDimensionless)
-	statTimeoutTriggerSend   = stats.Int64("timeout_trigger_send", "Number of times the batch was sent due to a timeout trigger", stats.UnitDimensionless)
-	statBatchSendSize        = stats.Int64("batch_send_size", "Number of units in the batch", stats.UnitDimensionless)
-	statBatchSendSizeBytes   = stats.Int64("batch_send_size_bytes", "Number of bytes in batch that was sent", stats.UnitBytes)
+	statBatchSizeTriggerSend  = stats.Int64("batch_size_trigger_send", "Number of times the batch was sent due to a size trigger", stats.UnitDimensionless)
+	statTimeoutTriggerSend    = stats.Int64("timeout_trigger_send", "Number of times the batch was sent due to a timeout trigger", stats.UnitDimensionless)
+	statBatchBytesTriggerSend = stats.Int64("batch_bytes_trigger_send", "Number of times the batch was sent due to its size in bytes", stats.UnitDimensionless)
+	statBatchSendSize         = stats.Int64("batch_send_size", "Number of units in the batch", stats.UnitDimensionless)
+	statBatchSendSizeBytes    = stats.Int64("batch_send_size_bytes", "Number of bytes in batch that was sent", stats.UnitBytes)
 )
 
 // MetricViews returns the metrics views related to batching
@@ -50,6 +51,14 @@ func MetricViews() []*view.View {
 		Aggregation: view.Sum(),
 	}
 
+	countBatchBytesTriggerSendView := &view.View{
+		Name:        statBatchBytesTriggerSend.Name(),
+		Measure:     statBatchBytesTriggerSend,
+		Description: statBatchBytesTriggerSend.Description(),
+		TagKeys:     processorTagKeys,
+		Aggregation: view.Sum(),
+	}
+
 	distributionBatchSendSizeView := &view.View{
 		Name:        statBatchSendSize.Name(),
 		Measure:     statBatchSendSize,
@@ -73,6 +82,7 @@ func MetricViews() []*view.View {
 		countTimeoutTriggerSendView,
 		distributionBatchSendSizeView,
 		distributionBatchSendSizeBytesView,
+		countBatchBytesTriggerSendView,
 	}
 
 	return obsreport.ProcessorMetricViews(typeStr, legacyViews)
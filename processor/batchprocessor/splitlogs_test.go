@@ -0,0 +1,127 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batchprocessor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal"
+	otlpcollectorlogs "go.opentelemetry.io/collector/internal/data/protogen/collector/logs/v1"
+	"go.opentelemetry.io/collector/internal/testdata"
+)
+
+func TestSplitLogs_noop(t *testing.T) {
+	ld := testdata.GenerateLogDataManyLogsSameResource(20)
+	splitSize := 40
+	split := splitLogs(splitSize, ld)
+	assert.Equal(t, ld, split)
+
+	ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().Resize(5)
+	assert.EqualValues(t, ld, split)
+}
+
+func TestSplitLogs(t *testing.T) {
+	ld := testdata.GenerateLogDataManyLogsSameResource(20)
+	logs := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	for i := 0; i < logs.Len(); i++ {
+		logs.At(i).SetName(getTestLogName(0, i))
+	}
+	cp := pdata.NewLogs()
+	cp.ResourceLogs().Resize(1)
+	cp.ResourceLogs().At(0).InstrumentationLibraryLogs().Resize(1)
+	cp.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().Resize(5)
+	cpLogs := cp.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	ld.ResourceLogs().At(0).Resource().CopyTo(
+		cp.ResourceLogs().At(0).Resource())
+	ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).InstrumentationLibrary().CopyTo(
+		cp.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).InstrumentationLibrary())
+	logs.At(19).CopyTo(cpLogs.At(0))
+	logs.At(18).CopyTo(cpLogs.At(1))
+	logs.At(17).CopyTo(cpLogs.At(2))
+	logs.At(16).CopyTo(cpLogs.At(3))
+	logs.At(15).CopyTo(cpLogs.At(4))
+
+	splitSize := 5
+	split := splitLogs(splitSize, ld)
+	assert.Equal(t, splitSize, split.LogRecordCount())
+	assert.Equal(t, cp, split)
+	assert.Equal(t, 15, ld.LogRecordCount())
+	assert.Equal(t, "test-log-int-0-19", split.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).Name())
+	assert.Equal(t, "test-log-int-0-15", split.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(4).Name())
+}
+
+func TestSplitLogsMultipleResourceLogs_split_size_greater_than_log_size(t *testing.T) {
+	ld := testdata.GenerateLogDataManyLogsSameResource(20)
+	logs := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	for i := 0; i < logs.Len(); i++ {
+		logs.At(i).SetName(getTestLogName(0, i))
+	}
+	ld.ResourceLogs().Resize(2)
+	// add second index to resource logs
+	testdata.GenerateLogDataManyLogsSameResource(20).
+		ResourceLogs().At(0).CopyTo(ld.ResourceLogs().At(1))
+	logs = ld.ResourceLogs().At(1).InstrumentationLibraryLogs().At(0).Logs()
+	for i := 0; i < logs.Len(); i++ {
+		logs.At(i).SetName(getTestLogName(1, i))
+	}
+
+	splitSize := 25
+	split := splitLogs(splitSize, ld)
+	assert.Equal(t, splitSize, split.LogRecordCount())
+	assert.Equal(t, 40-splitSize, ld.LogRecordCount())
+	assert.Equal(t, 1, ld.ResourceLogs().Len())
+	assert.Equal(t, "test-log-int-1-19", split.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).Name())
+	assert.Equal(t, "test-log-int-1-0", split.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(19).Name())
+	assert.Equal(t, "test-log-int-0-19", split.ResourceLogs().At(1).InstrumentationLibraryLogs().At(0).Logs().At(0).Name())
+	assert.Equal(t, "test-log-int-0-15", split.ResourceLogs().At(1).InstrumentationLibraryLogs().At(0).Logs().At(4).Name())
+}
+
+func TestSplitLogsBytes(t *testing.T) {
+	ld := testdata.GenerateLogDataManyLogsSameResource(20)
+	ld.ResourceLogs().Resize(2)
+	testdata.GenerateLogDataManyLogsSameResource(20).
+		ResourceLogs().At(0).CopyTo(ld.ResourceLogs().At(1))
+	// the second resource has two instrumentation libraries and a larger log record
+	ld.ResourceLogs().At(1).Resource().Attributes().UpsertString("service.name", "svc")
+	ld.ResourceLogs().At(1).InstrumentationLibraryLogs().Resize(2)
+	ld.ResourceLogs().At(1).InstrumentationLibraryLogs().At(1).InstrumentationLibrary().SetName("lib")
+	ld.ResourceLogs().At(1).InstrumentationLibraryLogs().At(1).Logs().Resize(1)
+	ld.ResourceLogs().At(1).InstrumentationLibraryLogs().At(1).Logs().At(0).SetName(strings.Repeat("x", 200))
+
+	requestSize := func(ld pdata.Logs) int {
+		return (&otlpcollectorlogs.ExportLogsServiceRequest{ResourceLogs: internal.LogsToOtlp(ld.InternalRep())}).Size()
+	}
+	total := requestSize(ld)
+	assert.Equal(t, total, logsBytes(ld))
+
+	for _, maxBytes := range []int{1, 100, 300, total / 2, total - 1} {
+		toSplit := ld.Clone()
+		split := splitLogsBytes(maxBytes, toSplit)
+		assert.Equal(t, 41, split.LogRecordCount()+toSplit.LogRecordCount())
+		assert.Equal(t, requestSize(split), logsBytes(split))
+		if split.LogRecordCount() > 1 {
+			assert.LessOrEqual(t, requestSize(split), maxBytes)
+		}
+		// one more log record doesn't fit
+		assert.Greater(t, requestSize(splitLogs(split.LogRecordCount()+1, ld.Clone())), maxBytes)
+	}
+
+	split := splitLogsBytes(total, ld)
+	assert.Equal(t, ld, split)
+}
@@ -60,6 +60,7 @@ func splitMetrics(size int, toSplit pdata.Metrics) pdata.Metrics {
 				rm.InstrumentationLibraryMetrics().Resize(rm.InstrumentationLibraryMetrics().Len() - 1)
 			}
 			if copiedMetrics == size {
+				destRs.InstrumentationLibraryMetrics().Resize(ilmCount)
 				result.ResourceMetrics().Resize(rmsCount)
 				return result
 			}
@@ -72,3 +73,45 @@ func splitMetrics(size int, toSplit pdata.Metrics) pdata.Metrics {
 	result.ResourceMetrics().Resize(rmsCount)
 	return result
 }
+
+// splitMetricsBytes removes metrics from the input data and returns a new data holding as many metrics
+// as fit in maxBytes once serialized as an OTLP request, and at least one metric.
+func splitMetricsBytes(maxBytes int, toSplit pdata.Metrics) pdata.Metrics {
+	return splitMetrics(metricsWithinBytes(maxBytes, toSplit), toSplit)
+}
+
+// metricsWithinBytes returns the number of metrics splitMetrics has to take from md, in the same order,
+// for the result to fit in maxBytes once serialized as an OTLP request. It is at least one.
+func metricsWithinBytes(maxBytes int, md pdata.Metrics) int {
+	count := 0
+	size := 0
+	rms := pdata.MetricsToOtlp(md)
+	for i := len(rms) - 1; i >= 0; i-- {
+		rm := rms[i]
+		rmSize := protoFieldSize(rm.Resource.Size())
+		for j := len(rm.InstrumentationLibraryMetrics) - 1; j >= 0; j-- {
+			ilm := rm.InstrumentationLibraryMetrics[j]
+			ilmSize := protoFieldSize(ilm.InstrumentationLibrary.Size())
+			for k := len(ilm.Metrics) - 1; k >= 0; k-- {
+				newILMSize := ilmSize + protoFieldSize(ilm.Metrics[k].Size())
+				if count > 0 && size+protoFieldSize(rmSize+protoFieldSize(newILMSize)) > maxBytes {
+					return count
+				}
+				ilmSize = newILMSize
+				count++
+			}
+			rmSize += protoFieldSize(ilmSize)
+		}
+		size += protoFieldSize(rmSize)
+	}
+	return count
+}
+
+// metricsBytes returns the size of md serialized as an OTLP request.
+func metricsBytes(md pdata.Metrics) int {
+	size := 0
+	for _, rm := range pdata.MetricsToOtlp(md) {
+		size += protoFieldSize(rm.Size())
+	}
+	return size
+}
@@ -15,11 +15,13 @@
 package batchprocessor
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
+	otlpcollectormetrics "go.opentelemetry.io/collector/internal/data/protogen/collector/metrics/v1"
 	"go.opentelemetry.io/collector/internal/testdata"
 )
 
@@ -111,3 +113,42 @@ func TestSplitMetricsMultipleResourceSpans_split_size_greater_than_metric_size(t
 	assert.Equal(t, "test-metric-int-0-19", split.ResourceMetrics().At(1).InstrumentationLibraryMetrics().At(0).Metrics().At(0).Name())
 	assert.Equal(t, "test-metric-int-0-15", split.ResourceMetrics().At(1).InstrumentationLibraryMetrics().At(0).Metrics().At(4).Name())
 }
+
+func TestSplitMetricsBytes(t *testing.T) {
+	md := testdata.GenerateMetricsManyMetricsSameResource(20)
+	md.ResourceMetrics().Resize(2)
+	testdata.GenerateMetricsManyMetricsSameResource(20).
+		ResourceMetrics().At(0).CopyTo(md.ResourceMetrics().At(1))
+	// the second resource has two instrumentation libraries and a larger metric
+	md.ResourceMetrics().At(1).Resource().Attributes().UpsertString("service.name", "svc")
+	md.ResourceMetrics().At(1).InstrumentationLibraryMetrics().Resize(2)
+	md.ResourceMetrics().At(1).InstrumentationLibraryMetrics().At(1).InstrumentationLibrary().SetName("lib")
+	md.ResourceMetrics().At(1).InstrumentationLibraryMetrics().At(1).Metrics().Resize(1)
+	md.ResourceMetrics().At(1).InstrumentationLibraryMetrics().At(1).Metrics().At(0).SetName(strings.Repeat("x", 200))
+
+	requestSize := func(md pdata.Metrics) int {
+		return (&otlpcollectormetrics.ExportMetricsServiceRequest{ResourceMetrics: pdata.MetricsToOtlp(md)}).Size()
+	}
+	total := requestSize(md)
+	assert.Equal(t, total, metricsBytes(md))
+
+	for _, maxBytes := range []int{1, 100, 300, total / 2, total - 1} {
+		toSplit := md.Clone()
+		split := splitMetricsBytes(maxBytes, toSplit)
+		assert.Equal(t, 41, split.MetricCount()+toSplit.MetricCount())
+		assert.Equal(t, requestSize(split), metricsBytes(split))
+		if split.MetricCount() > 1 {
+			assert.LessOrEqual(t, requestSize(split), maxBytes)
+		}
+		// one more metric doesn't fit
+		assert.Greater(t, requestSize(splitMetrics(split.MetricCount()+1, md.Clone())), maxBytes)
+	}
+
+	// only the instrumentation library of the larger metric is copied
+	split := splitMetricsBytes(1, md.Clone())
+	assert.Equal(t, 1, split.MetricCount())
+	assert.Equal(t, 1, split.ResourceMetrics().At(0).InstrumentationLibraryMetrics().Len())
+
+	split = splitMetricsBytes(total, md)
+	assert.Equal(t, md, split)
+}
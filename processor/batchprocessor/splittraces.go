@@ -67,3 +67,55 @@ func splitTrace(size int, toSplit pdata.Traces) pdata.Traces {
 	result.ResourceSpans().Resize(rssCount)
 	return result
 }
+
+// splitTraceBytes removes spans from the input trace and returns a new trace holding as many spans
+// as fit in maxBytes once serialized as an OTLP request, and at least one span.
+func splitTraceBytes(maxBytes int, toSplit pdata.Traces) pdata.Traces {
+	return splitTrace(spansWithinBytes(maxBytes, toSplit), toSplit)
+}
+
+// spansWithinBytes returns the number of spans splitTrace has to take from td, in the same order,
+// for the result to fit in maxBytes once serialized as an OTLP request. It is at least one.
+func spansWithinBytes(maxBytes int, td pdata.Traces) int {
+	count := 0
+	size := 0
+	rss := pdata.TracesToOtlp(td)
+	for i := len(rss) - 1; i >= 0; i-- {
+		rs := rss[i]
+		rsSize := protoFieldSize(rs.Resource.Size())
+		for j := len(rs.InstrumentationLibrarySpans) - 1; j >= 0; j-- {
+			ils := rs.InstrumentationLibrarySpans[j]
+			ilsSize := protoFieldSize(ils.InstrumentationLibrary.Size())
+			for k := len(ils.Spans) - 1; k >= 0; k-- {
+				newILSSize := ilsSize + protoFieldSize(ils.Spans[k].Size())
+				if count > 0 && size+protoFieldSize(rsSize+protoFieldSize(newILSSize)) > maxBytes {
+					return count
+				}
+				ilsSize = newILSSize
+				count++
+			}
+			rsSize += protoFieldSize(ilsSize)
+		}
+		size += protoFieldSize(rsSize)
+	}
+	return count
+}
+
+// tracesBytes returns the size of td serialized as an OTLP request.
+func tracesBytes(td pdata.Traces) int {
+	size := 0
+	for _, rs := range pdata.TracesToOtlp(td) {
+		size += protoFieldSize(rs.Size())
+	}
+	return size
+}
+
+// protoFieldSize returns the serialized size of a protobuf length-delimited field of the given size,
+// with a single byte tag as used by all the OTLP messages.
+func protoFieldSize(size int) int {
+	n := 2
+	for v := size; v >= 0x80; v >>= 7 {
+		n++
+	}
+	return n + size
+}
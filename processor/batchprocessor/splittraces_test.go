@@ -15,11 +15,13 @@
 package batchprocessor
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
+	otlpcollectortrace "go.opentelemetry.io/collector/internal/data/protogen/collector/trace/v1"
 	"go.opentelemetry.io/collector/internal/testdata"
 )
 
@@ -111,3 +113,37 @@ func TestSplitTracesMultipleResourceSpans_split_size_greater_than_span_size(t *t
 	assert.Equal(t, "test-span-0-19", split.ResourceSpans().At(1).InstrumentationLibrarySpans().At(0).Spans().At(0).Name())
 	assert.Equal(t, "test-span-0-15", split.ResourceSpans().At(1).InstrumentationLibrarySpans().At(0).Spans().At(4).Name())
 }
+
+func TestSplitTracesBytes(t *testing.T) {
+	td := testdata.GenerateTraceDataManySpansSameResource(20)
+	td.ResourceSpans().Resize(2)
+	testdata.GenerateTraceDataManySpansSameResource(20).
+		ResourceSpans().At(0).CopyTo(td.ResourceSpans().At(1))
+	// the second resource has two instrumentation libraries and a larger span
+	td.ResourceSpans().At(1).Resource().Attributes().UpsertString("service.name", "svc")
+	td.ResourceSpans().At(1).InstrumentationLibrarySpans().Resize(2)
+	td.ResourceSpans().At(1).InstrumentationLibrarySpans().At(1).InstrumentationLibrary().SetName("lib")
+	td.ResourceSpans().At(1).InstrumentationLibrarySpans().At(1).Spans().Resize(1)
+	td.ResourceSpans().At(1).InstrumentationLibrarySpans().At(1).Spans().At(0).SetName(strings.Repeat("x", 200))
+
+	requestSize := func(td pdata.Traces) int {
+		return (&otlpcollectortrace.ExportTraceServiceRequest{ResourceSpans: pdata.TracesToOtlp(td)}).Size()
+	}
+	total := requestSize(td)
+	assert.Equal(t, total, tracesBytes(td))
+
+	for _, maxBytes := range []int{1, 100, 300, total / 2, total - 1} {
+		toSplit := td.Clone()
+		split := splitTraceBytes(maxBytes, toSplit)
+		assert.Equal(t, 41, split.SpanCount()+toSplit.SpanCount())
+		assert.Equal(t, requestSize(split), tracesBytes(split))
+		if split.SpanCount() > 1 {
+			assert.LessOrEqual(t, requestSize(split), maxBytes)
+		}
+		// one more span doesn't fit
+		assert.Greater(t, requestSize(splitTrace(split.SpanCount()+1, td.Clone())), maxBytes)
+	}
+
+	split := splitTraceBytes(total, td)
+	assert.Equal(t, td, split)
+}
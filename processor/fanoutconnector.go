@@ -96,3 +96,38 @@ func (fc logsFanOutConnector) ConsumeLogs(ctx context.Context, ld pdata.Logs) er
 	}
 	return componenterror.CombineErrors(errs)
 }
+
+// FanOutConsumers returns the consumers wrapped by a fan-out connector created by this
+// package, cloning or not, or the given consumer itself if it is not a fan-out connector.
+func FanOutConsumers(c interface{}) []interface{} {
+	var result []interface{}
+	switch fc := c.(type) {
+	case traceFanOutConnector:
+		for _, tc := range fc {
+			result = append(result, tc)
+		}
+	case tracesCloningFanOutConnector:
+		for _, tc := range fc {
+			result = append(result, tc)
+		}
+	case metricsFanOutConnector:
+		for _, mc := range fc {
+			result = append(result, mc)
+		}
+	case metricsCloningFanOutConnector:
+		for _, mc := range fc {
+			result = append(result, mc)
+		}
+	case logsFanOutConnector:
+		for _, lc := range fc {
+			result = append(result, lc)
+		}
+	case logsCloningFanOutConnector:
+		for _, lc := range fc {
+			result = append(result, lc)
+		}
+	default:
+		result = append(result, c)
+	}
+	return result
+}
@@ -202,3 +202,18 @@ func TestLogsProcessorWhenOneErrors(t *testing.T) {
 	assert.Equal(t, wantMetricsCount, processors[0].(*consumertest.LogsSink).LogRecordsCount())
 	assert.Equal(t, wantMetricsCount, processors[2].(*consumertest.LogsSink).LogRecordsCount())
 }
+
+func TestFanOutConsumers(t *testing.T) {
+	traces := []consumer.TracesConsumer{new(consumertest.TracesSink), new(consumertest.TracesSink)}
+	assert.Equal(t, []interface{}{traces[0], traces[1]}, FanOutConsumers(NewTracesFanOutConnector(traces)))
+	assert.Equal(t, []interface{}{traces[0], traces[1]}, FanOutConsumers(NewTracesCloningFanOutConnector(traces)))
+	assert.Equal(t, []interface{}{traces[0]}, FanOutConsumers(NewTracesFanOutConnector(traces[:1])))
+
+	metrics := []consumer.MetricsConsumer{new(consumertest.MetricsSink), new(consumertest.MetricsSink)}
+	assert.Equal(t, []interface{}{metrics[0], metrics[1]}, FanOutConsumers(NewMetricsFanOutConnector(metrics)))
+	assert.Equal(t, []interface{}{metrics[0], metrics[1]}, FanOutConsumers(NewMetricsCloningFanOutConnector(metrics)))
+
+	logs := []consumer.LogsConsumer{new(consumertest.LogsSink), new(consumertest.LogsSink)}
+	assert.Equal(t, []interface{}{logs[0], logs[1]}, FanOutConsumers(NewLogsFanOutConnector(logs)))
+	assert.Equal(t, []interface{}{logs[0], logs[1]}, FanOutConsumers(NewLogsCloningFanOutConnector(logs)))
+}
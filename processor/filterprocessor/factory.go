@@ -30,12 +30,17 @@ const (
 
 var processorCapabilities = component.ProcessorCapabilities{MutatesConsumedData: false}
 
+// The spans and log records are filtered in place.
+var mutatingProcessorCapabilities = component.ProcessorCapabilities{MutatesConsumedData: true}
+
 // NewFactory returns a new factory for the Filter processor.
 func NewFactory() component.ProcessorFactory {
 	return processorhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		processorhelper.WithMetrics(createMetricsProcessor))
+		processorhelper.WithTraces(createTracesProcessor),
+		processorhelper.WithMetrics(createMetricsProcessor),
+		processorhelper.WithLogs(createLogsProcessor))
 }
 
 func createDefaultConfig() configmodels.Processor {
@@ -63,3 +68,37 @@ func createMetricsProcessor(
 		fp,
 		processorhelper.WithCapabilities(processorCapabilities))
 }
+
+func createTracesProcessor(
+	_ context.Context,
+	params component.ProcessorCreateParams,
+	cfg configmodels.Processor,
+	nextConsumer consumer.TracesConsumer,
+) (component.TracesProcessor, error) {
+	fsp, err := newFilterSpanProcessor(params.Logger, cfg.(*Config))
+	if err != nil {
+		return nil, err
+	}
+	return processorhelper.NewTraceProcessor(
+		cfg,
+		nextConsumer,
+		fsp,
+		processorhelper.WithCapabilities(mutatingProcessorCapabilities))
+}
+
+func createLogsProcessor(
+	_ context.Context,
+	params component.ProcessorCreateParams,
+	cfg configmodels.Processor,
+	nextConsumer consumer.LogsConsumer,
+) (component.LogsProcessor, error) {
+	flp, err := newFilterLogProcessor(params.Logger, cfg.(*Config))
+	if err != nil {
+		return nil, err
+	}
+	return processorhelper.NewLogsProcessor(
+		cfg,
+		nextConsumer,
+		flp,
+		processorhelper.WithCapabilities(mutatingProcessorCapabilities))
+}
@@ -52,18 +52,36 @@ func TestCreateDefaultConfig(t *testing.T) {
 
 func TestCreateProcessors(t *testing.T) {
 	tests := []struct {
-		configName string
-		succeed    bool
+		configName     string
+		tracesSucceed  bool
+		metricsSucceed bool
+		logsSucceed    bool
 	}{
 		{
-			configName: "config_regexp.yaml",
-			succeed:    true,
+			configName:     "config_regexp.yaml",
+			tracesSucceed:  true,
+			metricsSucceed: true,
+			logsSucceed:    true,
 		}, {
-			configName: "config_strict.yaml",
-			succeed:    true,
+			configName:     "config_strict.yaml",
+			tracesSucceed:  true,
+			metricsSucceed: true,
+			logsSucceed:    true,
 		}, {
-			configName: "config_invalid.yaml",
-			succeed:    false,
+			configName:     "config_traces_logs.yaml",
+			tracesSucceed:  true,
+			metricsSucceed: true,
+			logsSucceed:    true,
+		}, {
+			configName:     "config_invalid.yaml",
+			tracesSucceed:  true,
+			metricsSucceed: false,
+			logsSucceed:    true,
+		}, {
+			configName:     "config_invalid_traces_logs.yaml",
+			tracesSucceed:  false,
+			metricsSucceed: true,
+			logsSucceed:    false,
 		},
 	}
 
@@ -81,13 +99,16 @@ func TestCreateProcessors(t *testing.T) {
 				factory := NewFactory()
 
 				tp, tErr := factory.CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, consumertest.NewTracesNop())
-				// Not implemented error
-				assert.NotNil(t, tErr)
-				assert.Nil(t, tp)
+				assert.Equal(t, test.tracesSucceed, tp != nil)
+				assert.Equal(t, test.tracesSucceed, tErr == nil)
+
+				lp, lErr := factory.CreateLogsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, consumertest.NewLogsNop())
+				assert.Equal(t, test.logsSucceed, lp != nil)
+				assert.Equal(t, test.logsSucceed, lErr == nil)
 
 				mp, mErr := factory.CreateMetricsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, consumertest.NewMetricsNop())
-				assert.Equal(t, test.succeed, mp != nil)
-				assert.Equal(t, test.succeed, mErr == nil)
+				assert.Equal(t, test.metricsSucceed, mp != nil)
+				assert.Equal(t, test.metricsSucceed, mErr == nil)
 			})
 		}
 	}
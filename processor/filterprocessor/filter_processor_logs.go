@@ -0,0 +1,121 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filterprocessor
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/filterlog"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+type filterLogProcessor struct {
+	include filterlog.Matcher
+	exclude filterlog.Matcher
+	logger  *zap.Logger
+}
+
+func newFilterLogProcessor(logger *zap.Logger, cfg *Config) (*filterLogProcessor, error) {
+	inc, err := filterlog.NewMatcher(cfg.Logs.Include)
+	if err != nil {
+		return nil, err
+	}
+
+	exc, err := filterlog.NewMatcher(cfg.Logs.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(
+		"Log filter configured",
+		zap.Any("include", cfg.Logs.Include),
+		zap.Any("exclude", cfg.Logs.Exclude),
+	)
+
+	return &filterLogProcessor{
+		include: inc,
+		exclude: exc,
+		logger:  logger,
+	}, nil
+}
+
+// ProcessLogs filters the given log records based off the filterLogProcessor's filters.
+// Instrumentation libraries and resources left without log records are removed.
+func (flp *filterLogProcessor) ProcessLogs(_ context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	rls := ld.ResourceLogs()
+	keptRls := make([]pdata.ResourceLogs, 0, rls.Len())
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		ills := rl.InstrumentationLibraryLogs()
+		keptIlls := make([]pdata.InstrumentationLibraryLogs, 0, ills.Len())
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			logs := ill.Logs()
+			keptLogs := make([]pdata.LogRecord, 0, logs.Len())
+			for k := 0; k < logs.Len(); k++ {
+				lr := logs.At(k)
+				if !flp.skipLogRecord(lr, rl.Resource(), ill.InstrumentationLibrary()) {
+					keptLogs = append(keptLogs, lr)
+				}
+			}
+			if len(keptLogs) == 0 {
+				continue
+			}
+			if len(keptLogs) < logs.Len() {
+				logs.Resize(0)
+				for _, lr := range keptLogs {
+					logs.Append(lr)
+				}
+			}
+			keptIlls = append(keptIlls, ill)
+		}
+		if len(keptIlls) == 0 {
+			continue
+		}
+		if len(keptIlls) < ills.Len() {
+			ills.Resize(0)
+			for _, ill := range keptIlls {
+				ills.Append(ill)
+			}
+		}
+		keptRls = append(keptRls, rl)
+	}
+
+	if len(keptRls) == 0 {
+		return ld, processorhelper.ErrSkipProcessingData
+	}
+	if len(keptRls) < rls.Len() {
+		rls.Resize(0)
+		for _, rl := range keptRls {
+			rls.Append(rl)
+		}
+	}
+	return ld, nil
+}
+
+// skipLogRecord returns true if the log record must be dropped, include
+// properties are checked before exclude properties.
+func (flp *filterLogProcessor) skipLogRecord(lr pdata.LogRecord, resource pdata.Resource, library pdata.InstrumentationLibrary) bool {
+	if flp.include != nil && !flp.include.MatchLogRecord(lr, resource, library) {
+		return true
+	}
+	if flp.exclude != nil && flp.exclude.MatchLogRecord(lr, resource, library) {
+		return true
+	}
+	return false
+}
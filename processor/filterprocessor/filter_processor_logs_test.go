@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filterprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/filterconfig"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// logWithResource describes the log records of a resource by their name.
+type logWithResource struct {
+	service  string
+	logNames []string
+}
+
+func logsWithNames(resources []logWithResource) pdata.Logs {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(len(resources))
+	for i, r := range resources {
+		rl := ld.ResourceLogs().At(i)
+		rl.Resource().Attributes().InsertString(conventions.AttributeServiceName, r.service)
+		rl.InstrumentationLibraryLogs().Resize(1)
+		logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+		logs.Resize(len(r.logNames))
+		for j, name := range r.logNames {
+			logs.At(j).SetName(name)
+		}
+	}
+	return ld
+}
+
+func logNames(ld pdata.Logs) [][]string {
+	var names [][]string
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		var resourceNames []string
+		ills := rls.At(i).InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			logs := ills.At(j).Logs()
+			for k := 0; k < logs.Len(); k++ {
+				resourceNames = append(resourceNames, logs.At(k).Name())
+			}
+		}
+		names = append(names, resourceNames)
+	}
+	return names
+}
+
+func TestFilterLogProcessor(t *testing.T) {
+	input := []logWithResource{
+		{service: "checkout", logNames: []string{"debug.cache", "order", "debug.db"}},
+		{service: "cart", logNames: []string{"debug.cache", "add"}},
+		{service: "noisy", logNames: []string{"debug.loop"}},
+	}
+
+	tests := []struct {
+		name   string
+		inc    *filterconfig.MatchProperties
+		exc    *filterconfig.MatchProperties
+		outLog [][]string
+	}{
+		{
+			name: "excludeDebug",
+			exc: &filterconfig.MatchProperties{
+				Config:   filterset.Config{MatchType: filterset.Regexp},
+				LogNames: []string{"debug.*"},
+			},
+			outLog: [][]string{{"order"}, {"add"}},
+		},
+		{
+			name: "excludeNoisyService",
+			exc: &filterconfig.MatchProperties{
+				Config:    filterset.Config{MatchType: filterset.Strict},
+				Resources: []filterconfig.Attribute{{Key: conventions.AttributeServiceName, Value: "noisy"}},
+			},
+			outLog: [][]string{{"debug.cache", "order", "debug.db"}, {"debug.cache", "add"}},
+		},
+		{
+			name: "includeExclude",
+			inc: &filterconfig.MatchProperties{
+				Config:    filterset.Config{MatchType: filterset.Strict},
+				Resources: []filterconfig.Attribute{{Key: conventions.AttributeServiceName, Value: "checkout"}},
+			},
+			exc: &filterconfig.MatchProperties{
+				Config:   filterset.Config{MatchType: filterset.Regexp},
+				LogNames: []string{"debug.*"},
+			},
+			outLog: [][]string{{"order"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			next := new(consumertest.LogsSink)
+			cfg := createDefaultConfig().(*Config)
+			cfg.Logs = LogFilters{Include: test.inc, Exclude: test.exc}
+			lp, err := createLogsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, next)
+			require.NoError(t, err)
+
+			require.NoError(t, lp.ConsumeLogs(context.Background(), logsWithNames(input)))
+			require.Len(t, next.AllLogs(), 1)
+			assert.Equal(t, test.outLog, logNames(next.AllLogs()[0]))
+		})
+	}
+}
+
+func TestFilterLogProcessor_AllFiltered(t *testing.T) {
+	next := new(consumertest.LogsSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.Logs.Include = &filterconfig.MatchProperties{
+		Config:   filterset.Config{MatchType: filterset.Strict},
+		LogNames: []string{"order"},
+	}
+	lp, err := createLogsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, next)
+	require.NoError(t, err)
+
+	ld := logsWithNames([]logWithResource{{service: "cart", logNames: []string{"debug.cache"}}})
+	require.NoError(t, lp.ConsumeLogs(context.Background(), ld))
+	assert.Empty(t, next.AllLogs())
+}
@@ -0,0 +1,109 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filterprocessor
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/filterspan"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+type filterSpanProcessor struct {
+	include filterspan.Matcher
+	exclude filterspan.Matcher
+	logger  *zap.Logger
+}
+
+func newFilterSpanProcessor(logger *zap.Logger, cfg *Config) (*filterSpanProcessor, error) {
+	inc, err := filterspan.NewMatcher(cfg.Spans.Include)
+	if err != nil {
+		return nil, err
+	}
+
+	exc, err := filterspan.NewMatcher(cfg.Spans.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(
+		"Span filter configured",
+		zap.Any("include", cfg.Spans.Include),
+		zap.Any("exclude", cfg.Spans.Exclude),
+	)
+
+	return &filterSpanProcessor{
+		include: inc,
+		exclude: exc,
+		logger:  logger,
+	}, nil
+}
+
+// ProcessTraces filters the given spans based off the filterSpanProcessor's filters.
+// Instrumentation libraries and resources left without spans are removed.
+func (fsp *filterSpanProcessor) ProcessTraces(_ context.Context, td pdata.Traces) (pdata.Traces, error) {
+	rss := td.ResourceSpans()
+	keptRss := make([]pdata.ResourceSpans, 0, rss.Len())
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		ilss := rs.InstrumentationLibrarySpans()
+		keptIlss := make([]pdata.InstrumentationLibrarySpans, 0, ilss.Len())
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			spans := ils.Spans()
+			keptSpans := make([]pdata.Span, 0, spans.Len())
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if !filterspan.SkipSpan(fsp.include, fsp.exclude, span, rs.Resource(), ils.InstrumentationLibrary()) {
+					keptSpans = append(keptSpans, span)
+				}
+			}
+			if len(keptSpans) == 0 {
+				continue
+			}
+			if len(keptSpans) < spans.Len() {
+				spans.Resize(0)
+				for _, span := range keptSpans {
+					spans.Append(span)
+				}
+			}
+			keptIlss = append(keptIlss, ils)
+		}
+		if len(keptIlss) == 0 {
+			continue
+		}
+		if len(keptIlss) < ilss.Len() {
+			ilss.Resize(0)
+			for _, ils := range keptIlss {
+				ilss.Append(ils)
+			}
+		}
+		keptRss = append(keptRss, rs)
+	}
+
+	if len(keptRss) == 0 {
+		return td, processorhelper.ErrSkipProcessingData
+	}
+	if len(keptRss) < rss.Len() {
+		rss.Resize(0)
+		for _, rs := range keptRss {
+			rss.Append(rs)
+		}
+	}
+	return td, nil
+}
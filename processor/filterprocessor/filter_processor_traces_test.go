@@ -0,0 +1,162 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filterprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/filterconfig"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// spanWithResource describes the spans of a resource by their name, the spans
+// named "health" have the http.target attribute set to "/health".
+type spanWithResource struct {
+	service   string
+	spanNames []string
+}
+
+func tracesWithNames(resources []spanWithResource) pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(len(resources))
+	for i, r := range resources {
+		rs := td.ResourceSpans().At(i)
+		rs.Resource().Attributes().InsertString(conventions.AttributeServiceName, r.service)
+		rs.InstrumentationLibrarySpans().Resize(1)
+		spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+		spans.Resize(len(r.spanNames))
+		for j, name := range r.spanNames {
+			spans.At(j).SetName(name)
+			if name == "health" {
+				spans.At(j).Attributes().InsertString("http.target", "/health")
+			}
+		}
+	}
+	return td
+}
+
+func spanNames(td pdata.Traces) [][]string {
+	var names [][]string
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		var resourceNames []string
+		ilss := rss.At(i).InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				resourceNames = append(resourceNames, spans.At(k).Name())
+			}
+		}
+		names = append(names, resourceNames)
+	}
+	return names
+}
+
+func TestFilterSpanProcessor(t *testing.T) {
+	input := []spanWithResource{
+		{service: "checkout", spanNames: []string{"health", "pay", "ping"}},
+		{service: "cart", spanNames: []string{"health", "add"}},
+		{service: "checkout-worker", spanNames: []string{"health"}},
+	}
+
+	tests := []struct {
+		name    string
+		inc     *filterconfig.MatchProperties
+		exc     *filterconfig.MatchProperties
+		outSpan [][]string
+	}{
+		{
+			name: "excludeHealthChecks",
+			exc: &filterconfig.MatchProperties{
+				Config:     filterset.Config{MatchType: filterset.Strict},
+				Attributes: []filterconfig.Attribute{{Key: "http.target", Value: "/health"}},
+			},
+			outSpan: [][]string{{"pay", "ping"}, {"add"}},
+		},
+		{
+			name: "includeServices",
+			inc: &filterconfig.MatchProperties{
+				Config:   filterset.Config{MatchType: filterset.Regexp},
+				Services: []string{"checkout.*"},
+			},
+			outSpan: [][]string{{"health", "pay", "ping"}, {"health"}},
+		},
+		{
+			name: "includeExclude",
+			inc: &filterconfig.MatchProperties{
+				Config:   filterset.Config{MatchType: filterset.Strict},
+				Services: []string{"checkout"},
+			},
+			exc: &filterconfig.MatchProperties{
+				Config:    filterset.Config{MatchType: filterset.Regexp},
+				SpanNames: []string{"health|ping"},
+			},
+			outSpan: [][]string{{"pay"}},
+		},
+		{
+			name: "excludeExpr",
+			exc: &filterconfig.MatchProperties{
+				Config:      filterset.Config{MatchType: filterconfig.Expr},
+				Expressions: []string{`Attribute("http.target") == "/health" || ResourceAttribute("service.name") == "cart"`},
+			},
+			outSpan: [][]string{{"pay", "ping"}},
+		},
+		{
+			name: "noFilters",
+			outSpan: [][]string{
+				{"health", "pay", "ping"}, {"health", "add"}, {"health"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			next := new(consumertest.TracesSink)
+			cfg := createDefaultConfig().(*Config)
+			cfg.Spans = SpanFilters{Include: test.inc, Exclude: test.exc}
+			tp, err := createTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, next)
+			require.NoError(t, err)
+			assert.True(t, tp.GetCapabilities().MutatesConsumedData)
+
+			require.NoError(t, tp.ConsumeTraces(context.Background(), tracesWithNames(input)))
+			require.Len(t, next.AllTraces(), 1)
+			assert.Equal(t, test.outSpan, spanNames(next.AllTraces()[0]))
+		})
+	}
+}
+
+func TestFilterSpanProcessor_AllFiltered(t *testing.T) {
+	next := new(consumertest.TracesSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.Spans.Exclude = &filterconfig.MatchProperties{
+		Config:    filterset.Config{MatchType: filterset.Strict},
+		SpanNames: []string{"health"},
+	}
+	tp, err := createTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, next)
+	require.NoError(t, err)
+
+	td := tracesWithNames([]spanWithResource{{service: "cart", spanNames: []string{"health", "health"}}})
+	require.NoError(t, tp.ConsumeTraces(context.Background(), td))
+	assert.Empty(t, next.AllTraces())
+}
@@ -0,0 +1,34 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbytraceprocessor
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config is the configuration for the Group by trace processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// WaitDuration is how long the spans of a trace are held, from the arrival
+	// of its first span, before the trace is released.
+	WaitDuration time.Duration `mapstructure:"wait_duration"`
+
+	// NumTraces is the maximum number of traces held in memory. When a new
+	// trace arrives while the limit is reached, the oldest trace is evicted.
+	NumTraces int `mapstructure:"num_traces"`
+}
@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbytraceprocessor
+
+import (
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/processor"
+)
+
+var (
+	statTracesEvicted       = stats.Int64("traces_evicted", "Number of traces evicted from memory before being released", stats.UnitDimensionless)
+	statTraceReleaseLatency = stats.Int64("trace_release_latency", "Time the traces were held in memory before being released", stats.UnitMilliseconds)
+	statNumTracesInMemory   = stats.Int64("num_traces_in_memory", "Number of traces held in memory", stats.UnitDimensionless)
+)
+
+// MetricViews returns the metrics views related to the grouping of traces.
+func MetricViews() []*view.View {
+	processorTagKeys := []tag.Key{processor.TagProcessorNameKey}
+
+	countTracesEvictedView := &view.View{
+		Name:        statTracesEvicted.Name(),
+		Measure:     statTracesEvicted,
+		Description: statTracesEvicted.Description(),
+		TagKeys:     processorTagKeys,
+		Aggregation: view.Sum(),
+	}
+
+	distributionTraceReleaseLatencyView := &view.View{
+		Name:        statTraceReleaseLatency.Name(),
+		Measure:     statTraceReleaseLatency,
+		Description: statTraceReleaseLatency.Description(),
+		TagKeys:     processorTagKeys,
+		Aggregation: view.Distribution(10, 25, 50, 75, 100, 250, 500, 750, 1000, 2000, 3000, 4000, 5000, 10000, 20000, 30000, 60000),
+	}
+
+	lastValueNumTracesInMemoryView := &view.View{
+		Name:        statNumTracesInMemory.Name(),
+		Measure:     statNumTracesInMemory,
+		Description: statNumTracesInMemory.Description(),
+		TagKeys:     processorTagKeys,
+		Aggregation: view.LastValue(),
+	}
+
+	legacyViews := []*view.View{
+		countTracesEvictedView,
+		distributionTraceReleaseLatencyView,
+		lastValueNumTracesInMemoryView,
+	}
+
+	return obsreport.ProcessorMetricViews(typeStr, legacyViews)
+}
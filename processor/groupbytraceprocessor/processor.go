@@ -0,0 +1,277 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbytraceprocessor
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/batchpersignal"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/processor"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+// groupByTraceProcessor holds the spans of each trace for the wait duration,
+// and then releases all the spans received for the trace as a single batch.
+//
+// The traces are held in memory up to the maximum number of traces, after
+// which the oldest trace is evicted, without being released, to make room
+// for a new one.
+type groupByTraceProcessor struct {
+	name         string
+	logger       *zap.Logger
+	nextConsumer consumer.TracesConsumer
+	waitDuration time.Duration
+	numTraces    int
+	obsrep       *obsreport.ProcessorObsReport
+	statsTags    []tag.Mutator
+
+	lock sync.Mutex
+	// traces are the traces held in memory, also in order of arrival in
+	// order to evict the oldest one.
+	traces   map[pdata.TraceID]*traceEntry
+	order    *list.List
+	shutdown bool
+	// releases tracks the traces being released.
+	releases sync.WaitGroup
+}
+
+// traceEntry holds the spans received for a trace, grouped by resource and
+// instrumentation library.
+type traceEntry struct {
+	traceID   pdata.TraceID
+	received  time.Time
+	timer     *time.Timer
+	elem      *list.Element
+	spanCount int
+
+	traces    pdata.Traces
+	resources map[string]pdata.ResourceSpans
+	libraries map[string]pdata.InstrumentationLibrarySpans
+}
+
+var _ component.TracesProcessor = (*groupByTraceProcessor)(nil)
+
+func newGroupByTraceProcessor(logger *zap.Logger, nextConsumer consumer.TracesConsumer, cfg *Config, level configtelemetry.Level) (*groupByTraceProcessor, error) {
+	if cfg.WaitDuration <= 0 {
+		return nil, fmt.Errorf("wait_duration must be positive, got %v", cfg.WaitDuration)
+	}
+	if cfg.NumTraces <= 0 {
+		return nil, fmt.Errorf("num_traces must be positive, got %d", cfg.NumTraces)
+	}
+
+	return &groupByTraceProcessor{
+		name:         cfg.Name(),
+		logger:       logger,
+		nextConsumer: nextConsumer,
+		waitDuration: cfg.WaitDuration,
+		numTraces:    cfg.NumTraces,
+		obsrep:       obsreport.NewProcessorObsReport(level, cfg.Name()),
+		statsTags:    []tag.Mutator{tag.Insert(processor.TagProcessorNameKey, cfg.Name())},
+		traces:       make(map[pdata.TraceID]*traceEntry),
+		order:        list.New(),
+	}, nil
+}
+
+func (p *groupByTraceProcessor) GetCapabilities() component.ProcessorCapabilities {
+	// The incoming batches are copied when split by trace.
+	return component.ProcessorCapabilities{MutatesConsumedData: false}
+}
+
+// Start is invoked during service startup.
+func (p *groupByTraceProcessor) Start(context.Context, component.Host) error {
+	return nil
+}
+
+// Shutdown releases the traces held in memory, without waiting for the end of
+// their wait duration.
+func (p *groupByTraceProcessor) Shutdown(context.Context) error {
+	p.lock.Lock()
+	p.shutdown = true
+	var entries []*traceEntry
+	for elem := p.order.Front(); elem != nil; elem = elem.Next() {
+		entry := elem.Value.(*traceEntry)
+		entry.timer.Stop()
+		entries = append(entries, entry)
+	}
+	p.traces = make(map[pdata.TraceID]*traceEntry)
+	p.order.Init()
+	p.lock.Unlock()
+
+	for _, entry := range entries {
+		p.forward(entry)
+	}
+	p.releases.Wait()
+	return nil
+}
+
+// ConsumeTraces adds the spans to the traces held in memory.
+func (p *groupByTraceProcessor) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	traces := batchpersignal.SplitTraces(td)
+
+	p.lock.Lock()
+	if p.shutdown {
+		p.lock.Unlock()
+		// The traces can't be held anymore, they are forwarded as is.
+		return p.nextConsumer.ConsumeTraces(ctx, td)
+	}
+	var evicted []*traceEntry
+	for _, trace := range traces {
+		traceID := trace.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).TraceID()
+		entry, ok := p.traces[traceID]
+		if !ok {
+			if len(p.traces) >= p.numTraces {
+				evicted = append(evicted, p.evictOldest())
+			}
+			entry = p.newTraceEntry(traceID)
+		}
+		entry.add(trace)
+	}
+	numTraces := len(p.traces)
+	p.lock.Unlock()
+
+	for _, entry := range evicted {
+		p.logger.Debug("Trace evicted before being released", zap.String("trace_id", entry.traceID.HexString()))
+		p.obsrep.TracesDropped(ctx, entry.spanCount)
+	}
+	if len(evicted) > 0 {
+		_ = stats.RecordWithTags(ctx, p.statsTags, statTracesEvicted.M(int64(len(evicted))))
+	}
+	_ = stats.RecordWithTags(ctx, p.statsTags, statNumTracesInMemory.M(int64(numTraces)))
+	return nil
+}
+
+// newTraceEntry adds a new trace, released after the wait duration. Must be
+// called with the lock held.
+func (p *groupByTraceProcessor) newTraceEntry(traceID pdata.TraceID) *traceEntry {
+	entry := &traceEntry{
+		traceID:   traceID,
+		received:  time.Now(),
+		traces:    pdata.NewTraces(),
+		resources: make(map[string]pdata.ResourceSpans),
+		libraries: make(map[string]pdata.InstrumentationLibrarySpans),
+	}
+	entry.elem = p.order.PushBack(entry)
+	p.traces[traceID] = entry
+	entry.timer = time.AfterFunc(p.waitDuration, func() {
+		p.release(entry)
+	})
+	return entry
+}
+
+// evictOldest removes the oldest trace. Must be called with the lock held.
+func (p *groupByTraceProcessor) evictOldest() *traceEntry {
+	entry := p.order.Front().Value.(*traceEntry)
+	entry.timer.Stop()
+	p.remove(entry)
+	return entry
+}
+
+// remove removes the trace from memory. Must be called with the lock held.
+func (p *groupByTraceProcessor) remove(entry *traceEntry) {
+	delete(p.traces, entry.traceID)
+	p.order.Remove(entry.elem)
+}
+
+// release sends the trace to the next consumer once its wait duration expired.
+func (p *groupByTraceProcessor) release(entry *traceEntry) {
+	p.lock.Lock()
+	if p.traces[entry.traceID] != entry {
+		// The trace was evicted, or released on shutdown.
+		p.lock.Unlock()
+		return
+	}
+	p.remove(entry)
+	p.releases.Add(1)
+	p.lock.Unlock()
+
+	defer p.releases.Done()
+	p.forward(entry)
+}
+
+func (p *groupByTraceProcessor) forward(entry *traceEntry) {
+	ctx := context.Background()
+	latency := time.Since(entry.received)
+	_ = stats.RecordWithTags(ctx, p.statsTags, statTraceReleaseLatency.M(latency.Milliseconds()))
+
+	if err := p.nextConsumer.ConsumeTraces(ctx, entry.traces); err != nil {
+		p.logger.Error("Failed to send the released trace",
+			zap.String("trace_id", entry.traceID.HexString()),
+			zap.Int("spans", entry.spanCount),
+			zap.Error(err))
+	}
+}
+
+// add adds the spans of the trace, merging them into the resources and
+// instrumentation libraries already received.
+func (e *traceEntry) add(trace pdata.Traces) {
+	rss := trace.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		resKey := resourceKey(rs.Resource())
+		destRs, ok := e.resources[resKey]
+		if !ok {
+			destRss := e.traces.ResourceSpans()
+			destRss.Resize(destRss.Len() + 1)
+			destRs = destRss.At(destRss.Len() - 1)
+			rs.Resource().CopyTo(destRs.Resource())
+			e.resources[resKey] = destRs
+		}
+
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			e.spanCount += ils.Spans().Len()
+			library := ils.InstrumentationLibrary()
+			libKey := resKey + "\x01" + library.Name() + "\x00" + library.Version()
+			destIls, ok := e.libraries[libKey]
+			if !ok {
+				// The spans of the trace were copied when split, they can be
+				// moved as is.
+				destRs.InstrumentationLibrarySpans().Append(ils)
+				e.libraries[libKey] = ils
+				continue
+			}
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				destIls.Spans().Append(spans.At(k))
+			}
+		}
+	}
+}
+
+// resourceKey identifies the resource by its attributes.
+func resourceKey(resource pdata.Resource) string {
+	var kvs []string
+	resource.Attributes().ForEach(func(k string, v pdata.AttributeValue) {
+		kvs = append(kvs, k+"="+tracetranslator.AttributeValueToString(v, true))
+	})
+	sort.Strings(kvs)
+	return strings.Join(kvs, "\x00")
+}
@@ -0,0 +1,189 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbytraceprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+type testSpan struct {
+	service string
+	library string
+	traceID byte
+	name    string
+}
+
+func newTraces(spans ...testSpan) pdata.Traces {
+	td := pdata.NewTraces()
+	for _, s := range spans {
+		td.ResourceSpans().Resize(td.ResourceSpans().Len() + 1)
+		rs := td.ResourceSpans().At(td.ResourceSpans().Len() - 1)
+		rs.Resource().Attributes().InsertString("service.name", s.service)
+		rs.InstrumentationLibrarySpans().Resize(1)
+		ils := rs.InstrumentationLibrarySpans().At(0)
+		ils.InstrumentationLibrary().SetName(s.library)
+		ils.Spans().Resize(1)
+		ils.Spans().At(0).SetTraceID(pdata.NewTraceID([16]byte{s.traceID}))
+		ils.Spans().At(0).SetName(s.name)
+	}
+	return td
+}
+
+func newTestProcessor(t *testing.T, waitDuration time.Duration, numTraces int) (*groupByTraceProcessor, *consumertest.TracesSink) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.WaitDuration = waitDuration
+	cfg.NumTraces = numTraces
+	sink := &consumertest.TracesSink{}
+	p, err := newGroupByTraceProcessor(zap.NewNop(), sink, cfg, configtelemetry.LevelNone)
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	return p, sink
+}
+
+// spanNames returns the names of the spans grouped by resource, then by
+// instrumentation library.
+func spanNames(td pdata.Traces) map[string]map[string][]string {
+	result := make(map[string]map[string][]string)
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		service, _ := rss.At(i).Resource().Attributes().Get("service.name")
+		libraries := make(map[string][]string)
+		ilss := rss.At(i).InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				name := ilss.At(j).InstrumentationLibrary().Name()
+				libraries[name] = append(libraries[name], spans.At(k).Name())
+			}
+		}
+		result[service.StringVal()] = libraries
+	}
+	return result
+}
+
+// tracesByID indexes the released traces by the first byte of their trace ID.
+func tracesByID(traces []pdata.Traces) map[byte]pdata.Traces {
+	result := make(map[byte]pdata.Traces)
+	for _, td := range traces {
+		traceID := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).TraceID().Bytes()
+		result[traceID[0]] = td
+	}
+	return result
+}
+
+func TestReleaseTraceAfterWaitDuration(t *testing.T) {
+	p, sink := newTestProcessor(t, 100*time.Millisecond, 100)
+
+	require.NoError(t, p.ConsumeTraces(context.Background(), newTraces(
+		testSpan{service: "frontend", library: "http", traceID: 1, name: "GET /"},
+		testSpan{service: "backend", library: "grpc", traceID: 2, name: "Query"},
+	)))
+	require.NoError(t, p.ConsumeTraces(context.Background(), newTraces(
+		testSpan{service: "backend", library: "grpc", traceID: 1, name: "Get"},
+		testSpan{service: "frontend", library: "http", traceID: 1, name: "GET /favicon"},
+		testSpan{service: "frontend", library: "db", traceID: 1, name: "SELECT"},
+	)))
+	assert.Equal(t, 0, sink.SpansCount(), "the traces are held for the wait duration")
+
+	require.Eventually(t, func() bool {
+		return len(sink.AllTraces()) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	traces := tracesByID(sink.AllTraces())
+	// Each trace is released as a single batch.
+	assert.Equal(t, map[string]map[string][]string{
+		"frontend": {"http": {"GET /", "GET /favicon"}, "db": {"SELECT"}},
+		"backend":  {"grpc": {"Get"}},
+	}, spanNames(traces[1]))
+	assert.Equal(t, 2, traces[1].ResourceSpans().Len())
+	assert.Equal(t, map[string]map[string][]string{
+		"backend": {"grpc": {"Query"}},
+	}, spanNames(traces[2]))
+	assert.Empty(t, p.traces)
+
+	require.NoError(t, p.Shutdown(context.Background()))
+}
+
+func TestEvictOldestTrace(t *testing.T) {
+	p, sink := newTestProcessor(t, 100*time.Millisecond, 2)
+
+	for _, id := range []byte{1, 2, 3} {
+		require.NoError(t, p.ConsumeTraces(context.Background(), newTraces(
+			testSpan{service: "frontend", library: "http", traceID: id, name: "GET /"},
+		)))
+	}
+	// Adding spans to a held trace doesn't evict anything.
+	require.NoError(t, p.ConsumeTraces(context.Background(), newTraces(
+		testSpan{service: "frontend", library: "http", traceID: 2, name: "GET /favicon"},
+	)))
+
+	require.Eventually(t, func() bool {
+		return len(sink.AllTraces()) == 2
+	}, time.Second, 10*time.Millisecond)
+	// Wait for the evicted trace, which is never released.
+	time.Sleep(150 * time.Millisecond)
+
+	traces := tracesByID(sink.AllTraces())
+	require.Len(t, traces, 2)
+	assert.Equal(t, 2, traces[2].SpanCount())
+	assert.Equal(t, 1, traces[3].SpanCount())
+
+	require.NoError(t, p.Shutdown(context.Background()))
+}
+
+func TestShutdownReleasesTraces(t *testing.T) {
+	p, sink := newTestProcessor(t, time.Hour, 100)
+
+	require.NoError(t, p.ConsumeTraces(context.Background(), newTraces(
+		testSpan{service: "frontend", library: "http", traceID: 1, name: "GET /"},
+		testSpan{service: "frontend", library: "http", traceID: 2, name: "GET /"},
+	)))
+	require.NoError(t, p.Shutdown(context.Background()))
+	assert.Len(t, sink.AllTraces(), 2)
+
+	// The traces received after shutdown are forwarded as is.
+	require.NoError(t, p.ConsumeTraces(context.Background(), newTraces(
+		testSpan{service: "frontend", library: "http", traceID: 3, name: "GET /"},
+	)))
+	assert.Len(t, sink.AllTraces(), 3)
+}
+
+func TestInputNotMutated(t *testing.T) {
+	p, sink := newTestProcessor(t, 10*time.Millisecond, 100)
+
+	td := newTraces(
+		testSpan{service: "frontend", library: "http", traceID: 1, name: "GET /"},
+		testSpan{service: "frontend", library: "http", traceID: 1, name: "GET /favicon"},
+	)
+	want := td.Clone()
+	require.NoError(t, p.ConsumeTraces(context.Background(), td))
+	require.Eventually(t, func() bool {
+		return sink.SpansCount() == 2
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, want, td)
+	require.NoError(t, p.Shutdown(context.Background()))
+}
@@ -120,3 +120,31 @@ func (cg CGroups) MemoryQuota() (int64, bool, error) {
 	}
 	return int64(memLimitBytes), true, nil
 }
+
+// MemoryQuotaForCurrentProcess returns the total memory limit of the current
+// process, see memoryQuota.
+func MemoryQuotaForCurrentProcess() (int64, bool, error) {
+	return memoryQuota(_procPathMountInfo, _procPathCGroup)
+}
+
+// memoryQuota returns the total memory limit of the process with the given
+// `mountinfo` and `cgroup` files. The limit is read from the CGroup v1 memory
+// controller when it is mounted, as on CGroup v1 hosts and on hybrid hosts
+// which mount the unified hierarchy next to the v1 controllers, and from the
+// CGroup v2 unified hierarchy otherwise. If no limit is set, the function
+// returns `(-1, false, nil)`.
+func memoryQuota(procPathMountInfo, procPathCGroup string) (int64, bool, error) {
+	cgroups, err := NewCGroups(procPathMountInfo, procPathCGroup)
+	if err != nil {
+		return -1, false, err
+	}
+	if _, exists := cgroups[_cgroupSubsysMemory]; exists {
+		return cgroups.MemoryQuota()
+	}
+
+	cgroups2, err := NewCGroups2(procPathMountInfo, procPathCGroup)
+	if err != nil || cgroups2 == nil {
+		return -1, false, err
+	}
+	return cgroups2.MemoryQuota()
+}
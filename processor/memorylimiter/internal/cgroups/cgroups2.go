@@ -0,0 +1,133 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package cgroups
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+const (
+	// _cgroup2FSType is the Linux CGroup v2 file system type used in
+	// `/proc/$PID/mountinfo`.
+	_cgroup2FSType = "cgroup2"
+	// _cgroup2SubsysName is the name of the unified hierarchy entry in
+	// `/proc/$PID/cgroup`, which has the hierarchy ID 0 and no subsystems.
+	_cgroup2SubsysName = ""
+
+	_cgroup2MemoryMax          = "memory.max"
+	_cgroup2MemoryMaxUnlimited = "max"
+)
+
+// CGroups2 represents the CGroup of a process in the CGroup v2 unified
+// hierarchy.
+type CGroups2 struct {
+	mountPoint string
+	groupPath  string
+}
+
+// NewCGroups2 returns a new *CGroups2 from given `mountinfo` and `cgroup`
+// files for some process under `/proc` file system. It returns nil if the
+// unified hierarchy isn't mounted or the process doesn't belong to it, like on
+// hosts only using CGroup v1.
+func NewCGroups2(procPathMountInfo, procPathCGroup string) (*CGroups2, error) {
+	cgroupSubsystems, err := parseCGroupSubsystems(procPathCGroup)
+	if err != nil {
+		return nil, err
+	}
+	subsys, exists := cgroupSubsystems[_cgroup2SubsysName]
+	if !exists || subsys.ID != 0 {
+		return nil, nil
+	}
+
+	var cgroups2 *CGroups2
+	newMountPoint := func(mp *MountPoint) error {
+		if mp.FSType != _cgroup2FSType || cgroups2 != nil {
+			return nil
+		}
+
+		groupPath, err := mp.Translate(subsys.Name)
+		if err != nil {
+			return err
+		}
+		cgroups2 = &CGroups2{mountPoint: mp.MountPoint, groupPath: groupPath}
+		return nil
+	}
+
+	if err := parseMountInfo(procPathMountInfo, newMountPoint); err != nil {
+		return nil, err
+	}
+	return cgroups2, nil
+}
+
+// NewCGroups2ForCurrentProcess returns a new *CGroups2 instance for the
+// current process, or nil if it doesn't belong to a CGroup v2 unified
+// hierarchy.
+func NewCGroups2ForCurrentProcess() (*CGroups2, error) {
+	return NewCGroups2(_procPathMountInfo, _procPathCGroup)
+}
+
+// MountPoint returns the path the unified hierarchy is mounted at.
+func (cg *CGroups2) MountPoint() string {
+	return cg.mountPoint
+}
+
+// Path returns the path of the CGroup of the process.
+func (cg *CGroups2) Path() string {
+	return cg.groupPath
+}
+
+// MemoryQuota returns the total memory limit of the process. It is the lowest
+// `memory.max` of the CGroup and its ancestors up to the mount point, as a
+// limit set on a parent, e.g. a systemd slice, applies to all its children.
+// If none of them sets a limit (`max`), the method returns `(-1, false, nil)`.
+func (cg *CGroups2) MemoryQuota() (int64, bool, error) {
+	quota, defined := int64(-1), false
+	for path := cg.groupPath; ; path = filepath.Dir(path) {
+		limit, limited, err := readMemoryMax(NewCGroup(path))
+		if err != nil {
+			return -1, false, err
+		}
+		if limited && (!defined || limit < quota) {
+			quota, defined = limit, true
+		}
+		if path == cg.mountPoint || path == filepath.Dir(path) {
+			return quota, defined, nil
+		}
+	}
+}
+
+// readMemoryMax reads the `memory.max` of a CGroup, which is absent in the
+// root CGroup and when the memory controller isn't enabled for the CGroup.
+func readMemoryMax(cgroup *CGroup) (int64, bool, error) {
+	text, err := cgroup.readFirstLine(_cgroup2MemoryMax)
+	if os.IsNotExist(err) {
+		return -1, false, nil
+	}
+	if err != nil {
+		return -1, false, err
+	}
+	if text == _cgroup2MemoryMaxUnlimited {
+		return -1, false, nil
+	}
+	limit, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return -1, false, err
+	}
+	return limit, limit > 0, nil
+}
@@ -0,0 +1,159 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package cgroups
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCGroups2(t *testing.T) {
+	testTable := []struct {
+		name       string
+		mountPoint string
+		path       string
+	}{
+		{"cgroups2", "/sys/fs/cgroup", "/sys/fs/cgroup/system.slice/otelcol.service"},
+		{"cgroups2-container", "/sys/fs/cgroup", "/sys/fs/cgroup"},
+		{"cgroups2-hybrid", "/sys/fs/cgroup/unified", "/sys/fs/cgroup/unified/docker/otelcol"},
+	}
+
+	for _, tt := range testTable {
+		cgroups2, err := NewCGroups2(
+			filepath.Join(testDataProcPath, tt.name, "mountinfo"),
+			filepath.Join(testDataProcPath, tt.name, "cgroup"))
+		require.NoError(t, err, tt.name)
+		require.NotNil(t, cgroups2, tt.name)
+		assert.Equal(t, tt.mountPoint, cgroups2.MountPoint(), tt.name)
+		assert.Equal(t, tt.path, cgroups2.Path(), tt.name)
+	}
+}
+
+func TestNewCGroups2WithoutUnifiedHierarchy(t *testing.T) {
+	// The process doesn't belong to the unified hierarchy.
+	cgroups2, err := NewCGroups2(
+		filepath.Join(testDataProcPath, "cgroups", "mountinfo"),
+		filepath.Join(testDataProcPath, "cgroups", "cgroup"))
+	assert.NoError(t, err)
+	assert.Nil(t, cgroups2)
+
+	// The unified hierarchy isn't mounted.
+	cgroups2, err = NewCGroups2(
+		filepath.Join(testDataProcPath, "cgroups", "mountinfo"),
+		filepath.Join(testDataProcPath, "cgroups2", "cgroup"))
+	assert.NoError(t, err)
+	assert.Nil(t, cgroups2)
+}
+
+func TestNewCGroups2WithErrors(t *testing.T) {
+	testTable := []struct {
+		mountInfoPath string
+		cgroupPath    string
+	}{
+		{"non-existing-file", filepath.Join(testDataProcPath, "cgroups2", "cgroup")},
+		{"/dev/null", "non-existing-file"},
+		{
+			"/dev/null",
+			filepath.Join(testDataProcPath, "invalid-cgroup", "cgroup"),
+		},
+		{
+			filepath.Join(testDataProcPath, "invalid-mountinfo", "mountinfo"),
+			filepath.Join(testDataProcPath, "cgroups2", "cgroup"),
+		},
+		{
+			filepath.Join(testDataProcPath, "untranslatable-cgroups2", "mountinfo"),
+			filepath.Join(testDataProcPath, "untranslatable-cgroups2", "cgroup"),
+		},
+	}
+
+	for _, tt := range testTable {
+		cgroups2, err := NewCGroups2(tt.mountInfoPath, tt.cgroupPath)
+		assert.Nil(t, cgroups2)
+		assert.Error(t, err)
+	}
+}
+
+func TestCGroups2MemoryQuota(t *testing.T) {
+	testTable := []struct {
+		name            string
+		mountPoint      string
+		path            string
+		expectedQuota   int64
+		expectedDefined bool
+		shouldHaveError bool
+	}{
+		{
+			name:            "limited",
+			mountPoint:      filepath.Join(testDataCGroupsPath, "v2-limited"),
+			path:            filepath.Join(testDataCGroupsPath, "v2-limited"),
+			expectedQuota:   536870912,
+			expectedDefined: true,
+		},
+		{
+			name:            "unlimited",
+			mountPoint:      filepath.Join(testDataCGroupsPath, "v2-unlimited"),
+			path:            filepath.Join(testDataCGroupsPath, "v2-unlimited"),
+			expectedQuota:   -1,
+			expectedDefined: false,
+		},
+		{
+			name:            "absent",
+			mountPoint:      filepath.Join(testDataCGroupsPath, "cpu"),
+			path:            filepath.Join(testDataCGroupsPath, "cpu"),
+			expectedQuota:   -1,
+			expectedDefined: false,
+		},
+		{
+			name:            "invalid",
+			mountPoint:      filepath.Join(testDataCGroupsPath, "v2-invalid"),
+			path:            filepath.Join(testDataCGroupsPath, "v2-invalid"),
+			expectedQuota:   -1,
+			expectedDefined: false,
+			shouldHaveError: true,
+		},
+		{
+			name:            "parent",
+			mountPoint:      filepath.Join(testDataSysFSPath, "cgroups2"),
+			path:            filepath.Join(testDataSysFSPath, "cgroups2", "system.slice", "otelcol.service"),
+			expectedQuota:   2147483648,
+			expectedDefined: true,
+		},
+		{
+			name:            "lowest",
+			mountPoint:      filepath.Join(testDataSysFSPath, "cgroups2-hybrid"),
+			path:            filepath.Join(testDataSysFSPath, "cgroups2-hybrid", "unified", "docker", "otelcol"),
+			expectedQuota:   134217728,
+			expectedDefined: true,
+		},
+	}
+
+	for _, tt := range testTable {
+		cgroups2 := &CGroups2{mountPoint: tt.mountPoint, groupPath: tt.path}
+		quota, defined, err := cgroups2.MemoryQuota()
+		assert.Equal(t, tt.expectedQuota, quota, tt.name)
+		assert.Equal(t, tt.expectedDefined, defined, tt.name)
+
+		if tt.shouldHaveError {
+			assert.Error(t, err, tt.name)
+		} else {
+			assert.NoError(t, err, tt.name)
+		}
+	}
+}
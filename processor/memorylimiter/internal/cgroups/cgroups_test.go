@@ -135,3 +135,52 @@ func TestCGroupsCPUQuota(t *testing.T) {
 		}
 	}
 }
+
+func TestMemoryQuota(t *testing.T) {
+	testTable := []struct {
+		name          string
+		expectedQuota int64
+	}{
+		{name: "cgroups", expectedQuota: 1073741824},
+		// The limit of the parent slice applies to the service.
+		{name: "cgroups2", expectedQuota: 2147483648},
+		{name: "cgroups2-container", expectedQuota: 536870912},
+		// The v1 memory controller is used when it is mounted.
+		{name: "cgroups2-hybrid", expectedQuota: 268435456},
+		{name: "cgroups2-hybrid-unified-memory", expectedQuota: 134217728},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			cgroupPath := filepath.Join(testDataProcPath, tt.name, "cgroup")
+			quota, defined, err := memoryQuota(mountInfoWithSysFS(t, tt.name), cgroupPath)
+			assert.NoError(t, err)
+			assert.True(t, defined)
+			assert.Equal(t, tt.expectedQuota, quota)
+		})
+	}
+}
+
+func TestMemoryQuotaWithErrors(t *testing.T) {
+	testTable := []struct {
+		mountInfoPath string
+		cgroupPath    string
+	}{
+		{"non-existing-file", "/dev/null"},
+		{
+			filepath.Join(testDataProcPath, "untranslatable", "mountinfo"),
+			filepath.Join(testDataProcPath, "untranslatable", "cgroup"),
+		},
+		{
+			filepath.Join(testDataProcPath, "untranslatable-cgroups2", "mountinfo"),
+			filepath.Join(testDataProcPath, "untranslatable-cgroups2", "cgroup"),
+		},
+	}
+
+	for _, tt := range testTable {
+		quota, defined, err := memoryQuota(tt.mountInfoPath, tt.cgroupPath)
+		assert.Equal(t, int64(-1), quota)
+		assert.False(t, defined)
+		assert.Error(t, err)
+	}
+}
@@ -35,6 +35,7 @@
 // THE SOFTWARE.
 
 // Package cgroups provides utilities to access Linux control group (CGroups)
-// parameters (total memory, for example) for a given process.
+// parameters (total memory, for example) for a given process, in CGroup v1,
+// CGroup v2 unified and hybrid hierarchies.
 // The original implementation is taken from https://github.com/uber-go/automaxprocs
 package cgroups
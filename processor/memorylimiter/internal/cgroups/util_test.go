@@ -39,8 +39,13 @@
 package cgroups
 
 import (
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -48,6 +53,7 @@ var (
 	testDataPath        = filepath.Join(pwd, "testdata")
 	testDataCGroupsPath = filepath.Join(testDataPath, "cgroups")
 	testDataProcPath    = filepath.Join(testDataPath, "proc")
+	testDataSysFSPath   = filepath.Join(testDataPath, "sysfs")
 )
 
 func mustGetWd() string {
@@ -57,3 +63,17 @@ func mustGetWd() string {
 	}
 	return pwd
 }
+
+// mountInfoWithSysFS returns the path of a copy of the `mountinfo` file of the
+// given testdata/proc directory, with the mount points under /sys/fs/cgroup
+// moved to the matching testdata/sysfs directory.
+func mountInfoWithSysFS(t *testing.T, name string) string {
+	mountInfo, err := ioutil.ReadFile(filepath.Join(testDataProcPath, name, "mountinfo"))
+	require.NoError(t, err)
+	sysFS := filepath.Join(testDataSysFSPath, name)
+	mountInfo = []byte(strings.ReplaceAll(string(mountInfo), " /sys/fs/cgroup", " "+sysFS))
+
+	path := filepath.Join(t.TempDir(), "mountinfo")
+	require.NoError(t, ioutil.WriteFile(path, mountInfo, 0600))
+	return path
+}
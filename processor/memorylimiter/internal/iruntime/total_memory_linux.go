@@ -19,13 +19,9 @@ package iruntime
 import "go.opentelemetry.io/collector/processor/memorylimiter/internal/cgroups"
 
 // TotalMemory returns total available memory.
-// This implementation is meant for linux and uses cgroups to determine available memory.
+// This implementation is meant for linux and uses cgroups, v1 or v2, to determine available memory.
 func TotalMemory() (int64, error) {
-	cgroups, err := cgroups.NewCGroupsForCurrentProcess()
-	if err != nil {
-		return 0, err
-	}
-	memoryQuota, defined, err := cgroups.MemoryQuota()
+	memoryQuota, defined, err := cgroups.MemoryQuotaForCurrentProcess()
 	if err != nil || !defined {
 		return 0, err
 	}
@@ -0,0 +1,284 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// aggregateDataPoints removes the labels for which keep returns false from the
+// data points of the metric, and merges the data points left with the same
+// labels. Gauges and sums are merged with the aggregation type. Histograms and
+// summaries always add their counts and sums, histogram buckets are added when
+// the bounds are the same, and the quantiles of merged summaries are dropped as
+// they can't be combined.
+func aggregateDataPoints(metric pdata.Metric, keep func(label string) bool, aggType AggregationType) {
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		aggregateIntDataPoints(metric.IntGauge().DataPoints(), keep, aggType)
+	case pdata.MetricDataTypeDoubleGauge:
+		aggregateDoubleDataPoints(metric.DoubleGauge().DataPoints(), keep, aggType)
+	case pdata.MetricDataTypeIntSum:
+		aggregateIntDataPoints(metric.IntSum().DataPoints(), keep, aggType)
+	case pdata.MetricDataTypeDoubleSum:
+		aggregateDoubleDataPoints(metric.DoubleSum().DataPoints(), keep, aggType)
+	case pdata.MetricDataTypeIntHistogram:
+		aggregateIntHistogramDataPoints(metric.IntHistogram().DataPoints(), keep)
+	case pdata.MetricDataTypeDoubleHistogram:
+		aggregateDoubleHistogramDataPoints(metric.DoubleHistogram().DataPoints(), keep)
+	case pdata.MetricDataTypeDoubleSummary:
+		aggregateDoubleSummaryDataPoints(metric.DoubleSummary().DataPoints(), keep)
+	}
+}
+
+// groupByLabels returns the indexes of the data points grouped by their kept
+// labels, start time, timestamp and, if set, the extra key, in order of first
+// appearance. Only the data points of the same time interval are merged.
+func groupByLabels(
+	n int,
+	labelsAt func(i int) pdata.StringMap,
+	timesAt func(i int) (pdata.TimestampUnixNano, pdata.TimestampUnixNano),
+	keep func(label string) bool,
+	extraKey func(i int) string,
+) [][]int {
+	var groups [][]int
+	indexes := make(map[string]int)
+	for i := 0; i < n; i++ {
+		var kvs []string
+		labelsAt(i).ForEach(func(k string, v string) {
+			if keep(k) {
+				kvs = append(kvs, k+"="+v)
+			}
+		})
+		sort.Strings(kvs)
+		start, timestamp := timesAt(i)
+		key := fmt.Sprintf("%d\x00%d\x00%s", start, timestamp, strings.Join(kvs, "\x00"))
+		if extraKey != nil {
+			key += "\x01" + extraKey(i)
+		}
+
+		idx, ok := indexes[key]
+		if !ok {
+			idx = len(groups)
+			indexes[key] = idx
+			groups = append(groups, nil)
+		}
+		groups[idx] = append(groups[idx], i)
+	}
+	return groups
+}
+
+// removeLabels deletes the labels for which keep returns false.
+func removeLabels(labels pdata.StringMap, keep func(label string) bool) {
+	var removed []string
+	labels.ForEach(func(k string, _ string) {
+		if !keep(k) {
+			removed = append(removed, k)
+		}
+	})
+	for _, k := range removed {
+		labels.Delete(k)
+	}
+}
+
+func aggregateIntDataPoints(dps pdata.IntDataPointSlice, keep func(label string) bool, aggType AggregationType) {
+	groups := groupByLabels(
+		dps.Len(),
+		func(i int) pdata.StringMap { return dps.At(i).LabelsMap() },
+		func(i int) (pdata.TimestampUnixNano, pdata.TimestampUnixNano) {
+			return dps.At(i).StartTime(), dps.At(i).Timestamp()
+		},
+		keep,
+		nil)
+	kept := make([]pdata.IntDataPoint, 0, len(groups))
+	for _, group := range groups {
+		dp := dps.At(group[0])
+		sum, max := dp.Value(), dp.Value()
+		for _, i := range group[1:] {
+			other := dps.At(i)
+			sum += other.Value()
+			if other.Value() > max {
+				max = other.Value()
+			}
+		}
+		switch aggType {
+		case Sum:
+			dp.SetValue(sum)
+		case Mean:
+			dp.SetValue(sum / int64(len(group)))
+		case Max:
+			dp.SetValue(max)
+		}
+		removeLabels(dp.LabelsMap(), keep)
+		kept = append(kept, dp)
+	}
+
+	dps.Resize(0)
+	for _, dp := range kept {
+		dps.Append(dp)
+	}
+}
+
+func aggregateDoubleDataPoints(dps pdata.DoubleDataPointSlice, keep func(label string) bool, aggType AggregationType) {
+	groups := groupByLabels(
+		dps.Len(),
+		func(i int) pdata.StringMap { return dps.At(i).LabelsMap() },
+		func(i int) (pdata.TimestampUnixNano, pdata.TimestampUnixNano) {
+			return dps.At(i).StartTime(), dps.At(i).Timestamp()
+		},
+		keep,
+		nil)
+	kept := make([]pdata.DoubleDataPoint, 0, len(groups))
+	for _, group := range groups {
+		dp := dps.At(group[0])
+		sum, max := dp.Value(), dp.Value()
+		for _, i := range group[1:] {
+			other := dps.At(i)
+			sum += other.Value()
+			if other.Value() > max {
+				max = other.Value()
+			}
+		}
+		switch aggType {
+		case Sum:
+			dp.SetValue(sum)
+		case Mean:
+			dp.SetValue(sum / float64(len(group)))
+		case Max:
+			dp.SetValue(max)
+		}
+		removeLabels(dp.LabelsMap(), keep)
+		kept = append(kept, dp)
+	}
+
+	dps.Resize(0)
+	for _, dp := range kept {
+		dps.Append(dp)
+	}
+}
+
+func aggregateIntHistogramDataPoints(dps pdata.IntHistogramDataPointSlice, keep func(label string) bool) {
+	groups := groupByLabels(
+		dps.Len(),
+		func(i int) pdata.StringMap { return dps.At(i).LabelsMap() },
+		func(i int) (pdata.TimestampUnixNano, pdata.TimestampUnixNano) {
+			return dps.At(i).StartTime(), dps.At(i).Timestamp()
+		},
+		keep,
+		func(i int) string { return fmt.Sprint(dps.At(i).ExplicitBounds()) })
+	kept := make([]pdata.IntHistogramDataPoint, 0, len(groups))
+	for _, group := range groups {
+		dp := dps.At(group[0])
+		count, sum := dp.Count(), dp.Sum()
+		buckets := append([]uint64(nil), dp.BucketCounts()...)
+		for _, i := range group[1:] {
+			other := dps.At(i)
+			count += other.Count()
+			sum += other.Sum()
+			buckets = addBucketCounts(buckets, other.BucketCounts())
+		}
+		dp.SetCount(count)
+		dp.SetSum(sum)
+		dp.SetBucketCounts(buckets)
+		removeLabels(dp.LabelsMap(), keep)
+		kept = append(kept, dp)
+	}
+
+	dps.Resize(0)
+	for _, dp := range kept {
+		dps.Append(dp)
+	}
+}
+
+func aggregateDoubleHistogramDataPoints(dps pdata.DoubleHistogramDataPointSlice, keep func(label string) bool) {
+	groups := groupByLabels(
+		dps.Len(),
+		func(i int) pdata.StringMap { return dps.At(i).LabelsMap() },
+		func(i int) (pdata.TimestampUnixNano, pdata.TimestampUnixNano) {
+			return dps.At(i).StartTime(), dps.At(i).Timestamp()
+		},
+		keep,
+		func(i int) string { return fmt.Sprint(dps.At(i).ExplicitBounds()) })
+	kept := make([]pdata.DoubleHistogramDataPoint, 0, len(groups))
+	for _, group := range groups {
+		dp := dps.At(group[0])
+		count, sum := dp.Count(), dp.Sum()
+		buckets := append([]uint64(nil), dp.BucketCounts()...)
+		for _, i := range group[1:] {
+			other := dps.At(i)
+			count += other.Count()
+			sum += other.Sum()
+			buckets = addBucketCounts(buckets, other.BucketCounts())
+		}
+		dp.SetCount(count)
+		dp.SetSum(sum)
+		dp.SetBucketCounts(buckets)
+		removeLabels(dp.LabelsMap(), keep)
+		kept = append(kept, dp)
+	}
+
+	dps.Resize(0)
+	for _, dp := range kept {
+		dps.Append(dp)
+	}
+}
+
+func aggregateDoubleSummaryDataPoints(dps pdata.DoubleSummaryDataPointSlice, keep func(label string) bool) {
+	groups := groupByLabels(
+		dps.Len(),
+		func(i int) pdata.StringMap { return dps.At(i).LabelsMap() },
+		func(i int) (pdata.TimestampUnixNano, pdata.TimestampUnixNano) {
+			return dps.At(i).StartTime(), dps.At(i).Timestamp()
+		},
+		keep,
+		nil)
+	kept := make([]pdata.DoubleSummaryDataPoint, 0, len(groups))
+	for _, group := range groups {
+		dp := dps.At(group[0])
+		count, sum := dp.Count(), dp.Sum()
+		for _, i := range group[1:] {
+			other := dps.At(i)
+			count += other.Count()
+			sum += other.Sum()
+		}
+		dp.SetCount(count)
+		dp.SetSum(sum)
+		if len(group) > 1 {
+			dp.QuantileValues().Resize(0)
+		}
+		removeLabels(dp.LabelsMap(), keep)
+		kept = append(kept, dp)
+	}
+
+	dps.Resize(0)
+	for _, dp := range kept {
+		dps.Append(dp)
+	}
+}
+
+// addBucketCounts adds the bucket counts of other to buckets, both have the
+// same length as the data points have the same bounds.
+func addBucketCounts(buckets []uint64, other []uint64) []uint64 {
+	for i := range buckets {
+		if i < len(other) {
+			buckets[i] += other[i]
+		}
+	}
+	return buckets
+}
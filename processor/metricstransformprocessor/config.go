@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// MatchType specifies how the Include field of a transform is matched against
+// the metric names.
+type MatchType string
+
+const (
+	// StrictMatchType matches the metric named exactly as Include.
+	StrictMatchType MatchType = "strict"
+	// RegexpMatchType matches the metrics whose name matches the Include
+	// regular expression as a whole. NewName can refer to its capture groups.
+	RegexpMatchType MatchType = "regexp"
+)
+
+// ConfigAction is the action applied by a transform to the matching metrics.
+type ConfigAction string
+
+const (
+	// Update transforms the matching metrics in place.
+	Update ConfigAction = "update"
+	// Insert transforms a copy of each matching metric, added next to it.
+	Insert ConfigAction = "insert"
+)
+
+// OperationAction is the action of an operation applied to the data points of a
+// metric.
+type OperationAction string
+
+const (
+	// AddLabel adds the NewLabel label with the NewValue value to all the data
+	// points, keeping the current value of the data points already having it.
+	AddLabel OperationAction = "add_label"
+	// UpdateLabel renames the Label label to NewLabel, if set, and renames its
+	// values according to ValueActions.
+	UpdateLabel OperationAction = "update_label"
+	// DeleteLabel removes the Label label, aggregating the data points left
+	// with the same labels using AggregationType.
+	DeleteLabel OperationAction = "delete_label"
+	// AggregateLabels removes all the labels not in LabelSet, aggregating the
+	// data points left with the same labels using AggregationType.
+	AggregateLabels OperationAction = "aggregate_labels"
+)
+
+// AggregationType is how the values of the data points with the same labels
+// are combined.
+type AggregationType string
+
+const (
+	// Sum adds the values.
+	Sum AggregationType = "sum"
+	// Mean averages the values.
+	Mean AggregationType = "mean"
+	// Max keeps the greatest value.
+	Max AggregationType = "max"
+)
+
+// Config defines the configuration for the Metrics transform processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// Transforms are applied in order to the metrics.
+	Transforms []Transform `mapstructure:"transforms"`
+}
+
+// Transform defines the transformation applied to the metrics matching Include.
+type Transform struct {
+	// Include is the name, or the regular expression with the regexp match
+	// type, of the metrics to transform.
+	Include string `mapstructure:"include"`
+
+	// MatchType is how Include is matched, strict (the default) or regexp.
+	MatchType MatchType `mapstructure:"match_type"`
+
+	// Action is either update (the default) or insert.
+	Action ConfigAction `mapstructure:"action"`
+
+	// NewName is the new name of the metric. Required for the insert action.
+	NewName string `mapstructure:"new_name"`
+
+	// Operations are applied in order to the data points of the metric.
+	Operations []Operation `mapstructure:"operations"`
+}
+
+// Operation defines a change of the labels of the data points of a metric.
+type Operation struct {
+	// Action is the type of the operation.
+	Action OperationAction `mapstructure:"action"`
+
+	// Label is the label updated or deleted.
+	Label string `mapstructure:"label"`
+
+	// NewLabel is the label added, or the new name of the updated label.
+	NewLabel string `mapstructure:"new_label"`
+
+	// NewValue is the value of the added label.
+	NewValue string `mapstructure:"new_value"`
+
+	// ValueActions rename the values of the updated label.
+	ValueActions []ValueAction `mapstructure:"value_actions"`
+
+	// LabelSet is the set of labels kept when aggregating labels.
+	LabelSet []string `mapstructure:"label_set"`
+
+	// AggregationType is how the data points are aggregated when deleting or
+	// aggregating labels, sum (the default), mean or max.
+	AggregationType AggregationType `mapstructure:"aggregation_type"`
+}
+
+// ValueAction renames a value of a label.
+type ValueAction struct {
+	// Value is the current value of the label.
+	Value string `mapstructure:"value"`
+
+	// NewValue is the value replacing it.
+	NewValue string `mapstructure:"new_value"`
+}
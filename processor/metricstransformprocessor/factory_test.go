@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	cfg := createDefaultConfig()
+	assert.NotNil(t, cfg, "failed to create default config")
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+}
+
+func TestCreateProcessor(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	params := component.ProcessorCreateParams{Logger: zap.NewNop()}
+
+	mp, err := createMetricsProcessor(context.Background(), params, cfg, consumertest.NewMetricsNop())
+	assert.NotNil(t, mp)
+	assert.NoError(t, err, "cannot create metrics processor")
+
+	tp, err := NewFactory().CreateTracesProcessor(context.Background(), params, cfg, consumertest.NewTracesNop())
+	assert.Nil(t, tp)
+	assert.Error(t, err)
+}
+
+func TestCreateProcessorInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		transform Transform
+	}{
+		{
+			name:      "missing include",
+			transform: Transform{NewName: "name"},
+		},
+		{
+			name:      "unknown match type",
+			transform: Transform{Include: "name", MatchType: "glob"},
+		},
+		{
+			name:      "invalid regexp",
+			transform: Transform{Include: "(", MatchType: RegexpMatchType},
+		},
+		{
+			name:      "unknown action",
+			transform: Transform{Include: "name", Action: "delete"},
+		},
+		{
+			name:      "insert without new name",
+			transform: Transform{Include: "name", Action: Insert},
+		},
+		{
+			name:      "unknown operation",
+			transform: Transform{Include: "name", Operations: []Operation{{Action: "toggle_label"}}},
+		},
+		{
+			name:      "add label without new label",
+			transform: Transform{Include: "name", Operations: []Operation{{Action: AddLabel, NewValue: "v"}}},
+		},
+		{
+			name:      "update label without label",
+			transform: Transform{Include: "name", Operations: []Operation{{Action: UpdateLabel, NewLabel: "l"}}},
+		},
+		{
+			name:      "delete label without label",
+			transform: Transform{Include: "name", Operations: []Operation{{Action: DeleteLabel}}},
+		},
+		{
+			name:      "unknown aggregation type",
+			transform: Transform{Include: "name", Operations: []Operation{{Action: DeleteLabel, Label: "l", AggregationType: "min"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Transforms = []Transform{tt.transform}
+			params := component.ProcessorCreateParams{Logger: zap.NewNop()}
+
+			mp, err := createMetricsProcessor(context.Background(), params, cfg, consumertest.NewMetricsNop())
+			assert.Nil(t, mp)
+			assert.Error(t, err)
+		})
+	}
+}
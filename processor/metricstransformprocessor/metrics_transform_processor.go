@@ -0,0 +1,271 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+type internalTransform struct {
+	include    string
+	regexp     *regexp.Regexp
+	action     ConfigAction
+	newName    string
+	operations []internalOperation
+}
+
+type internalOperation struct {
+	Operation
+	// valueActions maps the values of the updated label to their new value.
+	valueActions map[string]string
+	// labelSet contains the labels kept when aggregating labels.
+	labelSet map[string]bool
+}
+
+type metricsTransformProcessor struct {
+	transforms []internalTransform
+	logger     *zap.Logger
+}
+
+func newMetricsTransformProcessor(logger *zap.Logger, cfg *Config) (*metricsTransformProcessor, error) {
+	transforms := make([]internalTransform, 0, len(cfg.Transforms))
+	for _, t := range cfg.Transforms {
+		it, err := newInternalTransform(t)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transform of %q: %w", t.Include, err)
+		}
+		transforms = append(transforms, it)
+	}
+
+	return &metricsTransformProcessor{
+		transforms: transforms,
+		logger:     logger,
+	}, nil
+}
+
+func newInternalTransform(t Transform) (internalTransform, error) {
+	it := internalTransform{
+		include: t.Include,
+		action:  t.Action,
+		newName: t.NewName,
+	}
+	if t.Include == "" {
+		return it, fmt.Errorf("include must be specified")
+	}
+
+	switch t.MatchType {
+	case "", StrictMatchType:
+	case RegexpMatchType:
+		re, err := regexp.Compile("^(?:" + t.Include + ")$")
+		if err != nil {
+			return it, err
+		}
+		it.regexp = re
+	default:
+		return it, fmt.Errorf("unknown match_type %q", t.MatchType)
+	}
+
+	switch t.Action {
+	case "":
+		it.action = Update
+	case Update:
+	case Insert:
+		if t.NewName == "" {
+			return it, fmt.Errorf("new_name must be specified with the insert action")
+		}
+	default:
+		return it, fmt.Errorf("unknown action %q", t.Action)
+	}
+
+	for _, op := range t.Operations {
+		iop, err := newInternalOperation(op)
+		if err != nil {
+			return it, err
+		}
+		it.operations = append(it.operations, iop)
+	}
+	return it, nil
+}
+
+func newInternalOperation(op Operation) (internalOperation, error) {
+	iop := internalOperation{Operation: op}
+	switch op.Action {
+	case AddLabel:
+		if op.NewLabel == "" {
+			return iop, fmt.Errorf("new_label must be specified with the %s operation", op.Action)
+		}
+	case UpdateLabel:
+		if op.Label == "" {
+			return iop, fmt.Errorf("label must be specified with the %s operation", op.Action)
+		}
+		iop.valueActions = make(map[string]string, len(op.ValueActions))
+		for _, va := range op.ValueActions {
+			iop.valueActions[va.Value] = va.NewValue
+		}
+	case DeleteLabel:
+		if op.Label == "" {
+			return iop, fmt.Errorf("label must be specified with the %s operation", op.Action)
+		}
+	case AggregateLabels:
+		iop.labelSet = make(map[string]bool, len(op.LabelSet))
+		for _, l := range op.LabelSet {
+			iop.labelSet[l] = true
+		}
+	default:
+		return iop, fmt.Errorf("unknown operation action %q", op.Action)
+	}
+
+	switch op.AggregationType {
+	case "":
+		iop.AggregationType = Sum
+	case Sum, Mean, Max:
+	default:
+		return iop, fmt.Errorf("unknown aggregation_type %q", op.AggregationType)
+	}
+	return iop, nil
+}
+
+// ProcessMetrics applies the transforms, in order, to the metrics.
+func (mtp *metricsTransformProcessor) ProcessMetrics(_ context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		ilms := rms.At(i).InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			metrics := ilms.At(j).Metrics()
+			for _, t := range mtp.transforms {
+				mtp.applyTransform(t, metrics)
+			}
+		}
+	}
+	return md, nil
+}
+
+// applyTransform applies the transform to the matching metrics of the slice,
+// the metrics inserted by the transform are appended to the slice.
+func (mtp *metricsTransformProcessor) applyTransform(t internalTransform, metrics pdata.MetricSlice) {
+	// The metrics inserted by this transform are not transformed again.
+	n := metrics.Len()
+	for k := 0; k < n; k++ {
+		metric := metrics.At(k)
+		newName, ok := t.match(metric.Name())
+		if !ok {
+			continue
+		}
+
+		if t.action == Insert {
+			inserted := pdata.NewMetric()
+			metric.CopyTo(inserted)
+			metrics.Append(inserted)
+			metric = inserted
+		}
+		if newName != "" {
+			metric.SetName(newName)
+		}
+		for _, op := range t.operations {
+			applyOperation(op, metric)
+		}
+	}
+}
+
+// match returns true if the metric name matches the transform, and the new
+// name of the metric if it must be renamed.
+func (t internalTransform) match(name string) (string, bool) {
+	if t.regexp == nil {
+		return t.newName, name == t.include
+	}
+	if !t.regexp.MatchString(name) {
+		return "", false
+	}
+	if t.newName == "" {
+		return "", true
+	}
+	return t.regexp.ReplaceAllString(name, t.newName), true
+}
+
+func applyOperation(op internalOperation, metric pdata.Metric) {
+	switch op.Action {
+	case AddLabel:
+		forEachLabelsMap(metric, func(labels pdata.StringMap) {
+			labels.Insert(op.NewLabel, op.NewValue)
+		})
+	case UpdateLabel:
+		forEachLabelsMap(metric, func(labels pdata.StringMap) {
+			value, ok := labels.Get(op.Label)
+			if !ok {
+				return
+			}
+			if newValue, ok := op.valueActions[value]; ok {
+				value = newValue
+			}
+			if op.NewLabel != "" && op.NewLabel != op.Label {
+				labels.Delete(op.Label)
+				labels.Upsert(op.NewLabel, value)
+				return
+			}
+			labels.Update(op.Label, value)
+		})
+	case DeleteLabel:
+		aggregateDataPoints(metric, func(label string) bool { return label != op.Label }, op.AggregationType)
+	case AggregateLabels:
+		aggregateDataPoints(metric, func(label string) bool { return op.labelSet[label] }, op.AggregationType)
+	}
+}
+
+// forEachLabelsMap calls f with the labels of each data point of the metric.
+func forEachLabelsMap(metric pdata.Metric, f func(labels pdata.StringMap)) {
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		dps := metric.IntGauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).LabelsMap())
+		}
+	case pdata.MetricDataTypeDoubleGauge:
+		dps := metric.DoubleGauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).LabelsMap())
+		}
+	case pdata.MetricDataTypeIntSum:
+		dps := metric.IntSum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).LabelsMap())
+		}
+	case pdata.MetricDataTypeDoubleSum:
+		dps := metric.DoubleSum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).LabelsMap())
+		}
+	case pdata.MetricDataTypeIntHistogram:
+		dps := metric.IntHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).LabelsMap())
+		}
+	case pdata.MetricDataTypeDoubleHistogram:
+		dps := metric.DoubleHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).LabelsMap())
+		}
+	case pdata.MetricDataTypeDoubleSummary:
+		dps := metric.DoubleSummary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).LabelsMap())
+		}
+	}
+}
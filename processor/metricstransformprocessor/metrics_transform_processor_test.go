@@ -0,0 +1,293 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+type testPoint struct {
+	labels    map[string]string
+	value     int64
+	start     pdata.TimestampUnixNano
+	timestamp pdata.TimestampUnixNano
+}
+
+func newTestMetrics(metrics ...pdata.Metric) pdata.Metrics {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	ilms := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics()
+	ilms.Resize(1)
+	for _, m := range metrics {
+		ilms.At(0).Metrics().Append(m)
+	}
+	return md
+}
+
+func newIntSum(name string, points ...testPoint) pdata.Metric {
+	m := pdata.NewMetric()
+	m.SetName(name)
+	m.SetDataType(pdata.MetricDataTypeIntSum)
+	m.IntSum().SetIsMonotonic(true)
+	m.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+	dps := m.IntSum().DataPoints()
+	dps.Resize(len(points))
+	for i, p := range points {
+		dps.At(i).LabelsMap().InitFromMap(p.labels)
+		dps.At(i).SetValue(p.value)
+		dps.At(i).SetStartTime(p.start)
+		dps.At(i).SetTimestamp(p.timestamp)
+	}
+	return m
+}
+
+func newDoubleGauge(name string, points ...testPoint) pdata.Metric {
+	m := pdata.NewMetric()
+	m.SetName(name)
+	m.SetDataType(pdata.MetricDataTypeDoubleGauge)
+	dps := m.DoubleGauge().DataPoints()
+	dps.Resize(len(points))
+	for i, p := range points {
+		dps.At(i).LabelsMap().InitFromMap(p.labels)
+		dps.At(i).SetValue(float64(p.value))
+		dps.At(i).SetTimestamp(p.timestamp)
+	}
+	return m
+}
+
+func processMetrics(t *testing.T, transforms []Transform, md pdata.Metrics) pdata.MetricSlice {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Transforms = transforms
+	mtp, err := newMetricsTransformProcessor(zap.NewNop(), cfg)
+	require.NoError(t, err)
+
+	md, err = mtp.ProcessMetrics(context.Background(), md)
+	require.NoError(t, err)
+	return md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+}
+
+func metricNames(metrics pdata.MetricSlice) []string {
+	var names []string
+	for i := 0; i < metrics.Len(); i++ {
+		names = append(names, metrics.At(i).Name())
+	}
+	return names
+}
+
+func TestProcessMetrics_Rename(t *testing.T) {
+	metrics := processMetrics(t,
+		[]Transform{
+			{Include: "old", NewName: "new"},
+			{Include: `system\.(.*)`, MatchType: RegexpMatchType, NewName: "host.${1}"},
+		},
+		newTestMetrics(newIntSum("old"), newIntSum("other"), newIntSum("system.cpu.time"), newIntSum("mysystem.cpu")))
+
+	assert.Equal(t, []string{"new", "other", "host.cpu.time", "mysystem.cpu"}, metricNames(metrics))
+}
+
+func TestProcessMetrics_Insert(t *testing.T) {
+	metrics := processMetrics(t,
+		[]Transform{{
+			Include: "requests",
+			Action:  Insert,
+			NewName: "requests_by_version",
+			Operations: []Operation{
+				{Action: AddLabel, NewLabel: "version", NewValue: "v1"},
+			},
+		}},
+		newTestMetrics(newIntSum("requests", testPoint{labels: map[string]string{"method": "GET"}, value: 3})))
+
+	require.Equal(t, []string{"requests", "requests_by_version"}, metricNames(metrics))
+
+	// The original metric is left untouched.
+	assert.EqualValues(t, map[string]string{"method": "GET"}, labelsOf(metrics.At(0).IntSum().DataPoints().At(0).LabelsMap()))
+	inserted := metrics.At(1).IntSum()
+	assert.True(t, inserted.IsMonotonic())
+	assert.Equal(t, pdata.AggregationTemporalityCumulative, inserted.AggregationTemporality())
+	require.Equal(t, 1, inserted.DataPoints().Len())
+	assert.EqualValues(t, 3, inserted.DataPoints().At(0).Value())
+	assert.EqualValues(t, map[string]string{"method": "GET", "version": "v1"}, labelsOf(inserted.DataPoints().At(0).LabelsMap()))
+}
+
+func TestProcessMetrics_AddAndUpdateLabels(t *testing.T) {
+	metrics := processMetrics(t,
+		[]Transform{{
+			Include: "cpu",
+			Operations: []Operation{
+				{Action: AddLabel, NewLabel: "host", NewValue: "default"},
+				{
+					Action:       UpdateLabel,
+					Label:        "state",
+					NewLabel:     "cpu_state",
+					ValueActions: []ValueAction{{Value: "idle", NewValue: "unused"}},
+				},
+			},
+		}},
+		newTestMetrics(newDoubleGauge("cpu",
+			testPoint{labels: map[string]string{"state": "idle"}},
+			testPoint{labels: map[string]string{"state": "user", "host": "h1"}},
+			testPoint{labels: map[string]string{}},
+		)))
+
+	dps := metrics.At(0).DoubleGauge().DataPoints()
+	require.Equal(t, 3, dps.Len())
+	assert.EqualValues(t, map[string]string{"cpu_state": "unused", "host": "default"}, labelsOf(dps.At(0).LabelsMap()))
+	assert.EqualValues(t, map[string]string{"cpu_state": "user", "host": "h1"}, labelsOf(dps.At(1).LabelsMap()))
+	assert.EqualValues(t, map[string]string{"host": "default"}, labelsOf(dps.At(2).LabelsMap()))
+}
+
+func TestProcessMetrics_DeleteLabel(t *testing.T) {
+	points := []testPoint{
+		{labels: map[string]string{"cpu": "0", "state": "idle"}, value: 4, start: 10, timestamp: 100},
+		{labels: map[string]string{"cpu": "1", "state": "idle"}, value: 8, start: 10, timestamp: 100},
+		{labels: map[string]string{"cpu": "0", "state": "user"}, value: 1, start: 10, timestamp: 100},
+		// Data points of other time intervals aren't merged.
+		{labels: map[string]string{"cpu": "1", "state": "idle"}, value: 3, start: 10, timestamp: 110},
+		{labels: map[string]string{"cpu": "0", "state": "idle"}, value: 2, start: 20, timestamp: 110},
+	}
+	tests := []struct {
+		aggregationType AggregationType
+		want            []int64
+	}{
+		{aggregationType: Sum, want: []int64{12, 1}},
+		{aggregationType: Mean, want: []int64{6, 1}},
+		{aggregationType: Max, want: []int64{8, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.aggregationType), func(t *testing.T) {
+			metrics := processMetrics(t,
+				[]Transform{{
+					Include:    "cpu",
+					Operations: []Operation{{Action: DeleteLabel, Label: "cpu", AggregationType: tt.aggregationType}},
+				}},
+				newTestMetrics(newIntSum("cpu", points...)))
+
+			dps := metrics.At(0).IntSum().DataPoints()
+			require.Equal(t, 4, dps.Len())
+			assert.EqualValues(t, map[string]string{"state": "idle"}, labelsOf(dps.At(0).LabelsMap()))
+			assert.Equal(t, tt.want[0], dps.At(0).Value())
+			assert.EqualValues(t, 10, dps.At(0).StartTime())
+			assert.EqualValues(t, 100, dps.At(0).Timestamp())
+			assert.EqualValues(t, map[string]string{"state": "user"}, labelsOf(dps.At(1).LabelsMap()))
+			assert.Equal(t, tt.want[1], dps.At(1).Value())
+			assert.EqualValues(t, map[string]string{"state": "idle"}, labelsOf(dps.At(2).LabelsMap()))
+			assert.EqualValues(t, 3, dps.At(2).Value())
+			assert.EqualValues(t, 110, dps.At(2).Timestamp())
+			assert.EqualValues(t, map[string]string{"state": "idle"}, labelsOf(dps.At(3).LabelsMap()))
+			assert.EqualValues(t, 2, dps.At(3).Value())
+			assert.EqualValues(t, 20, dps.At(3).StartTime())
+		})
+	}
+}
+
+func TestProcessMetrics_AggregateLabels(t *testing.T) {
+	metrics := processMetrics(t,
+		[]Transform{{
+			Include:    "latency",
+			Operations: []Operation{{Action: AggregateLabels, LabelSet: []string{"service"}, AggregationType: Mean}},
+		}},
+		newTestMetrics(newDoubleGauge("latency",
+			testPoint{labels: map[string]string{"service": "a", "method": "GET"}, value: 1},
+			testPoint{labels: map[string]string{"service": "a", "method": "POST"}, value: 2},
+			testPoint{labels: map[string]string{"service": "b", "method": "GET"}, value: 5},
+		)))
+
+	dps := metrics.At(0).DoubleGauge().DataPoints()
+	require.Equal(t, 2, dps.Len())
+	assert.EqualValues(t, map[string]string{"service": "a"}, labelsOf(dps.At(0).LabelsMap()))
+	assert.Equal(t, 1.5, dps.At(0).Value())
+	assert.EqualValues(t, map[string]string{"service": "b"}, labelsOf(dps.At(1).LabelsMap()))
+	assert.Equal(t, 5.0, dps.At(1).Value())
+}
+
+func TestProcessMetrics_AggregateHistogram(t *testing.T) {
+	m := pdata.NewMetric()
+	m.SetName("latency")
+	m.SetDataType(pdata.MetricDataTypeDoubleHistogram)
+	dps := m.DoubleHistogram().DataPoints()
+	dps.Resize(3)
+	for i, host := range []string{"h1", "h2", "h3"} {
+		dp := dps.At(i)
+		dp.LabelsMap().InitFromMap(map[string]string{"host": host})
+		dp.SetCount(uint64(i + 1))
+		dp.SetSum(float64(10 * (i + 1)))
+		dp.SetExplicitBounds([]float64{10, 100})
+		dp.SetBucketCounts([]uint64{uint64(i + 1), 0, 0})
+	}
+	// Data points with different bounds aren't merged.
+	dps.At(2).SetExplicitBounds([]float64{50})
+	dps.At(2).SetBucketCounts([]uint64{3, 0})
+
+	metrics := processMetrics(t,
+		[]Transform{{Include: "latency", Operations: []Operation{{Action: DeleteLabel, Label: "host"}}}},
+		newTestMetrics(m))
+
+	dps = metrics.At(0).DoubleHistogram().DataPoints()
+	require.Equal(t, 2, dps.Len())
+	assert.Equal(t, 0, dps.At(0).LabelsMap().Len())
+	assert.EqualValues(t, 3, dps.At(0).Count())
+	assert.Equal(t, 30.0, dps.At(0).Sum())
+	assert.Equal(t, []float64{10, 100}, dps.At(0).ExplicitBounds())
+	assert.Equal(t, []uint64{3, 0, 0}, dps.At(0).BucketCounts())
+	assert.EqualValues(t, 3, dps.At(1).Count())
+	assert.Equal(t, []float64{50}, dps.At(1).ExplicitBounds())
+}
+
+func TestProcessMetrics_AggregateSummary(t *testing.T) {
+	m := pdata.NewMetric()
+	m.SetName("latency")
+	m.SetDataType(pdata.MetricDataTypeDoubleSummary)
+	dps := m.DoubleSummary().DataPoints()
+	dps.Resize(3)
+	for i, host := range []string{"h1", "h2", "h3"} {
+		dp := dps.At(i)
+		dp.LabelsMap().InitFromMap(map[string]string{"host": host, "region": "eu"})
+		dp.SetCount(uint64(i + 1))
+		dp.SetSum(float64(10 * (i + 1)))
+		dp.QuantileValues().Resize(1)
+		dp.QuantileValues().At(0).SetQuantile(0.5)
+		dp.QuantileValues().At(0).SetValue(float64(i))
+	}
+	dps.At(2).LabelsMap().Update("region", "us")
+
+	metrics := processMetrics(t,
+		[]Transform{{Include: "latency", Operations: []Operation{{Action: DeleteLabel, Label: "host"}}}},
+		newTestMetrics(m))
+
+	dps = metrics.At(0).DoubleSummary().DataPoints()
+	require.Equal(t, 2, dps.Len())
+	assert.EqualValues(t, map[string]string{"region": "eu"}, labelsOf(dps.At(0).LabelsMap()))
+	assert.EqualValues(t, 3, dps.At(0).Count())
+	assert.Equal(t, 30.0, dps.At(0).Sum())
+	// The quantiles of merged data points can't be computed.
+	assert.Equal(t, 0, dps.At(0).QuantileValues().Len())
+	assert.EqualValues(t, map[string]string{"region": "us"}, labelsOf(dps.At(1).LabelsMap()))
+	assert.Equal(t, 1, dps.At(1).QuantileValues().Len())
+}
+
+func labelsOf(labels pdata.StringMap) map[string]string {
+	m := make(map[string]string, labels.Len())
+	labels.ForEach(func(k string, v string) {
+		m[k] = v
+	})
+	return m
+}
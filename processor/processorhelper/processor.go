@@ -29,7 +29,7 @@ import (
 	"go.opentelemetry.io/collector/obsreport"
 )
 
-// ErrSkipProcessingData is a sentinel value to indicate when traces or metrics should intentionally be dropped
+// ErrSkipProcessingData is a sentinel value to indicate when traces, metrics or logs should intentionally be dropped
 // from further processing in the pipeline because the data is determined to be irrelevant. A processor can return this error
 // to stop further processing without propagating an error back up the pipeline to logs.
 var ErrSkipProcessingData = errors.New("sentinel error to skip processing data from the remainder of the pipeline")
@@ -142,6 +142,9 @@ func (tp *tracesProcessor) ConsumeTraces(ctx context.Context, td pdata.Traces) e
 	td, err = tp.processor.ProcessTraces(ctx, td)
 	span.Annotate(tp.traceAttributes, "End processing.")
 	if err != nil {
+		if err == ErrSkipProcessingData {
+			return nil
+		}
 		return err
 	}
 	return tp.nextConsumer.ConsumeTraces(ctx, td)
@@ -227,6 +230,9 @@ func (lp *logProcessor) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
 	ld, err = lp.processor.ProcessLogs(ctx, ld)
 	span.Annotate(lp.traceAttributes, "End processing.")
 	if err != nil {
+		if err == ErrSkipProcessingData {
+			return nil
+		}
 		return err
 	}
 	return lp.nextConsumer.ConsumeLogs(ctx, ld)
@@ -0,0 +1,60 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routingprocessor
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+const (
+	// ResourceAttributeSource takes the routing value from an attribute of the
+	// resource of each batch.
+	ResourceAttributeSource = "resource"
+	// ContextAttributeSource takes the routing value from the client.Client
+	// stored in the context by the receiver.
+	ContextAttributeSource = "context"
+)
+
+// Config defines configuration for the Routing processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// FromAttribute is the name of the attribute holding the value used to pick
+	// a route. For the context source, the supported values are the fields of
+	// client.Client: "ip", "receiver", "subject" or "metadata.<key>".
+	FromAttribute string `mapstructure:"from_attribute"`
+
+	// AttributeSource is where FromAttribute is looked up, either "resource"
+	// (the default) or "context".
+	AttributeSource string `mapstructure:"attribute_source"`
+
+	// DefaultExporters are the exporters receiving the data that has no routing
+	// value or whose value is not in the routing table. Optional.
+	DefaultExporters []string `mapstructure:"default_exporters"`
+
+	// Table contains the routes.
+	Table []RoutingTableItem `mapstructure:"table"`
+}
+
+// RoutingTableItem specifies the exporters receiving the data whose routing value
+// is Value.
+type RoutingTableItem struct {
+	// Value is the value of the routing attribute for this route.
+	Value string `mapstructure:"value"`
+
+	// Exporters are the names of the exporters of this route. They must be
+	// exporters of the pipeline the processor is part of.
+	Exporters []string `mapstructure:"exporters"`
+}
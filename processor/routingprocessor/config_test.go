@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routingprocessor
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Processors[typeStr] = factory
+	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+
+	require.Nil(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, cfg.Processors["routing"],
+		&Config{
+			ProcessorSettings: configmodels.ProcessorSettings{
+				TypeVal: "routing",
+				NameVal: "routing",
+			},
+			FromAttribute:    "tenant",
+			AttributeSource:  ResourceAttributeSource,
+			DefaultExporters: []string{"exampleexporter"},
+			Table: []RoutingTableItem{
+				{Value: "acme", Exporters: []string{"exampleexporter/acme"}},
+				{Value: "globex", Exporters: []string{"exampleexporter", "exampleexporter/globex"}},
+			},
+		})
+
+	assert.Equal(t, cfg.Processors["routing/context"],
+		&Config{
+			ProcessorSettings: configmodels.ProcessorSettings{
+				TypeVal: "routing",
+				NameVal: "routing/context",
+			},
+			FromAttribute:   "ip",
+			AttributeSource: ContextAttributeSource,
+			Table: []RoutingTableItem{
+				{Value: "10.0.0.1", Exporters: []string{"exampleexporter/acme"}},
+			},
+		})
+}
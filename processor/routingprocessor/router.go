@@ -0,0 +1,375 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routingprocessor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/processor"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+var (
+	errNoFromAttribute = fmt.Errorf("from_attribute must be specified")
+	errNoTable         = fmt.Errorf("the routing table must have at least one route")
+)
+
+// route holds the consumers of the exporters of a route, only the one matching
+// the data type of the processor is set.
+type route struct {
+	traces  consumer.TracesConsumer
+	metrics consumer.MetricsConsumer
+	logs    consumer.LogsConsumer
+}
+
+// router sends each resource batch to the exporters of the route matching its
+// routing value, or to the default exporters when there is no matching route.
+// The processor must be the last one of its pipeline: its next consumer is the
+// fan-out to the exporters of the pipeline, among which the routes are resolved.
+type router struct {
+	logger       *zap.Logger
+	config       Config
+	dataType     configmodels.DataType
+	nextConsumer interface{}
+
+	// routes and defaultRoute are resolved on Start, when the exporters are known.
+	routes       map[string]*route
+	defaultRoute *route
+}
+
+var (
+	_ component.TracesProcessor  = (*router)(nil)
+	_ component.MetricsProcessor = (*router)(nil)
+	_ component.LogsProcessor    = (*router)(nil)
+)
+
+func newRouter(logger *zap.Logger, cfg *Config, dataType configmodels.DataType, nextConsumer interface{}) (*router, error) {
+	if cfg.FromAttribute == "" {
+		return nil, errNoFromAttribute
+	}
+	switch cfg.AttributeSource {
+	case "", ResourceAttributeSource:
+	case ContextAttributeSource:
+		if !isClientAttribute(cfg.FromAttribute) {
+			return nil, fmt.Errorf("from_attribute %q is not a supported client attribute", cfg.FromAttribute)
+		}
+	default:
+		return nil, fmt.Errorf("unknown attribute_source %q", cfg.AttributeSource)
+	}
+	if len(cfg.Table) == 0 {
+		return nil, errNoTable
+	}
+
+	values := make(map[string]bool, len(cfg.Table))
+	for _, item := range cfg.Table {
+		if len(item.Exporters) == 0 {
+			return nil, fmt.Errorf("the route for value %q must have at least one exporter", item.Value)
+		}
+		if values[item.Value] {
+			return nil, fmt.Errorf("duplicate route for value %q", item.Value)
+		}
+		values[item.Value] = true
+	}
+
+	return &router{
+		logger:       logger,
+		config:       *cfg,
+		dataType:     dataType,
+		nextConsumer: nextConsumer,
+	}, nil
+}
+
+// Start resolves the exporters of the routes among the exporters of the pipeline,
+// which the next consumer fans out to.
+func (r *router) Start(_ context.Context, host component.Host) error {
+	available := make(map[string]component.Exporter)
+	for _, next := range processor.FanOutConsumers(r.nextConsumer) {
+		for cfg, exp := range host.GetExporters()[r.dataType] {
+			if exp == next {
+				available[cfg.Name()] = exp
+			}
+		}
+	}
+
+	routes := make(map[string]*route, len(r.config.Table))
+	for _, item := range r.config.Table {
+		rt, err := r.newRoute(available, item.Exporters)
+		if err != nil {
+			return err
+		}
+		routes[item.Value] = rt
+	}
+	r.routes = routes
+
+	if len(r.config.DefaultExporters) > 0 {
+		rt, err := r.newRoute(available, r.config.DefaultExporters)
+		if err != nil {
+			return err
+		}
+		r.defaultRoute = rt
+	}
+	return nil
+}
+
+func (r *router) newRoute(available map[string]component.Exporter, names []string) (*route, error) {
+	var tcs []consumer.TracesConsumer
+	var mcs []consumer.MetricsConsumer
+	var lcs []consumer.LogsConsumer
+	for _, name := range names {
+		exp, ok := available[name]
+		if !ok {
+			return nil, fmt.Errorf("routing processor %q: exporter %q is not an exporter of the %s pipeline, or the processor is not the last one of the pipeline", r.config.Name(), name, r.dataType)
+		}
+		switch r.dataType {
+		case configmodels.TracesDataType:
+			tcs = append(tcs, exp.(consumer.TracesConsumer))
+		case configmodels.MetricsDataType:
+			mcs = append(mcs, exp.(consumer.MetricsConsumer))
+		case configmodels.LogsDataType:
+			lcs = append(lcs, exp.(consumer.LogsConsumer))
+		}
+	}
+
+	rt := &route{}
+	switch r.dataType {
+	case configmodels.TracesDataType:
+		rt.traces = processor.NewTracesFanOutConnector(tcs)
+	case configmodels.MetricsDataType:
+		rt.metrics = processor.NewMetricsFanOutConnector(mcs)
+	case configmodels.LogsDataType:
+		rt.logs = processor.NewLogsFanOutConnector(lcs)
+	}
+	return rt, nil
+}
+
+// Shutdown is invoked during service shutdown.
+func (r *router) Shutdown(context.Context) error {
+	return nil
+}
+
+func (r *router) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: false}
+}
+
+// routeFor returns the route of the given routing value, nil if the data must
+// be dropped.
+func (r *router) routeFor(value string, found bool) *route {
+	if found {
+		if rt, ok := r.routes[value]; ok {
+			return rt
+		}
+	}
+	return r.defaultRoute
+}
+
+func (r *router) contextRoute(ctx context.Context) *route {
+	c, ok := client.FromContext(ctx)
+	if !ok {
+		return r.defaultRoute
+	}
+	return r.routeFor(clientAttribute(c, r.config.FromAttribute))
+}
+
+func (r *router) resourceRoute(resource pdata.Resource) *route {
+	v, ok := resource.Attributes().Get(r.config.FromAttribute)
+	if !ok {
+		return r.defaultRoute
+	}
+	return r.routeFor(tracetranslator.AttributeValueToString(v, false), true)
+}
+
+// routeGroups keeps the routes used by a batch in the order they were first seen.
+type routeGroups struct {
+	routes  []*route
+	indexes map[*route]int
+}
+
+// group returns the index of the group of rt, and true if it is a new group.
+func (rg *routeGroups) group(rt *route) (int, bool) {
+	if i, ok := rg.indexes[rt]; ok {
+		return i, false
+	}
+	if rg.indexes == nil {
+		rg.indexes = make(map[*route]int)
+	}
+	rg.indexes[rt] = len(rg.routes)
+	rg.routes = append(rg.routes, rt)
+	return len(rg.routes) - 1, true
+}
+
+func (r *router) logDropped(resources int) {
+	if resources > 0 {
+		r.logger.Debug("Dropping data without a route", zap.Int("resources", resources))
+	}
+}
+
+func (r *router) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	if r.config.AttributeSource == ContextAttributeSource {
+		rt := r.contextRoute(ctx)
+		if rt == nil {
+			r.logDropped(td.ResourceSpans().Len())
+			return nil
+		}
+		return rt.traces.ConsumeTraces(ctx, td)
+	}
+
+	var rg routeGroups
+	var batches []pdata.Traces
+	dropped := 0
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		rt := r.resourceRoute(rs.Resource())
+		if rt == nil {
+			dropped++
+			continue
+		}
+		idx, isNew := rg.group(rt)
+		if isNew {
+			batches = append(batches, pdata.NewTraces())
+		}
+		batches[idx].ResourceSpans().Append(rs)
+	}
+	r.logDropped(dropped)
+
+	var errs []error
+	for i, rt := range rg.routes {
+		if err := rt.traces.ConsumeTraces(ctx, batches[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return componenterror.CombineErrors(errs)
+}
+
+func (r *router) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	if r.config.AttributeSource == ContextAttributeSource {
+		rt := r.contextRoute(ctx)
+		if rt == nil {
+			r.logDropped(md.ResourceMetrics().Len())
+			return nil
+		}
+		return rt.metrics.ConsumeMetrics(ctx, md)
+	}
+
+	var rg routeGroups
+	var batches []pdata.Metrics
+	dropped := 0
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		rt := r.resourceRoute(rm.Resource())
+		if rt == nil {
+			dropped++
+			continue
+		}
+		idx, isNew := rg.group(rt)
+		if isNew {
+			batches = append(batches, pdata.NewMetrics())
+		}
+		batches[idx].ResourceMetrics().Append(rm)
+	}
+	r.logDropped(dropped)
+
+	var errs []error
+	for i, rt := range rg.routes {
+		if err := rt.metrics.ConsumeMetrics(ctx, batches[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return componenterror.CombineErrors(errs)
+}
+
+func (r *router) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
+	if r.config.AttributeSource == ContextAttributeSource {
+		rt := r.contextRoute(ctx)
+		if rt == nil {
+			r.logDropped(ld.ResourceLogs().Len())
+			return nil
+		}
+		return rt.logs.ConsumeLogs(ctx, ld)
+	}
+
+	var rg routeGroups
+	var batches []pdata.Logs
+	dropped := 0
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		rt := r.resourceRoute(rl.Resource())
+		if rt == nil {
+			dropped++
+			continue
+		}
+		idx, isNew := rg.group(rt)
+		if isNew {
+			batches = append(batches, pdata.NewLogs())
+		}
+		batches[idx].ResourceLogs().Append(rl)
+	}
+	r.logDropped(dropped)
+
+	var errs []error
+	for i, rt := range rg.routes {
+		if err := rt.logs.ConsumeLogs(ctx, batches[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return componenterror.CombineErrors(errs)
+}
+
+// clientMetadataPrefix is the prefix of the attributes naming a metadata key of the client,
+// e.g. "metadata.x-tenant".
+const clientMetadataPrefix = "metadata."
+
+// isClientAttribute returns whether the name is a supported attribute of the client.
+func isClientAttribute(name string) bool {
+	name = strings.ToLower(name)
+	switch name {
+	case "ip", "receiver", "subject":
+		return true
+	}
+	return strings.HasPrefix(name, clientMetadataPrefix) && len(name) > len(clientMetadataPrefix)
+}
+
+// clientAttribute returns the value of the named attribute of the client. For the metadata
+// attributes, the first value of the key is returned.
+func clientAttribute(c *client.Client, name string) (string, bool) {
+	name = strings.ToLower(name)
+	switch name {
+	case "ip":
+		return c.IP, true
+	case "receiver":
+		return c.Receiver, true
+	case "subject":
+		return c.Subject, true
+	}
+
+	if strings.HasPrefix(name, clientMetadataPrefix) {
+		if values := c.Metadata[strings.TrimPrefix(name, clientMetadataPrefix)]; len(values) > 0 {
+			return values[0], true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,248 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routingprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/processor"
+)
+
+// mockHost exposes the exporters of the pipelines to the processor.
+type mockHost struct {
+	component.Host
+	exporters map[configmodels.DataType]map[configmodels.Exporter]component.Exporter
+}
+
+func (m *mockHost) GetExporters() map[configmodels.DataType]map[configmodels.Exporter]component.Exporter {
+	return m.exporters
+}
+
+// newMockHost returns a host with an exporter for each of the given names for
+// all the data types.
+func newMockHost(names ...string) (*mockHost, map[string]*componenttest.ExampleExporterConsumer) {
+	consumers := make(map[string]*componenttest.ExampleExporterConsumer)
+	exporters := make(map[configmodels.DataType]map[configmodels.Exporter]component.Exporter)
+	for _, dataType := range []configmodels.DataType{configmodels.TracesDataType, configmodels.MetricsDataType, configmodels.LogsDataType} {
+		exporters[dataType] = make(map[configmodels.Exporter]component.Exporter)
+	}
+	for _, name := range names {
+		exp := &componenttest.ExampleExporterConsumer{}
+		consumers[name] = exp
+		cfg := &componenttest.ExampleExporter{ExporterSettings: configmodels.ExporterSettings{TypeVal: "exampleexporter", NameVal: name}}
+		for _, byConfig := range exporters {
+			byConfig[cfg] = exp
+		}
+	}
+	return &mockHost{Host: componenttest.NewNopHost(), exporters: exporters}, consumers
+}
+
+// pipeline returns the fan-out to the given exporters, the next consumer of the router when
+// they are the exporters of its pipeline.
+func pipeline(dataType configmodels.DataType, exps map[string]*componenttest.ExampleExporterConsumer, names ...string) interface{} {
+	var tcs []consumer.TracesConsumer
+	var mcs []consumer.MetricsConsumer
+	var lcs []consumer.LogsConsumer
+	for _, name := range names {
+		tcs = append(tcs, exps[name])
+		mcs = append(mcs, exps[name])
+		lcs = append(lcs, exps[name])
+	}
+	switch dataType {
+	case configmodels.TracesDataType:
+		return processor.NewTracesFanOutConnector(tcs)
+	case configmodels.MetricsDataType:
+		return processor.NewMetricsFanOutConnector(mcs)
+	default:
+		return processor.NewLogsFanOutConnector(lcs)
+	}
+}
+
+func newTestConfig() *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.FromAttribute = "tenant"
+	cfg.DefaultExporters = []string{"default"}
+	cfg.Table = []RoutingTableItem{
+		{Value: "acme", Exporters: []string{"acme"}},
+		{Value: "globex", Exporters: []string{"acme", "globex"}},
+	}
+	return cfg
+}
+
+func TestNewRouter_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+	}{
+		{name: "no from_attribute", modify: func(cfg *Config) { cfg.FromAttribute = "" }},
+		{name: "unknown source", modify: func(cfg *Config) { cfg.AttributeSource = "span" }},
+		{name: "unknown client attribute", modify: func(cfg *Config) { cfg.AttributeSource = ContextAttributeSource }},
+		{name: "empty client metadata key", modify: func(cfg *Config) {
+			cfg.AttributeSource = ContextAttributeSource
+			cfg.FromAttribute = "metadata."
+		}},
+		{name: "no table", modify: func(cfg *Config) { cfg.Table = nil }},
+		{name: "route without exporters", modify: func(cfg *Config) { cfg.Table[0].Exporters = nil }},
+		{name: "duplicate value", modify: func(cfg *Config) { cfg.Table[1].Value = "acme" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig()
+			tt.modify(cfg)
+			_, err := newRouter(zap.NewNop(), cfg, configmodels.TracesDataType, consumertest.NewTracesNop())
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRouter_StartUnknownExporter(t *testing.T) {
+	host, exps := newMockHost("default", "acme")
+	r, err := newRouter(zap.NewNop(), newTestConfig(), configmodels.TracesDataType, pipeline(configmodels.TracesDataType, exps, "default", "acme"))
+	require.NoError(t, err)
+	assert.Error(t, r.Start(context.Background(), host))
+}
+
+func TestRouter_StartExporterOfAnotherPipeline(t *testing.T) {
+	host, exps := newMockHost("default", "acme", "globex")
+	// globex is an exporter of another pipeline
+	r, err := newRouter(zap.NewNop(), newTestConfig(), configmodels.TracesDataType, pipeline(configmodels.TracesDataType, exps, "default", "acme"))
+	require.NoError(t, err)
+	assert.Error(t, r.Start(context.Background(), host))
+}
+
+func TestRouter_StartNotLastProcessor(t *testing.T) {
+	host, _ := newMockHost("default", "acme", "globex")
+	r, err := newRouter(zap.NewNop(), newTestConfig(), configmodels.TracesDataType, consumertest.NewTracesNop())
+	require.NoError(t, err)
+	assert.Error(t, r.Start(context.Background(), host))
+}
+
+func TestRouter_RouteTracesByResourceAttribute(t *testing.T) {
+	host, exps := newMockHost("default", "acme", "globex")
+	r, err := newRouter(zap.NewNop(), newTestConfig(), configmodels.TracesDataType, pipeline(configmodels.TracesDataType, exps, "default", "acme", "globex"))
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), host))
+
+	td := pdata.NewTraces()
+	for _, tenant := range []string{"acme", "globex", "unknown", "acme", ""} {
+		rs := testdata.GenerateTraceDataOneSpan().ResourceSpans().At(0)
+		if tenant != "" {
+			rs.Resource().Attributes().UpsertString("tenant", tenant)
+		}
+		td.ResourceSpans().Append(rs)
+	}
+
+	require.NoError(t, r.ConsumeTraces(context.Background(), td))
+
+	require.Len(t, exps["acme"].Traces, 2)
+	assert.Equal(t, 2, exps["acme"].Traces[0].ResourceSpans().Len())
+	assert.Equal(t, 1, exps["acme"].Traces[1].ResourceSpans().Len())
+	require.Len(t, exps["globex"].Traces, 1)
+	assert.Equal(t, 1, exps["globex"].Traces[0].ResourceSpans().Len())
+	require.Len(t, exps["default"].Traces, 1)
+	assert.Equal(t, 2, exps["default"].Traces[0].ResourceSpans().Len())
+
+	require.NoError(t, r.Shutdown(context.Background()))
+}
+
+func TestRouter_DropWithoutDefaultRoute(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.DefaultExporters = nil
+	host, exps := newMockHost("acme", "globex")
+	r, err := newRouter(zap.NewNop(), cfg, configmodels.LogsDataType, pipeline(configmodels.LogsDataType, exps, "acme", "globex"))
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), host))
+
+	ld := testdata.GenerateLogDataTwoLogsSameResource()
+	require.NoError(t, r.ConsumeLogs(context.Background(), ld))
+	assert.Empty(t, exps["acme"].Logs)
+	assert.Empty(t, exps["globex"].Logs)
+
+	ld.ResourceLogs().At(0).Resource().Attributes().UpsertString("tenant", "globex")
+	require.NoError(t, r.ConsumeLogs(context.Background(), ld))
+	require.Len(t, exps["acme"].Logs, 1)
+	require.Len(t, exps["globex"].Logs, 1)
+	assert.Equal(t, 2, exps["globex"].Logs[0].LogRecordCount())
+}
+
+func TestRouter_RouteMetricsByContext(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.AttributeSource = ContextAttributeSource
+	cfg.FromAttribute = "ip"
+	cfg.Table = []RoutingTableItem{{Value: "10.0.0.1", Exporters: []string{"acme"}}}
+	host, exps := newMockHost("default", "acme")
+	r, err := newRouter(zap.NewNop(), cfg, configmodels.MetricsDataType, pipeline(configmodels.MetricsDataType, exps, "default", "acme"))
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), host))
+
+	md := testdata.GenerateMetricsOneMetric()
+	require.NoError(t, r.ConsumeMetrics(client.NewContext(context.Background(), &client.Client{IP: "10.0.0.1"}), md))
+	require.NoError(t, r.ConsumeMetrics(client.NewContext(context.Background(), &client.Client{IP: "10.0.0.2"}), md))
+	require.NoError(t, r.ConsumeMetrics(context.Background(), md))
+
+	assert.Len(t, exps["acme"].Metrics, 1)
+	assert.Len(t, exps["default"].Metrics, 2)
+}
+
+func TestRouter_RouteTracesByClientAttributes(t *testing.T) {
+	c := &client.Client{
+		IP:       "10.0.0.1",
+		Receiver: "otlp/internal",
+		Subject:  "agent-1",
+		Metadata: map[string][]string{"x-tenant": {"acme", "globex"}},
+	}
+	tests := []struct {
+		attribute string
+		value     string
+	}{
+		{attribute: "ip", value: "10.0.0.1"},
+		{attribute: "receiver", value: "otlp/internal"},
+		{attribute: "subject", value: "agent-1"},
+		{attribute: "metadata.x-tenant", value: "acme"},
+		{attribute: "Metadata.X-Tenant", value: "acme"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.attribute, func(t *testing.T) {
+			cfg := newTestConfig()
+			cfg.AttributeSource = ContextAttributeSource
+			cfg.FromAttribute = tt.attribute
+			cfg.Table = []RoutingTableItem{{Value: tt.value, Exporters: []string{"acme"}}}
+			host, exps := newMockHost("default", "acme")
+			r, err := newRouter(zap.NewNop(), cfg, configmodels.TracesDataType, pipeline(configmodels.TracesDataType, exps, "default", "acme"))
+			require.NoError(t, err)
+			require.NoError(t, r.Start(context.Background(), host))
+
+			td := testdata.GenerateTraceDataOneSpan()
+			require.NoError(t, r.ConsumeTraces(client.NewContext(context.Background(), c), td))
+			require.NoError(t, r.ConsumeTraces(client.NewContext(context.Background(), &client.Client{}), td))
+
+			assert.Len(t, exps["acme"].Traces, 1)
+			assert.Len(t, exps["default"].Traces, 1)
+		})
+	}
+}
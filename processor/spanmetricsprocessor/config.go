@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsprocessor
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Dimension defines an additional dimension of the metrics, taken from the
+// attributes of the spans.
+type Dimension struct {
+	// Name is the key of the span attribute, or of the resource attribute if
+	// the span doesn't have it. It is also the name of the label.
+	Name string `mapstructure:"name"`
+	// Default is the value of the label when neither the span nor its resource
+	// have the attribute. If not set, the label is omitted.
+	Default *string `mapstructure:"default"`
+}
+
+// Config defines the configuration for the Span metrics processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// MetricsExporter is the name of the metrics exporter receiving the
+	// generated metrics. It must be part of a metrics pipeline.
+	MetricsExporter string `mapstructure:"metrics_exporter"`
+
+	// LatencyHistogramBuckets are the upper bounds of the buckets of the
+	// latency histogram. If not set, buckets from 2ms to 10s are used.
+	LatencyHistogramBuckets []time.Duration `mapstructure:"latency_histogram_buckets"`
+
+	// Dimensions are the dimensions added to the default ones: service.name,
+	// operation, span.kind and status.code.
+	Dimensions []Dimension `mapstructure:"dimensions"`
+
+	// FlushInterval is the interval at which the metrics are sent to the
+	// metrics exporter.
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+
+	// MaxSeries is the maximum number of series kept in memory. Once reached,
+	// the spans of new series are not aggregated into the metrics.
+	MaxSeries int `mapstructure:"max_series"`
+}
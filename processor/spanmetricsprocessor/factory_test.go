@@ -0,0 +1,48 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	cfg := createDefaultConfig()
+	assert.NotNil(t, cfg, "failed to create default config")
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+}
+
+func TestCreateProcessor(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	params := component.ProcessorCreateParams{Logger: zap.NewNop()}
+
+	// The processor requires the metrics exporter.
+	tp, err := createTraceProcessor(context.Background(), params, cfg, consumertest.NewTracesNop())
+	assert.Nil(t, tp)
+	assert.Error(t, err)
+
+	cfg.MetricsExporter = "otlp"
+	tp, err = createTraceProcessor(context.Background(), params, cfg, consumertest.NewTracesNop())
+	assert.NotNil(t, tp)
+	assert.NoError(t, err, "cannot create trace processor")
+}
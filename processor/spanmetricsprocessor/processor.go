@@ -0,0 +1,378 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsprocessor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+const (
+	serviceNameLabel = conventions.AttributeServiceName
+	operationLabel   = "operation"
+	spanKindLabel    = "span.kind"
+	statusCodeLabel  = "status.code"
+
+	callsMetricName   = "calls_total"
+	errorsMetricName  = "errors_total"
+	latencyMetricName = "latency"
+
+	instrumentationLibraryName = "spanmetricsprocessor"
+)
+
+// label is a label of a series, the labels of a series are kept in the order
+// of the dimensions.
+type label struct {
+	key   string
+	value string
+}
+
+// series holds the aggregated values of the spans with the same labels.
+type series struct {
+	labels []label
+	calls  int64
+	errors int64
+	// bucketCounts has one more bucket than the bounds, for the values greater
+	// than the last bound.
+	bucketCounts []uint64
+	latencySum   float64
+}
+
+type processorImp struct {
+	logger       *zap.Logger
+	config       Config
+	nextConsumer consumer.TracesConsumer
+	// latencyBounds are the latency histogram bounds in milliseconds.
+	latencyBounds []float64
+
+	metricsExporter component.MetricsExporter
+	startTime       time.Time
+
+	mu     sync.Mutex
+	series map[string]*series
+	// droppedSpans is the number of spans not aggregated since the last flush
+	// because the series limit was reached.
+	droppedSpans int
+
+	shutdownC  chan struct{}
+	goroutines sync.WaitGroup
+}
+
+var _ component.TracesProcessor = (*processorImp)(nil)
+
+func newProcessor(logger *zap.Logger, cfg *Config, nextConsumer consumer.TracesConsumer) (*processorImp, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	if cfg.MetricsExporter == "" {
+		return nil, fmt.Errorf("metrics_exporter must be specified")
+	}
+	if cfg.FlushInterval <= 0 {
+		return nil, fmt.Errorf("flush_interval must be positive")
+	}
+	if cfg.MaxSeries <= 0 {
+		return nil, fmt.Errorf("max_series must be positive")
+	}
+
+	buckets := cfg.LatencyHistogramBuckets
+	if len(buckets) == 0 {
+		buckets = defaultLatencyHistogramBuckets
+	}
+	bounds := make([]float64, len(buckets))
+	for i, b := range buckets {
+		bounds[i] = float64(b) / float64(time.Millisecond)
+		if i > 0 && bounds[i] <= bounds[i-1] {
+			return nil, fmt.Errorf("latency_histogram_buckets must be sorted in increasing order")
+		}
+	}
+
+	names := map[string]bool{serviceNameLabel: true, operationLabel: true, spanKindLabel: true, statusCodeLabel: true}
+	for _, d := range cfg.Dimensions {
+		if d.Name == "" {
+			return nil, fmt.Errorf("dimensions must have a name")
+		}
+		if names[d.Name] {
+			return nil, fmt.Errorf("duplicate dimension %q", d.Name)
+		}
+		names[d.Name] = true
+	}
+
+	return &processorImp{
+		logger:        logger,
+		config:        *cfg,
+		nextConsumer:  nextConsumer,
+		latencyBounds: bounds,
+		series:        make(map[string]*series),
+		shutdownC:     make(chan struct{}),
+	}, nil
+}
+
+// Start finds the metrics exporter and starts sending the metrics periodically.
+func (p *processorImp) Start(_ context.Context, host component.Host) error {
+	for cfg, exp := range host.GetExporters()[configmodels.MetricsDataType] {
+		if cfg.Name() == p.config.MetricsExporter {
+			p.metricsExporter = exp.(component.MetricsExporter)
+			break
+		}
+	}
+	if p.metricsExporter == nil {
+		return fmt.Errorf("metrics exporter %q of processor %q not found in the metrics pipelines", p.config.MetricsExporter, p.config.Name())
+	}
+
+	p.startTime = time.Now()
+	p.goroutines.Add(1)
+	go p.flushLoop()
+	return nil
+}
+
+// Shutdown stops sending the metrics periodically, sending them a last time.
+func (p *processorImp) Shutdown(ctx context.Context) error {
+	if p.metricsExporter == nil {
+		return nil
+	}
+	close(p.shutdownC)
+	p.goroutines.Wait()
+	return p.flush(ctx)
+}
+
+func (p *processorImp) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: false}
+}
+
+// ConsumeTraces aggregates the spans into the metrics and forwards them to the
+// next consumer.
+func (p *processorImp) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	p.aggregate(td)
+	return p.nextConsumer.ConsumeTraces(ctx, td)
+}
+
+func (p *processorImp) aggregate(td pdata.Traces) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		resourceAttrs := rs.Resource().Attributes()
+		serviceName := ""
+		if v, ok := resourceAttrs.Get(conventions.AttributeServiceName); ok {
+			serviceName = v.StringVal()
+		}
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				p.aggregateSpan(serviceName, spans.At(k), resourceAttrs)
+			}
+		}
+	}
+}
+
+// aggregateSpan adds the span to its series, the span is dropped if it would
+// create a series beyond the limit. Must be called with the lock held.
+func (p *processorImp) aggregateSpan(serviceName string, span pdata.Span, resourceAttrs pdata.AttributeMap) {
+	labels := p.labels(serviceName, span, resourceAttrs)
+	key := seriesKey(labels)
+	s, ok := p.series[key]
+	if !ok {
+		if len(p.series) >= p.config.MaxSeries {
+			p.droppedSpans++
+			return
+		}
+		s = &series{labels: labels, bucketCounts: make([]uint64, len(p.latencyBounds)+1)}
+		p.series[key] = s
+	}
+
+	s.calls++
+	if span.Status().Code() == pdata.StatusCodeError {
+		s.errors++
+	}
+
+	var latency float64
+	if span.EndTime() > span.StartTime() {
+		latency = float64(span.EndTime()-span.StartTime()) / float64(time.Millisecond)
+	}
+	s.latencySum += latency
+	// The first bucket whose upper bound is greater than or equal to the latency.
+	s.bucketCounts[sort.SearchFloat64s(p.latencyBounds, latency)]++
+}
+
+func (p *processorImp) labels(serviceName string, span pdata.Span, resourceAttrs pdata.AttributeMap) []label {
+	labels := make([]label, 0, 4+len(p.config.Dimensions))
+	labels = append(labels,
+		label{key: serviceNameLabel, value: serviceName},
+		label{key: operationLabel, value: span.Name()},
+		label{key: spanKindLabel, value: span.Kind().String()},
+		label{key: statusCodeLabel, value: span.Status().Code().String()},
+	)
+	for _, d := range p.config.Dimensions {
+		v, ok := span.Attributes().Get(d.Name)
+		if !ok {
+			v, ok = resourceAttrs.Get(d.Name)
+		}
+		switch {
+		case ok:
+			labels = append(labels, label{key: d.Name, value: tracetranslator.AttributeValueToString(v, false)})
+		case d.Default != nil:
+			labels = append(labels, label{key: d.Name, value: *d.Default})
+		}
+	}
+	return labels
+}
+
+// seriesKey returns the key identifying the series with the given labels.
+func seriesKey(labels []label) string {
+	var sb strings.Builder
+	for _, l := range labels {
+		sb.WriteString(l.key)
+		sb.WriteByte(0)
+		sb.WriteString(l.value)
+		sb.WriteByte(0)
+	}
+	return sb.String()
+}
+
+func (p *processorImp) flushLoop() {
+	defer p.goroutines.Done()
+	ticker := time.NewTicker(p.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := p.flush(context.Background()); err != nil {
+				p.logger.Warn("Failed to export span metrics", zap.Error(err))
+			}
+		case <-p.shutdownC:
+			return
+		}
+	}
+}
+
+// flush sends the cumulative metrics of all the series to the metrics exporter.
+func (p *processorImp) flush(ctx context.Context) error {
+	md, ok := p.buildMetrics(time.Now())
+	if !ok {
+		return nil
+	}
+	return p.metricsExporter.ConsumeMetrics(ctx, md)
+}
+
+// buildMetrics returns the metrics of all the series, false if there is none.
+func (p *processorImp) buildMetrics(now time.Time) (pdata.Metrics, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.droppedSpans > 0 {
+		p.logger.Warn("Spans not aggregated into the metrics, the maximum number of series is reached",
+			zap.Int("dropped_spans", p.droppedSpans), zap.Int("max_series", p.config.MaxSeries))
+		p.droppedSpans = 0
+	}
+	if len(p.series) == 0 {
+		return pdata.Metrics{}, false
+	}
+
+	// Sort the series to produce the data points in a stable order.
+	keys := make([]string, 0, len(p.series))
+	for key := range p.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	startTime := pdata.TimestampUnixNano(p.startTime.UnixNano())
+	timestamp := pdata.TimestampUnixNano(now.UnixNano())
+
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	ilms := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics()
+	ilms.Resize(1)
+	ilm := ilms.At(0)
+	ilm.InstrumentationLibrary().SetName(instrumentationLibraryName)
+	metrics := ilm.Metrics()
+	metrics.Resize(3)
+
+	calls := metrics.At(0)
+	calls.SetName(callsMetricName)
+	calls.SetDescription("Number of spans")
+	calls.SetDataType(pdata.MetricDataTypeIntSum)
+	calls.IntSum().SetIsMonotonic(true)
+	calls.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+	errs := metrics.At(1)
+	errs.SetName(errorsMetricName)
+	errs.SetDescription("Number of spans with an error status")
+	errs.SetDataType(pdata.MetricDataTypeIntSum)
+	errs.IntSum().SetIsMonotonic(true)
+	errs.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+	latency := metrics.At(2)
+	latency.SetName(latencyMetricName)
+	latency.SetDescription("Duration of the spans")
+	latency.SetUnit("ms")
+	latency.SetDataType(pdata.MetricDataTypeDoubleHistogram)
+	latency.DoubleHistogram().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+	callsDps := calls.IntSum().DataPoints()
+	callsDps.Resize(len(keys))
+	errsDps := errs.IntSum().DataPoints()
+	errsDps.Resize(len(keys))
+	latencyDps := latency.DoubleHistogram().DataPoints()
+	latencyDps.Resize(len(keys))
+	for i, key := range keys {
+		s := p.series[key]
+
+		callsDp := callsDps.At(i)
+		callsDp.SetStartTime(startTime)
+		callsDp.SetTimestamp(timestamp)
+		callsDp.SetValue(s.calls)
+		insertLabels(callsDp.LabelsMap(), s.labels)
+
+		errsDp := errsDps.At(i)
+		errsDp.SetStartTime(startTime)
+		errsDp.SetTimestamp(timestamp)
+		errsDp.SetValue(s.errors)
+		insertLabels(errsDp.LabelsMap(), s.labels)
+
+		latencyDp := latencyDps.At(i)
+		latencyDp.SetStartTime(startTime)
+		latencyDp.SetTimestamp(timestamp)
+		latencyDp.SetCount(uint64(s.calls))
+		latencyDp.SetSum(s.latencySum)
+		latencyDp.SetBucketCounts(append([]uint64(nil), s.bucketCounts...))
+		latencyDp.SetExplicitBounds(append([]float64(nil), p.latencyBounds...))
+		insertLabels(latencyDp.LabelsMap(), s.labels)
+	}
+	return md, true
+}
+
+func insertLabels(labelsMap pdata.StringMap, labels []label) {
+	for _, l := range labels {
+		labelsMap.Insert(l.key, l.value)
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// mockHost exposes a metrics exporter to the processor.
+type mockHost struct {
+	component.Host
+	exporters map[configmodels.DataType]map[configmodels.Exporter]component.Exporter
+}
+
+func (m *mockHost) GetExporters() map[configmodels.DataType]map[configmodels.Exporter]component.Exporter {
+	return m.exporters
+}
+
+func newMockHost(name string, exp component.Exporter) *mockHost {
+	cfg := &componenttest.ExampleExporter{ExporterSettings: configmodels.ExporterSettings{TypeVal: "exampleexporter", NameVal: name}}
+	return &mockHost{
+		Host: componenttest.NewNopHost(),
+		exporters: map[configmodels.DataType]map[configmodels.Exporter]component.Exporter{
+			configmodels.MetricsDataType: {cfg: exp},
+		},
+	}
+}
+
+func newTestConfig() *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.MetricsExporter = "exampleexporter/metrics"
+	cfg.LatencyHistogramBuckets = []time.Duration{10 * time.Millisecond, 100 * time.Millisecond}
+	defaultMethod := "GET"
+	cfg.Dimensions = []Dimension{{Name: "http.method", Default: &defaultMethod}, {Name: "region"}}
+	return cfg
+}
+
+func addSpan(td pdata.Traces, service, name string, code pdata.StatusCode, duration time.Duration, method string) {
+	rss := td.ResourceSpans()
+	rss.Resize(rss.Len() + 1)
+	rs := rss.At(rss.Len() - 1)
+	rs.Resource().Attributes().InsertString(conventions.AttributeServiceName, service)
+	rs.Resource().Attributes().InsertString("region", "eu")
+	rs.InstrumentationLibrarySpans().Resize(1)
+	spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+	spans.Resize(1)
+	span := spans.At(0)
+	span.SetName(name)
+	span.SetKind(pdata.SpanKindSERVER)
+	span.Status().SetCode(code)
+	span.SetStartTime(pdata.TimestampUnixNano(time.Second))
+	span.SetEndTime(pdata.TimestampUnixNano(time.Second + duration))
+	if method != "" {
+		span.Attributes().InsertString("http.method", method)
+	}
+}
+
+func TestNewProcessor_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+	}{
+		{name: "no metrics exporter", modify: func(cfg *Config) { cfg.MetricsExporter = "" }},
+		{name: "no flush interval", modify: func(cfg *Config) { cfg.FlushInterval = 0 }},
+		{name: "unsorted buckets", modify: func(cfg *Config) { cfg.LatencyHistogramBuckets = []time.Duration{time.Second, time.Millisecond} }},
+		{name: "unnamed dimension", modify: func(cfg *Config) { cfg.Dimensions = []Dimension{{}} }},
+		{name: "no max series", modify: func(cfg *Config) { cfg.MaxSeries = 0 }},
+		{name: "duplicate dimension", modify: func(cfg *Config) { cfg.Dimensions = []Dimension{{Name: "operation"}} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig()
+			tt.modify(cfg)
+			_, err := newProcessor(zap.NewNop(), cfg, consumertest.NewTracesNop())
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestProcessor_StartUnknownExporter(t *testing.T) {
+	p, err := newProcessor(zap.NewNop(), newTestConfig(), consumertest.NewTracesNop())
+	require.NoError(t, err)
+	assert.Error(t, p.Start(context.Background(), newMockHost("exampleexporter", &componenttest.ExampleExporterConsumer{})))
+	assert.NoError(t, p.Shutdown(context.Background()))
+}
+
+func TestProcessor_AggregateSpans(t *testing.T) {
+	next := new(consumertest.TracesSink)
+	p, err := newProcessor(zap.NewNop(), newTestConfig(), next)
+	require.NoError(t, err)
+	exp := &componenttest.ExampleExporterConsumer{}
+	require.NoError(t, p.Start(context.Background(), newMockHost("exampleexporter/metrics", exp)))
+
+	td := pdata.NewTraces()
+	addSpan(td, "checkout", "pay", pdata.StatusCodeOk, 5*time.Millisecond, "POST")
+	addSpan(td, "checkout", "pay", pdata.StatusCodeOk, 50*time.Millisecond, "POST")
+	addSpan(td, "checkout", "pay", pdata.StatusCodeError, 500*time.Millisecond, "POST")
+	addSpan(td, "cart", "add", pdata.StatusCodeUnset, 10*time.Millisecond, "")
+	require.NoError(t, p.ConsumeTraces(context.Background(), td))
+	assert.Equal(t, 4, next.SpansCount())
+
+	// Shutdown sends the metrics a last time.
+	require.NoError(t, p.Shutdown(context.Background()))
+	require.Len(t, exp.Metrics, 1)
+
+	metrics := exp.Metrics[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 3, metrics.Len())
+	calls := metrics.At(0).IntSum()
+	errs := metrics.At(1).IntSum()
+	latency := metrics.At(2).DoubleHistogram()
+	assert.Equal(t, pdata.AggregationTemporalityCumulative, calls.AggregationTemporality())
+	assert.True(t, calls.IsMonotonic())
+
+	// One series for cart, and two for the pay operation with different status codes.
+	require.Equal(t, 3, calls.DataPoints().Len())
+	values := make(map[string]int64)
+	errValues := make(map[string]int64)
+	for i := 0; i < calls.DataPoints().Len(); i++ {
+		dp := calls.DataPoints().At(i)
+		service, _ := dp.LabelsMap().Get(conventions.AttributeServiceName)
+		status, _ := dp.LabelsMap().Get(statusCodeLabel)
+		values[service+"/"+status] = dp.Value()
+		errValues[service+"/"+status] = errs.DataPoints().At(i).Value()
+
+		method, _ := dp.LabelsMap().Get("http.method")
+		region, _ := dp.LabelsMap().Get("region")
+		assert.Equal(t, "eu", region)
+		if service == "cart" {
+			assert.Equal(t, "GET", method)
+		} else {
+			assert.Equal(t, "POST", method)
+		}
+	}
+	assert.Equal(t, map[string]int64{
+		"cart/STATUS_CODE_UNSET":     1,
+		"checkout/STATUS_CODE_OK":    2,
+		"checkout/STATUS_CODE_ERROR": 1,
+	}, values)
+	assert.Equal(t, map[string]int64{
+		"cart/STATUS_CODE_UNSET":     0,
+		"checkout/STATUS_CODE_OK":    0,
+		"checkout/STATUS_CODE_ERROR": 1,
+	}, errValues)
+
+	for i := 0; i < latency.DataPoints().Len(); i++ {
+		dp := latency.DataPoints().At(i)
+		service, _ := dp.LabelsMap().Get(conventions.AttributeServiceName)
+		status, _ := dp.LabelsMap().Get(statusCodeLabel)
+		assert.Equal(t, []float64{10, 100}, dp.ExplicitBounds())
+		switch service + "/" + status {
+		case "cart/STATUS_CODE_UNSET":
+			assert.Equal(t, []uint64{1, 0, 0}, dp.BucketCounts())
+		case "checkout/STATUS_CODE_OK":
+			assert.Equal(t, uint64(2), dp.Count())
+			assert.Equal(t, float64(55), dp.Sum())
+			assert.Equal(t, []uint64{1, 1, 0}, dp.BucketCounts())
+		case "checkout/STATUS_CODE_ERROR":
+			assert.Equal(t, []uint64{0, 0, 1}, dp.BucketCounts())
+		}
+	}
+}
+
+func TestProcessor_MaxSeries(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.MaxSeries = 2
+	next := new(consumertest.TracesSink)
+	p, err := newProcessor(zap.NewNop(), cfg, next)
+	require.NoError(t, err)
+	exp := &componenttest.ExampleExporterConsumer{}
+	require.NoError(t, p.Start(context.Background(), newMockHost("exampleexporter/metrics", exp)))
+
+	td := pdata.NewTraces()
+	addSpan(td, "checkout", "pay", pdata.StatusCodeOk, 5*time.Millisecond, "")
+	addSpan(td, "cart", "add", pdata.StatusCodeOk, 5*time.Millisecond, "")
+	// A third series is over the limit, but the existing ones are still updated.
+	addSpan(td, "cart", "remove", pdata.StatusCodeOk, 5*time.Millisecond, "")
+	addSpan(td, "checkout", "pay", pdata.StatusCodeOk, 5*time.Millisecond, "")
+	require.NoError(t, p.ConsumeTraces(context.Background(), td))
+	// The spans are forwarded regardless.
+	assert.Equal(t, 4, next.SpansCount())
+
+	require.NoError(t, p.Shutdown(context.Background()))
+	require.Len(t, exp.Metrics, 1)
+	calls := exp.Metrics[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0).IntSum()
+	require.Equal(t, 2, calls.DataPoints().Len())
+	values := make(map[string]int64)
+	for i := 0; i < calls.DataPoints().Len(); i++ {
+		dp := calls.DataPoints().At(i)
+		operation, _ := dp.LabelsMap().Get(operationLabel)
+		values[operation] = dp.Value()
+	}
+	assert.Equal(t, map[string]int64{"pay": 2, "add": 1}, values)
+}
+
+func TestProcessor_FlushPeriodically(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.FlushInterval = 10 * time.Millisecond
+	p, err := newProcessor(zap.NewNop(), cfg, consumertest.NewTracesNop())
+	require.NoError(t, err)
+	exp := &metricsSinkExporter{}
+	require.NoError(t, p.Start(context.Background(), newMockHost("exampleexporter/metrics", exp)))
+
+	td := pdata.NewTraces()
+	addSpan(td, "checkout", "pay", pdata.StatusCodeOk, 5*time.Millisecond, "")
+	require.NoError(t, p.ConsumeTraces(context.Background(), td))
+
+	// The metrics are cumulative, each flush sends the same series.
+	assert.Eventually(t, func() bool { return len(exp.AllMetrics()) >= 2 }, time.Second, time.Millisecond)
+	require.NoError(t, p.Shutdown(context.Background()))
+	for _, md := range exp.AllMetrics() {
+		assert.Equal(t, 3, md.MetricCount())
+	}
+}
+
+// metricsSinkExporter is a metrics exporter safe to use from the flush loop.
+type metricsSinkExporter struct {
+	consumertest.MetricsSink
+}
+
+func (e *metricsSinkExporter) Start(context.Context, component.Host) error {
+	return nil
+}
+
+func (e *metricsSinkExporter) Shutdown(context.Context) error {
+	return nil
+}
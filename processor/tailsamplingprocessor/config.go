@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/internal/processor/filterconfig"
+)
+
+// PolicyType indicates the type of sampling policy.
+type PolicyType string
+
+const (
+	// AlwaysSample samples all traces, typically used for debugging.
+	AlwaysSample PolicyType = "always_sample"
+	// Errors samples traces that contain at least one span with an error status.
+	Errors PolicyType = "errors"
+	// Latency samples traces whose duration is greater than or equal to a threshold.
+	Latency PolicyType = "latency"
+	// Attributes samples traces that contain at least one span matching a set of
+	// properties, see filterconfig.MatchProperties.
+	Attributes PolicyType = "attributes"
+	// RateLimiting samples traces as long as the number of sampled spans per second
+	// stays below a limit.
+	RateLimiting PolicyType = "rate_limiting"
+	// Probabilistic samples a percentage of the traces by hashing the trace ID.
+	Probabilistic PolicyType = "probabilistic"
+)
+
+// PolicyCfg holds the common configuration to all policies.
+type PolicyCfg struct {
+	// Name given to the instance of the policy, used in the metrics.
+	Name string `mapstructure:"name"`
+	// Type of the policy, this determines which of the settings below are used.
+	Type PolicyType `mapstructure:"type"`
+	// Latency contains the settings of a latency policy.
+	Latency LatencyCfg `mapstructure:"latency"`
+	// Match contains the properties a span must match to sample its trace,
+	// used by the attributes policy.
+	Match *filterconfig.MatchProperties `mapstructure:"match"`
+	// RateLimiting contains the settings of a rate limiting policy.
+	RateLimiting RateLimitingCfg `mapstructure:"rate_limiting"`
+	// Probabilistic contains the settings of a probabilistic policy.
+	Probabilistic ProbabilisticCfg `mapstructure:"probabilistic"`
+}
+
+// LatencyCfg holds the configurable settings to create a latency policy.
+type LatencyCfg struct {
+	// Threshold is the minimum duration, from the earliest span start to the
+	// latest span end, of a trace to be sampled.
+	Threshold time.Duration `mapstructure:"threshold"`
+}
+
+// RateLimitingCfg holds the configurable settings to create a rate limiting policy.
+type RateLimitingCfg struct {
+	// SpansPerSecond sets the limit on the maximum number of spans that can be
+	// sampled per second by the policy.
+	SpansPerSecond int64 `mapstructure:"spans_per_second"`
+}
+
+// ProbabilisticCfg holds the configurable settings to create a probabilistic policy.
+type ProbabilisticCfg struct {
+	// SamplingPercentage is the percentage rate at which traces are going to be
+	// sampled. Values greater or equal 100 are treated as "sample all traces",
+	// negative values are invalid.
+	SamplingPercentage float32 `mapstructure:"sampling_percentage"`
+	// HashSeed allows one to configure the hashing seed, see the
+	// probabilistic_sampler processor.
+	HashSeed uint32 `mapstructure:"hash_seed"`
+}
+
+// Config holds the configuration for tail-based sampling.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+	// DecisionWait is the time, since the first span of a trace is received, to wait
+	// before making the sampling decision for that trace.
+	DecisionWait time.Duration `mapstructure:"decision_wait"`
+	// NumTraces is the number of traces kept in memory. When the limit is reached
+	// the oldest trace is evicted; if it was still waiting for a decision the
+	// decision is made immediately.
+	NumTraces uint64 `mapstructure:"num_traces"`
+	// Policies are the sampling policies, a trace is sampled if any of them
+	// decides to sample it.
+	Policies []PolicyCfg `mapstructure:"policies"`
+}
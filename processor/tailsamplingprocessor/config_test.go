@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/internal/processor/filterconfig"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Processors[typeStr] = factory
+	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+
+	require.Nil(t, err)
+	require.NotNil(t, cfg)
+
+	p0 := cfg.Processors["tail_sampling"]
+	assert.Equal(t, p0,
+		&Config{
+			ProcessorSettings: configmodels.ProcessorSettings{
+				TypeVal: "tail_sampling",
+				NameVal: "tail_sampling",
+			},
+			DecisionWait: 10 * time.Second,
+			NumTraces:    100,
+			Policies: []PolicyCfg{
+				{
+					Name: "all-errors",
+					Type: Errors,
+				},
+				{
+					Name:    "slow-traces",
+					Type:    Latency,
+					Latency: LatencyCfg{Threshold: 500 * time.Millisecond},
+				},
+				{
+					Name: "checkout-service",
+					Type: Attributes,
+					Match: &filterconfig.MatchProperties{
+						Config:     filterset.Config{MatchType: filterset.Strict},
+						Services:   []string{"checkout"},
+						Attributes: []filterconfig.Attribute{{Key: "http.method", Value: "POST"}},
+					},
+				},
+				{
+					Name:         "limit",
+					Type:         RateLimiting,
+					RateLimiting: RateLimitingCfg{SpansPerSecond: 35},
+				},
+				{
+					Name:          "ten-percent",
+					Type:          Probabilistic,
+					Probabilistic: ProbabilisticCfg{SamplingPercentage: 10, HashSeed: 22},
+				},
+			},
+		})
+}
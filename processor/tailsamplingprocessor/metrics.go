@@ -0,0 +1,90 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/processor"
+)
+
+var (
+	tagPolicyKey, _  = tag.NewKey("policy")
+	tagSampledKey, _ = tag.NewKey("sampled")
+
+	statCountTracesSampled = stats.Int64("count_traces_sampled", "Count of traces that were sampled or not, per sampling policy", stats.UnitDimensionless)
+	statCountLateSpans     = stats.Int64("count_late_spans", "Count of spans received after the sampling decision of their trace", stats.UnitDimensionless)
+	statCountTracesEvicted = stats.Int64("count_traces_evicted", "Count of traces evicted from memory, including the ones decided early because of the eviction", stats.UnitDimensionless)
+	statTracesOnMemory     = stats.Int64("traces_on_memory", "Number of traces currently kept in memory", stats.UnitDimensionless)
+	statDecisionLatency    = stats.Int64("decision_latency", "Time from the first span of a trace until its sampling decision", stats.UnitMilliseconds)
+)
+
+// MetricViews returns the metrics views related to tail sampling.
+func MetricViews() []*view.View {
+	processorTagKeys := []tag.Key{processor.TagProcessorNameKey}
+
+	countTracesSampledView := &view.View{
+		Name:        statCountTracesSampled.Name(),
+		Measure:     statCountTracesSampled,
+		Description: statCountTracesSampled.Description(),
+		TagKeys:     []tag.Key{processor.TagProcessorNameKey, tagPolicyKey, tagSampledKey},
+		Aggregation: view.Sum(),
+	}
+
+	countLateSpansView := &view.View{
+		Name:        statCountLateSpans.Name(),
+		Measure:     statCountLateSpans,
+		Description: statCountLateSpans.Description(),
+		TagKeys:     []tag.Key{processor.TagProcessorNameKey, tagSampledKey},
+		Aggregation: view.Sum(),
+	}
+
+	countTracesEvictedView := &view.View{
+		Name:        statCountTracesEvicted.Name(),
+		Measure:     statCountTracesEvicted,
+		Description: statCountTracesEvicted.Description(),
+		TagKeys:     processorTagKeys,
+		Aggregation: view.Sum(),
+	}
+
+	tracesOnMemoryView := &view.View{
+		Name:        statTracesOnMemory.Name(),
+		Measure:     statTracesOnMemory,
+		Description: statTracesOnMemory.Description(),
+		TagKeys:     processorTagKeys,
+		Aggregation: view.LastValue(),
+	}
+
+	distributionDecisionLatencyView := &view.View{
+		Name:        statDecisionLatency.Name(),
+		Measure:     statDecisionLatency,
+		Description: statDecisionLatency.Description(),
+		TagKeys:     processorTagKeys,
+		Aggregation: view.Distribution(100, 500, 1000, 2000, 5000, 10000, 20000, 30000, 60000, 120000, 300000),
+	}
+
+	legacyViews := []*view.View{
+		countTracesSampledView,
+		countLateSpansView,
+		countTracesEvictedView,
+		tracesOnMemoryView,
+		distributionDecisionLatencyView,
+	}
+
+	return obsreport.ProcessorMetricViews(typeStr, legacyViews)
+}
@@ -0,0 +1,35 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTailSamplingProcessorMetrics(t *testing.T) {
+	viewNames := []string{
+		"count_traces_sampled",
+		"count_late_spans",
+		"count_traces_evicted",
+		"traces_on_memory",
+		"decision_latency",
+	}
+	views := MetricViews()
+	for i, viewName := range viewNames {
+		assert.Equal(t, "processor/tail_sampling/"+viewName, views[i].Name)
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/filterspan"
+)
+
+const (
+	// The constants help translate user friendly percentages to numbers direct used in sampling.
+	numHashBuckets        = 0x4000 // Using a power of 2 to avoid division.
+	bitMaskHashBuckets    = numHashBuckets - 1
+	percentageScaleFactor = numHashBuckets / 100.0
+)
+
+// policyEvaluator decides if a trace must be sampled.
+type policyEvaluator interface {
+	// evaluate returns true if the given trace must be sampled.
+	evaluate(trace *traceData) bool
+}
+
+// policy is a named instance of a policyEvaluator.
+type policy struct {
+	name      string
+	evaluator policyEvaluator
+	// lastResort policies are evaluated after the other ones, only for the traces that
+	// are not sampled yet, so that they don't spend their budget on traces sampled anyway.
+	lastResort bool
+}
+
+func newPolicies(cfgs []PolicyCfg) ([]*policy, error) {
+	if len(cfgs) == 0 {
+		return nil, fmt.Errorf("at least one policy must be configured")
+	}
+
+	names := make(map[string]bool, len(cfgs))
+	policies := make([]*policy, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		if cfg.Name == "" {
+			return nil, fmt.Errorf("policy of type %q must have a name", cfg.Type)
+		}
+		if names[cfg.Name] {
+			return nil, fmt.Errorf("duplicate policy name %q", cfg.Name)
+		}
+		names[cfg.Name] = true
+
+		evaluator, err := newPolicyEvaluator(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid policy %q: %w", cfg.Name, err)
+		}
+		policies = append(policies, &policy{name: cfg.Name, evaluator: evaluator, lastResort: cfg.Type == RateLimiting})
+	}
+	return policies, nil
+}
+
+func newPolicyEvaluator(cfg PolicyCfg) (policyEvaluator, error) {
+	switch cfg.Type {
+	case AlwaysSample:
+		return alwaysSample{}, nil
+	case Errors:
+		return errorsPolicy{}, nil
+	case Latency:
+		if cfg.Latency.Threshold <= 0 {
+			return nil, fmt.Errorf("latency threshold must be positive")
+		}
+		return &latencyPolicy{threshold: cfg.Latency.Threshold}, nil
+	case Attributes:
+		if cfg.Match == nil {
+			return nil, fmt.Errorf("match properties must be specified")
+		}
+		if err := cfg.Match.ValidateForSpans(); err != nil {
+			return nil, err
+		}
+		matcher, err := filterspan.NewMatcher(cfg.Match)
+		if err != nil {
+			return nil, err
+		}
+		return &attributesPolicy{matcher: matcher}, nil
+	case RateLimiting:
+		if cfg.RateLimiting.SpansPerSecond <= 0 {
+			return nil, fmt.Errorf("spans_per_second must be positive")
+		}
+		return &rateLimitingPolicy{spansPerSecond: cfg.RateLimiting.SpansPerSecond, now: time.Now}, nil
+	case Probabilistic:
+		if cfg.Probabilistic.SamplingPercentage < 0 {
+			return nil, fmt.Errorf("sampling_percentage must not be negative")
+		}
+		return &probabilisticPolicy{
+			scaledSamplingRate: uint32(cfg.Probabilistic.SamplingPercentage * percentageScaleFactor),
+			hashSeed:           cfg.Probabilistic.HashSeed,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown policy type %q", cfg.Type)
+	}
+}
+
+type alwaysSample struct{}
+
+func (alwaysSample) evaluate(*traceData) bool {
+	return true
+}
+
+type errorsPolicy struct{}
+
+func (errorsPolicy) evaluate(trace *traceData) bool {
+	return trace.anySpan(func(span pdata.Span, _ pdata.Resource, _ pdata.InstrumentationLibrary) bool {
+		return span.Status().Code() == pdata.StatusCodeError
+	})
+}
+
+type latencyPolicy struct {
+	threshold time.Duration
+}
+
+func (lp *latencyPolicy) evaluate(trace *traceData) bool {
+	var start, end pdata.TimestampUnixNano
+	trace.anySpan(func(span pdata.Span, _ pdata.Resource, _ pdata.InstrumentationLibrary) bool {
+		if start == 0 || span.StartTime() < start {
+			start = span.StartTime()
+		}
+		if span.EndTime() > end {
+			end = span.EndTime()
+		}
+		return false
+	})
+	return end > start && time.Duration(end-start) >= lp.threshold
+}
+
+type attributesPolicy struct {
+	matcher filterspan.Matcher
+}
+
+func (ap *attributesPolicy) evaluate(trace *traceData) bool {
+	return trace.anySpan(ap.matcher.MatchSpan)
+}
+
+// rateLimitingPolicy samples traces until the number of spans sampled during the
+// current second reaches the limit.
+type rateLimitingPolicy struct {
+	spansPerSecond int64
+	now            func() time.Time
+
+	currentSecond int64
+	spansInSecond int64
+}
+
+func (rlp *rateLimitingPolicy) evaluate(trace *traceData) bool {
+	second := rlp.now().Unix()
+	if second != rlp.currentSecond {
+		rlp.currentSecond = second
+		rlp.spansInSecond = 0
+	}
+
+	spans := int64(trace.spanCount)
+	if rlp.spansInSecond+spans > rlp.spansPerSecond {
+		return false
+	}
+	rlp.spansInSecond += spans
+	return true
+}
+
+type probabilisticPolicy struct {
+	scaledSamplingRate uint32
+	hashSeed           uint32
+}
+
+func (pp *probabilisticPolicy) evaluate(trace *traceData) bool {
+	var seed [4]byte
+	binary.LittleEndian.PutUint32(seed[:], pp.hashSeed)
+	tid := trace.traceID.Bytes()
+
+	// Hashing avoids bias from sources that do not generate random trace IDs.
+	h := fnv.New32a()
+	_, _ = h.Write(seed[:])
+	_, _ = h.Write(tid[:])
+	return h.Sum32()&bitMaskHashBuckets < pp.scaledSamplingRate
+}
@@ -0,0 +1,106 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/filterconfig"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+)
+
+func newTraceData(td pdata.Traces) *traceData {
+	id := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).TraceID()
+	return &traceData{traceID: id, batches: []pdata.Traces{td}, spanCount: td.SpanCount()}
+}
+
+func firstSpan(trace *traceData) pdata.Span {
+	return trace.batches[0].ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+}
+
+func TestErrorsPolicy(t *testing.T) {
+	trace := newTraceData(newTraces([]string{"svc"}, traceID(1), traceID(1)))
+	assert.False(t, errorsPolicy{}.evaluate(trace))
+	firstSpan(trace).Status().SetCode(pdata.StatusCodeError)
+	assert.True(t, errorsPolicy{}.evaluate(trace))
+}
+
+func TestLatencyPolicy(t *testing.T) {
+	trace := newTraceData(newTraces([]string{"frontend", "backend"}, traceID(1)))
+	policy := &latencyPolicy{threshold: time.Second}
+	start := pdata.TimestampUnixNano(time.Now().UnixNano())
+	firstSpan(trace).SetStartTime(start)
+	firstSpan(trace).SetEndTime(start + pdata.TimestampUnixNano(100*time.Millisecond))
+	backend := trace.batches[0].ResourceSpans().At(1).InstrumentationLibrarySpans().At(0).Spans().At(0)
+	backend.SetStartTime(start + pdata.TimestampUnixNano(10*time.Millisecond))
+	backend.SetEndTime(start + pdata.TimestampUnixNano(500*time.Millisecond))
+	assert.False(t, policy.evaluate(trace))
+
+	backend.SetEndTime(start + pdata.TimestampUnixNano(time.Second))
+	assert.True(t, policy.evaluate(trace))
+}
+
+func TestAttributesPolicy(t *testing.T) {
+	evaluator, err := newPolicyEvaluator(PolicyCfg{
+		Type: Attributes,
+		Match: &filterconfig.MatchProperties{
+			Config:     filterset.Config{MatchType: filterset.Strict},
+			Services:   []string{"backend"},
+			Attributes: []filterconfig.Attribute{{Key: "http.method", Value: "POST"}},
+		},
+	})
+	require.NoError(t, err)
+
+	trace := newTraceData(newTraces([]string{"frontend", "backend"}, traceID(1)))
+	assert.False(t, evaluator.evaluate(trace))
+	firstSpan(trace).Attributes().InsertString("http.method", "POST")
+	assert.False(t, evaluator.evaluate(trace))
+	backend := trace.batches[0].ResourceSpans().At(1).InstrumentationLibrarySpans().At(0).Spans().At(0)
+	backend.Attributes().InsertString("http.method", "POST")
+	assert.True(t, evaluator.evaluate(trace))
+}
+
+func TestRateLimitingPolicy(t *testing.T) {
+	now := time.Unix(1000, 0)
+	policy := &rateLimitingPolicy{spansPerSecond: 3, now: func() time.Time { return now }}
+	trace := newTraceData(newTraces([]string{"svc"}, traceID(1), traceID(1)))
+
+	assert.True(t, policy.evaluate(trace))
+	assert.False(t, policy.evaluate(trace))
+	now = now.Add(time.Second)
+	assert.True(t, policy.evaluate(trace))
+}
+
+func TestProbabilisticPolicy(t *testing.T) {
+	none := &probabilisticPolicy{scaledSamplingRate: 0}
+	all := &probabilisticPolicy{scaledSamplingRate: uint32(100 * percentageScaleFactor)}
+	half := &probabilisticPolicy{scaledSamplingRate: uint32(50 * percentageScaleFactor), hashSeed: 22}
+
+	sampled := 0
+	for i := 0; i < 256; i++ {
+		trace := newTraceData(newTraces([]string{"svc"}, traceID(byte(i))))
+		assert.False(t, none.evaluate(trace))
+		assert.True(t, all.evaluate(trace))
+		if half.evaluate(trace) {
+			sampled++
+		}
+	}
+	assert.InDelta(t, 128, sampled, 40)
+}
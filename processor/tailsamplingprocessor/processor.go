@@ -0,0 +1,315 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/batchpersignal"
+	"go.opentelemetry.io/collector/processor"
+)
+
+// decision is the sampling decision of a trace.
+type decision int
+
+const (
+	// pending means that the decision wait of the trace did not elapse yet.
+	pending decision = iota
+	// sampled means that the spans of the trace are forwarded.
+	sampled
+	// notSampled means that the spans of the trace are dropped.
+	notSampled
+)
+
+// defaultDecisionTickInterval is the interval at which traces are checked for an
+// elapsed decision wait.
+const defaultDecisionTickInterval = 100 * time.Millisecond
+
+// traceData keeps the spans received for a trace until its sampling decision is
+// made, and the decision afterwards so spans arriving late are handled the same way.
+type traceData struct {
+	traceID   pdata.TraceID
+	arrival   time.Time
+	spanCount int
+	// batches hold the spans of the trace as they were received, grouped by
+	// resource and instrumentation library.
+	batches  []pdata.Traces
+	decision decision
+}
+
+// anySpan calls f for each span of the trace until it returns true, in which case
+// anySpan also returns true.
+func (td *traceData) anySpan(f func(span pdata.Span, resource pdata.Resource, library pdata.InstrumentationLibrary) bool) bool {
+	for _, batch := range td.batches {
+		rss := batch.ResourceSpans()
+		for i := 0; i < rss.Len(); i++ {
+			rs := rss.At(i)
+			ilss := rs.InstrumentationLibrarySpans()
+			for j := 0; j < ilss.Len(); j++ {
+				ils := ilss.At(j)
+				spans := ils.Spans()
+				for k := 0; k < spans.Len(); k++ {
+					if f(spans.At(k), rs.Resource(), ils.InstrumentationLibrary()) {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+type tailSamplingSpanProcessor struct {
+	name         string
+	logger       *zap.Logger
+	nextConsumer consumer.TracesConsumer
+	decisionWait time.Duration
+	numTraces    int
+	policies     []*policy
+	tickInterval time.Duration
+
+	mu     sync.Mutex
+	traces map[pdata.TraceID]*traceData
+	// order contains the IDs of the traces in memory, oldest first.
+	order []pdata.TraceID
+	// pendingTraces contains the traces waiting for a decision, oldest first.
+	pendingTraces []*traceData
+
+	shutdownC  chan struct{}
+	goroutines sync.WaitGroup
+}
+
+var _ component.TracesProcessor = (*tailSamplingSpanProcessor)(nil)
+
+// newTraceProcessor returns a processor.TracesProcessor that will buffer the spans of
+// each trace for the configured decision wait and forward only the sampled traces.
+func newTraceProcessor(logger *zap.Logger, nextConsumer consumer.TracesConsumer, cfg Config) (*tailSamplingSpanProcessor, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	if cfg.DecisionWait <= 0 {
+		return nil, fmt.Errorf("decision_wait must be positive")
+	}
+	if cfg.NumTraces == 0 {
+		return nil, fmt.Errorf("num_traces must be greater than zero")
+	}
+
+	policies, err := newPolicies(cfg.Policies)
+	if err != nil {
+		return nil, err
+	}
+
+	tickInterval := defaultDecisionTickInterval
+	if cfg.DecisionWait < tickInterval {
+		tickInterval = cfg.DecisionWait
+	}
+
+	return &tailSamplingSpanProcessor{
+		name:         cfg.Name(),
+		logger:       logger,
+		nextConsumer: nextConsumer,
+		decisionWait: cfg.DecisionWait,
+		numTraces:    int(cfg.NumTraces),
+		policies:     policies,
+		tickInterval: tickInterval,
+		traces:       make(map[pdata.TraceID]*traceData),
+		shutdownC:    make(chan struct{}),
+	}, nil
+}
+
+func (tsp *tailSamplingSpanProcessor) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	var toSend []pdata.Traces
+	now := time.Now()
+
+	tsp.mu.Lock()
+	for _, batch := range batchpersignal.SplitTraces(td) {
+		traceID := batch.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).TraceID()
+		trace, ok := tsp.traces[traceID]
+		if !ok {
+			if len(tsp.traces) >= tsp.numTraces {
+				toSend = append(toSend, tsp.evictOldest()...)
+			}
+			trace = &traceData{traceID: traceID, arrival: now}
+			tsp.traces[traceID] = trace
+			tsp.order = append(tsp.order, traceID)
+			tsp.pendingTraces = append(tsp.pendingTraces, trace)
+		}
+
+		switch trace.decision {
+		case pending:
+			trace.batches = append(trace.batches, batch)
+			trace.spanCount += batch.SpanCount()
+		case sampled:
+			toSend = append(toSend, batch)
+			tsp.recordLateSpans(batch.SpanCount(), true)
+		case notSampled:
+			tsp.recordLateSpans(batch.SpanCount(), false)
+		}
+	}
+	tsp.mu.Unlock()
+
+	return tsp.send(ctx, toSend)
+}
+
+// evictOldest removes the oldest trace from memory, making its decision if it was
+// still pending. Returns the spans to forward if the trace was sampled.
+// Must be called with the lock held.
+func (tsp *tailSamplingSpanProcessor) evictOldest() []pdata.Traces {
+	traceID := tsp.order[0]
+	tsp.order = tsp.order[1:]
+	trace := tsp.traces[traceID]
+	delete(tsp.traces, traceID)
+	tsp.recordWithTags(nil, statCountTracesEvicted.M(1))
+
+	if trace.decision != pending {
+		return nil
+	}
+	return tsp.decide(trace)
+}
+
+// decide evaluates the policies for the trace, returning its spans if it is
+// sampled. The last resort policies are only evaluated if no other policy
+// sampled the trace. Must be called with the lock held.
+func (tsp *tailSamplingSpanProcessor) decide(trace *traceData) []pdata.Traces {
+	trace.decision = notSampled
+	for _, lastResort := range []bool{false, true} {
+		for _, p := range tsp.policies {
+			if p.lastResort != lastResort || (lastResort && trace.decision == sampled) {
+				continue
+			}
+			policySampled := p.evaluator.evaluate(trace)
+			if policySampled {
+				trace.decision = sampled
+			}
+			tsp.recordWithTags(
+				[]tag.Mutator{tag.Insert(tagPolicyKey, p.name), tag.Insert(tagSampledKey, strconv.FormatBool(policySampled))},
+				statCountTracesSampled.M(1))
+		}
+	}
+	tsp.recordWithTags(nil, statDecisionLatency.M(int64(time.Since(trace.arrival)/time.Millisecond)))
+
+	batches := trace.batches
+	trace.batches = nil
+	if trace.decision != sampled {
+		return nil
+	}
+	return batches
+}
+
+// decideElapsed makes the decision for all the traces whose decision wait elapsed.
+func (tsp *tailSamplingSpanProcessor) decideElapsed(now time.Time) []pdata.Traces {
+	var toSend []pdata.Traces
+
+	tsp.mu.Lock()
+	defer tsp.mu.Unlock()
+	for len(tsp.pendingTraces) > 0 && now.Sub(tsp.pendingTraces[0].arrival) >= tsp.decisionWait {
+		trace := tsp.pendingTraces[0]
+		tsp.pendingTraces = tsp.pendingTraces[1:]
+		// The decision may have been made already if the trace was evicted.
+		if trace.decision == pending {
+			toSend = append(toSend, tsp.decide(trace)...)
+		}
+	}
+	tsp.recordWithTags(nil, statTracesOnMemory.M(int64(len(tsp.traces))))
+	return toSend
+}
+
+// decideAll makes the decision for all the pending traces regardless of their
+// decision wait, used on shutdown.
+func (tsp *tailSamplingSpanProcessor) decideAll() []pdata.Traces {
+	var toSend []pdata.Traces
+
+	tsp.mu.Lock()
+	defer tsp.mu.Unlock()
+	for _, trace := range tsp.pendingTraces {
+		if trace.decision == pending {
+			toSend = append(toSend, tsp.decide(trace)...)
+		}
+	}
+	tsp.pendingTraces = nil
+	return toSend
+}
+
+func (tsp *tailSamplingSpanProcessor) decisionLoop() {
+	defer tsp.goroutines.Done()
+	ticker := time.NewTicker(tsp.tickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			if err := tsp.send(context.Background(), tsp.decideElapsed(now)); err != nil {
+				tsp.logger.Warn("Failed to forward sampled traces", zap.Error(err))
+			}
+		case <-tsp.shutdownC:
+			return
+		}
+	}
+}
+
+// send merges the given batches, keeping their resource grouping, and forwards them
+// to the next consumer.
+func (tsp *tailSamplingSpanProcessor) send(ctx context.Context, batches []pdata.Traces) error {
+	if len(batches) == 0 {
+		return nil
+	}
+	td := pdata.NewTraces()
+	for _, batch := range batches {
+		batch.ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+	}
+	return tsp.nextConsumer.ConsumeTraces(ctx, td)
+}
+
+func (tsp *tailSamplingSpanProcessor) recordLateSpans(count int, sampled bool) {
+	tsp.recordWithTags(
+		[]tag.Mutator{tag.Insert(tagSampledKey, strconv.FormatBool(sampled))},
+		statCountLateSpans.M(int64(count)))
+}
+
+func (tsp *tailSamplingSpanProcessor) recordWithTags(mutators []tag.Mutator, ms ...stats.Measurement) {
+	mutators = append(mutators, tag.Insert(processor.TagProcessorNameKey, tsp.name))
+	_ = stats.RecordWithTags(context.Background(), mutators, ms...)
+}
+
+func (tsp *tailSamplingSpanProcessor) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: false}
+}
+
+// Start is invoked during service startup.
+func (tsp *tailSamplingSpanProcessor) Start(context.Context, component.Host) error {
+	tsp.goroutines.Add(1)
+	go tsp.decisionLoop()
+	return nil
+}
+
+// Shutdown is invoked during service shutdown. The decision is made for all the
+// pending traces and the sampled ones are forwarded.
+func (tsp *tailSamplingSpanProcessor) Shutdown(ctx context.Context) error {
+	close(tsp.shutdownC)
+	tsp.goroutines.Wait()
+	return tsp.send(ctx, tsp.decideAll())
+}
@@ -0,0 +1,182 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func newTestConfig(policies ...PolicyCfg) Config {
+	cfg := *createDefaultConfig().(*Config)
+	cfg.DecisionWait = time.Hour
+	cfg.Policies = policies
+	return cfg
+}
+
+func traceID(b byte) pdata.TraceID {
+	return pdata.NewTraceID([16]byte{b, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, b})
+}
+
+// newTraces creates one resource per service, each one containing one span per trace ID.
+func newTraces(services []string, ids ...pdata.TraceID) pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(len(services))
+	for i, service := range services {
+		rs := td.ResourceSpans().At(i)
+		rs.Resource().Attributes().InsertString("service.name", service)
+		rs.InstrumentationLibrarySpans().Resize(1)
+		spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+		spans.Resize(len(ids))
+		for j, id := range ids {
+			spans.At(j).SetTraceID(id)
+			spans.At(j).SetName(service)
+		}
+	}
+	return td
+}
+
+func TestTailSampling_DecisionAfterWait(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	cfg := newTestConfig(PolicyCfg{Name: "errors", Type: Errors})
+	cfg.DecisionWait = 50 * time.Millisecond
+	tsp, err := newTraceProcessor(zap.NewNop(), sink, cfg)
+	require.NoError(t, err)
+	require.NoError(t, tsp.Start(context.Background(), componenttest.NewNopHost()))
+
+	td := newTraces([]string{"frontend", "backend"}, traceID(1), traceID(2))
+	errSpan := td.ResourceSpans().At(1).InstrumentationLibrarySpans().At(0).Spans().At(0)
+	errSpan.Status().SetCode(pdata.StatusCodeError)
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), td))
+	assert.Equal(t, 0, sink.SpansCount())
+
+	assert.Eventually(t, func() bool { return sink.SpansCount() == 2 }, time.Second, time.Millisecond)
+	got := sink.AllTraces()[0]
+	require.Equal(t, 2, got.ResourceSpans().Len())
+	for i := 0; i < got.ResourceSpans().Len(); i++ {
+		span := got.ResourceSpans().At(i).InstrumentationLibrarySpans().At(0).Spans().At(0)
+		assert.Equal(t, traceID(1), span.TraceID())
+	}
+
+	// Late spans follow the decision of their trace.
+	sink.Reset()
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), newTraces([]string{"late"}, traceID(1), traceID(2))))
+	assert.Equal(t, 1, sink.SpansCount())
+	assert.Equal(t, traceID(1), sink.AllTraces()[0].ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).TraceID())
+
+	require.NoError(t, tsp.Shutdown(context.Background()))
+}
+
+func TestTailSampling_EvictionDecidesEarly(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	cfg := newTestConfig(PolicyCfg{Name: "all", Type: AlwaysSample})
+	cfg.NumTraces = 2
+	tsp, err := newTraceProcessor(zap.NewNop(), sink, cfg)
+	require.NoError(t, err)
+
+	for i := byte(1); i <= 3; i++ {
+		require.NoError(t, tsp.ConsumeTraces(context.Background(), newTraces([]string{"svc"}, traceID(i))))
+	}
+	assert.Len(t, tsp.traces, 2)
+	require.Equal(t, 1, sink.SpansCount())
+	assert.Equal(t, traceID(1), sink.AllTraces()[0].ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).TraceID())
+
+	// The remaining traces are decided on shutdown.
+	require.NoError(t, tsp.Shutdown(context.Background()))
+	assert.Equal(t, 3, sink.SpansCount())
+}
+
+func TestTailSampling_NotSampledOnShutdown(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	tsp, err := newTraceProcessor(zap.NewNop(), sink, newTestConfig(PolicyCfg{Name: "errors", Type: Errors}))
+	require.NoError(t, err)
+	require.NoError(t, tsp.Start(context.Background(), componenttest.NewNopHost()))
+
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), newTraces([]string{"svc"}, traceID(1))))
+	require.NoError(t, tsp.Shutdown(context.Background()))
+	assert.Equal(t, 0, sink.SpansCount())
+}
+
+func TestTailSampling_RateLimitingEvaluatedLast(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	rateLimiting := PolicyCfg{Name: "rate", Type: RateLimiting}
+	rateLimiting.RateLimiting.SpansPerSecond = 1
+	// the rate limiting policy is listed first, but must not spend its budget on the
+	// traces sampled by the other policy
+	tsp, err := newTraceProcessor(zap.NewNop(), sink, newTestConfig(rateLimiting, PolicyCfg{Name: "errors", Type: Errors}))
+	require.NoError(t, err)
+
+	withError := newTraces([]string{"svc"}, traceID(1))
+	withError.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).Status().SetCode(pdata.StatusCodeError)
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), withError))
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), newTraces([]string{"svc"}, traceID(2))))
+	require.NoError(t, tsp.Shutdown(context.Background()))
+
+	assert.Equal(t, 2, sink.SpansCount())
+}
+
+func TestTailSampling_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  func(cfg *Config)
+	}{
+		{name: "no policies", cfg: func(cfg *Config) { cfg.Policies = nil }},
+		{name: "no decision wait", cfg: func(cfg *Config) { cfg.DecisionWait = 0 }},
+		{name: "no traces", cfg: func(cfg *Config) { cfg.NumTraces = 0 }},
+		{name: "unnamed policy", cfg: func(cfg *Config) { cfg.Policies[0].Name = "" }},
+		{name: "duplicate policy", cfg: func(cfg *Config) { cfg.Policies = append(cfg.Policies, cfg.Policies[0]) }},
+		{name: "unknown type", cfg: func(cfg *Config) { cfg.Policies[0].Type = "unknown" }},
+		{name: "no latency threshold", cfg: func(cfg *Config) { cfg.Policies[0].Type = Latency }},
+		{name: "no match", cfg: func(cfg *Config) { cfg.Policies[0].Type = Attributes }},
+		{name: "no rate limit", cfg: func(cfg *Config) { cfg.Policies[0].Type = RateLimiting }},
+		{name: "negative sampling percentage", cfg: func(cfg *Config) {
+			cfg.Policies[0].Type = Probabilistic
+			cfg.Policies[0].Probabilistic.SamplingPercentage = -1
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := newTestConfig(PolicyCfg{Name: "all", Type: AlwaysSample})
+			test.cfg(&cfg)
+			_, err := newTraceProcessor(zap.NewNop(), consumertest.NewTracesNop(), cfg)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTailSampling_GroupsSpansByTrace(t *testing.T) {
+	tsp, err := newTraceProcessor(zap.NewNop(), new(consumertest.TracesSink), newTestConfig(PolicyCfg{Name: "all", Type: AlwaysSample}))
+	require.NoError(t, err)
+
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), newTraces([]string{"frontend", "backend"}, traceID(1), traceID(2), traceID(1))))
+	require.Len(t, tsp.traces, 2)
+
+	trace := tsp.traces[traceID(1)]
+	assert.Equal(t, 4, trace.spanCount)
+	require.Len(t, trace.batches, 1)
+	require.Equal(t, 2, trace.batches[0].ResourceSpans().Len())
+	service, _ := trace.batches[0].ResourceSpans().At(1).Resource().Attributes().Get("service.name")
+	assert.Equal(t, "backend", service.StringVal())
+	assert.Equal(t, 2, tsp.traces[traceID(2)].spanCount)
+}
@@ -0,0 +1,45 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package temporalityprocessor
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+const (
+	// DeltaTemporality converts cumulative metrics to delta.
+	DeltaTemporality = "delta"
+	// CumulativeTemporality converts delta metrics to cumulative.
+	CumulativeTemporality = "cumulative"
+)
+
+// Config defines the configuration for the Temporality processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// AggregationTemporality is the temporality the sums and histograms are
+	// converted to, either delta or cumulative.
+	AggregationTemporality string `mapstructure:"aggregation_temporality"`
+
+	// Metrics are the names of the metrics converted. All the metrics are
+	// converted if empty.
+	Metrics []string `mapstructure:"metrics"`
+
+	// MaxStale is how long the state of a series is kept after its last data
+	// point. Once expired, the series starts over as a new one.
+	MaxStale time.Duration `mapstructure:"max_stale"`
+}
@@ -0,0 +1,355 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package temporalityprocessor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+type temporalityProcessor struct {
+	logger   *zap.Logger
+	toDelta  bool
+	metrics  map[string]bool
+	maxStale time.Duration
+	now      func() time.Time
+
+	lock   sync.Mutex
+	series map[string]*series
+	lastGC time.Time
+}
+
+func newTemporalityProcessor(logger *zap.Logger, cfg *Config) (*temporalityProcessor, error) {
+	var toDelta bool
+	switch cfg.AggregationTemporality {
+	case DeltaTemporality:
+		toDelta = true
+	case CumulativeTemporality:
+	default:
+		return nil, fmt.Errorf("aggregation_temporality must be %q or %q, got %q",
+			DeltaTemporality, CumulativeTemporality, cfg.AggregationTemporality)
+	}
+	if cfg.MaxStale <= 0 {
+		return nil, fmt.Errorf("max_stale must be positive, got %v", cfg.MaxStale)
+	}
+
+	metrics := make(map[string]bool, len(cfg.Metrics))
+	for _, name := range cfg.Metrics {
+		metrics[name] = true
+	}
+
+	return &temporalityProcessor{
+		logger:   logger,
+		toDelta:  toDelta,
+		metrics:  metrics,
+		maxStale: cfg.MaxStale,
+		now:      time.Now,
+		series:   make(map[string]*series),
+		lastGC:   time.Now(),
+	}, nil
+}
+
+// ProcessMetrics converts the sums and histograms to the configured temporality.
+// The data points that can't be converted are dropped, as well as the metrics
+// left without data points.
+func (tp *temporalityProcessor) ProcessMetrics(_ context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	tp.lock.Lock()
+	defer tp.lock.Unlock()
+
+	now := tp.now()
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		resKey := resourceKey(rm.Resource())
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			metrics := ilms.At(j).Metrics()
+			kept := make([]pdata.Metric, 0, metrics.Len())
+			for k := 0; k < metrics.Len(); k++ {
+				if tp.convertMetric(resKey, metrics.At(k), now) {
+					kept = append(kept, metrics.At(k))
+				}
+			}
+			if len(kept) == metrics.Len() {
+				continue
+			}
+			metrics.Resize(0)
+			for _, metric := range kept {
+				metrics.Append(metric)
+			}
+		}
+	}
+	tp.maybeGC(now)
+
+	if md.MetricCount() == 0 {
+		return md, processorhelper.ErrSkipProcessingData
+	}
+	return md, nil
+}
+
+// convertMetric converts the metric if needed, and returns false if it has no
+// data points left.
+func (tp *temporalityProcessor) convertMetric(resKey string, metric pdata.Metric, now time.Time) bool {
+	if len(tp.metrics) > 0 && !tp.metrics[metric.Name()] {
+		return true
+	}
+
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntSum:
+		sum := metric.IntSum()
+		if !tp.converts(sum.AggregationTemporality()) {
+			return true
+		}
+		tp.convertIntDataPoints(resKey, metric, sum.DataPoints(), sum.IsMonotonic(), now)
+		sum.SetAggregationTemporality(tp.target())
+		return sum.DataPoints().Len() > 0
+	case pdata.MetricDataTypeDoubleSum:
+		sum := metric.DoubleSum()
+		if !tp.converts(sum.AggregationTemporality()) {
+			return true
+		}
+		tp.convertDoubleDataPoints(resKey, metric, sum.DataPoints(), sum.IsMonotonic(), now)
+		sum.SetAggregationTemporality(tp.target())
+		return sum.DataPoints().Len() > 0
+	case pdata.MetricDataTypeIntHistogram:
+		histogram := metric.IntHistogram()
+		if !tp.converts(histogram.AggregationTemporality()) {
+			return true
+		}
+		tp.convertIntHistogramDataPoints(resKey, metric, histogram.DataPoints(), now)
+		histogram.SetAggregationTemporality(tp.target())
+		return histogram.DataPoints().Len() > 0
+	case pdata.MetricDataTypeDoubleHistogram:
+		histogram := metric.DoubleHistogram()
+		if !tp.converts(histogram.AggregationTemporality()) {
+			return true
+		}
+		tp.convertDoubleHistogramDataPoints(resKey, metric, histogram.DataPoints(), now)
+		histogram.SetAggregationTemporality(tp.target())
+		return histogram.DataPoints().Len() > 0
+	}
+	// Gauges and summaries have no temporality.
+	return true
+}
+
+// converts returns true if the metrics with the temporality must be converted.
+func (tp *temporalityProcessor) converts(temporality pdata.AggregationTemporality) bool {
+	if tp.toDelta {
+		return temporality == pdata.AggregationTemporalityCumulative
+	}
+	return temporality == pdata.AggregationTemporalityDelta
+}
+
+func (tp *temporalityProcessor) target() pdata.AggregationTemporality {
+	if tp.toDelta {
+		return pdata.AggregationTemporalityDelta
+	}
+	return pdata.AggregationTemporalityCumulative
+}
+
+func (tp *temporalityProcessor) convertIntDataPoints(resKey string, metric pdata.Metric, dps pdata.IntDataPointSlice, monotonic bool, now time.Time) {
+	kept := make([]pdata.IntDataPoint, 0, dps.Len())
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		p := point{start: dp.StartTime(), timestamp: dp.Timestamp(), intValue: dp.Value()}
+		out, ok := tp.convertPoint(seriesKey(resKey, metric, dp.LabelsMap()), p, monotonic, now)
+		if !ok {
+			continue
+		}
+		dp.SetStartTime(out.start)
+		dp.SetValue(out.intValue)
+		kept = append(kept, dp)
+	}
+
+	if len(kept) == dps.Len() {
+		return
+	}
+	dps.Resize(0)
+	for _, dp := range kept {
+		dps.Append(dp)
+	}
+}
+
+func (tp *temporalityProcessor) convertDoubleDataPoints(resKey string, metric pdata.Metric, dps pdata.DoubleDataPointSlice, monotonic bool, now time.Time) {
+	kept := make([]pdata.DoubleDataPoint, 0, dps.Len())
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		p := point{start: dp.StartTime(), timestamp: dp.Timestamp(), doubleValue: dp.Value()}
+		out, ok := tp.convertPoint(seriesKey(resKey, metric, dp.LabelsMap()), p, monotonic, now)
+		if !ok {
+			continue
+		}
+		dp.SetStartTime(out.start)
+		dp.SetValue(out.doubleValue)
+		kept = append(kept, dp)
+	}
+
+	if len(kept) == dps.Len() {
+		return
+	}
+	dps.Resize(0)
+	for _, dp := range kept {
+		dps.Append(dp)
+	}
+}
+
+func (tp *temporalityProcessor) convertIntHistogramDataPoints(resKey string, metric pdata.Metric, dps pdata.IntHistogramDataPointSlice, now time.Time) {
+	kept := make([]pdata.IntHistogramDataPoint, 0, dps.Len())
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		p := point{
+			start:     dp.StartTime(),
+			timestamp: dp.Timestamp(),
+			histogram: true,
+			intValue:  dp.Sum(),
+			count:     dp.Count(),
+			bounds:    append([]float64(nil), dp.ExplicitBounds()...),
+			buckets:   append([]uint64(nil), dp.BucketCounts()...),
+		}
+		out, ok := tp.convertPoint(seriesKey(resKey, metric, dp.LabelsMap()), p, true, now)
+		if !ok {
+			continue
+		}
+		dp.SetStartTime(out.start)
+		dp.SetSum(out.intValue)
+		dp.SetCount(out.count)
+		dp.SetBucketCounts(append([]uint64(nil), out.buckets...))
+		kept = append(kept, dp)
+	}
+
+	if len(kept) == dps.Len() {
+		return
+	}
+	dps.Resize(0)
+	for _, dp := range kept {
+		dps.Append(dp)
+	}
+}
+
+func (tp *temporalityProcessor) convertDoubleHistogramDataPoints(resKey string, metric pdata.Metric, dps pdata.DoubleHistogramDataPointSlice, now time.Time) {
+	kept := make([]pdata.DoubleHistogramDataPoint, 0, dps.Len())
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		p := point{
+			start:       dp.StartTime(),
+			timestamp:   dp.Timestamp(),
+			histogram:   true,
+			doubleValue: dp.Sum(),
+			count:       dp.Count(),
+			bounds:      append([]float64(nil), dp.ExplicitBounds()...),
+			buckets:     append([]uint64(nil), dp.BucketCounts()...),
+		}
+		out, ok := tp.convertPoint(seriesKey(resKey, metric, dp.LabelsMap()), p, true, now)
+		if !ok {
+			continue
+		}
+		dp.SetStartTime(out.start)
+		dp.SetSum(out.doubleValue)
+		dp.SetCount(out.count)
+		dp.SetBucketCounts(append([]uint64(nil), out.buckets...))
+		kept = append(kept, dp)
+	}
+
+	if len(kept) == dps.Len() {
+		return
+	}
+	dps.Resize(0)
+	for _, dp := range kept {
+		dps.Append(dp)
+	}
+}
+
+// convertPoint returns the converted point, and false if the point must be
+// dropped.
+func (tp *temporalityProcessor) convertPoint(key string, p point, monotonic bool, now time.Time) (point, bool) {
+	s, ok := tp.series[key]
+	if ok && now.Sub(s.lastSeen) > tp.maxStale {
+		// The series expired, it starts over.
+		ok = false
+	}
+	if !ok {
+		s = &series{}
+		tp.series[key] = s
+	} else if p.timestamp <= s.timestamp {
+		tp.logger.Debug("Dropping out of order data point", zap.String("series", key))
+		return p, false
+	}
+	s.lastSeen = now
+
+	if tp.toDelta {
+		return toDelta(s, ok, p, monotonic)
+	}
+	return toCumulative(s, ok, p), true
+}
+
+// toDelta returns the difference between the cumulative point p and the
+// previous point of the series. Like for the Prometheus receiver, a series is
+// reset when its start time changes or, if monotonic, when a value decreases.
+func toDelta(s *series, known bool, p point, monotonic bool) (point, bool) {
+	prev := s.point
+	s.point = p
+
+	if !known || p.start != prev.start {
+		// The first point of a series is a delta since its start time, when
+		// known, otherwise it is only kept as the reference of the next point.
+		return p, p.start != 0 && p.start < p.timestamp
+	}
+	if !p.sameBuckets(prev) || (monotonic && p.decreased(prev)) {
+		// The series was reset since the previous point, but the time of the
+		// reset is unknown.
+		return p, false
+	}
+
+	delta := p.sub(prev)
+	delta.start = prev.timestamp
+	return delta, true
+}
+
+// toCumulative adds the delta point p to the series, and returns the sum since
+// the start of the series. The series starts over when the buckets of the
+// histogram change.
+func toCumulative(s *series, known bool, p point) point {
+	if !known || !p.sameBuckets(s.point) {
+		if p.start == 0 {
+			p.start = p.timestamp
+		}
+		s.point = p
+		return s.point
+	}
+
+	s.point = s.point.add(p)
+	s.timestamp = p.timestamp
+	return s.point
+}
+
+// maybeGC removes the expired series, at most once per max_stale.
+func (tp *temporalityProcessor) maybeGC(now time.Time) {
+	if now.Sub(tp.lastGC) < tp.maxStale {
+		return
+	}
+	for key, s := range tp.series {
+		if now.Sub(s.lastSeen) > tp.maxStale {
+			delete(tp.series, key)
+		}
+	}
+	tp.lastGC = now
+}
@@ -0,0 +1,359 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package temporalityprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+type testPoint struct {
+	start     pdata.TimestampUnixNano
+	timestamp pdata.TimestampUnixNano
+	value     int64
+	buckets   []uint64
+}
+
+func newTestProcessor(t *testing.T, temporality string) *temporalityProcessor {
+	cfg := createDefaultConfig().(*Config)
+	cfg.AggregationTemporality = temporality
+	tp, err := newTemporalityProcessor(zap.NewNop(), cfg)
+	require.NoError(t, err)
+	return tp
+}
+
+func newTestMetrics(metrics ...pdata.Metric) pdata.Metrics {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	md.ResourceMetrics().At(0).Resource().Attributes().InsertString("service.name", "test")
+	ilms := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics()
+	ilms.Resize(1)
+	for _, m := range metrics {
+		ilms.At(0).Metrics().Append(m)
+	}
+	return md
+}
+
+func newIntSum(name string, temporality pdata.AggregationTemporality, monotonic bool, points ...testPoint) pdata.Metric {
+	m := pdata.NewMetric()
+	m.SetName(name)
+	m.SetDataType(pdata.MetricDataTypeIntSum)
+	m.IntSum().SetAggregationTemporality(temporality)
+	m.IntSum().SetIsMonotonic(monotonic)
+	dps := m.IntSum().DataPoints()
+	dps.Resize(len(points))
+	for i, p := range points {
+		dps.At(i).SetStartTime(p.start)
+		dps.At(i).SetTimestamp(p.timestamp)
+		dps.At(i).SetValue(p.value)
+	}
+	return m
+}
+
+func newDoubleSum(name string, temporality pdata.AggregationTemporality, monotonic bool, points ...testPoint) pdata.Metric {
+	m := pdata.NewMetric()
+	m.SetName(name)
+	m.SetDataType(pdata.MetricDataTypeDoubleSum)
+	m.DoubleSum().SetAggregationTemporality(temporality)
+	m.DoubleSum().SetIsMonotonic(monotonic)
+	dps := m.DoubleSum().DataPoints()
+	dps.Resize(len(points))
+	for i, p := range points {
+		dps.At(i).SetStartTime(p.start)
+		dps.At(i).SetTimestamp(p.timestamp)
+		dps.At(i).SetValue(float64(p.value))
+	}
+	return m
+}
+
+func newDoubleHistogram(name string, temporality pdata.AggregationTemporality, bounds []float64, points ...testPoint) pdata.Metric {
+	m := pdata.NewMetric()
+	m.SetName(name)
+	m.SetDataType(pdata.MetricDataTypeDoubleHistogram)
+	m.DoubleHistogram().SetAggregationTemporality(temporality)
+	dps := m.DoubleHistogram().DataPoints()
+	dps.Resize(len(points))
+	for i, p := range points {
+		dps.At(i).SetStartTime(p.start)
+		dps.At(i).SetTimestamp(p.timestamp)
+		dps.At(i).SetSum(float64(p.value))
+		var count uint64
+		for _, c := range p.buckets {
+			count += c
+		}
+		dps.At(i).SetCount(count)
+		dps.At(i).SetExplicitBounds(bounds)
+		dps.At(i).SetBucketCounts(p.buckets)
+	}
+	return m
+}
+
+// process processes the metrics and returns the first metric, or false if it
+// was dropped.
+func process(t *testing.T, tp *temporalityProcessor, md pdata.Metrics) (pdata.Metric, bool) {
+	md, err := tp.ProcessMetrics(context.Background(), md)
+	if err == processorhelper.ErrSkipProcessingData {
+		return pdata.Metric{}, false
+	}
+	require.NoError(t, err)
+	return md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0), true
+}
+
+func intPoints(dps pdata.IntDataPointSlice) []testPoint {
+	var points []testPoint
+	for i := 0; i < dps.Len(); i++ {
+		points = append(points, testPoint{start: dps.At(i).StartTime(), timestamp: dps.At(i).Timestamp(), value: dps.At(i).Value()})
+	}
+	return points
+}
+
+func TestProcessMetrics_CumulativeToDelta(t *testing.T) {
+	tp := newTestProcessor(t, DeltaTemporality)
+	tests := []struct {
+		name string
+		in   testPoint
+		want []testPoint
+	}{
+		{
+			name: "first point with start time",
+			in:   testPoint{start: 100, timestamp: 200, value: 10},
+			want: []testPoint{{start: 100, timestamp: 200, value: 10}},
+		},
+		{
+			name: "next point",
+			in:   testPoint{start: 100, timestamp: 300, value: 15},
+			want: []testPoint{{start: 200, timestamp: 300, value: 5}},
+		},
+		{
+			name: "out of order point",
+			in:   testPoint{start: 100, timestamp: 250, value: 12},
+		},
+		{
+			name: "reset without start time change",
+			in:   testPoint{start: 100, timestamp: 400, value: 3},
+		},
+		{
+			name: "point after reset",
+			in:   testPoint{start: 100, timestamp: 500, value: 11},
+			want: []testPoint{{start: 400, timestamp: 500, value: 8}},
+		},
+		{
+			name: "start time change",
+			in:   testPoint{start: 550, timestamp: 600, value: 4},
+			want: []testPoint{{start: 550, timestamp: 600, value: 4}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metric, ok := process(t, tp, newTestMetrics(newIntSum("requests", pdata.AggregationTemporalityCumulative, true, tt.in)))
+			if tt.want == nil {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, pdata.AggregationTemporalityDelta, metric.IntSum().AggregationTemporality())
+			assert.Equal(t, tt.want, intPoints(metric.IntSum().DataPoints()))
+		})
+	}
+}
+
+func TestProcessMetrics_CumulativeToDeltaUnknownStartTime(t *testing.T) {
+	tp := newTestProcessor(t, DeltaTemporality)
+
+	// The first point is only the reference of the next one.
+	_, ok := process(t, tp, newTestMetrics(newIntSum("requests", pdata.AggregationTemporalityCumulative, true, testPoint{timestamp: 100, value: 10})))
+	assert.False(t, ok)
+
+	metric, ok := process(t, tp, newTestMetrics(newIntSum("requests", pdata.AggregationTemporalityCumulative, true, testPoint{timestamp: 200, value: 12})))
+	require.True(t, ok)
+	assert.Equal(t, []testPoint{{start: 100, timestamp: 200, value: 2}}, intPoints(metric.IntSum().DataPoints()))
+}
+
+func TestProcessMetrics_CumulativeToDeltaNonMonotonic(t *testing.T) {
+	tp := newTestProcessor(t, DeltaTemporality)
+
+	process(t, tp, newTestMetrics(newDoubleSum("queue_size", pdata.AggregationTemporalityCumulative, false, testPoint{start: 100, timestamp: 200, value: 10})))
+	metric, ok := process(t, tp, newTestMetrics(newDoubleSum("queue_size", pdata.AggregationTemporalityCumulative, false, testPoint{start: 100, timestamp: 300, value: 4})))
+
+	// A decreasing non monotonic sum isn't a reset.
+	require.True(t, ok)
+	dps := metric.DoubleSum().DataPoints()
+	require.Equal(t, 1, dps.Len())
+	assert.Equal(t, -6.0, dps.At(0).Value())
+	assert.EqualValues(t, 200, dps.At(0).StartTime())
+}
+
+func TestProcessMetrics_CumulativeToDeltaHistogram(t *testing.T) {
+	tp := newTestProcessor(t, DeltaTemporality)
+	bounds := []float64{10, 100}
+
+	process(t, tp, newTestMetrics(newDoubleHistogram("latency", pdata.AggregationTemporalityCumulative, bounds,
+		testPoint{start: 100, timestamp: 200, value: 50, buckets: []uint64{1, 2, 0}})))
+	metric, ok := process(t, tp, newTestMetrics(newDoubleHistogram("latency", pdata.AggregationTemporalityCumulative, bounds,
+		testPoint{start: 100, timestamp: 300, value: 80, buckets: []uint64{3, 2, 1}})))
+
+	require.True(t, ok)
+	assert.Equal(t, pdata.AggregationTemporalityDelta, metric.DoubleHistogram().AggregationTemporality())
+	dp := metric.DoubleHistogram().DataPoints().At(0)
+	assert.EqualValues(t, 200, dp.StartTime())
+	assert.EqualValues(t, 300, dp.Timestamp())
+	assert.EqualValues(t, 3, dp.Count())
+	assert.Equal(t, 30.0, dp.Sum())
+	assert.Equal(t, []uint64{2, 0, 1}, dp.BucketCounts())
+
+	// A decreasing bucket is a reset.
+	_, ok = process(t, tp, newTestMetrics(newDoubleHistogram("latency", pdata.AggregationTemporalityCumulative, bounds,
+		testPoint{start: 100, timestamp: 400, value: 90, buckets: []uint64{2, 3, 2}})))
+	assert.False(t, ok)
+}
+
+func TestProcessMetrics_DeltaToCumulative(t *testing.T) {
+	tp := newTestProcessor(t, CumulativeTemporality)
+	tests := []struct {
+		name string
+		in   testPoint
+		want []testPoint
+	}{
+		{
+			name: "first point",
+			in:   testPoint{start: 100, timestamp: 200, value: 10},
+			want: []testPoint{{start: 100, timestamp: 200, value: 10}},
+		},
+		{
+			name: "next point",
+			in:   testPoint{start: 200, timestamp: 300, value: 5},
+			want: []testPoint{{start: 100, timestamp: 300, value: 15}},
+		},
+		{
+			name: "duplicate point",
+			in:   testPoint{start: 200, timestamp: 300, value: 5},
+		},
+		{
+			name: "point without start time",
+			in:   testPoint{timestamp: 400, value: 1},
+			want: []testPoint{{start: 100, timestamp: 400, value: 16}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metric, ok := process(t, tp, newTestMetrics(newIntSum("requests", pdata.AggregationTemporalityDelta, true, tt.in)))
+			if tt.want == nil {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, pdata.AggregationTemporalityCumulative, metric.IntSum().AggregationTemporality())
+			assert.Equal(t, tt.want, intPoints(metric.IntSum().DataPoints()))
+		})
+	}
+}
+
+func TestProcessMetrics_DeltaToCumulativeHistogram(t *testing.T) {
+	tp := newTestProcessor(t, CumulativeTemporality)
+
+	process(t, tp, newTestMetrics(newDoubleHistogram("latency", pdata.AggregationTemporalityDelta, []float64{10},
+		testPoint{start: 100, timestamp: 200, value: 20, buckets: []uint64{1, 1}})))
+	metric, ok := process(t, tp, newTestMetrics(newDoubleHistogram("latency", pdata.AggregationTemporalityDelta, []float64{10},
+		testPoint{start: 200, timestamp: 300, value: 5, buckets: []uint64{1, 0}})))
+
+	require.True(t, ok)
+	dp := metric.DoubleHistogram().DataPoints().At(0)
+	assert.EqualValues(t, 100, dp.StartTime())
+	assert.EqualValues(t, 3, dp.Count())
+	assert.Equal(t, 25.0, dp.Sum())
+	assert.Equal(t, []uint64{2, 1}, dp.BucketCounts())
+
+	// The series starts over when the buckets change.
+	metric, ok = process(t, tp, newTestMetrics(newDoubleHistogram("latency", pdata.AggregationTemporalityDelta, []float64{10, 20},
+		testPoint{start: 300, timestamp: 400, value: 5, buckets: []uint64{1, 0, 0}})))
+	require.True(t, ok)
+	dp = metric.DoubleHistogram().DataPoints().At(0)
+	assert.EqualValues(t, 300, dp.StartTime())
+	assert.EqualValues(t, 1, dp.Count())
+	assert.Equal(t, []uint64{1, 0, 0}, dp.BucketCounts())
+}
+
+func TestProcessMetrics_SeriesKey(t *testing.T) {
+	tp := newTestProcessor(t, CumulativeTemporality)
+
+	newMetrics := func(service string, labelValue string, value int64) pdata.Metrics {
+		m := newIntSum("requests", pdata.AggregationTemporalityDelta, true, testPoint{start: 100, timestamp: 200, value: value})
+		m.IntSum().DataPoints().At(0).LabelsMap().Insert("method", labelValue)
+		md := newTestMetrics(m)
+		md.ResourceMetrics().At(0).Resource().Attributes().UpdateString("service.name", service)
+		return md
+	}
+
+	process(t, tp, newMetrics("a", "GET", 1))
+	// Different labels or resources are different series.
+	for _, md := range []pdata.Metrics{newMetrics("a", "POST", 2), newMetrics("b", "GET", 3)} {
+		metric, ok := process(t, tp, md)
+		require.True(t, ok)
+		assert.EqualValues(t, 100, metric.IntSum().DataPoints().At(0).StartTime())
+	}
+	assert.Len(t, tp.series, 3)
+}
+
+func TestProcessMetrics_Unconverted(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.AggregationTemporality = DeltaTemporality
+	cfg.Metrics = []string{"requests"}
+	tp, err := newTemporalityProcessor(zap.NewNop(), cfg)
+	require.NoError(t, err)
+
+	gauge := pdata.NewMetric()
+	gauge.SetName("requests")
+	gauge.SetDataType(pdata.MetricDataTypeIntGauge)
+	gauge.IntGauge().DataPoints().Resize(1)
+
+	md := newTestMetrics(
+		newIntSum("other", pdata.AggregationTemporalityCumulative, true, testPoint{timestamp: 100, value: 1}),
+		newIntSum("requests", pdata.AggregationTemporalityDelta, true, testPoint{timestamp: 100, value: 2}),
+		gauge)
+	want := md.Clone()
+
+	md, err = tp.ProcessMetrics(context.Background(), md)
+	require.NoError(t, err)
+	assert.Equal(t, want, md)
+	assert.Empty(t, tp.series)
+}
+
+func TestProcessMetrics_ExpireSeries(t *testing.T) {
+	tp := newTestProcessor(t, CumulativeTemporality)
+	now := time.Now()
+	tp.now = func() time.Time { return now }
+
+	process(t, tp, newTestMetrics(newIntSum("requests", pdata.AggregationTemporalityDelta, true, testPoint{start: 100, timestamp: 200, value: 10})))
+
+	now = now.Add(defaultMaxStale + time.Second)
+	metric, ok := process(t, tp, newTestMetrics(newIntSum("requests", pdata.AggregationTemporalityDelta, true, testPoint{start: 200, timestamp: 300, value: 5})))
+
+	// The series started over.
+	require.True(t, ok)
+	assert.Equal(t, []testPoint{{start: 200, timestamp: 300, value: 5}}, intPoints(metric.IntSum().DataPoints()))
+
+	// Idle series are removed.
+	now = now.Add(defaultMaxStale + time.Second)
+	process(t, tp, newTestMetrics(newIntSum("other", pdata.AggregationTemporalityDelta, true, testPoint{start: 100, timestamp: 200, value: 1})))
+	assert.Len(t, tp.series, 1)
+}
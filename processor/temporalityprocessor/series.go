@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package temporalityprocessor
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+// point holds the values of a data point of a sum or of a histogram,
+// independently of the type of the data point.
+type point struct {
+	start     pdata.TimestampUnixNano
+	timestamp pdata.TimestampUnixNano
+
+	// histogram is true for histogram data points, whose sum is kept in
+	// intValue or doubleValue.
+	histogram   bool
+	intValue    int64
+	doubleValue float64
+	count       uint64
+	bounds      []float64
+	buckets     []uint64
+}
+
+// decreased returns true if a value of p is less than the one of prev, meaning
+// that a monotonic cumulative series has been reset.
+func (p point) decreased(prev point) bool {
+	if p.histogram {
+		if p.count < prev.count {
+			return true
+		}
+		for i := range p.buckets {
+			if i < len(prev.buckets) && p.buckets[i] < prev.buckets[i] {
+				return true
+			}
+		}
+		return false
+	}
+	return p.intValue < prev.intValue || p.doubleValue < prev.doubleValue
+}
+
+// sameBuckets returns true if p and other have the same histogram buckets.
+func (p point) sameBuckets(other point) bool {
+	if len(p.bounds) != len(other.bounds) || len(p.buckets) != len(other.buckets) {
+		return false
+	}
+	for i := range p.bounds {
+		if p.bounds[i] != other.bounds[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sub returns the values of p minus the ones of prev, both having the same
+// buckets.
+func (p point) sub(prev point) point {
+	p.intValue -= prev.intValue
+	p.doubleValue -= prev.doubleValue
+	p.count -= prev.count
+	if p.buckets != nil {
+		buckets := make([]uint64, len(p.buckets))
+		for i := range buckets {
+			buckets[i] = p.buckets[i] - prev.buckets[i]
+		}
+		p.buckets = buckets
+	}
+	return p
+}
+
+// add returns the values of p plus the ones of other, both having the same
+// buckets.
+func (p point) add(other point) point {
+	p.intValue += other.intValue
+	p.doubleValue += other.doubleValue
+	p.count += other.count
+	if p.buckets != nil {
+		buckets := make([]uint64, len(p.buckets))
+		for i := range buckets {
+			buckets[i] = p.buckets[i] + other.buckets[i]
+		}
+		p.buckets = buckets
+	}
+	return p
+}
+
+// series is the state of a series: the last cumulative point received when
+// converting to delta, or the accumulated point when converting to cumulative.
+type series struct {
+	point
+	lastSeen time.Time
+}
+
+// resourceKey returns the part of the key of the series identifying the
+// resource.
+func resourceKey(resource pdata.Resource) string {
+	var kvs []string
+	resource.Attributes().ForEach(func(k string, v pdata.AttributeValue) {
+		kvs = append(kvs, k+"="+tracetranslator.AttributeValueToString(v, true))
+	})
+	sort.Strings(kvs)
+	return strings.Join(kvs, "\x00")
+}
+
+// seriesKey returns the key of the series of a data point of the metric.
+func seriesKey(resKey string, metric pdata.Metric, labels pdata.StringMap) string {
+	var kvs []string
+	labels.ForEach(func(k string, v string) {
+		kvs = append(kvs, k+"="+v)
+	})
+	sort.Strings(kvs)
+
+	var b strings.Builder
+	b.WriteString(resKey)
+	b.WriteByte('\x01')
+	b.WriteString(metric.Name())
+	b.WriteByte('\x01')
+	b.WriteString(metric.DataType().String())
+	b.WriteByte('\x01')
+	b.WriteString(strings.Join(kvs, "\x00"))
+	return b.String()
+}
@@ -15,6 +15,7 @@
 package jaegerreceiver
 
 import (
+	"go.opentelemetry.io/collector/config/configadmission"
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configmodels"
@@ -71,4 +72,9 @@ type Config struct {
 	configmodels.ReceiverSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
 	Protocols                     `mapstructure:"protocols"`
 	RemoteSampling                *RemoteSamplingConfig `mapstructure:"remote_sampling"`
+
+	// Admission limits the size of the requests being processed by the grpc and thrift_http protocols
+	// together, rather than by each of them separately. It can't be combined with the admission of
+	// the protocols. (optional)
+	Admission *configadmission.Admission `mapstructure:"admission"`
 }
@@ -18,6 +18,7 @@ package jaegerreceiver
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"strconv"
@@ -59,6 +60,8 @@ func NewFactory() component.ReceiverFactory {
 		receiverhelper.WithCustomUnmarshaler(customUnmarshaler))
 }
 
+var errAdmissionConflict = errors.New("admission can't be set both for the receiver and for its protocols")
+
 // customUnmarshaler is used to add defaults for named but empty protocols
 func customUnmarshaler(componentViperSection *viper.Viper, intoCfg interface{}) error {
 	if componentViperSection == nil || len(componentViperSection.AllKeys()) == 0 {
@@ -155,6 +158,19 @@ func createTraceReceiver(
 	remoteSamplingConfig := rCfg.RemoteSampling
 
 	var config configuration
+	var grpcOpts []configgrpc.ToServerOption
+	if rCfg.Admission != nil {
+		if (rCfg.Protocols.GRPC != nil && rCfg.Protocols.GRPC.Admission != nil) ||
+			(rCfg.Protocols.ThriftHTTP != nil && rCfg.Protocols.ThriftHTTP.Admission != nil) {
+			return nil, errAdmissionConflict
+		}
+		var err error
+		if config.CollectorAdmission, err = rCfg.Admission.NewController(); err != nil {
+			return nil, err
+		}
+		grpcOpts = append(grpcOpts, configgrpc.WithAdmissionController(config.CollectorAdmission))
+	}
+
 	// Set ports
 	if rCfg.Protocols.GRPC != nil {
 		var err error
@@ -162,14 +178,10 @@ func createTraceReceiver(
 		if err != nil {
 			return nil, fmt.Errorf("unable to extract port for GRPC: %w", err)
 		}
-
-		config.CollectorGRPCOptions, err = rCfg.Protocols.GRPC.ToServerOption()
-		if err != nil {
-			return nil, err
-		}
 	}
 
 	if rCfg.Protocols.ThriftHTTP != nil {
+		config.CollectorHTTPSettings = *rCfg.Protocols.ThriftHTTP
 		var err error
 		config.CollectorHTTPPort, err = extractPortFromEndpoint(rCfg.Protocols.ThriftHTTP.Endpoint)
 		if err != nil {
@@ -233,6 +245,16 @@ func createTraceReceiver(
 		return nil, err
 	}
 
+	// The server options are built once the configuration is validated, as they start the
+	// authenticator closed by the receiver.
+	if rCfg.Protocols.GRPC != nil {
+		var err error
+		config.CollectorGRPCOptions, config.CollectorGRPCAuth, err = rCfg.Protocols.GRPC.ToServerOption(grpcOpts...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Create the receiver.
 	return newJaegerReceiver(rCfg.Name(), &config, nextConsumer, params), nil
 }
@@ -27,6 +27,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configadmission"
 	"go.opentelemetry.io/collector/config/configcheck"
 	"go.opentelemetry.io/collector/config/configerror"
 	"go.opentelemetry.io/collector/config/configgrpc"
@@ -111,6 +112,21 @@ func TestCreateDefaultGRPCEndpoint(t *testing.T) {
 	assert.Equal(t, 14250, r.(*jReceiver).config.CollectorGRPCPort, "grpc port should be default")
 }
 
+func TestCreateSharedAdmission(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Admission = &configadmission.Admission{LimitMiB: 1}
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+
+	r, err := factory.CreateTracesReceiver(context.Background(), params, cfg, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, r.(*jReceiver).config.CollectorAdmission)
+
+	cfg.ThriftHTTP.Admission = &configadmission.Admission{LimitMiB: 1}
+	_, err = factory.CreateTracesReceiver(context.Background(), params, cfg, nil)
+	assert.Equal(t, errAdmissionConflict, err)
+}
+
 func TestCreateTLSGPRCEndpoint(t *testing.T) {
 	factory := NewFactory()
 	cfg := factory.CreateDefaultConfig()
@@ -17,6 +17,7 @@ package jaegerreceiver
 import (
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"mime"
 	"net"
@@ -47,7 +48,9 @@ import (
 	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/config/configadmission"
 	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/obsreport"
 	jaegertranslator "go.opentelemetry.io/collector/translator/trace/jaeger"
@@ -56,10 +59,15 @@ import (
 // configuration defines the behavior and the ports that
 // the Jaeger receiver will use.
 type configuration struct {
-	CollectorThriftPort  int
-	CollectorHTTPPort    int
-	CollectorGRPCPort    int
-	CollectorGRPCOptions []grpc.ServerOption
+	CollectorThriftPort   int
+	CollectorHTTPPort     int
+	CollectorHTTPSettings confighttp.HTTPServerSettings
+	CollectorGRPCPort     int
+	CollectorGRPCOptions  []grpc.ServerOption
+	// CollectorGRPCAuth stops the authenticator of the gRPC collector, if any.
+	CollectorGRPCAuth io.Closer
+	// CollectorAdmission is shared by the gRPC and HTTP collectors, if set.
+	CollectorAdmission *configadmission.Controller
 
 	AgentCompactThriftPort       int
 	AgentCompactThriftConfig     ServerConfigUDP
@@ -86,6 +94,8 @@ type jReceiver struct {
 
 	grpc            *grpc.Server
 	collectorServer *http.Server
+	// collectorAuth stops the authenticator of the HTTP collector, if any.
+	collectorAuth io.Closer
 
 	agentSamplingManager *jSamplingConfig.SamplingManager
 	agentProcessors      []processors.Processor
@@ -233,6 +243,13 @@ func (jr *jReceiver) Shutdown(context.Context) error {
 			jr.grpc.Stop()
 			jr.grpc = nil
 		}
+		for _, auth := range []io.Closer{jr.collectorAuth, jr.config.CollectorGRPCAuth} {
+			if auth != nil {
+				if aerr := auth.Close(); aerr != nil {
+					errs = append(errs, aerr)
+				}
+			}
+		}
 		err = componenterror.CombineErrors(errs)
 	})
 
@@ -289,7 +306,7 @@ func (jr *jReceiver) GetBaggageRestrictions(ctx context.Context, serviceName str
 }
 
 func (jr *jReceiver) PostSpans(ctx context.Context, r *api_v2.PostSpansRequest) (*api_v2.PostSpansResponse, error) {
-	if c, ok := client.FromGRPC(ctx); ok {
+	if c, ok := client.FromGRPC(ctx, client.WithReceiver(jr.instanceName)); ok {
 		ctx = client.NewContext(ctx, c)
 	}
 
@@ -431,7 +448,7 @@ func (jr *jReceiver) decodeThriftHTTPBody(r *http.Request) (*jaeger.Batch, *http
 // HandleThriftHTTPBatch implements Jaeger HTTP Thrift handler.
 func (jr *jReceiver) HandleThriftHTTPBatch(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	if c, ok := client.FromHTTP(r); ok {
+	if c, ok := client.FromHTTP(r, client.WithReceiver(jr.instanceName)); ok {
 		ctx = client.NewContext(ctx, c)
 	}
 
@@ -461,15 +478,25 @@ func (jr *jReceiver) startCollector(host component.Host) error {
 
 	if jr.collectorHTTPEnabled() {
 		// Now the collector that runs over HTTP
+		nr := mux.NewRouter()
+		nr.HandleFunc("/api/traces", jr.HandleThriftHTTPBatch).Methods(http.MethodPost)
+		var opts []confighttp.ToServerOption
+		if jr.config.CollectorAdmission != nil {
+			opts = append(opts, confighttp.WithAdmissionController(jr.config.CollectorAdmission))
+		}
+		cs, auth, err := jr.config.CollectorHTTPSettings.ToServer(nr, opts...)
+		if err != nil {
+			return err
+		}
+		jr.collectorAuth = auth
+
 		caddr := jr.collectorHTTPAddr()
 		cln, cerr := net.Listen("tcp", caddr)
 		if cerr != nil {
 			return fmt.Errorf("failed to bind to Collector address %q: %v", caddr, cerr)
 		}
 
-		nr := mux.NewRouter()
-		nr.HandleFunc("/api/traces", jr.HandleThriftHTTPBatch).Methods(http.MethodPost)
-		jr.collectorServer = &http.Server{Handler: nr}
+		jr.collectorServer = cs
 		go func() {
 			_ = jr.collectorServer.Serve(cln)
 		}()
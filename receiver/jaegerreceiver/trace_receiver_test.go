@@ -43,6 +43,7 @@ import (
 	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configauth"
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configtls"
@@ -98,6 +99,7 @@ func TestThriftHTTPBodyDecode(t *testing.T) {
 func TestClientIPDetection(t *testing.T) {
 	ch := make(chan context.Context)
 	jr := jReceiver{
+		instanceName: jaegerReceiver,
 		nextConsumer: traceConsumer{
 			func(ctx context.Context, _ pdata.Traces) {
 				ch <- ctx
@@ -111,8 +113,9 @@ func TestClientIPDetection(t *testing.T) {
 	r, err := jaegerBatchToHTTPBody(batch)
 	require.NoError(t, err)
 
-	wantClient, ok := client.FromHTTP(r)
+	wantClient, ok := client.FromHTTP(r, client.WithReceiver(jaegerReceiver))
 	assert.True(t, ok)
+	assert.Equal(t, jaegerReceiver, wantClient.Receiver)
 	jr.HandleThriftHTTPBatch(httptest.NewRecorder(), r)
 
 	select {
@@ -161,6 +164,28 @@ func TestReception(t *testing.T) {
 	assert.EqualValues(t, td, gotTraces[0])
 }
 
+func TestReceptionFailsOnInvalidAuth(t *testing.T) {
+	port := testutil.GetAvailablePort(t)
+	config := &configuration{
+		CollectorHTTPPort: int(port),
+		CollectorHTTPSettings: confighttp.HTTPServerSettings{
+			Auth: &configauth.Authentication{
+				OIDC: &configauth.OIDC{
+					IssuerURL: "http://oidc.acme.invalid",
+					Audience:  "unit-test",
+				},
+			},
+		},
+	}
+	sink := new(consumertest.TracesSink)
+
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	jr := newJaegerReceiver(jaegerReceiver, config, sink, params)
+	defer jr.Shutdown(context.Background())
+
+	assert.Error(t, jr.Start(context.Background(), componenttest.NewNopHost()))
+}
+
 func TestPortsNotOpen(t *testing.T) {
 	// an empty config should result in no open ports
 	config := &configuration{}
@@ -26,12 +26,14 @@ import (
 )
 
 const (
-	typeStr         = "kafka"
-	defaultTopic    = "otlp_spans"
-	defaultEncoding = "otlp_proto"
-	defaultBroker   = "localhost:9092"
-	defaultClientID = "otel-collector"
-	defaultGroupID  = defaultClientID
+	typeStr             = "kafka"
+	defaultTracesTopic  = "otlp_spans"
+	defaultMetricsTopic = "otlp_metrics"
+	defaultLogsTopic    = "otlp_logs"
+	defaultEncoding     = "otlp_proto"
+	defaultBroker       = "localhost:9092"
+	defaultClientID     = "otel-collector"
+	defaultGroupID      = defaultClientID
 
 	// default from sarama.NewConfig()
 	defaultMetadataRetryMax = 3
@@ -53,10 +55,30 @@ func WithAddUnmarshallers(encodingMarshaller map[string]Unmarshaller) FactoryOpt
 	}
 }
 
+// WithAddMetricsUnmarshallers adds metrics unmarshallers.
+func WithAddMetricsUnmarshallers(encodingMarshaller map[string]MetricsUnmarshaller) FactoryOption {
+	return func(factory *kafkaReceiverFactory) {
+		for encoding, unmarshaller := range encodingMarshaller {
+			factory.metricsUnmarshalers[encoding] = unmarshaller
+		}
+	}
+}
+
+// WithAddLogsUnmarshallers adds logs unmarshallers.
+func WithAddLogsUnmarshallers(encodingMarshaller map[string]LogsUnmarshaller) FactoryOption {
+	return func(factory *kafkaReceiverFactory) {
+		for encoding, unmarshaller := range encodingMarshaller {
+			factory.logsUnmarshalers[encoding] = unmarshaller
+		}
+	}
+}
+
 // NewFactory creates Kafka receiver factory.
 func NewFactory(options ...FactoryOption) component.ReceiverFactory {
 	f := &kafkaReceiverFactory{
-		unmarshalers: defaultUnmarshallers(),
+		unmarshalers:        defaultUnmarshallers(),
+		metricsUnmarshalers: defaultMetricsUnmarshallers(),
+		logsUnmarshalers:    defaultLogsUnmarshallers(),
 	}
 	for _, o := range options {
 		o(f)
@@ -64,7 +86,9 @@ func NewFactory(options ...FactoryOption) component.ReceiverFactory {
 	return receiverhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		receiverhelper.WithTraces(f.createTraceReceiver))
+		receiverhelper.WithTraces(f.createTraceReceiver),
+		receiverhelper.WithMetrics(f.createMetricsReceiver),
+		receiverhelper.WithLogs(f.createLogsReceiver))
 }
 
 func createDefaultConfig() configmodels.Receiver {
@@ -73,7 +97,7 @@ func createDefaultConfig() configmodels.Receiver {
 			TypeVal: typeStr,
 			NameVal: typeStr,
 		},
-		Topic:    defaultTopic,
+		Topic:    "",
 		Encoding: defaultEncoding,
 		Brokers:  []string{defaultBroker},
 		ClientID: defaultClientID,
@@ -89,7 +113,9 @@ func createDefaultConfig() configmodels.Receiver {
 }
 
 type kafkaReceiverFactory struct {
-	unmarshalers map[string]Unmarshaller
+	unmarshalers        map[string]Unmarshaller
+	metricsUnmarshalers map[string]MetricsUnmarshaller
+	logsUnmarshalers    map[string]LogsUnmarshaller
 }
 
 func (f *kafkaReceiverFactory) createTraceReceiver(
@@ -98,8 +124,45 @@ func (f *kafkaReceiverFactory) createTraceReceiver(
 	cfg configmodels.Receiver,
 	nextConsumer consumer.TracesConsumer,
 ) (component.TracesReceiver, error) {
-	c := cfg.(*Config)
-	r, err := newReceiver(*c, params, f.unmarshalers, nextConsumer)
+	c := *cfg.(*Config)
+	if c.Topic == "" {
+		c.Topic = defaultTracesTopic
+	}
+	r, err := newTracesReceiver(c, params, f.unmarshalers, nextConsumer)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (f *kafkaReceiverFactory) createMetricsReceiver(
+	_ context.Context,
+	params component.ReceiverCreateParams,
+	cfg configmodels.Receiver,
+	nextConsumer consumer.MetricsConsumer,
+) (component.MetricsReceiver, error) {
+	c := *cfg.(*Config)
+	if c.Topic == "" {
+		c.Topic = defaultMetricsTopic
+	}
+	r, err := newMetricsReceiver(c, params, f.metricsUnmarshalers, nextConsumer)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (f *kafkaReceiverFactory) createLogsReceiver(
+	_ context.Context,
+	params component.ReceiverCreateParams,
+	cfg configmodels.Receiver,
+	nextConsumer consumer.LogsConsumer,
+) (component.LogsReceiver, error) {
+	c := *cfg.(*Config)
+	if c.Topic == "" {
+		c.Topic = defaultLogsTopic
+	}
+	r, err := newLogsReceiver(c, params, f.logsUnmarshalers, nextConsumer)
 	if err != nil {
 		return nil, err
 	}
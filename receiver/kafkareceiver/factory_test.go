@@ -31,7 +31,7 @@ func TestCreateDefaultConfig(t *testing.T) {
 	assert.NotNil(t, cfg, "failed to create default config")
 	assert.NoError(t, configcheck.ValidateConfig(cfg))
 	assert.Equal(t, []string{defaultBroker}, cfg.Brokers)
-	assert.Equal(t, defaultTopic, cfg.Topic)
+	assert.Empty(t, cfg.Topic)
 	assert.Equal(t, defaultGroupID, cfg.GroupID)
 	assert.Equal(t, defaultClientID, cfg.ClientID)
 }
@@ -58,6 +58,53 @@ func TestCreateTraceReceiver_error(t *testing.T) {
 	assert.NotNil(t, r)
 }
 
+func TestCreateMetricsReceiver(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Brokers = []string{"invalid:9092"}
+	cfg.ProtocolVersion = "2.0.0"
+	f := kafkaReceiverFactory{metricsUnmarshalers: defaultMetricsUnmarshallers()}
+	r, err := f.createMetricsReceiver(context.Background(), component.ReceiverCreateParams{}, cfg, nil)
+	// no available broker
+	require.Error(t, err)
+	assert.Nil(t, r)
+}
+
+func TestCreateMetricsReceiver_error(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.ProtocolVersion = "2.0.0"
+	// disable contacting broker at startup
+	cfg.Metadata.Full = false
+	f := kafkaReceiverFactory{metricsUnmarshalers: defaultMetricsUnmarshallers()}
+	r, err := f.createMetricsReceiver(context.Background(), component.ReceiverCreateParams{}, cfg, nil)
+	require.NoError(t, err)
+	require.NotNil(t, r)
+	assert.Equal(t, []string{defaultMetricsTopic}, r.(*kafkaConsumer).topics)
+	assert.Empty(t, cfg.Topic)
+}
+
+func TestCreateLogsReceiver(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Brokers = []string{"invalid:9092"}
+	cfg.ProtocolVersion = "2.0.0"
+	f := kafkaReceiverFactory{logsUnmarshalers: defaultLogsUnmarshallers()}
+	r, err := f.createLogsReceiver(context.Background(), component.ReceiverCreateParams{}, cfg, nil)
+	// no available broker
+	require.Error(t, err)
+	assert.Nil(t, r)
+}
+
+func TestCreateLogsReceiver_error(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.ProtocolVersion = "2.0.0"
+	// disable contacting broker at startup
+	cfg.Metadata.Full = false
+	f := kafkaReceiverFactory{logsUnmarshalers: defaultLogsUnmarshallers()}
+	r, err := f.createLogsReceiver(context.Background(), component.ReceiverCreateParams{}, cfg, nil)
+	require.NoError(t, err)
+	require.NotNil(t, r)
+	assert.Equal(t, []string{defaultLogsTopic}, r.(*kafkaConsumer).topics)
+}
+
 func TestWithUnmarshallers(t *testing.T) {
 	unmarshaller := &customUnamarshaller{}
 	f := NewFactory(WithAddUnmarshallers(map[string]Unmarshaller{unmarshaller.Encoding(): unmarshaller}))
@@ -80,6 +127,26 @@ func TestWithUnmarshallers(t *testing.T) {
 	})
 }
 
+func TestWithMetricsAndLogsUnmarshallers(t *testing.T) {
+	metricsUnmarshaller := &customMetricsUnamarshaller{}
+	logsUnmarshaller := &customLogsUnamarshaller{}
+	f := NewFactory(
+		WithAddMetricsUnmarshallers(map[string]MetricsUnmarshaller{metricsUnmarshaller.Encoding(): metricsUnmarshaller}),
+		WithAddLogsUnmarshallers(map[string]LogsUnmarshaller{logsUnmarshaller.Encoding(): logsUnmarshaller}))
+	cfg := createDefaultConfig().(*Config)
+	// disable contacting broker
+	cfg.Metadata.Full = false
+	cfg.ProtocolVersion = "2.0.0"
+	cfg.Encoding = "custom"
+
+	metricsReceiver, err := f.CreateMetricsReceiver(context.Background(), component.ReceiverCreateParams{}, cfg, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, metricsReceiver)
+	logsReceiver, err := f.CreateLogsReceiver(context.Background(), component.ReceiverCreateParams{}, cfg, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, logsReceiver)
+}
+
 type customUnamarshaller struct {
 }
 
@@ -92,3 +159,29 @@ func (c customUnamarshaller) Unmarshal(bytes []byte) (pdata.Traces, error) {
 func (c customUnamarshaller) Encoding() string {
 	return "custom"
 }
+
+type customMetricsUnamarshaller struct {
+}
+
+var _ MetricsUnmarshaller = (*customMetricsUnamarshaller)(nil)
+
+func (c customMetricsUnamarshaller) Unmarshal([]byte) (pdata.Metrics, error) {
+	panic("implement me")
+}
+
+func (c customMetricsUnamarshaller) Encoding() string {
+	return "custom"
+}
+
+type customLogsUnamarshaller struct {
+}
+
+var _ LogsUnmarshaller = (*customLogsUnamarshaller)(nil)
+
+func (c customLogsUnamarshaller) Unmarshal([]byte) (pdata.Logs, error) {
+	panic("implement me")
+}
+
+func (c customLogsUnamarshaller) Encoding() string {
+	return "custom"
+}
@@ -40,22 +40,57 @@ var errUnrecognizedEncoding = fmt.Errorf("unrecognized encoding")
 type kafkaConsumer struct {
 	name              string
 	consumerGroup     sarama.ConsumerGroup
-	nextConsumer      consumer.TracesConsumer
+	messageConsumer   messageConsumer
 	topics            []string
 	cancelConsumeLoop context.CancelFunc
-	unmarshaller      Unmarshaller
 
 	logger *zap.Logger
 }
 
 var _ component.Receiver = (*kafkaConsumer)(nil)
 
-func newReceiver(config Config, params component.ReceiverCreateParams, unmarshalers map[string]Unmarshaller, nextConsumer consumer.TracesConsumer) (*kafkaConsumer, error) {
+// messageConsumer unmarshals the body of a message and passes the result to the next consumer.
+type messageConsumer interface {
+	consume(ctx context.Context, body []byte) error
+}
+
+func newTracesReceiver(config Config, params component.ReceiverCreateParams, unmarshalers map[string]Unmarshaller, nextConsumer consumer.TracesConsumer) (*kafkaConsumer, error) {
+	unmarshaller := unmarshalers[config.Encoding]
+	if unmarshaller == nil {
+		return nil, errUnrecognizedEncoding
+	}
+	return newReceiver(config, params, &tracesMessageConsumer{
+		name:         config.Name(),
+		unmarshaller: unmarshaller,
+		nextConsumer: nextConsumer,
+	})
+}
+
+func newMetricsReceiver(config Config, params component.ReceiverCreateParams, unmarshalers map[string]MetricsUnmarshaller, nextConsumer consumer.MetricsConsumer) (*kafkaConsumer, error) {
+	unmarshaller := unmarshalers[config.Encoding]
+	if unmarshaller == nil {
+		return nil, errUnrecognizedEncoding
+	}
+	return newReceiver(config, params, &metricsMessageConsumer{
+		name:         config.Name(),
+		unmarshaller: unmarshaller,
+		nextConsumer: nextConsumer,
+	})
+}
+
+func newLogsReceiver(config Config, params component.ReceiverCreateParams, unmarshalers map[string]LogsUnmarshaller, nextConsumer consumer.LogsConsumer) (*kafkaConsumer, error) {
 	unmarshaller := unmarshalers[config.Encoding]
 	if unmarshaller == nil {
 		return nil, errUnrecognizedEncoding
 	}
+	return newReceiver(config, params, &logsMessageConsumer{
+		name:         config.Name(),
+		unmarshaller: unmarshaller,
+		nextConsumer: nextConsumer,
+	})
+}
 
+func newReceiver(config Config, params component.ReceiverCreateParams, messageConsumer messageConsumer) (*kafkaConsumer, error) {
 	c := sarama.NewConfig()
 	c.ClientID = config.ClientID
 	c.Metadata.Full = config.Metadata.Full
@@ -76,12 +111,11 @@ func newReceiver(config Config, params component.ReceiverCreateParams, unmarshal
 		return nil, err
 	}
 	return &kafkaConsumer{
-		name:          config.Name(),
-		consumerGroup: client,
-		topics:        []string{config.Topic},
-		nextConsumer:  nextConsumer,
-		unmarshaller:  unmarshaller,
-		logger:        params.Logger,
+		name:            config.Name(),
+		consumerGroup:   client,
+		topics:          []string{config.Topic},
+		messageConsumer: messageConsumer,
+		logger:          params.Logger,
 	}, nil
 }
 
@@ -89,11 +123,10 @@ func (c *kafkaConsumer) Start(context.Context, component.Host) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	c.cancelConsumeLoop = cancel
 	consumerGroup := &consumerGroupHandler{
-		name:         c.name,
-		logger:       c.logger,
-		unmarshaller: c.unmarshaller,
-		nextConsumer: c.nextConsumer,
-		ready:        make(chan bool),
+		name:            c.name,
+		logger:          c.logger,
+		messageConsumer: c.messageConsumer,
+		ready:           make(chan bool),
 	}
 	go c.consumeLoop(ctx, consumerGroup)
 	<-consumerGroup.ready
@@ -122,11 +155,10 @@ func (c *kafkaConsumer) Shutdown(context.Context) error {
 }
 
 type consumerGroupHandler struct {
-	name         string
-	unmarshaller Unmarshaller
-	nextConsumer consumer.TracesConsumer
-	ready        chan bool
-	readyCloser  sync.Once
+	name            string
+	messageConsumer messageConsumer
+	ready           chan bool
+	readyCloser     sync.Once
 
 	logger *zap.Logger
 }
@@ -157,25 +189,77 @@ func (c *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession,
 			zap.String("topic", message.Topic))
 		session.MarkMessage(message, "")
 
-		ctx := obsreport.ReceiverContext(session.Context(), c.name, transport)
-		ctx = obsreport.StartTraceDataReceiveOp(ctx, c.name, transport)
 		statsTags := []tag.Mutator{tag.Insert(tagInstanceName, c.name)}
-		_ = stats.RecordWithTags(ctx, statsTags,
+		_ = stats.RecordWithTags(session.Context(), statsTags,
 			statMessageCount.M(1),
 			statMessageOffset.M(message.Offset),
 			statMessageOffsetLag.M(claim.HighWaterMarkOffset()-message.Offset-1))
 
-		traces, err := c.unmarshaller.Unmarshal(message.Value)
-		if err != nil {
-			c.logger.Error("failed to unmarshall message", zap.Error(err))
-			return err
-		}
-
-		err = c.nextConsumer.ConsumeTraces(session.Context(), traces)
-		obsreport.EndTraceDataReceiveOp(ctx, c.unmarshaller.Encoding(), traces.SpanCount(), err)
-		if err != nil {
+		if err := c.messageConsumer.consume(session.Context(), message.Value); err != nil {
+			c.logger.Error("failed to consume message", zap.Error(err))
 			return err
 		}
 	}
 	return nil
 }
+
+type tracesMessageConsumer struct {
+	name         string
+	unmarshaller Unmarshaller
+	nextConsumer consumer.TracesConsumer
+}
+
+func (c *tracesMessageConsumer) consume(ctx context.Context, body []byte) error {
+	obsCtx := obsreport.ReceiverContext(ctx, c.name, transport)
+	obsCtx = obsreport.StartTraceDataReceiveOp(obsCtx, c.name, transport)
+	traces, err := c.unmarshaller.Unmarshal(body)
+	if err != nil {
+		obsreport.EndTraceDataReceiveOp(obsCtx, c.unmarshaller.Encoding(), 0, err)
+		return fmt.Errorf("failed to unmarshall message: %w", err)
+	}
+
+	err = c.nextConsumer.ConsumeTraces(ctx, traces)
+	obsreport.EndTraceDataReceiveOp(obsCtx, c.unmarshaller.Encoding(), traces.SpanCount(), err)
+	return err
+}
+
+type metricsMessageConsumer struct {
+	name         string
+	unmarshaller MetricsUnmarshaller
+	nextConsumer consumer.MetricsConsumer
+}
+
+func (c *metricsMessageConsumer) consume(ctx context.Context, body []byte) error {
+	obsCtx := obsreport.ReceiverContext(ctx, c.name, transport)
+	obsCtx = obsreport.StartMetricsReceiveOp(obsCtx, c.name, transport)
+	metrics, err := c.unmarshaller.Unmarshal(body)
+	if err != nil {
+		obsreport.EndMetricsReceiveOp(obsCtx, c.unmarshaller.Encoding(), 0, err)
+		return fmt.Errorf("failed to unmarshall message: %w", err)
+	}
+
+	_, numPoints := metrics.MetricAndDataPointCount()
+	err = c.nextConsumer.ConsumeMetrics(ctx, metrics)
+	obsreport.EndMetricsReceiveOp(obsCtx, c.unmarshaller.Encoding(), numPoints, err)
+	return err
+}
+
+type logsMessageConsumer struct {
+	name         string
+	unmarshaller LogsUnmarshaller
+	nextConsumer consumer.LogsConsumer
+}
+
+func (c *logsMessageConsumer) consume(ctx context.Context, body []byte) error {
+	obsCtx := obsreport.ReceiverContext(ctx, c.name, transport)
+	obsCtx = obsreport.StartLogsReceiveOp(obsCtx, c.name, transport)
+	logs, err := c.unmarshaller.Unmarshal(body)
+	if err != nil {
+		obsreport.EndLogsReceiveOp(obsCtx, c.unmarshaller.Encoding(), 0, err)
+		return fmt.Errorf("failed to unmarshall message: %w", err)
+	}
+
+	err = c.nextConsumer.ConsumeLogs(ctx, logs)
+	obsreport.EndLogsReceiveOp(obsCtx, c.unmarshaller.Encoding(), logs.LogRecordCount(), err)
+	return err
+}
@@ -34,7 +34,9 @@ import (
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/exporter/kafkaexporter"
+	otlpmetrics "go.opentelemetry.io/collector/internal/data/protogen/collector/metrics/v1"
 	otlptrace "go.opentelemetry.io/collector/internal/data/protogen/collector/trace/v1"
+	"go.opentelemetry.io/collector/internal/testdata"
 )
 
 func TestNewReceiver_version_err(t *testing.T) {
@@ -42,7 +44,7 @@ func TestNewReceiver_version_err(t *testing.T) {
 		Encoding:        defaultEncoding,
 		ProtocolVersion: "none",
 	}
-	r, err := newReceiver(c, component.ReceiverCreateParams{}, defaultUnmarshallers(), consumertest.NewTracesNop())
+	r, err := newTracesReceiver(c, component.ReceiverCreateParams{}, defaultUnmarshallers(), consumertest.NewTracesNop())
 	assert.Error(t, err)
 	assert.Nil(t, r)
 }
@@ -51,7 +53,7 @@ func TestNewReceiver_encoding_err(t *testing.T) {
 	c := Config{
 		Encoding: "foo",
 	}
-	r, err := newReceiver(c, component.ReceiverCreateParams{}, defaultUnmarshallers(), consumertest.NewTracesNop())
+	r, err := newTracesReceiver(c, component.ReceiverCreateParams{}, defaultUnmarshallers(), consumertest.NewTracesNop())
 	require.Error(t, err)
 	assert.Nil(t, r)
 	assert.EqualError(t, err, errUnrecognizedEncoding.Error())
@@ -72,7 +74,7 @@ func TestNewExporter_err_auth_type(t *testing.T) {
 			Full: false,
 		},
 	}
-	r, err := newReceiver(c, component.ReceiverCreateParams{}, defaultUnmarshallers(), consumertest.NewTracesNop())
+	r, err := newTracesReceiver(c, component.ReceiverCreateParams{}, defaultUnmarshallers(), consumertest.NewTracesNop())
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to load TLS config")
 	assert.Nil(t, r)
@@ -81,9 +83,9 @@ func TestNewExporter_err_auth_type(t *testing.T) {
 func TestReceiverStart(t *testing.T) {
 	testClient := testConsumerGroup{once: &sync.Once{}}
 	c := kafkaConsumer{
-		nextConsumer:  consumertest.NewTracesNop(),
-		logger:        zap.NewNop(),
-		consumerGroup: testClient,
+		messageConsumer: &tracesMessageConsumer{nextConsumer: consumertest.NewTracesNop()},
+		logger:          zap.NewNop(),
+		consumerGroup:   testClient,
 	}
 
 	err := c.Start(context.Background(), nil)
@@ -94,9 +96,9 @@ func TestReceiverStart(t *testing.T) {
 func TestReceiverStartConsume(t *testing.T) {
 	testClient := testConsumerGroup{once: &sync.Once{}}
 	c := kafkaConsumer{
-		nextConsumer:  consumertest.NewTracesNop(),
-		logger:        zap.NewNop(),
-		consumerGroup: testClient,
+		messageConsumer: &tracesMessageConsumer{nextConsumer: consumertest.NewTracesNop()},
+		logger:          zap.NewNop(),
+		consumerGroup:   testClient,
 	}
 	ctx, cancelFunc := context.WithCancel(context.Background())
 	c.cancelConsumeLoop = cancelFunc
@@ -114,9 +116,9 @@ func TestReceiver_error(t *testing.T) {
 	expectedErr := fmt.Errorf("handler error")
 	testClient := testConsumerGroup{once: &sync.Once{}, err: expectedErr}
 	c := kafkaConsumer{
-		nextConsumer:  consumertest.NewTracesNop(),
-		logger:        logger,
-		consumerGroup: testClient,
+		messageConsumer: &tracesMessageConsumer{nextConsumer: consumertest.NewTracesNop()},
+		logger:          logger,
+		consumerGroup:   testClient,
 	}
 
 	err := c.Start(context.Background(), nil)
@@ -134,10 +136,12 @@ func TestConsumerGroupHandler(t *testing.T) {
 	defer view.Unregister(views...)
 
 	c := consumerGroupHandler{
-		unmarshaller: &otlpProtoUnmarshaller{},
-		logger:       zap.NewNop(),
-		ready:        make(chan bool),
-		nextConsumer: consumertest.NewTracesNop(),
+		messageConsumer: &tracesMessageConsumer{
+			unmarshaller: &otlpProtoUnmarshaller{},
+			nextConsumer: consumertest.NewTracesNop(),
+		},
+		logger: zap.NewNop(),
+		ready:  make(chan bool),
 	}
 
 	testSession := testConsumerGroupSession{}
@@ -178,10 +182,12 @@ func TestConsumerGroupHandler(t *testing.T) {
 
 func TestConsumerGroupHandler_error_unmarshall(t *testing.T) {
 	c := consumerGroupHandler{
-		unmarshaller: &otlpProtoUnmarshaller{},
-		logger:       zap.NewNop(),
-		ready:        make(chan bool),
-		nextConsumer: consumertest.NewTracesNop(),
+		messageConsumer: &tracesMessageConsumer{
+			unmarshaller: &otlpProtoUnmarshaller{},
+			nextConsumer: consumertest.NewTracesNop(),
+		},
+		logger: zap.NewNop(),
+		ready:  make(chan bool),
 	}
 
 	wg := sync.WaitGroup{}
@@ -204,10 +210,12 @@ func TestConsumerGroupHandler_error_nextConsumer(t *testing.T) {
 	consumerError := fmt.Errorf("failed to consumer")
 	nextConsumer.SetConsumeError(consumerError)
 	c := consumerGroupHandler{
-		unmarshaller: &otlpProtoUnmarshaller{},
-		logger:       zap.NewNop(),
-		ready:        make(chan bool),
-		nextConsumer: nextConsumer,
+		messageConsumer: &tracesMessageConsumer{
+			unmarshaller: &otlpProtoUnmarshaller{},
+			nextConsumer: nextConsumer,
+		},
+		logger: zap.NewNop(),
+		ready:  make(chan bool),
 	}
 
 	wg := sync.WaitGroup{}
@@ -233,6 +241,92 @@ func TestConsumerGroupHandler_error_nextConsumer(t *testing.T) {
 	wg.Wait()
 }
 
+func TestConsumerGroupHandler_metrics(t *testing.T) {
+	nextConsumer := new(consumertest.MetricsSink)
+	c := consumerGroupHandler{
+		messageConsumer: &metricsMessageConsumer{
+			unmarshaller: &otlpMetricsProtoUnmarshaller{},
+			nextConsumer: nextConsumer,
+		},
+		logger: zap.NewNop(),
+		ready:  make(chan bool),
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	groupClaim := &testConsumerGroupClaim{
+		messageChan: make(chan *sarama.ConsumerMessage),
+	}
+	go func() {
+		err := c.ConsumeClaim(testConsumerGroupSession{}, groupClaim)
+		assert.NoError(t, err)
+		wg.Done()
+	}()
+
+	request := &otlpmetrics.ExportMetricsServiceRequest{
+		ResourceMetrics: pdata.MetricsToOtlp(testdata.GenerateMetricsOneMetric()),
+	}
+	bts, err := request.Marshal()
+	require.NoError(t, err)
+	groupClaim.messageChan <- &sarama.ConsumerMessage{Value: bts}
+	close(groupClaim.messageChan)
+	wg.Wait()
+	assert.Len(t, nextConsumer.AllMetrics(), 1)
+}
+
+func TestConsumerGroupHandler_metrics_error_unmarshall(t *testing.T) {
+	c := consumerGroupHandler{
+		messageConsumer: &metricsMessageConsumer{
+			unmarshaller: &otlpMetricsProtoUnmarshaller{},
+			nextConsumer: consumertest.NewMetricsNop(),
+		},
+		logger: zap.NewNop(),
+		ready:  make(chan bool),
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	groupClaim := &testConsumerGroupClaim{
+		messageChan: make(chan *sarama.ConsumerMessage),
+	}
+	go func() {
+		err := c.ConsumeClaim(testConsumerGroupSession{}, groupClaim)
+		assert.Error(t, err)
+		wg.Done()
+	}()
+	groupClaim.messageChan <- &sarama.ConsumerMessage{Value: []byte("!@#")}
+	close(groupClaim.messageChan)
+	wg.Wait()
+}
+
+func TestConsumerGroupHandler_logs(t *testing.T) {
+	nextConsumer := new(consumertest.LogsSink)
+	c := consumerGroupHandler{
+		messageConsumer: &logsMessageConsumer{
+			unmarshaller: &otlpLogsJSONUnmarshaller{},
+			nextConsumer: nextConsumer,
+		},
+		logger: zap.NewNop(),
+		ready:  make(chan bool),
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	groupClaim := &testConsumerGroupClaim{
+		messageChan: make(chan *sarama.ConsumerMessage),
+	}
+	go func() {
+		err := c.ConsumeClaim(testConsumerGroupSession{}, groupClaim)
+		assert.NoError(t, err)
+		wg.Done()
+	}()
+
+	groupClaim.messageChan <- &sarama.ConsumerMessage{Value: []byte(`{"resourceLogs":[{"instrumentationLibraryLogs":[{"logs":[{"name":"log"}]}]}]}`)}
+	close(groupClaim.messageChan)
+	wg.Wait()
+	assert.Equal(t, 1, nextConsumer.LogRecordsCount())
+}
+
 type testConsumerGroupClaim struct {
 	messageChan chan *sarama.ConsumerMessage
 }
@@ -15,10 +15,19 @@
 package kafkareceiver
 
 import (
+	"bytes"
+
+	"github.com/gogo/protobuf/jsonpb"
+
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal"
+	otlplogs "go.opentelemetry.io/collector/internal/data/protogen/collector/logs/v1"
+	otlpmetrics "go.opentelemetry.io/collector/internal/data/protogen/collector/metrics/v1"
 	otlptrace "go.opentelemetry.io/collector/internal/data/protogen/collector/trace/v1"
 )
 
+const otlpJSONEncoding = "otlp_json"
+
 type otlpProtoUnmarshaller struct {
 }
 
@@ -36,3 +45,93 @@ func (p *otlpProtoUnmarshaller) Unmarshal(bytes []byte) (pdata.Traces, error) {
 func (*otlpProtoUnmarshaller) Encoding() string {
 	return defaultEncoding
 }
+
+type otlpJSONUnmarshaller struct {
+}
+
+var _ Unmarshaller = (*otlpJSONUnmarshaller)(nil)
+
+func (p *otlpJSONUnmarshaller) Unmarshal(data []byte) (pdata.Traces, error) {
+	request := &otlptrace.ExportTraceServiceRequest{}
+	err := jsonpb.Unmarshal(bytes.NewReader(data), request)
+	if err != nil {
+		return pdata.NewTraces(), err
+	}
+	return pdata.TracesFromOtlp(request.GetResourceSpans()), nil
+}
+
+func (*otlpJSONUnmarshaller) Encoding() string {
+	return otlpJSONEncoding
+}
+
+type otlpMetricsProtoUnmarshaller struct {
+}
+
+var _ MetricsUnmarshaller = (*otlpMetricsProtoUnmarshaller)(nil)
+
+func (p *otlpMetricsProtoUnmarshaller) Unmarshal(bytes []byte) (pdata.Metrics, error) {
+	request := &otlpmetrics.ExportMetricsServiceRequest{}
+	err := request.Unmarshal(bytes)
+	if err != nil {
+		return pdata.NewMetrics(), err
+	}
+	return pdata.MetricsFromOtlp(request.GetResourceMetrics()), nil
+}
+
+func (*otlpMetricsProtoUnmarshaller) Encoding() string {
+	return defaultEncoding
+}
+
+type otlpMetricsJSONUnmarshaller struct {
+}
+
+var _ MetricsUnmarshaller = (*otlpMetricsJSONUnmarshaller)(nil)
+
+func (p *otlpMetricsJSONUnmarshaller) Unmarshal(data []byte) (pdata.Metrics, error) {
+	request := &otlpmetrics.ExportMetricsServiceRequest{}
+	err := jsonpb.Unmarshal(bytes.NewReader(data), request)
+	if err != nil {
+		return pdata.NewMetrics(), err
+	}
+	return pdata.MetricsFromOtlp(request.GetResourceMetrics()), nil
+}
+
+func (*otlpMetricsJSONUnmarshaller) Encoding() string {
+	return otlpJSONEncoding
+}
+
+type otlpLogsProtoUnmarshaller struct {
+}
+
+var _ LogsUnmarshaller = (*otlpLogsProtoUnmarshaller)(nil)
+
+func (p *otlpLogsProtoUnmarshaller) Unmarshal(bytes []byte) (pdata.Logs, error) {
+	request := &otlplogs.ExportLogsServiceRequest{}
+	err := request.Unmarshal(bytes)
+	if err != nil {
+		return pdata.NewLogs(), err
+	}
+	return pdata.LogsFromInternalRep(internal.LogsFromOtlp(request.GetResourceLogs())), nil
+}
+
+func (*otlpLogsProtoUnmarshaller) Encoding() string {
+	return defaultEncoding
+}
+
+type otlpLogsJSONUnmarshaller struct {
+}
+
+var _ LogsUnmarshaller = (*otlpLogsJSONUnmarshaller)(nil)
+
+func (p *otlpLogsJSONUnmarshaller) Unmarshal(data []byte) (pdata.Logs, error) {
+	request := &otlplogs.ExportLogsServiceRequest{}
+	err := jsonpb.Unmarshal(bytes.NewReader(data), request)
+	if err != nil {
+		return pdata.NewLogs(), err
+	}
+	return pdata.LogsFromInternalRep(internal.LogsFromOtlp(request.GetResourceLogs())), nil
+}
+
+func (*otlpLogsJSONUnmarshaller) Encoding() string {
+	return otlpJSONEncoding
+}
@@ -15,13 +15,20 @@
 package kafkareceiver
 
 import (
+	"bytes"
 	"testing"
 
+	"github.com/gogo/protobuf/jsonpb"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal"
+	otlplogs "go.opentelemetry.io/collector/internal/data/protogen/collector/logs/v1"
+	otlpmetrics "go.opentelemetry.io/collector/internal/data/protogen/collector/metrics/v1"
 	otlptrace "go.opentelemetry.io/collector/internal/data/protogen/collector/trace/v1"
+	"go.opentelemetry.io/collector/internal/testdata"
 )
 
 func TestUnmarshallOTLP(t *testing.T) {
@@ -47,3 +54,85 @@ func TestUnmarshallOTLP_error(t *testing.T) {
 	assert.Equal(t, pdata.NewTraces(), got)
 	assert.Error(t, err)
 }
+
+func TestUnmarshallOTLPJSON(t *testing.T) {
+	td := testdata.GenerateTraceDataOneSpan()
+	request := &otlptrace.ExportTraceServiceRequest{
+		ResourceSpans: pdata.TracesToOtlp(td),
+	}
+	buf := &bytes.Buffer{}
+	require.NoError(t, (&jsonpb.Marshaler{}).Marshal(buf, request))
+
+	p := otlpJSONUnmarshaller{}
+	got, err := p.Unmarshal(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, td, got)
+	assert.Equal(t, "otlp_json", p.Encoding())
+
+	_, err = p.Unmarshal([]byte("+$%"))
+	assert.Error(t, err)
+}
+
+func TestUnmarshallOTLPMetrics(t *testing.T) {
+	md := testdata.GenerateMetricsOneMetric()
+	request := &otlpmetrics.ExportMetricsServiceRequest{
+		ResourceMetrics: pdata.MetricsToOtlp(md),
+	}
+	protoBytes, err := request.Marshal()
+	require.NoError(t, err)
+	jsonBuf := &bytes.Buffer{}
+	require.NoError(t, (&jsonpb.Marshaler{}).Marshal(jsonBuf, request))
+
+	tests := []struct {
+		unmarshaller MetricsUnmarshaller
+		encoding     string
+		bytes        []byte
+	}{
+		{unmarshaller: &otlpMetricsProtoUnmarshaller{}, encoding: "otlp_proto", bytes: protoBytes},
+		{unmarshaller: &otlpMetricsJSONUnmarshaller{}, encoding: "otlp_json", bytes: jsonBuf.Bytes()},
+	}
+	for _, test := range tests {
+		t.Run(test.encoding, func(t *testing.T) {
+			got, err := test.unmarshaller.Unmarshal(test.bytes)
+			require.NoError(t, err)
+			assert.Equal(t, md, got)
+			assert.Equal(t, test.encoding, test.unmarshaller.Encoding())
+
+			got, err = test.unmarshaller.Unmarshal([]byte("+$%"))
+			assert.Error(t, err)
+			assert.Equal(t, pdata.NewMetrics(), got)
+		})
+	}
+}
+
+func TestUnmarshallOTLPLogs(t *testing.T) {
+	ld := testdata.GenerateLogDataOneLog()
+	request := &otlplogs.ExportLogsServiceRequest{
+		ResourceLogs: internal.LogsToOtlp(ld.InternalRep()),
+	}
+	protoBytes, err := request.Marshal()
+	require.NoError(t, err)
+	jsonBuf := &bytes.Buffer{}
+	require.NoError(t, (&jsonpb.Marshaler{}).Marshal(jsonBuf, request))
+
+	tests := []struct {
+		unmarshaller LogsUnmarshaller
+		encoding     string
+		bytes        []byte
+	}{
+		{unmarshaller: &otlpLogsProtoUnmarshaller{}, encoding: "otlp_proto", bytes: protoBytes},
+		{unmarshaller: &otlpLogsJSONUnmarshaller{}, encoding: "otlp_json", bytes: jsonBuf.Bytes()},
+	}
+	for _, test := range tests {
+		t.Run(test.encoding, func(t *testing.T) {
+			got, err := test.unmarshaller.Unmarshal(test.bytes)
+			require.NoError(t, err)
+			assert.Equal(t, ld, got)
+			assert.Equal(t, test.encoding, test.unmarshaller.Encoding())
+
+			got, err = test.unmarshaller.Unmarshal([]byte("+$%"))
+			assert.Error(t, err)
+			assert.Equal(t, pdata.NewLogs(), got)
+		})
+	}
+}
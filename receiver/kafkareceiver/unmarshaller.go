@@ -27,9 +27,28 @@ type Unmarshaller interface {
 	Encoding() string
 }
 
+// MetricsUnmarshaller deserializes the message body.
+type MetricsUnmarshaller interface {
+	// Unmarshal deserializes the message body into metrics.
+	Unmarshal([]byte) (pdata.Metrics, error)
+
+	// Encoding of the serialized messages.
+	Encoding() string
+}
+
+// LogsUnmarshaller deserializes the message body.
+type LogsUnmarshaller interface {
+	// Unmarshal deserializes the message body into logs.
+	Unmarshal([]byte) (pdata.Logs, error)
+
+	// Encoding of the serialized messages.
+	Encoding() string
+}
+
 // defaultUnmarshallers returns map of supported encodings with Unmarshaller.
 func defaultUnmarshallers() map[string]Unmarshaller {
 	otlp := &otlpProtoUnmarshaller{}
+	otlpJSON := &otlpJSONUnmarshaller{}
 	jaegerProto := jaegerProtoSpanUnmarshaller{}
 	jaegerJSON := jaegerJSONSpanUnmarshaller{}
 	zipkinProto := zipkinProtoSpanUnmarshaller{}
@@ -37,6 +56,7 @@ func defaultUnmarshallers() map[string]Unmarshaller {
 	zipkinThrift := zipkinThriftSpanUnmarshaller{}
 	return map[string]Unmarshaller{
 		otlp.Encoding():         otlp,
+		otlpJSON.Encoding():     otlpJSON,
 		jaegerProto.Encoding():  jaegerProto,
 		jaegerJSON.Encoding():   jaegerJSON,
 		zipkinProto.Encoding():  zipkinProto,
@@ -44,3 +64,23 @@ func defaultUnmarshallers() map[string]Unmarshaller {
 		zipkinThrift.Encoding(): zipkinThrift,
 	}
 }
+
+// defaultMetricsUnmarshallers returns map of supported encodings with MetricsUnmarshaller.
+func defaultMetricsUnmarshallers() map[string]MetricsUnmarshaller {
+	otlp := &otlpMetricsProtoUnmarshaller{}
+	otlpJSON := &otlpMetricsJSONUnmarshaller{}
+	return map[string]MetricsUnmarshaller{
+		otlp.Encoding():     otlp,
+		otlpJSON.Encoding(): otlpJSON,
+	}
+}
+
+// defaultLogsUnmarshallers returns map of supported encodings with LogsUnmarshaller.
+func defaultLogsUnmarshallers() map[string]LogsUnmarshaller {
+	otlp := &otlpLogsProtoUnmarshaller{}
+	otlpJSON := &otlpLogsJSONUnmarshaller{}
+	return map[string]LogsUnmarshaller{
+		otlp.Encoding():     otlp,
+		otlpJSON.Encoding(): otlpJSON,
+	}
+}
@@ -39,13 +39,16 @@ func (rOpts *Config) buildOptions() ([]ocOption, error) {
 		opts = append(opts, withCorsOrigins(rOpts.CorsOrigins))
 	}
 
-	grpcServerOptions, err := rOpts.GRPCServerSettings.ToServerOption()
+	grpcServerOptions, auth, err := rOpts.GRPCServerSettings.ToServerOption()
 	if err != nil {
 		return nil, err
 	}
 	if len(grpcServerOptions) > 0 {
 		opts = append(opts, withGRPCServerOptions(grpcServerOptions...))
 	}
+	if auth != nil {
+		opts = append(opts, withAuthenticator(auth))
+	}
 
 	return opts, nil
 }
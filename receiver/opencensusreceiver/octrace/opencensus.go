@@ -77,7 +77,7 @@ var errTraceExportProtocolViolation = errors.New("protocol violation: Export's f
 // OpenCensus-traceproto compatible libraries/applications.
 func (ocr *Receiver) Export(tes agenttracepb.TraceService_ExportServer) error {
 	ctx := tes.Context()
-	if c, ok := client.FromGRPC(ctx); ok {
+	if c, ok := client.FromGRPC(ctx, client.WithReceiver(ocr.instanceName)); ok {
 		ctx = client.NewContext(ctx, c)
 	}
 
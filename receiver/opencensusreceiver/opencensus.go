@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"sync"
@@ -46,6 +47,8 @@ type ocReceiver struct {
 	gatewayMux        *gatewayruntime.ServeMux
 	corsOrigins       []string
 	grpcServerOptions []grpc.ServerOption
+	// auth stops the authenticator of the gRPC server, if any.
+	auth io.Closer
 
 	traceReceiverOpts []octrace.Option
 
@@ -74,14 +77,7 @@ func newOpenCensusReceiver(
 	mc consumer.MetricsConsumer,
 	opts ...ocOption,
 ) (*ocReceiver, error) {
-	// TODO: (@odeke-em) use options to enable address binding changes.
-	ln, err := net.Listen(transport, addr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to bind to address %q: %v", addr, err)
-	}
-
 	ocr := &ocReceiver{
-		ln:          ln,
 		corsOrigins: []string{}, // Disable CORS by default.
 		gatewayMux:  gatewayruntime.NewServeMux(),
 	}
@@ -90,6 +86,16 @@ func newOpenCensusReceiver(
 		opt.withReceiver(ocr)
 	}
 
+	// TODO: (@odeke-em) use options to enable address binding changes.
+	ln, err := net.Listen(transport, addr)
+	if err != nil {
+		if ocr.auth != nil {
+			_ = ocr.auth.Close()
+		}
+		return nil, fmt.Errorf("failed to bind to address %q: %v", addr, err)
+	}
+	ocr.ln = ln
+
 	ocr.instanceName = instanceName
 	ocr.traceConsumer = tc
 	ocr.metricsConsumer = mc
@@ -198,6 +204,10 @@ func (ocr *ocReceiver) stop() error {
 			_ = ocr.ln.Close()
 		}
 
+		if ocr.auth != nil {
+			err = ocr.auth.Close()
+		}
+
 		// TODO: @(odeke-em) investigate what utility invoking (*grpc.Server).Stop()
 		// gives us yet we invoke (net.Listener).Close().
 		// Sure (*grpc.Server).Stop() enables proper shutdown but imposes
@@ -15,6 +15,8 @@
 package opencensusreceiver
 
 import (
+	"io"
+
 	"google.golang.org/grpc"
 )
 
@@ -54,3 +56,18 @@ func withGRPCServerOptions(gsOpts ...grpc.ServerOption) ocOption {
 	gsvOpts := grpcServerOptions(gsOpts)
 	return gsvOpts
 }
+
+var _ ocOption = (*authenticator)(nil)
+
+type authenticator struct {
+	closer io.Closer
+}
+
+func (a *authenticator) withReceiver(ocr *ocReceiver) {
+	ocr.auth = a.closer
+}
+
+// withAuthenticator sets the authenticator of the gRPC server, closed when the receiver is stopped.
+func withAuthenticator(auth io.Closer) ocOption {
+	return &authenticator{closer: auth}
+}
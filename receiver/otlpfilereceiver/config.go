@@ -0,0 +1,52 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpfilereceiver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines configuration for OTLP file receiver.
+type Config struct {
+	configmodels.ReceiverSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+
+	// Include is the list of glob patterns of the files to read. The files are
+	// read in the order of the patterns, and in lexical order for each pattern.
+	Include []string `mapstructure:"include"`
+
+	// Format is the encoding of the requests in the files, json (the default)
+	// or proto, as written by the file exporter.
+	Format string `mapstructure:"format"`
+
+	// RequestsPerSecond limits the rate at which the requests are replayed.
+	// The requests are replayed as fast as possible if not set.
+	RequestsPerSecond float64 `mapstructure:"requests_per_second"`
+
+	// RebaseTimestamps shifts all the timestamps so that the earliest timestamp
+	// of the first replayed request is the time at which it is replayed. The
+	// relative timing of the data is kept.
+	RebaseTimestamps bool `mapstructure:"rebase_timestamps"`
+
+	// Follow keeps reading the files as data is appended to them, and reads the
+	// files matching the patterns that are created later. The files are read
+	// once if not set.
+	Follow bool `mapstructure:"follow"`
+
+	// PollInterval is the interval at which the files are checked for new data
+	// in follow mode.
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+}
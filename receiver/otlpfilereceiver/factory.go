@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpfilereceiver
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/exporter/fileexporter"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+)
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "otlpfile"
+
+	defaultPollInterval = time.Second
+)
+
+// NewFactory creates a factory for OTLP file receiver.
+func NewFactory() component.ReceiverFactory {
+	return receiverhelper.NewFactory(
+		typeStr,
+		createDefaultConfig,
+		receiverhelper.WithTraces(createTraceReceiver),
+		receiverhelper.WithMetrics(createMetricsReceiver),
+		receiverhelper.WithLogs(createLogsReceiver))
+}
+
+func createDefaultConfig() configmodels.Receiver {
+	return &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: typeStr,
+			NameVal: typeStr,
+		},
+		Format:       fileexporter.FormatJSON,
+		PollInterval: defaultPollInterval,
+	}
+}
+
+func createTraceReceiver(
+	_ context.Context,
+	params component.ReceiverCreateParams,
+	cfg configmodels.Receiver,
+	nextConsumer consumer.TracesConsumer,
+) (component.TracesReceiver, error) {
+	oCfg := cfg.(*Config)
+	return newFileReceiver(params.Logger, oCfg, &tracesReplayer{
+		name:         oCfg.Name(),
+		format:       oCfg.Format,
+		nextConsumer: nextConsumer,
+	})
+}
+
+func createMetricsReceiver(
+	_ context.Context,
+	params component.ReceiverCreateParams,
+	cfg configmodels.Receiver,
+	nextConsumer consumer.MetricsConsumer,
+) (component.MetricsReceiver, error) {
+	oCfg := cfg.(*Config)
+	return newFileReceiver(params.Logger, oCfg, &metricsReplayer{
+		name:         oCfg.Name(),
+		format:       oCfg.Format,
+		nextConsumer: nextConsumer,
+	})
+}
+
+func createLogsReceiver(
+	_ context.Context,
+	params component.ReceiverCreateParams,
+	cfg configmodels.Receiver,
+	nextConsumer consumer.LogsConsumer,
+) (component.LogsReceiver, error) {
+	oCfg := cfg.(*Config)
+	return newFileReceiver(params.Logger, oCfg, &logsReplayer{
+		name:         oCfg.Name(),
+		format:       oCfg.Format,
+		nextConsumer: nextConsumer,
+	})
+}
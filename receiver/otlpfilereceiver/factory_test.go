@@ -0,0 +1,101 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpfilereceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	cfg := createDefaultConfig()
+	assert.NotNil(t, cfg, "failed to create default config")
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+}
+
+func TestCreateReceivers(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Include = []string{"testdata/*.json"}
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+
+	tr, err := createTraceReceiver(context.Background(), params, cfg, consumertest.NewTracesNop())
+	require.NoError(t, err)
+	assert.NotNil(t, tr)
+
+	mr, err := createMetricsReceiver(context.Background(), params, cfg, consumertest.NewMetricsNop())
+	require.NoError(t, err)
+	assert.NotNil(t, mr)
+
+	lr, err := createLogsReceiver(context.Background(), params, cfg, consumertest.NewLogsNop())
+	require.NoError(t, err)
+	assert.NotNil(t, lr)
+}
+
+func TestCreateReceiverInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+	}{
+		{
+			name:   "no include",
+			modify: func(cfg *Config) {},
+		},
+		{
+			name: "invalid pattern",
+			modify: func(cfg *Config) {
+				cfg.Include = []string{"testdata/[.json"}
+			},
+		},
+		{
+			name: "unknown format",
+			modify: func(cfg *Config) {
+				cfg.Include = []string{"testdata/*.json"}
+				cfg.Format = "yaml"
+			},
+		},
+		{
+			name: "negative rate",
+			modify: func(cfg *Config) {
+				cfg.Include = []string{"testdata/*.json"}
+				cfg.RequestsPerSecond = -1
+			},
+		},
+		{
+			name: "no poll interval",
+			modify: func(cfg *Config) {
+				cfg.Include = []string{"testdata/*.json"}
+				cfg.Follow = true
+				cfg.PollInterval = 0
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tt.modify(cfg)
+			r, err := createTraceReceiver(context.Background(), component.ReceiverCreateParams{Logger: zap.NewNop()}, cfg, consumertest.NewTracesNop())
+			assert.Error(t, err)
+			assert.Nil(t, r)
+		})
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpfilereceiver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/gogo/protobuf/proto"
+
+	"go.opentelemetry.io/collector/exporter/fileexporter"
+)
+
+const readChunkSize = 64 * 1024
+
+var (
+	errInvalidKey  = errors.New("invalid request key")
+	errInvalidSize = errors.New("invalid request size")
+	errTruncated   = errors.New("truncated request at the end of the file")
+	errNotObject   = errors.New("request is not a JSON object")
+)
+
+// fileReader reads the requests of a file. The data following the last
+// complete request is kept, so that the file can be followed as it is written.
+type fileReader struct {
+	path   string
+	format string
+	file   *os.File
+	info   os.FileInfo
+	reader io.Reader
+	// compressed is set for gzip files, which are read as a stream and can't
+	// be followed.
+	compressed bool
+	// offset is the number of bytes read from the file.
+	offset int64
+	// buf holds the data read but not yet returned as requests.
+	buf   []byte
+	chunk []byte
+	// err is the error that stopped the reading of the file.
+	err error
+}
+
+func openFile(path string, format string) (*fileReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	fr := &fileReader{
+		path:   path,
+		format: format,
+		file:   file,
+		info:   info,
+		reader: file,
+		chunk:  make([]byte, readChunkSize),
+	}
+	if filepath.Ext(path) == ".gz" {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		fr.reader = gz
+		fr.compressed = true
+	}
+	return fr, nil
+}
+
+// next returns the next request of the file and the field number of its data
+// type, or nil when the end of the file is reached. The data after the last
+// complete request is returned as a last request if final is set, otherwise it
+// is kept until more data is written.
+func (fr *fileReader) next(final bool) (int, []byte, error) {
+	for {
+		fieldNumber, request, n, err := splitRequest(fr.buf, fr.format)
+		if err != nil {
+			return 0, nil, err
+		}
+		if n > 0 {
+			fr.buf = fr.buf[n:]
+			if len(request) == 0 {
+				continue
+			}
+			return fieldNumber, request, nil
+		}
+
+		count, err := fr.reader.Read(fr.chunk)
+		fr.buf = append(fr.buf, fr.chunk[:count]...)
+		fr.offset += int64(count)
+		if count > 0 {
+			continue
+		}
+		if err == io.EOF {
+			if !final {
+				return 0, nil, nil
+			}
+			request, err := fr.remaining()
+			return 0, request, err
+		}
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+}
+
+// remaining returns the data after the last complete request. A JSON request
+// not followed by a newline is complete, but a length-delimited one is not.
+func (fr *fileReader) remaining() ([]byte, error) {
+	request := bytes.TrimSpace(fr.buf)
+	fr.buf = nil
+	if len(request) == 0 {
+		return nil, nil
+	}
+	if fr.format == fileexporter.FormatProto {
+		return nil, errTruncated
+	}
+	return request, nil
+}
+
+// splitRequest returns the first complete request of buf, and the number of
+// bytes it takes, zero if buf doesn't hold a complete request. The field number
+// of the data type of the request is returned for the proto format, the JSON
+// requests being identified by their content.
+func splitRequest(buf []byte, format string) (int, []byte, int, error) {
+	if format == fileexporter.FormatProto {
+		key, k := binary.Uvarint(buf)
+		if k < 0 || (k > 0 && (key&7 != proto.WireBytes || key>>3 == 0)) {
+			return 0, nil, 0, errInvalidKey
+		}
+		if k == 0 {
+			return 0, nil, 0, nil
+		}
+		size, n := binary.Uvarint(buf[k:])
+		if n < 0 {
+			return 0, nil, 0, errInvalidSize
+		}
+		if n == 0 || uint64(len(buf)-k-n) < size {
+			return 0, nil, 0, nil
+		}
+		start := k + n
+		end := start + int(size)
+		return int(key >> 3), buf[start:end], end, nil
+	}
+	i := bytes.IndexByte(buf, '\n')
+	if i < 0 {
+		return 0, nil, 0, nil
+	}
+	return 0, bytes.TrimSpace(buf[:i]), i + 1, nil
+}
+
+// jsonFieldNumber returns the field number of the data type of a JSON request,
+// given by its first field, or zero for an empty request.
+func jsonFieldNumber(request []byte) (int, error) {
+	decoder := json.NewDecoder(bytes.NewReader(request))
+	token, err := decoder.Token()
+	if err != nil {
+		return 0, err
+	}
+	if token != json.Delim('{') {
+		return 0, errNotObject
+	}
+	if !decoder.More() {
+		return 0, nil
+	}
+	if token, err = decoder.Token(); err != nil {
+		return 0, err
+	}
+	switch token {
+	case "resourceSpans", "resource_spans":
+		return fileexporter.TracesFieldNumber, nil
+	case "resourceMetrics", "resource_metrics":
+		return fileexporter.MetricsFieldNumber, nil
+	case "resourceLogs", "resource_logs":
+		return fileexporter.LogsFieldNumber, nil
+	}
+	return 0, fmt.Errorf("unknown request field %v", token)
+}
+
+// checkTruncated restarts reading the file from the beginning if it was
+// truncated since it was last read.
+func (fr *fileReader) checkTruncated() error {
+	if fr.compressed {
+		return nil
+	}
+	info, err := fr.file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() >= fr.offset {
+		return nil
+	}
+	if _, err := fr.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	fr.offset = 0
+	fr.buf = nil
+	return nil
+}
+
+func (fr *fileReader) close() error {
+	return fr.file.Close()
+}
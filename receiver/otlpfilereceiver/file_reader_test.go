@@ -0,0 +1,131 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpfilereceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/exporter/fileexporter"
+)
+
+func TestSplitRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      string
+		buf         []byte
+		fieldNumber int
+		request     []byte
+		n           int
+		err         error
+	}{
+		{
+			name:   "json empty",
+			format: fileexporter.FormatJSON,
+		},
+		{
+			name:   "json incomplete",
+			format: fileexporter.FormatJSON,
+			buf:    []byte(`{"resourceSpans"`),
+		},
+		{
+			name:    "json line",
+			format:  fileexporter.FormatJSON,
+			buf:     []byte("{}\r\n{}"),
+			request: []byte("{}"),
+			n:       4,
+		},
+		{
+			name:   "json empty line",
+			format: fileexporter.FormatJSON,
+			buf:    []byte("\n{}"),
+			n:      1,
+		},
+		{
+			name:   "proto incomplete key",
+			format: fileexporter.FormatProto,
+			buf:    []byte{0x80},
+		},
+		{
+			name:   "proto incomplete size",
+			format: fileexporter.FormatProto,
+			buf:    []byte{0x0a, 0x80},
+		},
+		{
+			name:   "proto incomplete message",
+			format: fileexporter.FormatProto,
+			buf:    []byte{0x0a, 3, 1, 2},
+		},
+		{
+			name:        "proto message",
+			format:      fileexporter.FormatProto,
+			buf:         []byte{0x12, 2, 1, 2, 3},
+			fieldNumber: fileexporter.MetricsFieldNumber,
+			request:     []byte{1, 2},
+			n:           4,
+		},
+		{
+			name:   "proto invalid wire type",
+			format: fileexporter.FormatProto,
+			buf:    []byte{0x08, 1},
+			err:    errInvalidKey,
+		},
+		{
+			name:   "proto invalid field number",
+			format: fileexporter.FormatProto,
+			buf:    []byte{0x02, 1, 1},
+			err:    errInvalidKey,
+		},
+		{
+			name:   "proto invalid size",
+			format: fileexporter.FormatProto,
+			buf:    []byte{0x0a, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01},
+			err:    errInvalidSize,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fieldNumber, request, n, err := splitRequest(tt.buf, tt.format)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.fieldNumber, fieldNumber)
+			assert.Equal(t, tt.request, request)
+			assert.Equal(t, tt.n, n)
+		})
+	}
+}
+
+func TestJSONFieldNumber(t *testing.T) {
+	tests := []struct {
+		request     string
+		fieldNumber int
+		err         bool
+	}{
+		{request: `{"resourceSpans":[]}`, fieldNumber: fileexporter.TracesFieldNumber},
+		{request: `{"resource_metrics":[]}`, fieldNumber: fileexporter.MetricsFieldNumber},
+		{request: ` { "resourceLogs" : [] }`, fieldNumber: fileexporter.LogsFieldNumber},
+		{request: `{}`},
+		{request: `{"unknown":[]}`, err: true},
+		{request: `[]`, err: true},
+		{request: `{invalid`, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.request, func(t *testing.T) {
+			fieldNumber, err := jsonFieldNumber([]byte(tt.request))
+			assert.Equal(t, tt.err, err != nil)
+			assert.Equal(t, tt.fieldNumber, fieldNumber)
+		})
+	}
+}
@@ -0,0 +1,331 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpfilereceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/fileexporter"
+)
+
+var (
+	errNoInclude           = errors.New("at least one include pattern must be set")
+	errNegativeRate        = errors.New("requests_per_second must not be negative")
+	errInvalidPollInterval = errors.New("poll_interval must be positive when following the files")
+)
+
+// fileReceiver replays the requests read from files in the format written by
+// the file exporter.
+type fileReceiver struct {
+	logger       *zap.Logger
+	include      []string
+	format       string
+	follow       bool
+	pollInterval time.Duration
+	rebase       bool
+	replayer     replayer
+
+	// interval is the minimum time between two replayed requests.
+	interval   time.Duration
+	nextReplay time.Time
+
+	// offset is added to the timestamps when rebasing them, computed from the
+	// first replayed request with timestamps.
+	offset    int64
+	offsetSet bool
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+var _ component.Receiver = (*fileReceiver)(nil)
+
+func newFileReceiver(logger *zap.Logger, cfg *Config, replayer replayer) (*fileReceiver, error) {
+	if len(cfg.Include) == 0 {
+		return nil, errNoInclude
+	}
+	for _, pattern := range cfg.Include {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid include pattern %q: %w", pattern, err)
+		}
+	}
+	if cfg.Format != fileexporter.FormatJSON && cfg.Format != fileexporter.FormatProto {
+		return nil, fmt.Errorf("unknown format %q, must be %q or %q", cfg.Format, fileexporter.FormatJSON, fileexporter.FormatProto)
+	}
+	if cfg.RequestsPerSecond < 0 {
+		return nil, errNegativeRate
+	}
+	if cfg.Follow && cfg.PollInterval <= 0 {
+		return nil, errInvalidPollInterval
+	}
+
+	r := &fileReceiver{
+		logger:       logger,
+		include:      cfg.Include,
+		format:       cfg.Format,
+		follow:       cfg.Follow,
+		pollInterval: cfg.PollInterval,
+		rebase:       cfg.RebaseTimestamps,
+		replayer:     replayer,
+	}
+	if cfg.RequestsPerSecond > 0 {
+		r.interval = time.Duration(float64(time.Second) / cfg.RequestsPerSecond)
+	}
+	return r, nil
+}
+
+// Start starts replaying the files in the background.
+func (r *fileReceiver) Start(context.Context, component.Host) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.done = make(chan struct{})
+	go r.run(ctx)
+	return nil
+}
+
+// Shutdown stops replaying the files, and waits for the request being
+// replayed to be consumed.
+func (r *fileReceiver) Shutdown(context.Context) error {
+	if r.cancel == nil {
+		return nil
+	}
+	r.cancel()
+	<-r.done
+	return nil
+}
+
+func (r *fileReceiver) run(ctx context.Context) {
+	defer close(r.done)
+
+	readers := make(map[string]*fileReader)
+	defer func() {
+		for _, fr := range readers {
+			fr.close()
+		}
+	}()
+
+	if !r.follow {
+		r.poll(ctx, readers)
+		r.logger.Info("Finished replaying the files")
+		return
+	}
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+	for {
+		r.poll(ctx, readers)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll replays the requests that were not yet read from the files matching the
+// patterns. When following, the files are kept open to be polled again, and a
+// file is recognized when renamed to another matching path, so that rotated
+// files are not replayed twice. The files that stop matching the patterns, or
+// whose path is replaced by a new file, are read until their end and closed.
+func (r *fileReceiver) poll(ctx context.Context, readers map[string]*fileReader) {
+	matched := make(map[string]bool)
+	for _, path := range r.match() {
+		if ctx.Err() != nil {
+			return
+		}
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		matched[path] = true
+
+		fr := readers[path]
+		if fr != nil && !os.SameFile(fr.info, info) {
+			r.finish(ctx, fr)
+			delete(readers, path)
+			fr = nil
+		}
+		if fr == nil {
+			fr = renamedReader(readers, info)
+		}
+		if fr == nil {
+			if fr, err = openFile(path, r.format); err != nil {
+				r.logger.Error("Failed to open file", zap.String("path", path), zap.Error(err))
+				continue
+			}
+		}
+		delete(readers, fr.path)
+		fr.path = path
+		readers[path] = fr
+
+		if !r.follow {
+			r.finish(ctx, fr)
+			delete(readers, path)
+			continue
+		}
+		r.read(ctx, fr, false)
+	}
+
+	for path, fr := range readers {
+		if !matched[path] {
+			r.finish(ctx, fr)
+			delete(readers, path)
+		}
+	}
+}
+
+// match returns the paths matching the patterns, without duplicates.
+func (r *fileReceiver) match() []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, pattern := range r.include {
+		// The patterns are validated when creating the receiver.
+		matches, _ := filepath.Glob(pattern)
+		sort.Strings(matches)
+		for _, path := range matches {
+			if !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths
+}
+
+// renamedReader returns the reader of the given file opened under another
+// path, or nil.
+func renamedReader(readers map[string]*fileReader, info os.FileInfo) *fileReader {
+	for _, fr := range readers {
+		if os.SameFile(fr.info, info) {
+			return fr
+		}
+	}
+	return nil
+}
+
+// finish replays the remaining requests of the file and closes it.
+func (r *fileReceiver) finish(ctx context.Context, fr *fileReader) {
+	r.read(ctx, fr, true)
+	if err := fr.close(); err != nil {
+		r.logger.Warn("Failed to close file", zap.String("path", fr.path), zap.Error(err))
+	}
+}
+
+// read replays the requests read from the file until its end.
+func (r *fileReceiver) read(ctx context.Context, fr *fileReader, final bool) {
+	if fr.err != nil {
+		return
+	}
+	if r.follow {
+		if err := fr.checkTruncated(); err != nil {
+			r.logger.Error("Failed to read file", zap.String("path", fr.path), zap.Error(err))
+			return
+		}
+	}
+	for ctx.Err() == nil {
+		fieldNumber, request, err := fr.next(final)
+		if err != nil {
+			fr.err = err
+			r.logger.Error("Failed to read file", zap.String("path", fr.path), zap.Error(err))
+			return
+		}
+		if request == nil {
+			return
+		}
+		r.replay(ctx, fr.path, fieldNumber, request)
+	}
+}
+
+// replay replays the request if it holds the data type of the receiver. The
+// files may hold several data types, since the file exporter writes the data of
+// all its pipelines to the same file.
+func (r *fileReceiver) replay(ctx context.Context, path string, fieldNumber int, request []byte) {
+	if r.format == fileexporter.FormatJSON {
+		var err error
+		if fieldNumber, err = jsonFieldNumber(request); err != nil {
+			r.logger.Error("Failed to decode request", zap.String("path", path), zap.Error(err))
+			return
+		}
+	}
+	if fieldNumber != r.replayer.fieldNumber() {
+		return
+	}
+
+	data, err := r.replayer.decode(request)
+	if err != nil {
+		r.logger.Error("Failed to decode request", zap.String("path", path), zap.Error(err))
+		return
+	}
+	if err := r.wait(ctx); err != nil {
+		return
+	}
+	if r.rebase {
+		r.rebaseTimestamps(data)
+	}
+	if err := r.replayer.consume(ctx, data); err != nil {
+		r.logger.Error("Failed to replay request", zap.String("path", path), zap.Error(err))
+	}
+}
+
+// wait waits until the next request can be replayed, according to the rate
+// limit.
+func (r *fileReceiver) wait(ctx context.Context) error {
+	if r.interval == 0 {
+		return nil
+	}
+	now := time.Now()
+	if d := r.nextReplay.Sub(now); d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+		now = r.nextReplay
+	}
+	r.nextReplay = now.Add(r.interval)
+	return nil
+}
+
+func (r *fileReceiver) rebaseTimestamps(data interface{}) {
+	if !r.offsetSet {
+		var earliest pdata.TimestampUnixNano
+		r.replayer.rewriteTimestamps(data, func(ts pdata.TimestampUnixNano) pdata.TimestampUnixNano {
+			if earliest == 0 || ts < earliest {
+				earliest = ts
+			}
+			return ts
+		})
+		if earliest == 0 {
+			return
+		}
+		r.offset = time.Now().UnixNano() - int64(earliest)
+		r.offsetSet = true
+	}
+	r.replayer.rewriteTimestamps(data, func(ts pdata.TimestampUnixNano) pdata.TimestampUnixNano {
+		return pdata.TimestampUnixNano(int64(ts) + r.offset)
+	})
+}
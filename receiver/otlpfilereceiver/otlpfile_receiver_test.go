@@ -0,0 +1,360 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpfilereceiver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/fileexporter"
+	"go.opentelemetry.io/collector/internal/testdata"
+)
+
+// encodeTraces returns the traces as written to a file by the file exporter.
+func encodeTraces(t *testing.T, format string, td pdata.Traces) []byte {
+	return encode(t, format, func(factory component.ExporterFactory, params component.ExporterCreateParams, cfg *fileexporter.Config) error {
+		exp, err := factory.CreateTracesExporter(context.Background(), params, cfg)
+		require.NoError(t, err)
+		require.NoError(t, exp.ConsumeTraces(context.Background(), td))
+		return exp.Shutdown(context.Background())
+	})
+}
+
+// encodeMetrics returns the metrics as written to a file by the file exporter.
+func encodeMetrics(t *testing.T, format string, md pdata.Metrics) []byte {
+	return encode(t, format, func(factory component.ExporterFactory, params component.ExporterCreateParams, cfg *fileexporter.Config) error {
+		exp, err := factory.CreateMetricsExporter(context.Background(), params, cfg)
+		require.NoError(t, err)
+		require.NoError(t, exp.ConsumeMetrics(context.Background(), md))
+		return exp.Shutdown(context.Background())
+	})
+}
+
+// encodeLogs returns the logs as written to a file by the file exporter.
+func encodeLogs(t *testing.T, format string, ld pdata.Logs) []byte {
+	return encode(t, format, func(factory component.ExporterFactory, params component.ExporterCreateParams, cfg *fileexporter.Config) error {
+		exp, err := factory.CreateLogsExporter(context.Background(), params, cfg)
+		require.NoError(t, err)
+		require.NoError(t, exp.ConsumeLogs(context.Background(), ld))
+		return exp.Shutdown(context.Background())
+	})
+}
+
+func encode(t *testing.T, format string, export func(component.ExporterFactory, component.ExporterCreateParams, *fileexporter.Config) error) []byte {
+	dir, err := ioutil.TempDir("", "otlpfile")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	factory := fileexporter.NewFactory()
+	cfg := factory.CreateDefaultConfig().(*fileexporter.Config)
+	cfg.Path = filepath.Join(dir, "data")
+	cfg.Format = format
+	require.NoError(t, export(factory, component.ExporterCreateParams{Logger: zap.NewNop()}, cfg))
+
+	buf, err := ioutil.ReadFile(cfg.Path)
+	require.NoError(t, err)
+	return buf
+}
+
+func writeFile(t *testing.T, path string, data ...[]byte) {
+	require.NoError(t, ioutil.WriteFile(path, bytes.Join(data, nil), 0600))
+}
+
+func appendFile(t *testing.T, path string, data []byte) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(t, err)
+	_, err = file.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "otlpfile")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func newTestTracesReceiver(t *testing.T, cfg *Config, sink *consumertest.TracesSink) component.TracesReceiver {
+	r, err := createTraceReceiver(context.Background(), component.ReceiverCreateParams{Logger: zap.NewNop()}, cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { assert.NoError(t, r.Shutdown(context.Background())) })
+	return r
+}
+
+func TestReplayTracesJSON(t *testing.T) {
+	dir := tempDir(t)
+	td := testdata.GenerateTraceDataTwoSpansSameResource()
+	request := encodeTraces(t, fileexporter.FormatJSON, td)
+	writeFile(t, filepath.Join(dir, "traces-1.json"), request, []byte("\n"), request)
+	writeFile(t, filepath.Join(dir, "traces-2.json"), request)
+	writeFile(t, filepath.Join(dir, "ignored.txt"), request)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Include = []string{filepath.Join(dir, "traces-*.json"), filepath.Join(dir, "traces-1.json")}
+	sink := new(consumertest.TracesSink)
+	newTestTracesReceiver(t, cfg, sink)
+
+	require.Eventually(t, func() bool { return sink.SpansCount() == 6 }, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	require.Len(t, sink.AllTraces(), 3)
+	for _, got := range sink.AllTraces() {
+		assert.EqualValues(t, td, got)
+	}
+}
+
+func TestReplayTracesInvalidRequest(t *testing.T) {
+	dir := tempDir(t)
+	request := encodeTraces(t, fileexporter.FormatJSON, testdata.GenerateTraceDataOneSpan())
+	// The last request isn't followed by a newline.
+	writeFile(t, filepath.Join(dir, "traces.json"), []byte("{invalid\n"), request, bytes.TrimSpace(request))
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Include = []string{filepath.Join(dir, "traces.json")}
+	sink := new(consumertest.TracesSink)
+	newTestTracesReceiver(t, cfg, sink)
+
+	require.Eventually(t, func() bool { return sink.SpansCount() == 2 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestReplayMetricsProto(t *testing.T) {
+	dir := tempDir(t)
+	md := testdata.GenerateMetricsAllTypesEmptyDataPoint()
+	request := encodeMetrics(t, fileexporter.FormatProto, md)
+	writeFile(t, filepath.Join(dir, "metrics.pb"), request, request)
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, err := gz.Write(request)
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	writeFile(t, filepath.Join(dir, "metrics-backup.pb.gz"), compressed.Bytes())
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Include = []string{filepath.Join(dir, "metrics*")}
+	cfg.Format = fileexporter.FormatProto
+	sink := new(consumertest.MetricsSink)
+	r, err := createMetricsReceiver(context.Background(), component.ReceiverCreateParams{Logger: zap.NewNop()}, cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer r.Shutdown(context.Background())
+
+	require.Eventually(t, func() bool { return len(sink.AllMetrics()) == 3 }, 5*time.Second, 10*time.Millisecond)
+	for _, got := range sink.AllMetrics() {
+		assert.EqualValues(t, md, got)
+	}
+}
+
+func TestReplayLogsProto(t *testing.T) {
+	dir := tempDir(t)
+	ld := testdata.GenerateLogDataTwoLogsSameResource()
+	request := encodeLogs(t, fileexporter.FormatProto, ld)
+	// The last request is truncated.
+	writeFile(t, filepath.Join(dir, "logs.pb"), request, request[:len(request)-1])
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Include = []string{filepath.Join(dir, "logs.pb")}
+	cfg.Format = fileexporter.FormatProto
+	sink := new(consumertest.LogsSink)
+	r, err := createLogsReceiver(context.Background(), component.ReceiverCreateParams{Logger: zap.NewNop()}, cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer r.Shutdown(context.Background())
+
+	require.Eventually(t, func() bool { return sink.LogRecordsCount() == 2 }, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	require.Len(t, sink.AllLogs(), 1)
+	assert.EqualValues(t, ld, sink.AllLogs()[0])
+}
+
+func TestRebaseTimestamps(t *testing.T) {
+	dir := tempDir(t)
+	td := testdata.GenerateTraceDataOneSpan()
+	later := testdata.GenerateTraceDataOneSpan()
+	span := later.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+	span.SetStartTime(testdata.TestSpanStartTimestamp + pdata.TimestampUnixNano(time.Minute))
+	span.SetEndTime(0)
+	writeFile(t, filepath.Join(dir, "traces.json"),
+		encodeTraces(t, fileexporter.FormatJSON, td),
+		encodeTraces(t, fileexporter.FormatJSON, later))
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Include = []string{filepath.Join(dir, "traces.json")}
+	cfg.RebaseTimestamps = true
+	sink := new(consumertest.TracesSink)
+	before := time.Now()
+	newTestTracesReceiver(t, cfg, sink)
+
+	require.Eventually(t, func() bool { return sink.SpansCount() == 2 }, 5*time.Second, 10*time.Millisecond)
+	first := sink.AllTraces()[0].ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+	second := sink.AllTraces()[1].ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+
+	start := time.Unix(0, int64(first.StartTime()))
+	assert.False(t, start.Before(before))
+	assert.False(t, start.After(time.Now()))
+	assert.Equal(t, testdata.TestSpanEndTime.Sub(testdata.TestSpanStartTime), time.Duration(first.EndTime()-first.StartTime()))
+	assert.Equal(t, time.Minute, time.Duration(second.StartTime()-first.StartTime()))
+	assert.Equal(t, pdata.TimestampUnixNano(0), second.EndTime())
+}
+
+func TestRequestsPerSecond(t *testing.T) {
+	dir := tempDir(t)
+	request := encodeTraces(t, fileexporter.FormatJSON, testdata.GenerateTraceDataOneSpan())
+	writeFile(t, filepath.Join(dir, "traces.json"), request, request, request)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Include = []string{filepath.Join(dir, "traces.json")}
+	cfg.RequestsPerSecond = 20
+	sink := new(consumertest.TracesSink)
+	start := time.Now()
+	newTestTracesReceiver(t, cfg, sink)
+
+	require.Eventually(t, func() bool { return sink.SpansCount() == 3 }, 5*time.Second, 5*time.Millisecond)
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(100*time.Millisecond))
+}
+
+func TestShutdownWhileWaiting(t *testing.T) {
+	dir := tempDir(t)
+	request := encodeTraces(t, fileexporter.FormatJSON, testdata.GenerateTraceDataOneSpan())
+	writeFile(t, filepath.Join(dir, "traces.json"), request, request)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Include = []string{filepath.Join(dir, "traces.json")}
+	cfg.RequestsPerSecond = 0.001
+	sink := new(consumertest.TracesSink)
+	r, err := createTraceReceiver(context.Background(), component.ReceiverCreateParams{Logger: zap.NewNop()}, cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+
+	require.Eventually(t, func() bool { return sink.SpansCount() == 1 }, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.Equal(t, 1, sink.SpansCount())
+}
+
+func TestFollow(t *testing.T) {
+	dir := tempDir(t)
+	path := filepath.Join(dir, "traces.pb")
+	request := encodeTraces(t, fileexporter.FormatProto, testdata.GenerateTraceDataOneSpan())
+	writeFile(t, path, request)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Include = []string{filepath.Join(dir, "traces*.pb")}
+	cfg.Format = fileexporter.FormatProto
+	cfg.Follow = true
+	cfg.PollInterval = 10 * time.Millisecond
+	sink := new(consumertest.TracesSink)
+	newTestTracesReceiver(t, cfg, sink)
+	require.Eventually(t, func() bool { return sink.SpansCount() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// A partially written request is replayed once complete.
+	appendFile(t, path, request[:5])
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 1, sink.SpansCount())
+	appendFile(t, path, request[5:])
+	require.Eventually(t, func() bool { return sink.SpansCount() == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	// A file renamed to a matching path isn't replayed again.
+	appendFile(t, path, request)
+	require.NoError(t, os.Rename(path, filepath.Join(dir, "traces-1.pb")))
+	writeFile(t, path, request)
+	require.Eventually(t, func() bool { return sink.SpansCount() == 4 }, 5*time.Second, 10*time.Millisecond)
+
+	// A file renamed to a path not matching is read until its end.
+	appendFile(t, path, request)
+	require.NoError(t, os.Rename(path, filepath.Join(dir, "traces.pb.1")))
+	require.Eventually(t, func() bool { return sink.SpansCount() == 5 }, 5*time.Second, 10*time.Millisecond)
+
+	// A truncated file is read again from its beginning.
+	writeFile(t, filepath.Join(dir, "traces-2.pb"), request, request)
+	require.Eventually(t, func() bool { return sink.SpansCount() == 7 }, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, os.Truncate(filepath.Join(dir, "traces-2.pb"), 0))
+	appendFile(t, filepath.Join(dir, "traces-2.pb"), request)
+	require.Eventually(t, func() bool { return sink.SpansCount() == 8 }, 5*time.Second, 10*time.Millisecond)
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 8, sink.SpansCount())
+}
+
+func TestReplayMixedDataTypes(t *testing.T) {
+	td := testdata.GenerateTraceDataOneSpan()
+	// without libraries, the metrics are valid traces in the proto format
+	md := testdata.GenerateMetricsNoLibraries()
+	ld := testdata.GenerateLogDataOneLog()
+
+	for _, format := range []string{fileexporter.FormatJSON, fileexporter.FormatProto} {
+		t.Run(format, func(t *testing.T) {
+			dir := tempDir(t)
+			// The file exporter of a traces, a metrics and a logs pipeline writes all of them to the same file.
+			data := encode(t, format, func(factory component.ExporterFactory, params component.ExporterCreateParams, cfg *fileexporter.Config) error {
+				texp, err := factory.CreateTracesExporter(context.Background(), params, cfg)
+				require.NoError(t, err)
+				mexp, err := factory.CreateMetricsExporter(context.Background(), params, cfg)
+				require.NoError(t, err)
+				lexp, err := factory.CreateLogsExporter(context.Background(), params, cfg)
+				require.NoError(t, err)
+				require.NoError(t, mexp.ConsumeMetrics(context.Background(), md))
+				require.NoError(t, texp.ConsumeTraces(context.Background(), td))
+				require.NoError(t, lexp.ConsumeLogs(context.Background(), ld))
+				return texp.Shutdown(context.Background())
+			})
+			writeFile(t, filepath.Join(dir, "data"), data)
+
+			cfg := createDefaultConfig().(*Config)
+			cfg.Include = []string{filepath.Join(dir, "data")}
+			cfg.Format = format
+			params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+			tsink := new(consumertest.TracesSink)
+			tr, err := createTraceReceiver(context.Background(), params, cfg, tsink)
+			require.NoError(t, err)
+			msink := new(consumertest.MetricsSink)
+			mr, err := createMetricsReceiver(context.Background(), params, cfg, msink)
+			require.NoError(t, err)
+			lsink := new(consumertest.LogsSink)
+			lr, err := createLogsReceiver(context.Background(), params, cfg, lsink)
+			require.NoError(t, err)
+			for _, r := range []component.Receiver{tr, mr, lr} {
+				require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+				defer r.Shutdown(context.Background())
+			}
+
+			// Each receiver replays the requests of its data type only.
+			require.Eventually(t, func() bool {
+				return len(tsink.AllTraces()) == 1 && len(msink.AllMetrics()) == 1 && len(lsink.AllLogs()) == 1
+			}, 5*time.Second, 10*time.Millisecond)
+			time.Sleep(50 * time.Millisecond)
+			require.Len(t, tsink.AllTraces(), 1)
+			assert.EqualValues(t, td, tsink.AllTraces()[0])
+			require.Len(t, msink.AllMetrics(), 1)
+			assert.EqualValues(t, md, msink.AllMetrics()[0])
+			require.Len(t, lsink.AllLogs(), 1)
+			assert.EqualValues(t, ld, lsink.AllLogs()[0])
+		})
+	}
+}
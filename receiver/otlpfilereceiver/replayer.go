@@ -0,0 +1,267 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpfilereceiver
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/fileexporter"
+	"go.opentelemetry.io/collector/internal"
+	otlplogs "go.opentelemetry.io/collector/internal/data/protogen/collector/logs/v1"
+	otlpmetrics "go.opentelemetry.io/collector/internal/data/protogen/collector/metrics/v1"
+	otlptrace "go.opentelemetry.io/collector/internal/data/protogen/collector/trace/v1"
+	"go.opentelemetry.io/collector/obsreport"
+)
+
+const transport = "file"
+
+// Unmarshaler configuration used for unmarshaling Protobuf JSON. Use default config.
+var unmarshaler = &jsonpb.Unmarshaler{}
+
+// replayer decodes the requests of one data type and passes them to the next consumer.
+type replayer interface {
+	// fieldNumber returns the field number identifying the data type in the
+	// files, see fileexporter.FormatProto.
+	fieldNumber() int
+
+	// decode decodes a request read from a file.
+	decode(request []byte) (interface{}, error)
+
+	// rewriteTimestamps replaces all the non-zero timestamps of the data by
+	// the result of f.
+	rewriteTimestamps(data interface{}, f func(pdata.TimestampUnixNano) pdata.TimestampUnixNano)
+
+	// consume passes the data to the next consumer.
+	consume(ctx context.Context, data interface{}) error
+}
+
+// unmarshalRequest decodes the request in the given format into message.
+func unmarshalRequest(format string, request []byte, message proto.Message) error {
+	if format == fileexporter.FormatProto {
+		return proto.Unmarshal(request, message)
+	}
+	return unmarshaler.Unmarshal(bytes.NewReader(request), message)
+}
+
+type tracesReplayer struct {
+	name         string
+	format       string
+	nextConsumer consumer.TracesConsumer
+}
+
+func (r *tracesReplayer) fieldNumber() int {
+	return fileexporter.TracesFieldNumber
+}
+
+func (r *tracesReplayer) decode(request []byte) (interface{}, error) {
+	var message otlptrace.ExportTraceServiceRequest
+	if err := unmarshalRequest(r.format, request, &message); err != nil {
+		return nil, err
+	}
+	return pdata.TracesFromOtlp(message.ResourceSpans), nil
+}
+
+func (r *tracesReplayer) rewriteTimestamps(data interface{}, f func(pdata.TimestampUnixNano) pdata.TimestampUnixNano) {
+	rss := data.(pdata.Traces).ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		ilss := rss.At(i).InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				span.SetStartTime(rewrite(span.StartTime(), f))
+				span.SetEndTime(rewrite(span.EndTime(), f))
+				events := span.Events()
+				for l := 0; l < events.Len(); l++ {
+					event := events.At(l)
+					event.SetTimestamp(rewrite(event.Timestamp(), f))
+				}
+			}
+		}
+	}
+}
+
+func (r *tracesReplayer) consume(ctx context.Context, data interface{}) error {
+	td := data.(pdata.Traces)
+	ctx = obsreport.ReceiverContext(ctx, r.name, transport)
+	ctx = obsreport.StartTraceDataReceiveOp(ctx, r.name, transport)
+	err := r.nextConsumer.ConsumeTraces(ctx, td)
+	obsreport.EndTraceDataReceiveOp(ctx, r.format, td.SpanCount(), err)
+	return err
+}
+
+type metricsReplayer struct {
+	name         string
+	format       string
+	nextConsumer consumer.MetricsConsumer
+}
+
+func (r *metricsReplayer) fieldNumber() int {
+	return fileexporter.MetricsFieldNumber
+}
+
+func (r *metricsReplayer) decode(request []byte) (interface{}, error) {
+	var message otlpmetrics.ExportMetricsServiceRequest
+	if err := unmarshalRequest(r.format, request, &message); err != nil {
+		return nil, err
+	}
+	return pdata.MetricsFromOtlp(message.ResourceMetrics), nil
+}
+
+func (r *metricsReplayer) rewriteTimestamps(data interface{}, f func(pdata.TimestampUnixNano) pdata.TimestampUnixNano) {
+	rms := data.(pdata.Metrics).ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		ilms := rms.At(i).InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			metrics := ilms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				rewriteMetricTimestamps(metrics.At(k), f)
+			}
+		}
+	}
+}
+
+func rewriteMetricTimestamps(metric pdata.Metric, f func(pdata.TimestampUnixNano) pdata.TimestampUnixNano) {
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		rewriteIntDataPoints(metric.IntGauge().DataPoints(), f)
+	case pdata.MetricDataTypeDoubleGauge:
+		rewriteDoubleDataPoints(metric.DoubleGauge().DataPoints(), f)
+	case pdata.MetricDataTypeIntSum:
+		rewriteIntDataPoints(metric.IntSum().DataPoints(), f)
+	case pdata.MetricDataTypeDoubleSum:
+		rewriteDoubleDataPoints(metric.DoubleSum().DataPoints(), f)
+	case pdata.MetricDataTypeIntHistogram:
+		dps := metric.IntHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			dp.SetStartTime(rewrite(dp.StartTime(), f))
+			dp.SetTimestamp(rewrite(dp.Timestamp(), f))
+			rewriteIntExemplars(dp.Exemplars(), f)
+		}
+	case pdata.MetricDataTypeDoubleHistogram:
+		dps := metric.DoubleHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			dp.SetStartTime(rewrite(dp.StartTime(), f))
+			dp.SetTimestamp(rewrite(dp.Timestamp(), f))
+			rewriteDoubleExemplars(dp.Exemplars(), f)
+		}
+	case pdata.MetricDataTypeDoubleSummary:
+		dps := metric.DoubleSummary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			dp.SetStartTime(rewrite(dp.StartTime(), f))
+			dp.SetTimestamp(rewrite(dp.Timestamp(), f))
+		}
+	}
+}
+
+func rewriteIntDataPoints(dps pdata.IntDataPointSlice, f func(pdata.TimestampUnixNano) pdata.TimestampUnixNano) {
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		dp.SetStartTime(rewrite(dp.StartTime(), f))
+		dp.SetTimestamp(rewrite(dp.Timestamp(), f))
+		rewriteIntExemplars(dp.Exemplars(), f)
+	}
+}
+
+func rewriteDoubleDataPoints(dps pdata.DoubleDataPointSlice, f func(pdata.TimestampUnixNano) pdata.TimestampUnixNano) {
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		dp.SetStartTime(rewrite(dp.StartTime(), f))
+		dp.SetTimestamp(rewrite(dp.Timestamp(), f))
+		rewriteDoubleExemplars(dp.Exemplars(), f)
+	}
+}
+
+func rewriteIntExemplars(exemplars pdata.IntExemplarSlice, f func(pdata.TimestampUnixNano) pdata.TimestampUnixNano) {
+	for i := 0; i < exemplars.Len(); i++ {
+		exemplar := exemplars.At(i)
+		exemplar.SetTimestamp(rewrite(exemplar.Timestamp(), f))
+	}
+}
+
+func rewriteDoubleExemplars(exemplars pdata.DoubleExemplarSlice, f func(pdata.TimestampUnixNano) pdata.TimestampUnixNano) {
+	for i := 0; i < exemplars.Len(); i++ {
+		exemplar := exemplars.At(i)
+		exemplar.SetTimestamp(rewrite(exemplar.Timestamp(), f))
+	}
+}
+
+func (r *metricsReplayer) consume(ctx context.Context, data interface{}) error {
+	md := data.(pdata.Metrics)
+	ctx = obsreport.ReceiverContext(ctx, r.name, transport)
+	ctx = obsreport.StartMetricsReceiveOp(ctx, r.name, transport)
+	err := r.nextConsumer.ConsumeMetrics(ctx, md)
+	_, numPoints := md.MetricAndDataPointCount()
+	obsreport.EndMetricsReceiveOp(ctx, r.format, numPoints, err)
+	return err
+}
+
+type logsReplayer struct {
+	name         string
+	format       string
+	nextConsumer consumer.LogsConsumer
+}
+
+func (r *logsReplayer) fieldNumber() int {
+	return fileexporter.LogsFieldNumber
+}
+
+func (r *logsReplayer) decode(request []byte) (interface{}, error) {
+	var message otlplogs.ExportLogsServiceRequest
+	if err := unmarshalRequest(r.format, request, &message); err != nil {
+		return nil, err
+	}
+	return pdata.LogsFromInternalRep(internal.LogsFromOtlp(message.ResourceLogs)), nil
+}
+
+func (r *logsReplayer) rewriteTimestamps(data interface{}, f func(pdata.TimestampUnixNano) pdata.TimestampUnixNano) {
+	rls := data.(pdata.Logs).ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		ills := rls.At(i).InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			logs := ills.At(j).Logs()
+			for k := 0; k < logs.Len(); k++ {
+				log := logs.At(k)
+				log.SetTimestamp(rewrite(log.Timestamp(), f))
+			}
+		}
+	}
+}
+
+func (r *logsReplayer) consume(ctx context.Context, data interface{}) error {
+	ld := data.(pdata.Logs)
+	ctx = obsreport.ReceiverContext(ctx, r.name, transport)
+	ctx = obsreport.StartLogsReceiveOp(ctx, r.name, transport)
+	err := r.nextConsumer.ConsumeLogs(ctx, ld)
+	obsreport.EndLogsReceiveOp(ctx, r.format, ld.LogRecordCount(), err)
+	return err
+}
+
+// rewrite returns the result of f for a non-zero timestamp, and zero otherwise.
+func rewrite(ts pdata.TimestampUnixNano, f func(pdata.TimestampUnixNano) pdata.TimestampUnixNano) pdata.TimestampUnixNano {
+	if ts == 0 {
+		return 0
+	}
+	return f(ts)
+}
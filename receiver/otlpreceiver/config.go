@@ -15,6 +15,7 @@
 package otlpreceiver
 
 import (
+	"go.opentelemetry.io/collector/config/configadmission"
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configmodels"
@@ -31,4 +32,8 @@ type Config struct {
 
 	// Protocols is the configuration for the supported protocols, currently gRPC and HTTP (Proto and JSON).
 	Protocols `mapstructure:"protocols"`
+
+	// Admission limits the size of the requests being processed by all the protocols together, rather
+	// than by each of them separately. It can't be combined with the admission of the protocols. (optional)
+	Admission *configadmission.Admission `mapstructure:"admission"`
 }
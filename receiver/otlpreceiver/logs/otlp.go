@@ -69,7 +69,7 @@ func (r *Receiver) sendToNextConsumer(ctx context.Context, ld pdata.Logs) error
 		return nil
 	}
 
-	if c, ok := client.FromGRPC(ctx); ok {
+	if c, ok := client.FromGRPC(ctx, client.WithReceiver(r.instanceName)); ok {
 		ctx = client.NewContext(ctx, c)
 	}
 
@@ -67,7 +67,7 @@ func (r *Receiver) sendToNextConsumer(ctx context.Context, md pdata.Metrics) err
 		return nil
 	}
 
-	if c, ok := client.FromGRPC(ctx); ok {
+	if c, ok := client.FromGRPC(ctx, client.WithReceiver(r.instanceName)); ok {
 		ctx = client.NewContext(ctx, c)
 	}
 
@@ -17,6 +17,7 @@ package otlpreceiver
 import (
 	"context"
 	"errors"
+	"io"
 	"net"
 	"net/http"
 	"sync"
@@ -27,6 +28,7 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/config/configadmission"
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/consumer"
@@ -38,12 +40,19 @@ import (
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/trace"
 )
 
+var errAdmissionConflict = errors.New("admission can't be set both for the receiver and for its protocols")
+
 // otlpReceiver is the type that exposes Trace and Metrics reception.
 type otlpReceiver struct {
 	cfg        *Config
 	serverGRPC *grpc.Server
 	gatewayMux *gatewayruntime.ServeMux
 	serverHTTP *http.Server
+	// authGRPC and authHTTP stop the authenticators of the servers, if any.
+	authGRPC io.Closer
+	authHTTP io.Closer
+	// admission is shared by the servers of all the protocols, if set.
+	admission *configadmission.Controller
 
 	traceReceiver   *trace.Receiver
 	metricsReceiver *metrics.Receiver
@@ -63,11 +72,23 @@ func newOtlpReceiver(cfg *Config, logger *zap.Logger) (*otlpReceiver, error) {
 		cfg:    cfg,
 		logger: logger,
 	}
+	var grpcOpts []configgrpc.ToServerOption
+	if cfg.Admission != nil {
+		if (cfg.GRPC != nil && cfg.GRPC.Admission != nil) || (cfg.HTTP != nil && cfg.HTTP.Admission != nil) {
+			return nil, errAdmissionConflict
+		}
+		var err error
+		if r.admission, err = cfg.Admission.NewController(); err != nil {
+			return nil, err
+		}
+		grpcOpts = append(grpcOpts, configgrpc.WithAdmissionController(r.admission))
+	}
 	if cfg.GRPC != nil {
-		opts, err := cfg.GRPC.ToServerOption()
+		opts, auth, err := cfg.GRPC.ToServerOption(grpcOpts...)
 		if err != nil {
 			return nil, err
 		}
+		r.authGRPC = auth
 		r.serverGRPC = grpc.NewServer(opts...)
 	}
 	if cfg.HTTP != nil {
@@ -140,10 +161,14 @@ func (r *otlpReceiver) startProtocolServers(host component.Host) error {
 		}
 	}
 	if r.cfg.HTTP != nil {
-		r.serverHTTP = r.cfg.HTTP.ToServer(
-			r.gatewayMux,
-			confighttp.WithErrorHandler(errorHandler),
-		)
+		opts := []confighttp.ToServerOption{confighttp.WithErrorHandler(errorHandler)}
+		if r.admission != nil {
+			opts = append(opts, confighttp.WithAdmissionController(r.admission))
+		}
+		r.serverHTTP, r.authHTTP, err = r.cfg.HTTP.ToServer(r.gatewayMux, opts...)
+		if err != nil {
+			return err
+		}
 		err = r.startHTTPServer(r.cfg.HTTP, host)
 		if err != nil {
 			return err
@@ -171,15 +196,26 @@ func (r *otlpReceiver) Start(_ context.Context, host component.Host) error {
 func (r *otlpReceiver) Shutdown(context.Context) error {
 	var err error
 	r.stopOnce.Do(func() {
-		err = nil
+		var errs []error
 
 		if r.serverHTTP != nil {
-			err = r.serverHTTP.Close()
+			if err := r.serverHTTP.Close(); err != nil {
+				errs = append(errs, err)
+			}
 		}
 
 		if r.serverGRPC != nil {
 			r.serverGRPC.Stop()
 		}
+
+		for _, auth := range []io.Closer{r.authHTTP, r.authGRPC} {
+			if auth != nil {
+				if err := auth.Close(); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+		err = componenterror.CombineErrors(errs)
 	})
 	return err
 }
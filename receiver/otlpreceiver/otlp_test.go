@@ -21,9 +21,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"path"
+	"strings"
 	"testing"
 	"time"
 
@@ -39,6 +42,8 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configadmission"
+	"go.opentelemetry.io/collector/config/configauth"
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configmodels"
@@ -775,3 +780,110 @@ func compressGzip(body []byte) (*bytes.Buffer, error) {
 
 	return &buf, nil
 }
+
+// blockingTracesConsumer blocks the traces until released.
+type blockingTracesConsumer struct {
+	received chan struct{}
+	release  chan struct{}
+}
+
+func (c *blockingTracesConsumer) ConsumeTraces(context.Context, pdata.Traces) error {
+	c.received <- struct{}{}
+	<-c.release
+	return nil
+}
+
+// newLargeTraceRequest returns a request of about the given size.
+func newLargeTraceRequest(size int) *collectortrace.ExportTraceServiceRequest {
+	return &collectortrace.ExportTraceServiceRequest{
+		ResourceSpans: []*otlptrace.ResourceSpans{{
+			InstrumentationLibrarySpans: []*otlptrace.InstrumentationLibrarySpans{{
+				Spans: []*otlptrace.Span{{Name: strings.Repeat("a", size)}},
+			}},
+		}},
+	}
+}
+
+func TestSharedAdmission(t *testing.T) {
+	grpcAddr := testutil.GetAvailableLocalAddress(t)
+	httpAddr := testutil.GetAvailableLocalAddress(t)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.SetName(otlpReceiverName)
+	cfg.GRPC.NetAddr.Endpoint = grpcAddr
+	cfg.HTTP.Endpoint = httpAddr
+	cfg.Admission = &configadmission.Admission{LimitMiB: 1}
+	tc := &blockingTracesConsumer{received: make(chan struct{}), release: make(chan struct{})}
+	r := newReceiver(t, factory, cfg, tc, nil)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer r.Shutdown(context.Background())
+
+	// a large request is being processed by the gRPC server
+	conn, err := grpc.Dial(grpcAddr, grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+	defer conn.Close()
+	go func() {
+		_, _ = collectortrace.NewTraceServiceClient(conn).Export(context.Background(), newLargeTraceRequest(600*1024))
+	}()
+	<-tc.received
+	defer close(tc.release)
+
+	// test
+	body, err := newLargeTraceRequest(600 * 1024).Marshal()
+	require.NoError(t, err)
+	resp, err := http.Post("http://"+httpAddr+"/v1/traces", "application/x-protobuf", bytes.NewReader(body))
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// verify
+	// the HTTP request doesn't fit within the limit shared with the gRPC server
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+}
+
+func TestAdmissionConflict(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Admission = &configadmission.Admission{LimitMiB: 1}
+	cfg.HTTP.Admission = &configadmission.Admission{LimitMiB: 1}
+
+	_, err := newOtlpReceiver(cfg, zap.NewNop())
+	assert.Equal(t, errAdmissionConflict, err)
+
+	cfg.HTTP.Admission = nil
+	cfg.Admission = &configadmission.Admission{}
+	_, err = newOtlpReceiver(cfg, zap.NewNop())
+	assert.Error(t, err)
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
+
+func TestShutdownClosesAuthenticators(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.SetName(otlpReceiverName)
+	cfg.GRPC.NetAddr.Endpoint = testutil.GetAvailableLocalAddress(t)
+	cfg.HTTP.Endpoint = testutil.GetAvailableLocalAddress(t)
+	auth := &configauth.Authentication{
+		APIKeys: &configauth.APIKeys{File: path.Join("..", "..", "config", "configauth", "testdata", "api_keys")},
+	}
+	cfg.GRPC.Auth = auth
+	cfg.HTTP.Auth = auth
+	r := newReceiver(t, factory, cfg, new(consumertest.TracesSink), nil)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+
+	// the started authenticators are closed with the receiver
+	var closed []string
+	for name, auth := range map[string]*io.Closer{"grpc": &r.authGRPC, "http": &r.authHTTP} {
+		name, started := name, *auth
+		require.NotNil(t, started, name)
+		*auth = closerFunc(func() error {
+			closed = append(closed, name)
+			return started.Close()
+		})
+	}
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.ElementsMatch(t, []string{"grpc", "http"}, closed)
+}
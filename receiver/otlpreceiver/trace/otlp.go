@@ -95,7 +95,7 @@ func (r *Receiver) sendToNextConsumer(ctx context.Context, td pdata.Traces) erro
 		return nil
 	}
 
-	if c, ok := client.FromGRPC(ctx); ok {
+	if c, ok := client.FromGRPC(ctx, client.WithReceiver(r.instanceName)); ok {
 		ctx = client.NewContext(ctx, c)
 	}
 
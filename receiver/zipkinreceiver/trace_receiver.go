@@ -64,6 +64,9 @@ type ZipkinReceiver struct {
 	stopOnce  sync.Once
 	server    *http.Server
 	config    *Config
+
+	// auth stops the authenticator of the server, if any.
+	auth io.Closer
 }
 
 var _ http.Handler = (*ZipkinReceiver)(nil)
@@ -96,7 +99,10 @@ func (zr *ZipkinReceiver) Start(ctx context.Context, host component.Host) error
 	zr.startOnce.Do(func() {
 		err = nil
 		zr.host = host
-		zr.server = zr.config.HTTPServerSettings.ToServer(zr)
+		zr.server, zr.auth, err = zr.config.HTTPServerSettings.ToServer(zr)
+		if err != nil {
+			return
+		}
 		var listener net.Listener
 		listener, err = zr.config.HTTPServerSettings.ToListener()
 		if err != nil {
@@ -194,6 +200,11 @@ func (zr *ZipkinReceiver) Shutdown(context.Context) error {
 	var err = componenterror.ErrAlreadyStopped
 	zr.stopOnce.Do(func() {
 		err = zr.server.Close()
+		if zr.auth != nil {
+			if errAuth := zr.auth.Close(); errAuth != nil && err == nil {
+				err = errAuth
+			}
+		}
 	})
 	return err
 }
@@ -202,7 +213,8 @@ func (zr *ZipkinReceiver) Shutdown(context.Context) error {
 // a compression such as "gzip", "deflate", "zlib", is found, the body will
 // be uncompressed accordingly or return the body untouched if otherwise.
 // Clients such as Zipkin-Java do this behavior e.g.
-//    send "Content-Encoding":"gzip" of the JSON content.
+//
+//	send "Content-Encoding":"gzip" of the JSON content.
 func processBodyIfNecessary(req *http.Request) io.Reader {
 	switch req.Header.Get("Content-Encoding") {
 	default:
@@ -243,7 +255,7 @@ const (
 // unmarshals them and sends them along to the nextConsumer.
 func (zr *ZipkinReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	if c, ok := client.FromHTTP(r); ok {
+	if c, ok := client.FromHTTP(r, client.WithReceiver(zr.instanceName)); ok {
 		ctx = client.NewContext(ctx, c)
 	}
 
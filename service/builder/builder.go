@@ -17,12 +17,14 @@ package builder
 import (
 	"flag"
 	"fmt"
+	"time"
 )
 
 const (
 	// flags
-	configCfg      = "config"
-	memBallastFlag = "mem-ballast-size-mib"
+	configCfg           = "config"
+	configWatchInterval = "config-watch-interval"
+	memBallastFlag      = "mem-ballast-size-mib"
 
 	kindLogKey        = "component_kind"
 	kindLogsReceiver  = "receiver"
@@ -34,13 +36,17 @@ const (
 )
 
 var (
-	configFile     *string
-	memBallastSize *uint
+	configFile        *string
+	configWatchPeriod *time.Duration
+	memBallastSize    *uint
 )
 
 // Flags adds flags related to basic building of the collector application to the given flagset.
 func Flags(flags *flag.FlagSet) {
 	configFile = flags.String(configCfg, "", "Path to the config file")
+	configWatchPeriod = flags.Duration(configWatchInterval, 0,
+		"Interval at which the config file is checked for changes, the configuration is reloaded when the file "+
+			"changes. The file is not watched when this is not specified. The configuration is also reloaded on SIGHUP.")
 	memBallastSize = flags.Uint(memBallastFlag, 0,
 		fmt.Sprintf("Flag to specify size of memory (MiB) ballast to set. Ballast is not used when this is not specified. "+
 			"default settings: 0"))
@@ -51,6 +57,11 @@ func GetConfigFile() string {
 	return *configFile
 }
 
+// ConfigWatchInterval returns the interval at which the config file is checked for changes, 0 if disabled.
+func ConfigWatchInterval() time.Duration {
+	return *configWatchPeriod
+}
+
 // MemBallastSize returns the size of memory ballast to use in MBs
 func MemBallastSize() int {
 	return int(*memBallastSize)
@@ -0,0 +1,177 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builder
+
+import (
+	"reflect"
+	"sort"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// exporterLookupProcessors are the types of the processors which look up exporters from
+// the host when they start, rather than using the exporters of their pipeline.
+var exporterLookupProcessors = map[configmodels.Type]bool{
+	"routing":     true,
+	"spanmetrics": true,
+}
+
+// ConfigDiff contains the names of the components that must be rebuilt to move from
+// one configuration to another. A component is included if it was added, removed or
+// modified, or if any component it is connected to must be rebuilt: a pipeline is
+// rebuilt when any of its processors or exporters changes, or when any exporter changes
+// if one of its processors looks up exporters from the host, and a receiver is rebuilt
+// when any pipeline it is attached to is rebuilt.
+type ConfigDiff struct {
+	Extensions map[string]bool
+	Exporters  map[string]bool
+	Pipelines  map[string]bool
+	Receivers  map[string]bool
+}
+
+// NewConfigDiff compares the running configuration with a new one.
+func NewConfigDiff(oldCfg, newCfg *configmodels.Config) *ConfigDiff {
+	diff := &ConfigDiff{
+		Extensions: make(map[string]bool),
+		Exporters:  make(map[string]bool),
+		Pipelines:  make(map[string]bool),
+		Receivers:  make(map[string]bool),
+	}
+
+	oldExts := enabledExtensions(oldCfg)
+	newExts := enabledExtensions(newCfg)
+	for name := range unionKeys(oldExts, newExts) {
+		if !reflect.DeepEqual(oldExts[name], newExts[name]) {
+			diff.Extensions[name] = true
+		}
+	}
+
+	oldExpTypes := exportersDataTypes(oldCfg)
+	newExpTypes := exportersDataTypes(newCfg)
+	for name := range unionKeys(oldCfg.Exporters, newCfg.Exporters) {
+		if !reflect.DeepEqual(oldCfg.Exporters[name], newCfg.Exporters[name]) ||
+			!reflect.DeepEqual(oldExpTypes[name], newExpTypes[name]) {
+			diff.Exporters[name] = true
+		}
+	}
+
+	for name := range unionKeys(oldCfg.Service.Pipelines, newCfg.Service.Pipelines) {
+		oldPipeline := oldCfg.Service.Pipelines[name]
+		newPipeline := newCfg.Service.Pipelines[name]
+		if oldPipeline == nil || newPipeline == nil || !reflect.DeepEqual(oldPipeline, newPipeline) {
+			diff.Pipelines[name] = true
+			continue
+		}
+		for _, procName := range newPipeline.Processors {
+			if !reflect.DeepEqual(oldCfg.Processors[procName], newCfg.Processors[procName]) {
+				diff.Pipelines[name] = true
+			}
+			// The processors looking up exporters from the host when they start must not
+			// keep the replaced ones.
+			if len(diff.Exporters) > 0 && exporterLookupProcessors[newCfg.Processors[procName].Type()] {
+				diff.Pipelines[name] = true
+			}
+		}
+		for _, expName := range newPipeline.Exporters {
+			if diff.Exporters[expName] {
+				diff.Pipelines[name] = true
+			}
+		}
+	}
+
+	oldRcvPipelines := receiversPipelines(oldCfg)
+	newRcvPipelines := receiversPipelines(newCfg)
+	for name := range unionKeys(oldCfg.Receivers, newCfg.Receivers) {
+		if !reflect.DeepEqual(oldCfg.Receivers[name], newCfg.Receivers[name]) ||
+			!reflect.DeepEqual(oldRcvPipelines[name], newRcvPipelines[name]) {
+			diff.Receivers[name] = true
+			continue
+		}
+		for _, pipelineName := range newRcvPipelines[name] {
+			if diff.Pipelines[pipelineName] {
+				diff.Receivers[name] = true
+			}
+		}
+	}
+
+	return diff
+}
+
+// IsEmpty returns true if no component must be rebuilt.
+func (diff *ConfigDiff) IsEmpty() bool {
+	return len(diff.Extensions) == 0 && len(diff.Exporters) == 0 &&
+		len(diff.Pipelines) == 0 && len(diff.Receivers) == 0
+}
+
+// PipelinesChanged returns true if any receiver, pipeline or exporter must be rebuilt.
+func (diff *ConfigDiff) PipelinesChanged() bool {
+	return len(diff.Exporters) != 0 || len(diff.Pipelines) != 0 || len(diff.Receivers) != 0
+}
+
+func enabledExtensions(cfg *configmodels.Config) map[string]configmodels.Extension {
+	result := make(map[string]configmodels.Extension, len(cfg.Service.Extensions))
+	for _, name := range cfg.Service.Extensions {
+		result[name] = cfg.Extensions[name]
+	}
+	return result
+}
+
+// exportersDataTypes returns the sorted data types each exporter receives, since
+// exporters are created for each data type they are used with.
+func exportersDataTypes(cfg *configmodels.Config) map[string][]configmodels.DataType {
+	set := make(map[string]map[configmodels.DataType]bool)
+	for _, pipeline := range cfg.Service.Pipelines {
+		for _, expName := range pipeline.Exporters {
+			if set[expName] == nil {
+				set[expName] = make(map[configmodels.DataType]bool)
+			}
+			set[expName][pipeline.InputType] = true
+		}
+	}
+
+	result := make(map[string][]configmodels.DataType, len(set))
+	for expName, dataTypes := range set {
+		for dataType := range dataTypes {
+			result[expName] = append(result[expName], dataType)
+		}
+		sort.Slice(result[expName], func(i, j int) bool { return result[expName][i] < result[expName][j] })
+	}
+	return result
+}
+
+// receiversPipelines returns the sorted names of the pipelines each receiver is attached to.
+func receiversPipelines(cfg *configmodels.Config) map[string][]string {
+	result := make(map[string][]string)
+	for _, pipeline := range cfg.Service.Pipelines {
+		for _, rcvName := range pipeline.Receivers {
+			result[rcvName] = append(result[rcvName], pipeline.Name)
+		}
+	}
+	for _, pipelines := range result {
+		sort.Strings(pipelines)
+	}
+	return result
+}
+
+// unionKeys returns the union of the keys of two maps with string keys.
+func unionKeys(maps ...interface{}) map[string]bool {
+	result := make(map[string]bool)
+	for _, m := range maps {
+		for _, key := range reflect.ValueOf(m).MapKeys() {
+			result[key.String()] = true
+		}
+	}
+	return result
+}
@@ -0,0 +1,173 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/processor/spanmetricsprocessor"
+)
+
+func loadPipelinesBuilderConfig(t *testing.T) *configmodels.Config {
+	factories, err := componenttest.ExampleComponents()
+	require.NoError(t, err)
+	cfg, err := configtest.LoadConfigFile(t, "testdata/pipelines_builder.yaml", factories)
+	require.NoError(t, err)
+	return cfg
+}
+
+func TestConfigDiff_NoChanges(t *testing.T) {
+	diff := NewConfigDiff(loadPipelinesBuilderConfig(t), loadPipelinesBuilderConfig(t))
+	assert.True(t, diff.IsEmpty())
+	assert.False(t, diff.PipelinesChanged())
+}
+
+func TestConfigDiff_ExporterChanged(t *testing.T) {
+	oldCfg := loadPipelinesBuilderConfig(t)
+	newCfg := loadPipelinesBuilderConfig(t)
+	newCfg.Exporters["exampleexporter/2"].(*componenttest.ExampleExporter).ExtraSetting = "changed"
+
+	diff := NewConfigDiff(oldCfg, newCfg)
+	assert.Equal(t, map[string]bool{"exampleexporter/2": true}, diff.Exporters)
+	// only the pipelines using the exporter, and their receivers, are rebuilt
+	assert.Equal(t, map[string]bool{"traces/2": true, "metrics/3": true, "logs": true}, diff.Pipelines)
+	assert.Equal(t, map[string]bool{"examplereceiver/2": true, "examplereceiver/3": true, "examplereceiver/multi": true}, diff.Receivers)
+	assert.Empty(t, diff.Extensions)
+	assert.True(t, diff.PipelinesChanged())
+}
+
+func TestConfigDiff_ProcessorChanged(t *testing.T) {
+	oldCfg := loadPipelinesBuilderConfig(t)
+	newCfg := loadPipelinesBuilderConfig(t)
+	newCfg.Processors["exampleprocessor"].(*componenttest.ExampleProcessorCfg).ExtraSetting = "changed"
+
+	diff := NewConfigDiff(oldCfg, newCfg)
+	assert.Empty(t, diff.Exporters)
+	assert.Equal(t, map[string]bool{"traces": true, "traces/2": true}, diff.Pipelines)
+	assert.Equal(t, map[string]bool{"examplereceiver": true, "examplereceiver/2": true, "examplereceiver/multi": true}, diff.Receivers)
+}
+
+func TestConfigDiff_PipelineRemoved(t *testing.T) {
+	oldCfg := loadPipelinesBuilderConfig(t)
+	newCfg := loadPipelinesBuilderConfig(t)
+	delete(newCfg.Service.Pipelines, "metrics/2")
+
+	diff := NewConfigDiff(oldCfg, newCfg)
+	assert.Empty(t, diff.Exporters)
+	assert.Equal(t, map[string]bool{"metrics/2": true}, diff.Pipelines)
+	assert.Equal(t, map[string]bool{"examplereceiver/3": true}, diff.Receivers)
+}
+
+func TestRebuild_ReusesUnchangedComponents(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	require.NoError(t, err)
+	oldCfg := loadPipelinesBuilderConfig(t)
+	newCfg := loadPipelinesBuilderConfig(t)
+	delete(newCfg.Service.Pipelines, "metrics/2")
+	diff := NewConfigDiff(oldCfg, newCfg)
+
+	appInfo := componenttest.TestApplicationStartInfo()
+	oldExporters, err := NewExportersBuilder(zap.NewNop(), appInfo, oldCfg, factories.Exporters).Build()
+	require.NoError(t, err)
+	oldPipelines, err := NewPipelinesBuilder(zap.NewNop(), appInfo, oldCfg, oldExporters, factories.Processors).Build()
+	require.NoError(t, err)
+	oldReceivers, err := NewReceiversBuilder(zap.NewNop(), appInfo, oldCfg, oldPipelines, factories.Receivers).Build()
+	require.NoError(t, err)
+
+	newExporters, err := NewExportersBuilder(zap.NewNop(), appInfo, newCfg, factories.Exporters).Rebuild(oldExporters, diff.Exporters)
+	require.NoError(t, err)
+	newPipelines, err := NewPipelinesBuilder(zap.NewNop(), appInfo, newCfg, newExporters, factories.Processors).Rebuild(oldPipelines, diff.Pipelines)
+	require.NoError(t, err)
+	newReceivers, err := NewReceiversBuilder(zap.NewNop(), appInfo, newCfg, newPipelines, factories.Receivers).Rebuild(oldReceivers, diff.Receivers)
+	require.NoError(t, err)
+
+	assert.Same(t, oldExporters.findByName("exampleexporter"), newExporters.findByName("exampleexporter"))
+	assert.Same(t, oldExporters.findByName("exampleexporter/2"), newExporters.findByName("exampleexporter/2"))
+	assert.Same(t, oldPipelines.findByName("traces"), newPipelines.findByName("traces"))
+	assert.Same(t, oldPipelines.findByName("metrics/3"), newPipelines.findByName("metrics/3"))
+	assert.Nil(t, newPipelines.findByName("metrics/2"))
+	assert.Same(t, oldReceivers.findByName("examplereceiver"), newReceivers.findByName("examplereceiver"))
+	assert.NotSame(t, oldReceivers.findByName("examplereceiver/3"), newReceivers.findByName("examplereceiver/3"))
+
+	assert.Len(t, newExporters.Filter(diff.Exporters), 0)
+	assert.Len(t, newPipelines.Filter(diff.Pipelines), 0)
+	assert.Len(t, newReceivers.Filter(diff.Receivers), 1)
+}
+
+// exportersHost is a component.Host returning the given exporters.
+type exportersHost struct {
+	component.Host
+	exporters Exporters
+}
+
+func (h *exportersHost) GetExporters() map[configmodels.DataType]map[configmodels.Exporter]component.Exporter {
+	return h.exporters.ToMapByDataType()
+}
+
+func TestRebuild_SpanMetricsUsesNewExporter(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	require.NoError(t, err)
+	spanMetricsFactory := spanmetricsprocessor.NewFactory()
+	factories.Processors[spanMetricsFactory.Type()] = spanMetricsFactory
+	oldCfg, err := configtest.LoadConfigFile(t, "testdata/spanmetrics_reload.yaml", factories)
+	require.NoError(t, err)
+	newCfg, err := configtest.LoadConfigFile(t, "testdata/spanmetrics_reload.yaml", factories)
+	require.NoError(t, err)
+	newCfg.Exporters["exampleexporter/metrics"].(*componenttest.ExampleExporter).ExtraSetting = "changed"
+
+	// the traces pipeline doesn't use the changed exporter, but its processor does
+	diff := NewConfigDiff(oldCfg, newCfg)
+	assert.Equal(t, map[string]bool{"exampleexporter/metrics": true}, diff.Exporters)
+	assert.True(t, diff.Pipelines["traces"])
+
+	ctx := context.Background()
+	appInfo := componenttest.TestApplicationStartInfo()
+	oldExporters, err := NewExportersBuilder(zap.NewNop(), appInfo, oldCfg, factories.Exporters).Build()
+	require.NoError(t, err)
+	oldPipelines, err := NewPipelinesBuilder(zap.NewNop(), appInfo, oldCfg, oldExporters, factories.Processors).Build()
+	require.NoError(t, err)
+	require.NoError(t, oldExporters.StartAll(ctx, componenttest.NewNopHost()))
+	require.NoError(t, oldPipelines.StartProcessors(ctx, &exportersHost{Host: componenttest.NewNopHost(), exporters: oldExporters}))
+
+	// reload, in the same order as the application
+	newExporters, err := NewExportersBuilder(zap.NewNop(), appInfo, newCfg, factories.Exporters).Rebuild(oldExporters, diff.Exporters)
+	require.NoError(t, err)
+	newPipelines, err := NewPipelinesBuilder(zap.NewNop(), appInfo, newCfg, newExporters, factories.Processors).Rebuild(oldPipelines, diff.Pipelines)
+	require.NoError(t, err)
+	require.NoError(t, oldPipelines.Filter(diff.Pipelines).ShutdownProcessors(ctx))
+	require.NoError(t, oldExporters.Filter(diff.Exporters).ShutdownAll(ctx))
+	require.NoError(t, newExporters.Filter(diff.Exporters).StartAll(ctx, componenttest.NewNopHost()))
+	require.NoError(t, newPipelines.Filter(diff.Pipelines).StartProcessors(ctx, &exportersHost{Host: componenttest.NewNopHost(), exporters: newExporters}))
+
+	require.NoError(t, newPipelines.findByName("traces").firstTC.ConsumeTraces(ctx, testdata.GenerateTraceDataOneSpan()))
+	// the metrics are flushed on shutdown
+	require.NoError(t, newPipelines.ShutdownProcessors(ctx))
+
+	oldExporter := oldExporters.findByName("exampleexporter/metrics").getMetricExporter().(*componenttest.ExampleExporterConsumer)
+	newExporter := newExporters.findByName("exampleexporter/metrics").getMetricExporter().(*componenttest.ExampleExporterConsumer)
+	assert.True(t, oldExporter.ExporterShutdown)
+	assert.Empty(t, oldExporter.Metrics)
+	assert.NotEmpty(t, newExporter.Metrics)
+}
@@ -112,6 +112,27 @@ func (exps Exporters) ShutdownAll(ctx context.Context) error {
 	return componenterror.CombineErrors(errs)
 }
 
+// findByName returns the exporter built for the config with the given name, or nil.
+func (exps Exporters) findByName(name string) *builtExporter {
+	for cfg, exp := range exps {
+		if cfg.Name() == name {
+			return exp
+		}
+	}
+	return nil
+}
+
+// Filter returns the subset of exporters whose config name is in names.
+func (exps Exporters) Filter(names map[string]bool) Exporters {
+	result := make(Exporters)
+	for cfg, v := range exps {
+		if names[cfg.Name()] {
+			result[cfg] = v
+		}
+	}
+	return result
+}
+
 func (exps Exporters) ToMapByDataType() map[configmodels.DataType]map[configmodels.Exporter]component.Exporter {
 
 	exportersMap := make(map[configmodels.DataType]map[configmodels.Exporter]component.Exporter)
@@ -160,6 +181,12 @@ func NewExportersBuilder(
 
 // BuildExporters exporters from config.
 func (eb *ExportersBuilder) Build() (Exporters, error) {
+	return eb.Rebuild(nil, nil)
+}
+
+// Rebuild builds the exporters from config that are listed in changed and reuses the
+// already built exporters with the same name from previous for all the others.
+func (eb *ExportersBuilder) Rebuild(previous Exporters, changed map[string]bool) (Exporters, error) {
 	exporters := make(Exporters)
 
 	// We need to calculate required input data types for each exporter so that we know
@@ -167,7 +194,12 @@ func (eb *ExportersBuilder) Build() (Exporters, error) {
 	exporterInputDataTypes := eb.calcExportersRequiredDataTypes()
 
 	// BuildExporters exporters based on configuration and required input data types.
-	for _, cfg := range eb.config.Exporters {
+	for name, cfg := range eb.config.Exporters {
+		if exp := previous.findByName(name); exp != nil && !changed[name] {
+			exporters[cfg] = exp
+			continue
+		}
+
 		componentLogger := eb.logger.With(zap.String(typeLogKey, string(cfg.Type())), zap.String(nameLogKey, cfg.Name()))
 		exp, err := eb.buildExporter(context.Background(), componentLogger, eb.appInfo, cfg, exporterInputDataTypes)
 		if err != nil {
@@ -74,6 +74,19 @@ func (exts Extensions) ShutdownAll(ctx context.Context) error {
 	return componenterror.CombineErrors(errs)
 }
 
+func (exts Extensions) NotifyPipelinesConfigured(pipelines configmodels.Pipelines) error {
+	for _, ext := range exts {
+		if pcw, ok := ext.extension.(component.PipelineConfigWatcher); ok {
+			if err := pcw.PipelinesConfigured(pipelines); err != nil {
+				ext.logger.Error("Error notifying extension of the pipelines configuration.")
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func (exts Extensions) NotifyPipelineReady() error {
 	for _, ext := range exts {
 		if pw, ok := ext.extension.(component.PipelineWatcher); ok {
@@ -102,6 +115,17 @@ func (exts Extensions) NotifyPipelineNotReady() error {
 	return componenterror.CombineErrors(errs)
 }
 
+// ExportWatchers returns the extensions interested in the outcome of the export operations.
+func (exts Extensions) ExportWatchers() []component.ExportWatcher {
+	var watchers []component.ExportWatcher
+	for _, ext := range exts {
+		if ew, ok := ext.extension.(component.ExportWatcher); ok {
+			watchers = append(watchers, ew)
+		}
+	}
+	return watchers
+}
+
 func (exts Extensions) ToMap() map[configmodels.Extension]component.ServiceExtension {
 	result := make(map[configmodels.Extension]component.ServiceExtension, len(exts))
 	for k, v := range exts {
@@ -110,6 +134,27 @@ func (exts Extensions) ToMap() map[configmodels.Extension]component.ServiceExten
 	return result
 }
 
+// findByName returns the extension built for the config with the given name, or nil.
+func (exts Extensions) findByName(name string) *builtExtension {
+	for cfg, ext := range exts {
+		if cfg.Name() == name {
+			return ext
+		}
+	}
+	return nil
+}
+
+// Filter returns the subset of extensions whose config name is in names.
+func (exts Extensions) Filter(names map[string]bool) Extensions {
+	result := make(Extensions)
+	for cfg, v := range exts {
+		if names[cfg.Name()] {
+			result[cfg] = v
+		}
+	}
+	return result
+}
+
 // ExportersBuilder builds exporters from config.
 type ExtensionsBuilder struct {
 	logger    *zap.Logger
@@ -130,6 +175,12 @@ func NewExtensionsBuilder(
 
 // Build extensions from config.
 func (eb *ExtensionsBuilder) Build() (Extensions, error) {
+	return eb.Rebuild(nil, nil)
+}
+
+// Rebuild builds the extensions from config that are listed in changed and reuses the
+// already built extensions with the same name from previous for all the others.
+func (eb *ExtensionsBuilder) Rebuild(previous Extensions, changed map[string]bool) (Extensions, error) {
 	extensions := make(Extensions)
 
 	for _, extName := range eb.config.Service.Extensions {
@@ -138,6 +189,11 @@ func (eb *ExtensionsBuilder) Build() (Extensions, error) {
 			return nil, fmt.Errorf("extension %q is not configured", extName)
 		}
 
+		if ext := previous.findByName(extName); ext != nil && !changed[extName] {
+			extensions[extCfg] = ext
+			continue
+		}
+
 		componentLogger := eb.logger.With(zap.String(typeLogKey, string(extCfg.Type())), zap.String(nameLogKey, extCfg.Name()))
 		ext, err := eb.buildExtension(componentLogger, eb.appInfo, extCfg)
 		if err != nil {
@@ -78,6 +78,27 @@ func (bps BuiltPipelines) ShutdownProcessors(ctx context.Context) error {
 	return componenterror.CombineErrors(errs)
 }
 
+// findByName returns the pipeline built for the config with the given name, or nil.
+func (bps BuiltPipelines) findByName(name string) *builtPipeline {
+	for cfg, bp := range bps {
+		if cfg.Name == name {
+			return bp
+		}
+	}
+	return nil
+}
+
+// Filter returns the subset of pipelines whose config name is in names.
+func (bps BuiltPipelines) Filter(names map[string]bool) BuiltPipelines {
+	result := make(BuiltPipelines)
+	for cfg, v := range bps {
+		if names[cfg.Name] {
+			result[cfg] = v
+		}
+	}
+	return result
+}
+
 // PipelinesBuilder builds pipelines from config.
 type PipelinesBuilder struct {
 	logger    *zap.Logger
@@ -101,9 +122,20 @@ func NewPipelinesBuilder(
 
 // BuildProcessors pipeline processors from config.
 func (pb *PipelinesBuilder) Build() (BuiltPipelines, error) {
+	return pb.Rebuild(nil, nil)
+}
+
+// Rebuild builds the pipelines from config that are listed in changed and reuses the
+// already built pipelines with the same name from previous for all the others.
+func (pb *PipelinesBuilder) Rebuild(previous BuiltPipelines, changed map[string]bool) (BuiltPipelines, error) {
 	pipelineProcessors := make(BuiltPipelines)
 
-	for _, pipeline := range pb.config.Service.Pipelines {
+	for name, pipeline := range pb.config.Service.Pipelines {
+		if bp := previous.findByName(name); bp != nil && !changed[name] {
+			pipelineProcessors[pipeline] = bp
+			continue
+		}
+
 		firstProcessor, err := pb.buildPipeline(context.Background(), pipeline)
 		if err != nil {
 			return nil, err
@@ -77,6 +77,27 @@ func (rcvs Receivers) StartAll(ctx context.Context, host component.Host) error {
 	return nil
 }
 
+// findByName returns the receiver built for the config with the given name, or nil.
+func (rcvs Receivers) findByName(name string) *builtReceiver {
+	for cfg, rcv := range rcvs {
+		if cfg.Name() == name {
+			return rcv
+		}
+	}
+	return nil
+}
+
+// Filter returns the subset of receivers whose config name is in names.
+func (rcvs Receivers) Filter(names map[string]bool) Receivers {
+	result := make(Receivers)
+	for cfg, v := range rcvs {
+		if names[cfg.Name()] {
+			result[cfg] = v
+		}
+	}
+	return result
+}
+
 // ReceiversBuilder builds receivers from config.
 type ReceiversBuilder struct {
 	logger         *zap.Logger
@@ -99,10 +120,21 @@ func NewReceiversBuilder(
 
 // BuildProcessors receivers from config.
 func (rb *ReceiversBuilder) Build() (Receivers, error) {
+	return rb.Rebuild(nil, nil)
+}
+
+// Rebuild builds the receivers from config that are listed in changed and reuses the
+// already built receivers with the same name from previous for all the others.
+func (rb *ReceiversBuilder) Rebuild(previous Receivers, changed map[string]bool) (Receivers, error) {
 	receivers := make(Receivers)
 
 	// BuildProcessors receivers based on configuration.
-	for _, cfg := range rb.config.Receivers {
+	for name, cfg := range rb.config.Receivers {
+		if rcv := previous.findByName(name); rcv != nil && !changed[name] {
+			receivers[cfg] = rcv
+			continue
+		}
+
 		logger := rb.logger.With(zap.String(typeLogKey, string(cfg.Type())), zap.String(nameLogKey, cfg.Name()))
 		rcv, err := rb.buildReceiver(context.Background(), logger, rb.appInfo, cfg)
 		if err != nil {
@@ -41,6 +41,7 @@ func TestDefaultComponents(t *testing.T) {
 		"hostmetrics",
 		"fluentforward",
 		"kafka",
+		"otlpfile",
 	}
 	expectedProcessors := []configmodels.Type{
 		"attributes",
@@ -50,6 +51,12 @@ func TestDefaultComponents(t *testing.T) {
 		"probabilistic_sampler",
 		"span",
 		"filter",
+		"tail_sampling",
+		"routing",
+		"spanmetrics",
+		"metricstransform",
+		"temporality",
+		"groupbytrace",
 	}
 	expectedExporters := []configmodels.Type{
 		"opencensus",
@@ -62,6 +69,7 @@ func TestDefaultComponents(t *testing.T) {
 		"otlp",
 		"otlphttp",
 		"kafka",
+		"loadbalancing",
 	}
 
 	factories, err := Components()
@@ -0,0 +1,190 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/service/builder"
+)
+
+// reloadConfiguration loads the configuration again and rebuilds only the components that
+// changed. If the new configuration cannot be loaded, validated or built, the error is logged
+// and the running components are left untouched. An error is returned only if the components
+// could not be restarted, in which case the application must terminate.
+func (app *Application) reloadConfiguration(ctx context.Context, factory ConfigFactory) error {
+	v := config.NewViper()
+	cfg, err := factory(v, app.rootCmd, app.factories)
+	if err != nil {
+		app.logger.Error("Cannot load configuration, keeping the running configuration", zap.Error(err))
+		return nil
+	}
+	if err = config.ValidateConfig(cfg, app.logger); err != nil {
+		app.logger.Error("Invalid configuration, keeping the running configuration", zap.Error(err))
+		return nil
+	}
+
+	diff := builder.NewConfigDiff(app.config, cfg)
+	if diff.IsEmpty() {
+		app.logger.Info("Configuration did not change")
+		return nil
+	}
+
+	// Build the changed components first, nothing is started or stopped yet
+	// so any error here keeps the running configuration.
+	extensions, err := builder.NewExtensionsBuilder(app.logger, app.info, cfg, app.factories.Extensions).Rebuild(app.builtExtensions, diff.Extensions)
+	if err != nil {
+		app.logger.Error("Cannot build extensions, keeping the running configuration", zap.Error(err))
+		return nil
+	}
+	exporters, err := builder.NewExportersBuilder(app.logger, app.info, cfg, app.factories.Exporters).Rebuild(app.builtExporters, diff.Exporters)
+	if err != nil {
+		app.logger.Error("Cannot build exporters, keeping the running configuration", zap.Error(err))
+		return nil
+	}
+	pipelines, err := builder.NewPipelinesBuilder(app.logger, app.info, cfg, exporters, app.factories.Processors).Rebuild(app.builtPipelines, diff.Pipelines)
+	if err != nil {
+		app.logger.Error("Cannot build pipelines, keeping the running configuration", zap.Error(err))
+		return nil
+	}
+	receivers, err := builder.NewReceiversBuilder(app.logger, app.info, cfg, pipelines, app.factories.Receivers).Rebuild(app.builtReceivers, diff.Receivers)
+	if err != nil {
+		app.logger.Error("Cannot build receivers, keeping the running configuration", zap.Error(err))
+		return nil
+	}
+
+	app.logger.Info("Applying configuration changes...",
+		zap.Int("extensions", len(diff.Extensions)),
+		zap.Int("exporters", len(diff.Exporters)),
+		zap.Int("pipelines", len(diff.Pipelines)),
+		zap.Int("receivers", len(diff.Receivers)))
+
+	if err = app.builtExtensions.NotifyPipelineNotReady(); err != nil {
+		app.logger.Warn("Failed to notify extensions that the pipeline is not ready", zap.Error(err))
+	}
+	// The extensions being replaced aren't notified of the exports anymore once they are shut down.
+	kept := make(builder.Extensions)
+	for cfg, ext := range app.builtExtensions {
+		if !diff.Extensions[cfg.Name()] {
+			kept[cfg] = ext
+		}
+	}
+	app.setExportWatchers(kept)
+	if err = app.shutdownChangedComponents(ctx, diff); err != nil {
+		app.logger.Warn("Failed to shutdown changed components", zap.Error(err))
+	}
+
+	app.v = v
+	app.config = cfg
+	app.builtExtensions = extensions
+	app.builtExporters = exporters
+	app.builtPipelines = pipelines
+	app.builtReceivers = receivers
+
+	if err = app.startChangedComponents(ctx, diff); err != nil {
+		app.logger.Error("Cannot start components of the new configuration, terminating process", zap.Error(err))
+		return err
+	}
+	app.setExportWatchers(app.builtExtensions)
+	if err = app.builtExtensions.NotifyPipelinesConfigured(cfg.Service.Pipelines); err != nil {
+		return err
+	}
+	if err = app.builtExtensions.NotifyPipelineReady(); err != nil {
+		return err
+	}
+
+	app.logger.Info("Configuration reloaded.")
+	return nil
+}
+
+// shutdownChangedComponents stops the running components that are going to be replaced,
+// in the same order as a regular shutdown.
+func (app *Application) shutdownChangedComponents(ctx context.Context, diff *builder.ConfigDiff) error {
+	var errs []error
+	if err := app.builtReceivers.Filter(diff.Receivers).ShutdownAll(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to stop receivers: %w", err))
+	}
+	if err := app.builtPipelines.Filter(diff.Pipelines).ShutdownProcessors(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to shutdown processors: %w", err))
+	}
+	if err := app.builtExporters.Filter(diff.Exporters).ShutdownAll(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to shutdown exporters: %w", err))
+	}
+	if err := app.builtExtensions.Filter(diff.Extensions).ShutdownAll(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to shutdown extensions: %w", err))
+	}
+	return componenterror.CombineErrors(errs)
+}
+
+// startChangedComponents starts the newly built components, in the same order as a regular start.
+func (app *Application) startChangedComponents(ctx context.Context, diff *builder.ConfigDiff) error {
+	if err := app.builtExtensions.Filter(diff.Extensions).StartAll(ctx, app); err != nil {
+		return fmt.Errorf("cannot start extensions: %w", err)
+	}
+	if err := app.builtExporters.Filter(diff.Exporters).StartAll(ctx, app); err != nil {
+		return fmt.Errorf("cannot start exporters: %w", err)
+	}
+	if err := app.builtPipelines.Filter(diff.Pipelines).StartProcessors(ctx, app); err != nil {
+		return fmt.Errorf("cannot start processors: %w", err)
+	}
+	if err := app.builtReceivers.Filter(diff.Receivers).StartAll(ctx, app); err != nil {
+		return fmt.Errorf("cannot start receivers: %w", err)
+	}
+	return nil
+}
+
+// watchConfigFile polls the config file every interval and signals the returned channel
+// when its modification time or size changes. Returns a nil channel if interval is 0.
+func watchConfigFile(file string, interval time.Duration, done <-chan struct{}, logger *zap.Logger) <-chan struct{} {
+	if interval <= 0 || file == "" {
+		return nil
+	}
+
+	changed := make(chan struct{}, 1)
+	last, err := os.Stat(file)
+	if err != nil {
+		logger.Warn("Cannot watch config file", zap.String("file", file), zap.Error(err))
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				info, err := os.Stat(file)
+				if err != nil {
+					continue
+				}
+				if last == nil || !info.ModTime().Equal(last.ModTime()) || info.Size() != last.Size() {
+					last = info
+					select {
+					case changed <- struct{}{}:
+					default:
+					}
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return changed
+}
@@ -27,6 +27,7 @@ import (
 	"path"
 	"runtime"
 	"sort"
+	"sync"
 	"syscall"
 
 	"github.com/spf13/cobra"
@@ -89,8 +90,14 @@ type Application struct {
 
 	// asyncErrorChannel is used to signal a fatal error from any component.
 	asyncErrorChannel chan error
+
+	// exportWatchers are the started extensions notified of the outcome of the export operations.
+	exportWatchersMu sync.RWMutex
+	exportWatchers   []component.ExportWatcher
 }
 
+var _ component.ExportReporter = (*Application)(nil)
+
 // Command returns Application's root command.
 func (app *Application) Command() *cobra.Command {
 	return app.rootCmd
@@ -225,6 +232,22 @@ func (app *Application) GetExporters() map[configmodels.DataType]map[configmodel
 	return app.builtExporters.ToMapByDataType()
 }
 
+// ReportExport notifies the started extensions implementing component.ExportWatcher of the
+// result of an export operation.
+func (app *Application) ReportExport(result component.ExportResult) {
+	app.exportWatchersMu.RLock()
+	defer app.exportWatchersMu.RUnlock()
+	for _, watcher := range app.exportWatchers {
+		watcher.ExportCompleted(result)
+	}
+}
+
+func (app *Application) setExportWatchers(extensions builder.Extensions) {
+	app.exportWatchersMu.Lock()
+	defer app.exportWatchersMu.Unlock()
+	app.exportWatchers = extensions.ExportWatchers()
+}
+
 func (app *Application) RegisterZPages(mux *http.ServeMux, pathPrefix string) {
 	mux.HandleFunc(path.Join(pathPrefix, servicezPath), app.handleServicezRequest)
 	mux.HandleFunc(path.Join(pathPrefix, pipelinezPath), app.handlePipelinezRequest)
@@ -262,24 +285,45 @@ func (app *Application) setupTelemetry(ballastSizeBytes uint64) error {
 	return nil
 }
 
-// runAndWaitForShutdownEvent waits for one of the shutdown events that can happen.
-func (app *Application) runAndWaitForShutdownEvent() {
+// runAndWaitForShutdownEvent waits for one of the shutdown events that can happen,
+// reloading the configuration on SIGHUP or when the config file changes.
+func (app *Application) runAndWaitForShutdownEvent(ctx context.Context, factory ConfigFactory) {
 	app.logger.Info("Everything is ready. Begin running and processing data.")
 
-	// plug SIGTERM signal into a channel.
+	// plug SIGTERM and SIGHUP signals into a channel.
 	app.signalsChannel = make(chan os.Signal, 1)
-	signal.Notify(app.signalsChannel, os.Interrupt, syscall.SIGTERM)
+	signal.Notify(app.signalsChannel, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
 
 	// set the channel to stop testing.
 	app.stopTestChan = make(chan struct{})
+
+	watchDone := make(chan struct{})
+	defer close(watchDone)
+	configChanged := watchConfigFile(builder.GetConfigFile(), builder.ConfigWatchInterval(), watchDone, app.logger)
+
 	app.stateChannel <- Running
-	select {
-	case err := <-app.asyncErrorChannel:
-		app.logger.Error("Asynchronous error received, terminating process", zap.Error(err))
-	case s := <-app.signalsChannel:
-		app.logger.Info("Received signal from OS", zap.String("signal", s.String()))
-	case <-app.stopTestChan:
-		app.logger.Info("Received stop test request")
+	for {
+		select {
+		case err := <-app.asyncErrorChannel:
+			app.logger.Error("Asynchronous error received, terminating process", zap.Error(err))
+		case s := <-app.signalsChannel:
+			if s == syscall.SIGHUP {
+				app.logger.Info("Received SIGHUP, reloading configuration")
+				if err := app.reloadConfiguration(ctx, factory); err == nil {
+					continue
+				}
+			} else {
+				app.logger.Info("Received signal from OS", zap.String("signal", s.String()))
+			}
+		case <-configChanged:
+			app.logger.Info("Config file changed, reloading configuration")
+			if err := app.reloadConfiguration(ctx, factory); err == nil {
+				continue
+			}
+		case <-app.stopTestChan:
+			app.logger.Info("Received stop test request")
+		}
+		break
 	}
 	app.stateChannel <- Closing
 }
@@ -322,7 +366,11 @@ func (app *Application) setupExtensions(ctx context.Context) error {
 		return fmt.Errorf("cannot build builtExtensions: %w", err)
 	}
 	app.logger.Info("Starting extensions...")
-	return app.builtExtensions.StartAll(ctx, app)
+	if err = app.builtExtensions.StartAll(ctx, app); err != nil {
+		return err
+	}
+	app.setExportWatchers(app.builtExtensions)
+	return nil
 }
 
 func (app *Application) setupPipelines(ctx context.Context) error {
@@ -400,6 +448,7 @@ func (app *Application) shutdownPipelines(ctx context.Context) error {
 
 func (app *Application) shutdownExtensions(ctx context.Context) error {
 	app.logger.Info("Stopping extensions...")
+	app.setExportWatchers(nil)
 	err := app.builtExtensions.ShutdownAll(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to shutdown extensions: %w", err)
@@ -431,13 +480,18 @@ func (app *Application) execute(ctx context.Context, factory ConfigFactory) erro
 		return err
 	}
 
+	err = app.builtExtensions.NotifyPipelinesConfigured(app.config.Service.Pipelines)
+	if err != nil {
+		return err
+	}
+
 	err = app.builtExtensions.NotifyPipelineReady()
 	if err != nil {
 		return err
 	}
 
 	// Everything is ready, now run until an event requiring shutdown happens.
-	app.runAndWaitForShutdownEvent()
+	app.runAndWaitForShutdownEvent(ctx, factory)
 
 	// Accumulate errors and proceed with shutting down remaining components.
 	var errs []error
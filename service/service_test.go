@@ -25,6 +25,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"testing"
 	"time"
@@ -41,9 +42,11 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/exporter/loggingexporter"
 	"go.opentelemetry.io/collector/processor/attributesprocessor"
 	"go.opentelemetry.io/collector/processor/batchprocessor"
 	"go.opentelemetry.io/collector/receiver/jaegerreceiver"
+	"go.opentelemetry.io/collector/receiver/otlpreceiver"
 	"go.opentelemetry.io/collector/service/builder"
 	"go.opentelemetry.io/collector/service/defaultcomponents"
 	"go.opentelemetry.io/collector/testutil"
@@ -174,6 +177,79 @@ func TestApplication_StartAsGoRoutine(t *testing.T) {
 	assert.Equal(t, Closed, <-app.GetStateChannel())
 }
 
+func TestApplication_ReloadOnSIGHUP(t *testing.T) {
+	factories, err := defaultcomponents.Components()
+	require.NoError(t, err)
+
+	logged := make(chan string, 100)
+	hook := func(entry zapcore.Entry) error {
+		select {
+		case logged <- entry.Message:
+		default:
+		}
+		return nil
+	}
+	waitForLog := func(msg string) {
+		for {
+			select {
+			case m := <-logged:
+				if m == msg {
+					return
+				}
+			case <-time.After(10 * time.Second):
+				require.Fail(t, "log message not received", msg)
+				return
+			}
+		}
+	}
+
+	var loads int32
+	endpoint := testutil.GetAvailableLocalAddress(t)
+	params := Parameters{
+		ApplicationStartInfo: componenttest.TestApplicationStartInfo(),
+		ConfigFactory: func(_ *viper.Viper, _ *cobra.Command, factories component.Factories) (*configmodels.Config, error) {
+			cfg := constructMimumalOpConfig(t, factories)
+			cfg.Receivers["otlp"].(*otlpreceiver.Config).GRPC.NetAddr.Endpoint = endpoint
+			switch atomic.AddInt32(&loads, 1) {
+			case 2:
+				cfg.Exporters["logging"].(*loggingexporter.Config).LogLevel = "debug"
+			case 3:
+				// References an exporter that does not exist, fails validation.
+				cfg.Service.Pipelines["traces"].Exporters = []string{"logging", "unknown"}
+			}
+			return cfg, nil
+		},
+		Factories:      factories,
+		LoggingOptions: []zap.Option{zap.Hooks(hook)},
+	}
+	app, err := New(params)
+	require.NoError(t, err)
+	app.Command().SetArgs([]string{"--metrics-level=NONE"})
+
+	appDone := make(chan struct{})
+	go func() {
+		defer close(appDone)
+		assert.NoError(t, app.Run())
+	}()
+
+	assert.Equal(t, Starting, <-app.GetStateChannel())
+	assert.Equal(t, Running, <-app.GetStateChannel())
+
+	app.signalsChannel <- syscall.SIGHUP
+	waitForLog("Configuration reloaded.")
+
+	app.signalsChannel <- syscall.SIGHUP
+	waitForLog("Invalid configuration, keeping the running configuration")
+
+	app.signalsChannel <- syscall.SIGTERM
+	<-appDone
+	assert.Equal(t, Closing, <-app.GetStateChannel())
+	assert.Equal(t, Closed, <-app.GetStateChannel())
+
+	assert.Equal(t, "debug", app.config.Exporters["logging"].(*loggingexporter.Config).LogLevel)
+	assert.Equal(t, []string{"logging"}, app.config.Service.Pipelines["traces"].Exporters)
+}
+
 // isAppAvailable checks if the healthcheck server at the given endpoint is
 // returning `available`.
 func isAppAvailable(t *testing.T, healthCheckEndPoint string) bool {
@@ -30,6 +30,8 @@ import (
 	"go.opentelemetry.io/collector/obsreport"
 	"go.opentelemetry.io/collector/processor"
 	"go.opentelemetry.io/collector/processor/batchprocessor"
+	"go.opentelemetry.io/collector/processor/groupbytraceprocessor"
+	"go.opentelemetry.io/collector/processor/tailsamplingprocessor"
 	fluentobserv "go.opentelemetry.io/collector/receiver/fluentforwardreceiver/observ"
 	"go.opentelemetry.io/collector/receiver/kafkareceiver"
 	telemetry2 "go.opentelemetry.io/collector/service/internal/telemetry"
@@ -65,11 +67,13 @@ func (tel *appTelemetry) init(asyncErrorChannel chan<- error, ballastSizeBytes u
 	var views []*view.View
 	views = append(views, batchprocessor.MetricViews()...)
 	views = append(views, fluentobserv.MetricViews()...)
+	views = append(views, groupbytraceprocessor.MetricViews()...)
 	views = append(views, jaegerexporter.MetricViews()...)
 	views = append(views, kafkareceiver.MetricViews()...)
 	views = append(views, obsreport.Configure(level)...)
 	views = append(views, processMetricsViews.Views()...)
 	views = append(views, processor.MetricViews()...)
+	views = append(views, tailsamplingprocessor.MetricViews()...)
 
 	tel.views = views
 	if err = view.Register(views...); err != nil {